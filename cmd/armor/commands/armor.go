@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
@@ -8,18 +9,22 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	lightstep "github.com/lightstep/lightstep-tracer-go"
 	stdopentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"sourcegraph.com/sourcegraph/appdash"
 	appdashot "sourcegraph.com/sourcegraph/appdash/opentracing"
 
 	"github.com/cdwlabs/armor/cmd/helpers"
 	"github.com/cdwlabs/armor/pb"
 	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/cdwlabs/armor/pkg/proxy/auth"
+	"github.com/cdwlabs/armor/pkg/proxy/autounseal"
 	"github.com/cdwlabs/armor/pkg/proxy/endpoints"
 	armorgrpc "github.com/cdwlabs/armor/pkg/proxy/grpc"
 	armorhealth "github.com/cdwlabs/armor/pkg/proxy/health"
@@ -82,19 +87,71 @@ var (
 
 // Flags that are to be added to commands.
 var (
-	adminAddr          string
-	httpAddr           string
-	grpcAddr           string
-	appdashAddr        string
-	lightstepToken     string
-	vaultAddr          string
-	vaultCACert        string
-	vaultCAPath        string
-	vaultTLSSkipVerify bool
-	cfgFile            string
-	policyConfigPath   string
-	awsAccessKeyID     string
-	awsSecretAccessKey string
+	adminAddr           string
+	httpAddr            string
+	grpcAddr            string
+	appdashAddr         string
+	lightstepToken      string
+	tlsCertFile         string
+	tlsKeyFile          string
+	tlsClientCAFile     string
+	tlsRequireCert      bool
+	adminTLSRequireCert bool
+	authPolicyFile      string
+	authVaultTokens     bool
+	vaultAddr           string
+	vaultNodes          []string
+	defaultCluster      string
+	vaultClusterID      string
+	vaultCACert         string
+	vaultCAPath         string
+	vaultTLSSkipVerify  bool
+	vaultPluginDir      string
+	cfgFile             string
+	policyConfigPath    string
+	tokenHolderStore    string
+	tokenHolderPath     string
+	tokenEncryption     string
+	tokenKMSKeyID       string
+	tokenKeyFile        string
+	tokenPrevKeyFiles   []string
+	pgpKeyringDir       string
+	enforcePGP          bool
+	initLockTTL         time.Duration
+	autoUnseal          bool
+	autoUnsealInterval  time.Duration
+	autoUnsealAttempts  int
+	autoUnsealWindow    time.Duration
+	autoUnsealAlerts    []string
+	autoUnsealAudit     string
+	ceremonyTTL         time.Duration
+	sealWatchInterval   time.Duration
+	sealWatchHeartbeat  time.Duration
+	deliveryMethod      string
+	deliveryFrom        string
+	deliverySubject     string
+	deliveryBody        string
+	deliveryRetries     int
+	deliveryInterval    time.Duration
+	smtpAddr            string
+	smtpUsername        string
+	smtpPassword        string
+	awsAccessKeyID      string
+	awsSecretAccessKey  string
+	awsProfile          string
+	awsRoleARN          string
+	awsRoleSession      string
+	awsRoleExternalID   string
+	dynamoEndpoint      string
+	dynamoRegion        string
+	dynamoHolderTable   string
+	dynamoInitTable     string
+	dynamoCeremonyTable string
+	dynamoBillingMode   string
+	dynamoReadCap       int64
+	dynamoWriteCap      int64
+	dynamoPITR          bool
+	dynamoKMSKeyID      string
 )
 
 // Execute adds all the child commands to the root command ArmorCmd and sets
@@ -118,7 +175,15 @@ func Execute() {
 
 // AddCommands adds child commands to the root command ArmorCmd.
 func AddCommands() {
-
+	ArmorCmd.AddCommand(exportCmd)
+	ArmorCmd.AddCommand(migrateTokenHoldersCmd)
+	ArmorCmd.AddCommand(reencryptTokenHoldersCmd)
+	ArmorCmd.AddCommand(deliverCmd)
+	ArmorCmd.AddCommand(statusCmd)
+	ArmorCmd.AddCommand(initCmd)
+	ArmorCmd.AddCommand(unsealCmd)
+	ArmorCmd.AddCommand(sealStatusCmd)
+	ArmorCmd.AddCommand(configureCmd)
 }
 
 // initRootPersistentFlags initialize common flags related to running the
@@ -144,10 +209,40 @@ func initRootPersistentFlags() {
 	lightstepTokenDesc := fmt.Sprintf("Enable LightStep tracing via a LightStep access token. Overrides the %s environment variable if set.\n%s", config.LightstepTokenEnvVar, "")
 	ArmorCmd.PersistentFlags().StringVar(&lightstepToken, "lightstep-token", "", lightstepTokenDesc)
 
+	// Listener TLS
+	tlsCertFileDesc := fmt.Sprintf("Certificate served by the admin, HTTP and gRPC listeners, which serve plain text if unset. Overrides the %s environment variable if set.\n", config.TLSCertFileEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&tlsCertFile, "tls-cert-file", "", tlsCertFileDesc)
+	tlsKeyFileDesc := fmt.Sprintf("Private key of the listeners' certificate. Overrides the %s environment variable if set.\n", config.TLSKeyFileEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&tlsKeyFile, "tls-key-file", "", tlsKeyFileDesc)
+	tlsClientCAFileDesc := fmt.Sprintf("CA verifying the certificates presented by clients. Overrides the %s environment variable if set.\n", config.TLSClientCAFileEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&tlsClientCAFile, "tls-client-ca-file", "", tlsClientCAFileDesc)
+	tlsRequireCertDesc := fmt.Sprintf("Require clients of the HTTP and gRPC listeners to present a certificate signed by the client CA. Overrides the %s environment variable if set. (default %t)\n", config.TLSRequireClientCertEnvVar, config.TLSRequireClientCertDefault)
+	ArmorCmd.PersistentFlags().BoolVar(&tlsRequireCert, "tls-require-client-cert", false, tlsRequireCertDesc)
+	adminTLSRequireCertDesc := fmt.Sprintf("Require clients of the admin listener to present a certificate signed by the client CA. Overrides the %s environment variable if set. (default %t)\n", config.AdminTLSRequireClientCertEnvVar, config.AdminTLSRequireClientCertDefault)
+	ArmorCmd.PersistentFlags().BoolVar(&adminTLSRequireCert, "admin-tls-require-client-cert", false, adminTLSRequireCertDesc)
+
+	// API authorization
+	authPolicyFileDesc := fmt.Sprintf("Policy file of the static bearer tokens and the methods each identity may call. Every caller may call every method if unset. Overrides the %s environment variable if set.\n", config.AuthPolicyFileEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&authPolicyFile, "auth-policy-file", "", authPolicyFileDesc)
+	authVaultTokensDesc := fmt.Sprintf("Validate bearer tokens other than the policy's static tokens with Vault's auth/token/lookup-self. Overrides the %s environment variable if set. (default %t)\n", config.AuthVaultTokensEnvVar, config.AuthVaultTokensDefault)
+	ArmorCmd.PersistentFlags().BoolVar(&authVaultTokens, "auth-vault-tokens", false, authVaultTokensDesc)
+
 	// Vault server listen address
 	vaultAddrDesc := fmt.Sprintf("The address of the Vault server. Overrides the %s environment variable if set. (default \"%s\")\n", config.VaultAddrEnvVar, config.VaultAddrDefault)
 	ArmorCmd.PersistentFlags().StringVar(&vaultAddr, "vault-address", "", vaultAddrDesc)
 
+	// Vault node addresses
+	vaultNodesDesc := fmt.Sprintf("Addresses of every node of an HA Vault cluster, which are unsealed and whose seal status is reported one by one. Overrides the %s environment variable if set. (default is the Vault server address alone)\n", config.VaultNodesEnvVar)
+	ArmorCmd.PersistentFlags().StringSliceVar(&vaultNodes, "vault-nodes", nil, vaultNodesDesc)
+
+	// Vault cluster identifier
+	vaultClusterIDDesc := fmt.Sprintf("Identifier under which the Vault server's root and unseal token holders are kept. Overrides the %s environment variable if set. (default is the Vault server address)\n", config.VaultClusterIDEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&vaultClusterID, "vault-cluster-id", "", vaultClusterIDDesc)
+
+	// Default Vault cluster
+	defaultClusterDesc := fmt.Sprintf("Vault cluster, of the clusters in the config file, of requests which don't name one. The %q cluster is the Vault server set by the vault-* flags. Overrides the %s environment variable if set. (default \"%s\")\n", config.DefaultClusterName, config.DefaultClusterEnvVar, config.DefaultClusterName)
+	ArmorCmd.PersistentFlags().StringVar(&defaultCluster, "default-cluster", "", defaultClusterDesc)
+
 	// Vault server ca cert
 	vaultCACertDesc := fmt.Sprintf("Path to PEM encoded CA cert file. Overrides the %s environment variable if set.\n%s", config.VaultCACertEnvVar, "")
 	ArmorCmd.PersistentFlags().StringVar(&vaultCACert, "vault-ca-cert", "", vaultCACertDesc)
@@ -160,6 +255,10 @@ func initRootPersistentFlags() {
 	vaultTLSSkipVerifyDesc := fmt.Sprintf("Do not verify TLS certificate. This is highly not recommended. Verification will also be skipped if the %s environment variable is set. (default %v)\n", config.VaultSkipVerifyEnvVar, config.VaultSkipVerifyDefault)
 	ArmorCmd.PersistentFlags().BoolVar(&vaultTLSSkipVerify, "vault-skip-verify", config.VaultSkipVerifyDefault, vaultTLSSkipVerifyDesc)
 
+	// Vault server plugin directory
+	vaultPluginDirDesc := fmt.Sprintf("Path to a local copy of the Vault server's plugin directory. Plugin binaries are verified against their sha256 before being registered in the plugin catalog. Overrides the %s environment variable if set.\n", config.VaultPluginDirEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&vaultPluginDir, "vault-plugin-dir", "", vaultPluginDirDesc)
+
 	// Armor policy directory
 	policyConfigDesc := fmt.Sprintf("Local download destination for configuring Vault with remote policy/configuration repositories. Overrides the %s environment variable if set. (default \"%s\")\n", config.PolicyConfigPathEnvVar, config.PolicyConfigPathDefault)
 	ArmorCmd.PersistentFlags().StringVar(&policyConfigPath, "policy-config-dir", "", policyConfigDesc)
@@ -168,6 +267,112 @@ func initRootPersistentFlags() {
 	cfgFileDesc := fmt.Sprintf("Path to a yaml configuration file. Use this configuration file when you don't want to use CLI arguments or set environment variables. Overrides the %s environment variable if set. NOTE: Environment variables take precedence over the configuration file.  And CLI arguments take precedence over environment variables. \n", config.ArmorConfigFileEnvVar)
 	ArmorCmd.PersistentFlags().StringVar(&cfgFile, "config", "", cfgFileDesc)
 
+	// Token holder store
+	tokenHolderStoreDesc := fmt.Sprintf("Where root and unseal token holders are kept: dynamodb, file or memory. Overrides the %s environment variable if set. (default \"%s\")\n", config.TokenHolderStoreEnvVar, config.TokenHolderStoreDefault)
+	ArmorCmd.PersistentFlags().StringVar(&tokenHolderStore, "token-holder-store", "", tokenHolderStoreDesc)
+
+	// Token holder file store location
+	tokenHolderPathDesc := fmt.Sprintf("Path to the token holder file used by the file store. Overrides the %s environment variable if set. (default \"%s\")\n", config.TokenHolderStorePathEnvVar, config.TokenHolderStorePathDefault)
+	ArmorCmd.PersistentFlags().StringVar(&tokenHolderPath, "token-holder-store-path", "", tokenHolderPathDesc)
+
+	// Token encryption
+	tokenEncryptionDesc := fmt.Sprintf("Encrypt root and unseal tokens at rest with master keys from kms or file. Tokens are kept in plaintext if not set. Overrides the %s environment variable if set.\n", config.TokenEncryptionEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&tokenEncryption, "token-encryption", "", tokenEncryptionDesc)
+
+	// Token encryption AWS KMS key
+	tokenKMSKeyIDDesc := fmt.Sprintf("AWS KMS key id, ARN or alias used by kms token encryption. Overrides the %s environment variable if set.\n", config.TokenEncryptionKMSKeyIDEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&tokenKMSKeyID, "token-encryption-kms-key-id", "", tokenKMSKeyIDDesc)
+
+	// Token encryption master key file
+	tokenKeyFileDesc := fmt.Sprintf("Path to the base64 encoded, 32 byte master key used by file token encryption. Overrides the %s environment variable if set.\n", config.TokenEncryptionKeyFileEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&tokenKeyFile, "token-encryption-key-file", "", tokenKeyFileDesc)
+
+	// Token encryption previous master key files
+	tokenPrevKeyFilesDesc := fmt.Sprintf("Paths to master key files in use before the file token encryption master key was rotated. Tokens they protect stay readable until re-encrypted. Overrides the %s environment variable if set.\n", config.TokenEncryptionPreviousKeyFilesEnvVar)
+	ArmorCmd.PersistentFlags().StringSliceVar(&tokenPrevKeyFiles, "token-encryption-previous-key-files", nil, tokenPrevKeyFilesDesc)
+
+	// Token holder PGP keys
+	pgpKeyringDirDesc := fmt.Sprintf("Directory of token holders' armored PGP public keys. Init PGP encrypts each token for its holder when pgp_keys or root_token_pgp_key aren't given. Overrides the %s environment variable if set.\n", config.PGPKeyringDirEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&pgpKeyringDir, "pgp-keyring-dir", "", pgpKeyringDirDesc)
+
+	// Enforce PGP encrypted tokens
+	enforcePGPDesc := fmt.Sprintf("Refuse to init Vault unless every unseal key and the root token are PGP encrypted. Overrides the %s environment variable if set. (default %v)\n", config.EnforcePGPEnvVar, config.EnforcePGPDefault)
+	ArmorCmd.PersistentFlags().BoolVar(&enforcePGP, "enforce-pgp", config.EnforcePGPDefault, enforcePGPDesc)
+
+	// Init lease
+	initLockTTLDesc := fmt.Sprintf("Lease an Armor replica holds on a pending Vault init. Once it lapses, a replica that stopped mid-init is reported as a half-finished init. Overrides the %s environment variable if set. (default %s)\n", config.InitLockTTLEnvVar, config.InitLockTTLDefault)
+	ArmorCmd.PersistentFlags().DurationVar(&initLockTTL, "init-lock-ttl", config.InitLockTTLDefault, initLockTTLDesc)
+
+	// Auto-unseal controller
+	autoUnsealDesc := fmt.Sprintf("Unseal sealed Vault nodes with the unseal tokens in the token holder store. Only tokens which aren't PGP encrypted can be used. Overrides the %s environment variable if set.\n", config.AutoUnsealEnvVar)
+	ArmorCmd.PersistentFlags().BoolVar(&autoUnseal, "auto-unseal", config.AutoUnsealDefault, autoUnsealDesc)
+
+	// Auto-unseal poll interval
+	autoUnsealIntervalDesc := fmt.Sprintf("Interval between seal status polls of the auto-unseal controller. Values which aren't positive fall back to the default. Overrides the %s environment variable if set. (default %s)\n", config.AutoUnsealIntervalEnvVar, config.AutoUnsealIntervalDefault)
+	ArmorCmd.PersistentFlags().DurationVar(&autoUnsealInterval, "auto-unseal-interval", config.AutoUnsealIntervalDefault, autoUnsealIntervalDesc)
+
+	// Auto-unseal attempts
+	autoUnsealAttemptsDesc := fmt.Sprintf("Number of auto-unseal attempts allowed per Vault node within the auto-unseal window. Must be positive. Overrides the %s environment variable if set. (default %d)\n", config.AutoUnsealMaxAttemptsEnvVar, config.AutoUnsealMaxAttemptsDefault)
+	ArmorCmd.PersistentFlags().IntVar(&autoUnsealAttempts, "auto-unseal-max-attempts", config.AutoUnsealMaxAttemptsDefault, autoUnsealAttemptsDesc)
+
+	// Auto-unseal window
+	autoUnsealWindowDesc := fmt.Sprintf("Window auto-unseal attempts are counted in. Values which aren't positive fall back to the default. Overrides the %s environment variable if set. (default %s)\n", config.AutoUnsealWindowEnvVar, config.AutoUnsealWindowDefault)
+	ArmorCmd.PersistentFlags().DurationVar(&autoUnsealWindow, "auto-unseal-window", config.AutoUnsealWindowDefault, autoUnsealWindowDesc)
+
+	// Auto-unseal alerts
+	autoUnsealAlertsDesc := fmt.Sprintf("Email addresses alerted, using the delivery method, of every auto-unseal attempt. Overrides the %s environment variable if set.\n", config.AutoUnsealAlertEmailsEnvVar)
+	ArmorCmd.PersistentFlags().StringSliceVar(&autoUnsealAlerts, "auto-unseal-alert-emails", nil, autoUnsealAlertsDesc)
+
+	// Auto-unseal audit file
+	autoUnsealAuditDesc := fmt.Sprintf("File audit records of the unseal tokens used by the auto-unseal controller are appended to. Records are only logged if not set. Overrides the %s environment variable if set.\n", config.AutoUnsealAuditFileEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&autoUnsealAudit, "auto-unseal-audit-file", "", autoUnsealAuditDesc)
+
+	// Unseal ceremony expiry
+	ceremonyTTLDesc := fmt.Sprintf("Time an unseal ceremony stays open for token holders to submit their unseal keys. Overrides the %s environment variable if set. (default %s)\n", config.UnsealCeremonyTTLEnvVar, config.UnsealCeremonyTTLDefault)
+	ArmorCmd.PersistentFlags().DurationVar(&ceremonyTTL, "unseal-ceremony-ttl", config.UnsealCeremonyTTLDefault, ceremonyTTLDesc)
+
+	// Seal status watch
+	sealWatchIntervalDesc := fmt.Sprintf("Interval between seal status polls of the Vault nodes watched through WatchSealStatus. Values which aren't positive fall back to the default. Overrides the %s environment variable if set. (default %s)\n", config.SealWatchIntervalEnvVar, config.SealWatchIntervalDefault)
+	ArmorCmd.PersistentFlags().DurationVar(&sealWatchInterval, "seal-watch-interval", config.SealWatchIntervalDefault, sealWatchIntervalDesc)
+	sealWatchHeartbeatDesc := fmt.Sprintf("Time after which watchers of an unchanged seal status are sent a heartbeat. Values which aren't positive fall back to the default. Overrides the %s environment variable if set. (default %s)\n", config.SealWatchHeartbeatEnvVar, config.SealWatchHeartbeatDefault)
+	ArmorCmd.PersistentFlags().DurationVar(&sealWatchHeartbeat, "seal-watch-heartbeat", config.SealWatchHeartbeatDefault, sealWatchHeartbeatDesc)
+
+	// Token delivery
+	deliveryMethodDesc := fmt.Sprintf("Deliver each token to its holder by smtp or ses after init. Only PGP encrypted tokens are delivered. Tokens aren't delivered if not set. Overrides the %s environment variable if set.\n", config.DeliveryMethodEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&deliveryMethod, "delivery-method", "", deliveryMethodDesc)
+
+	// Token delivery from address
+	deliveryFromDesc := fmt.Sprintf("From address of token delivery emails. Overrides the %s environment variable if set.\n", config.DeliveryFromEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&deliveryFrom, "delivery-from", "", deliveryFromDesc)
+
+	// Token delivery subject template
+	deliverySubjectDesc := fmt.Sprintf("Path to a Go text/template for the subject of token delivery emails. Overrides the %s environment variable if set.\n", config.DeliverySubjectTemplateEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&deliverySubject, "delivery-subject-template", "", deliverySubjectDesc)
+
+	// Token delivery body template
+	deliveryBodyDesc := fmt.Sprintf("Path to a Go text/template for the body of token delivery emails. Overrides the %s environment variable if set.\n", config.DeliveryBodyTemplateEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&deliveryBody, "delivery-body-template", "", deliveryBodyDesc)
+
+	// Token delivery retries
+	deliveryRetriesDesc := fmt.Sprintf("Number of retries of a failed token delivery. Overrides the %s environment variable if set. (default %d)\n", config.DeliveryRetriesEnvVar, config.DeliveryRetriesDefault)
+	ArmorCmd.PersistentFlags().IntVar(&deliveryRetries, "delivery-retries", config.DeliveryRetriesDefault, deliveryRetriesDesc)
+
+	// Token delivery retry interval
+	deliveryIntervalDesc := fmt.Sprintf("Wait between token delivery retries. Overrides the %s environment variable if set. (default %s)\n", config.DeliveryRetryIntervalEnvVar, config.DeliveryRetryIntervalDefault)
+	ArmorCmd.PersistentFlags().DurationVar(&deliveryInterval, "delivery-retry-interval", config.DeliveryRetryIntervalDefault, deliveryIntervalDesc)
+
+	// SMTP server address
+	smtpAddrDesc := fmt.Sprintf("SMTP server address (host:port) used by smtp delivery. Overrides the %s environment variable if set. (default \"%s\")\n", config.SMTPAddrEnvVar, config.SMTPAddrDefault)
+	ArmorCmd.PersistentFlags().StringVar(&smtpAddr, "smtp-address", "", smtpAddrDesc)
+
+	// SMTP username
+	smtpUsernameDesc := fmt.Sprintf("SMTP username used by smtp delivery. Overrides the %s environment variable if set.\n", config.SMTPUsernameEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&smtpUsername, "smtp-username", "", smtpUsernameDesc)
+
+	// SMTP password
+	smtpPasswordDesc := fmt.Sprintf("SMTP password used by smtp delivery. Overrides the %s environment variable if set.\n", config.SMTPPasswordEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&smtpPassword, "smtp-password", "", smtpPasswordDesc)
+
 	// AWS access key id
 	awsAccessKeyIDDesc := fmt.Sprintf("AWS access key id.  These AWS resources provide backend infrastructure to support Armor. Overrides the %s environment variable if set. (default \"%s\")\n", config.AWSAccessKeyIDEnvVar, "")
 	ArmorCmd.PersistentFlags().StringVar(&awsAccessKeyID, "aws-access-key-id", "", awsAccessKeyIDDesc)
@@ -176,6 +381,62 @@ func initRootPersistentFlags() {
 	awsSecretAccessKeyDesc := fmt.Sprintf("AWS secret access key.  These AWS resources provide backend infrastructure to support Armor. Overrides the %s environment variable if set. (default \"%s\")\n", config.AWSSecretAccessKeyEnvVar, "")
 	ArmorCmd.PersistentFlags().StringVar(&awsSecretAccessKey, "aws-secret-access-key", "", awsSecretAccessKeyDesc)
 
+	// AWS shared credentials profile
+	awsProfileDesc := fmt.Sprintf("Profile of the AWS shared credentials file, when neither Armor's nor the environment's AWS keys are set. Overrides the %s environment variable if set.\n", config.AWSProfileEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&awsProfile, "aws-profile", "", awsProfileDesc)
+
+	// AWS assumed role
+	awsRoleARNDesc := fmt.Sprintf("ARN of an AWS role assumed with the credentials found, e.g. the EC2 instance role. Overrides the %s environment variable if set.\n", config.AWSAssumeRoleARNEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&awsRoleARN, "aws-assume-role-arn", "", awsRoleARNDesc)
+
+	// AWS assumed role session name
+	awsRoleSessionDesc := fmt.Sprintf("Session name of the assumed AWS role. Overrides the %s environment variable if set. (default \"%s\")\n", config.AWSAssumeRoleSessionNameEnvVar, config.AWSAssumeRoleSessionNameDefault)
+	ArmorCmd.PersistentFlags().StringVar(&awsRoleSession, "aws-assume-role-session-name", "", awsRoleSessionDesc)
+
+	// AWS assumed role external id
+	awsRoleExternalIDDesc := fmt.Sprintf("External id required to assume the AWS role. Overrides the %s environment variable if set.\n", config.AWSAssumeRoleExternalIDEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&awsRoleExternalID, "aws-assume-role-external-id", "", awsRoleExternalIDDesc)
+
+	// DynamoDB endpoint
+	dynamoEndpointDesc := fmt.Sprintf("DynamoDB endpoint, e.g. http://localhost:8000 for DynamoDB Local. Overrides the %s environment variable if set.\n", config.DynamoDBEndpointEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&dynamoEndpoint, "dynamodb-endpoint", "", dynamoEndpointDesc)
+
+	// DynamoDB region
+	dynamoRegionDesc := fmt.Sprintf("AWS region of the DynamoDB tables, when not the default AWS region. Overrides the %s environment variable if set.\n", config.DynamoDBRegionEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&dynamoRegion, "dynamodb-region", "", dynamoRegionDesc)
+
+	// DynamoDB token holder table
+	dynamoHolderTableDesc := fmt.Sprintf("Name of the DynamoDB token holder table. Overrides the %s environment variable if set. (default \"%s\")\n", config.DynamoDBTokenHolderTableEnvVar, config.DynamoDBTokenHolderTableDefault)
+	ArmorCmd.PersistentFlags().StringVar(&dynamoHolderTable, "dynamodb-token-holder-table", "", dynamoHolderTableDesc)
+
+	// DynamoDB init record table
+	dynamoInitTableDesc := fmt.Sprintf("Name of the DynamoDB init record table. Overrides the %s environment variable if set. (default \"%s\")\n", config.DynamoDBInitRecordTableEnvVar, config.DynamoDBInitRecordTableDefault)
+	ArmorCmd.PersistentFlags().StringVar(&dynamoInitTable, "dynamodb-init-record-table", "", dynamoInitTableDesc)
+
+	// DynamoDB unseal ceremony table
+	dynamoCeremonyTableDesc := fmt.Sprintf("Name of the DynamoDB unseal ceremony table. Overrides the %s environment variable if set. (default \"%s\")\n", config.DynamoDBCeremonyTableEnvVar, config.DynamoDBCeremonyTableDefault)
+	ArmorCmd.PersistentFlags().StringVar(&dynamoCeremonyTable, "dynamodb-ceremony-table", "", dynamoCeremonyTableDesc)
+
+	// DynamoDB billing mode
+	dynamoBillingModeDesc := fmt.Sprintf("Billing mode of the DynamoDB tables Armor creates: provisioned or on_demand. Overrides the %s environment variable if set. (default \"%s\")\n", config.DynamoDBBillingModeEnvVar, config.DynamoDBBillingModeDefault)
+	ArmorCmd.PersistentFlags().StringVar(&dynamoBillingMode, "dynamodb-billing-mode", "", dynamoBillingModeDesc)
+
+	// DynamoDB read capacity
+	dynamoReadCapDesc := fmt.Sprintf("Provisioned read capacity units of the DynamoDB tables Armor creates. Overrides the %s environment variable if set.\n", config.DynamoDBReadCapacityEnvVar)
+	ArmorCmd.PersistentFlags().Int64Var(&dynamoReadCap, "dynamodb-read-capacity", config.DynamoDBReadCapacityDefault, dynamoReadCapDesc)
+
+	// DynamoDB write capacity
+	dynamoWriteCapDesc := fmt.Sprintf("Provisioned write capacity units of the DynamoDB tables Armor creates. Overrides the %s environment variable if set.\n", config.DynamoDBWriteCapacityEnvVar)
+	ArmorCmd.PersistentFlags().Int64Var(&dynamoWriteCap, "dynamodb-write-capacity", config.DynamoDBWriteCapacityDefault, dynamoWriteCapDesc)
+
+	// DynamoDB point-in-time-recovery
+	dynamoPITRDesc := fmt.Sprintf("Enable point-in-time-recovery on the DynamoDB tables Armor creates. Overrides the %s environment variable if set.\n", config.DynamoDBPITREnvVar)
+	ArmorCmd.PersistentFlags().BoolVar(&dynamoPITR, "dynamodb-pitr", config.DynamoDBPITRDefault, dynamoPITRDesc)
+
+	// DynamoDB KMS key
+	dynamoKMSKeyIDDesc := fmt.Sprintf("AWS KMS key id, ARN or alias encrypting the DynamoDB tables Armor creates, instead of a DynamoDB owned key. Overrides the %s environment variable if set.\n", config.DynamoDBKMSKeyIDEnvVar)
+	ArmorCmd.PersistentFlags().StringVar(&dynamoKMSKeyID, "dynamodb-kms-key-id", "", dynamoKMSKeyIDDesc)
+
 	// Set bash-completion
 	validConfigFilenames := []string{"yaml", "yml"}
 	ArmorCmd.PersistentFlags().SetAnnotation("config", cobra.BashCompFilenameExt, validConfigFilenames)
@@ -219,7 +480,7 @@ func startServices() {
 	defer logger.Log("msg", "goodbye")
 
 	// Metrics domain.
-	fieldKeys := []string{"method", "error"}
+	fieldKeys := []string{"method", "cluster", "error"}
 	var requestCount metrics.Counter
 	{
 		// Business level metrics.
@@ -273,10 +534,29 @@ func startServices() {
 		}
 	}
 
+	// Authorization domain.
+	var authorizer *auth.Authorizer
+	if path := cfg.GetString("auth_policy_file"); path != "" {
+		logger := log.NewContext(logger).With("authorizer", path)
+
+		policy, err := auth.LoadPolicy(path)
+		if err != nil {
+			logger.Log("exit", err)
+			os.Exit(1)
+		}
+
+		var lookup auth.TokenLookup
+		if cfg.GetBool("auth_vault_tokens") {
+			lookup = auth.VaultTokenLookup
+		}
+		authorizer = auth.New(policy, lookup)
+		logger.Log("identities", len(policy.Allow), "tokens", len(policy.Tokens), "vault_tokens", lookup != nil)
+	}
+
 	// Business domain.
 	svc := service.New(logger, requestCount, requestLatency)
 	// Endpoint domain.
-	eps := endpoints.New(svc, logger, duration, tracer)
+	eps := endpoints.New(svc, logger, duration, tracer, authorizer)
 
 	// Mechanical domain.
 	errChan := make(chan error)
@@ -289,6 +569,40 @@ func startServices() {
 	//errChan <- fmt.Errorf("%s", <-stopChan)
 	//	}()
 
+	// Auto-unseal controller.
+	if cfg.GetBool("auto_unseal") {
+		logger := log.NewContext(logger).With("controller", "auto-unseal")
+
+		attempts := prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "mystique",
+			Subsystem: "vault_proxy",
+			Name:      "auto_unseal_attempts",
+			Help:      "Number of auto-unseal attempts, by node and result.",
+		}, []string{"node", "result"})
+
+		ctl, err := autounseal.New(logger, attempts)
+		if err != nil {
+			logger.Log("exit", err)
+			os.Exit(1)
+		}
+
+		logger.Log("interval", ctl.Interval, "nodes", len(ctl.Nodes))
+		go ctl.Run(ctx)
+	}
+
+	// Listener TLS. The admin listener only verifies client certificates
+	// when required on its own, so health probes need not present one.
+	listenerTLS, err := config.ListenerTLSConfig(cfg.GetBool("tls_require_client_cert"))
+	if err != nil {
+		logger.Log("exit", err)
+		os.Exit(1)
+	}
+	adminTLS, err := config.ListenerTLSConfig(cfg.GetBool("admin_tls_require_client_cert"))
+	if err != nil {
+		logger.Log("exit", err)
+		os.Exit(1)
+	}
+
 	// Admin listener.
 	go func() {
 		logger := log.NewContext(logger).With("transport", "admin")
@@ -305,8 +619,8 @@ func startServices() {
 		m.HandleFunc("/healthz/status", armorhealth.HealthzStatusHandler)
 		//m.HandleFunc("/readiness/status", armorhealth.ReadinessStatusHandler)
 
-		logger.Log("addr", vadminAddr)
-		errChan <- http.ListenAndServe(vadminAddr, m)
+		logger.Log("addr", vadminAddr, "tls", adminTLS != nil)
+		errChan <- listenAndServe(vadminAddr, m, adminTLS)
 	}()
 
 	// Mechanical domain.
@@ -314,8 +628,8 @@ func startServices() {
 	go func() {
 		logger := log.NewContext(logger).With("transport", "HTTP")
 		mux := armorhttp.NewHandler(ctx, eps, tracer, logger)
-		logger.Log("addr", vhttpAddr)
-		errChan <- http.ListenAndServe(vhttpAddr, mux)
+		logger.Log("addr", vhttpAddr, "tls", listenerTLS != nil)
+		errChan <- listenAndServe(vhttpAddr, mux, listenerTLS)
 	}()
 
 	// gRPC transport.
@@ -329,10 +643,14 @@ func startServices() {
 		}
 
 		srv := armorgrpc.NewHandler(ctx, eps, tracer, logger)
-		s := grpc.NewServer()
+		var opts []grpc.ServerOption
+		if listenerTLS != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(listenerTLS)))
+		}
+		s := grpc.NewServer(opts...)
 		pb.RegisterVaultServer(s, srv)
 
-		logger.Log("addr", vgrpcAddr)
+		logger.Log("addr", vgrpcAddr, "tls", listenerTLS != nil)
 		errChan <- s.Serve(ln)
 	}()
 
@@ -344,6 +662,19 @@ func startServices() {
 			logger.Log("exit", err)
 		case s := <-stopChan:
 			logger.Log("msg", fmt.Sprintf("captured %v. exiting...goodbye", s))
+
+			// end the seal status watches, so their streams are closed
+			// rather than cut, waiting a bounded time for slow clients
+			closed := make(chan struct{})
+			go func() {
+				service.SealWatchers().Close()
+				close(closed)
+			}()
+			select {
+			case <-closed:
+			case <-time.After(sealWatchCloseTimeout):
+			}
+
 			// TODO: graceful shutdown is not yet implemented. Wait 'till Golang v1.8
 			// is released (est. Feb. 2017).
 			// Example: https://tylerchr.blog/golang-18-whats-coming/
@@ -353,3 +684,16 @@ func startServices() {
 		}
 	}
 }
+
+// sealWatchCloseTimeout bounds the wait for seal status watches to end on
+// shutdown.
+const sealWatchCloseTimeout = 5 * time.Second
+
+// listenAndServe serves handler on addr, over TLS if tlsConfig is set.
+func listenAndServe(addr string, handler http.Handler, tlsConfig *tls.Config) error {
+	if tlsConfig == nil {
+		return http.ListenAndServe(addr, handler)
+	}
+	srv := &http.Server{Addr: addr, Handler: handler, TLSConfig: tlsConfig}
+	return srv.ListenAndServeTLS("", "")
+}
@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	stdopentracing "github.com/opentracing/opentracing-go"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	yaml "gopkg.in/yaml.v2"
+
+	grpcclient "github.com/cdwlabs/armor/pkg/client/grpc"
+	httpclient "github.com/cdwlabs/armor/pkg/client/http"
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/cdwlabs/armor/pkg/proxy/auth"
+	"github.com/cdwlabs/armor/pkg/proxy/service"
+)
+
+var (
+	// ErrUnknownTransport is returned for a --transport other than http or
+	// grpc.
+	ErrUnknownTransport = errors.New("transport must be http or grpc")
+
+	// ErrUnknownOutput is returned for an --output other than json, yaml or
+	// table.
+	ErrUnknownOutput = errors.New("output must be json, yaml or table")
+)
+
+// Flags that are common to the client commands talking to an Armor server.
+var (
+	clientTransport     string
+	clientAddress       string
+	clientToken         string
+	clientOutput        string
+	clientCluster       string
+	clientTLS           bool
+	clientCACert        string
+	clientCert          string
+	clientKey           string
+	clientTLSServerName string
+	clientTLSSkipVerify bool
+	clientTimeout       time.Duration
+)
+
+// addClientFlags adds the flags of the client commands to cmd.
+func addClientFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&clientTransport, "transport", "http", "Transport used to talk to the Armor server, http or grpc.\n")
+	cmd.Flags().StringVar(&clientAddress, "address", "", "Address of the Armor server. (default \"localhost:8081\" over http, \"localhost:8082\" over grpc)\n")
+	cmd.Flags().StringVar(&clientToken, "token", "", "Bearer token presented to the Armor server. Defaults to the ARMOR_TOKEN environment variable.\n")
+	cmd.Flags().StringVarP(&clientOutput, "output", "o", "table", "Output format, json, yaml or table.\n")
+	cmd.Flags().StringVar(&clientCluster, "cluster", "", "Name of the Vault cluster. (default is the server's default cluster)\n")
+	cmd.Flags().BoolVar(&clientTLS, "tls", false, "Talk to the Armor server over TLS. Implied by the other TLS flags.\n")
+	cmd.Flags().StringVar(&clientCACert, "ca-cert", "", "CA verifying the Armor server's certificate. (default is the system's CAs)\n")
+	cmd.Flags().StringVar(&clientCert, "client-cert", "", "Client certificate presented to the Armor server.\n")
+	cmd.Flags().StringVar(&clientKey, "client-key", "", "Private key of the client certificate.\n")
+	cmd.Flags().StringVar(&clientTLSServerName, "tls-server-name", "", "Name the Armor server's certificate is verified against. (default is the host of --address)\n")
+	cmd.Flags().BoolVar(&clientTLSSkipVerify, "tls-skip-verify", false, "Do not verify the Armor server's certificate. Not recommended!\n")
+	cmd.Flags().DurationVar(&clientTimeout, "timeout", 30*time.Second, "Time to wait for the Armor server to connect and answer. Streamed progress, e.g. of configure, is waited for as long as it lasts.\n")
+}
+
+// newClient returns a Vault proxy Service talking to the Armor server of the
+// client flags, and a function closing its connection.
+func newClient() (service.Service, func(), error) {
+	var (
+		tracer = stdopentracing.GlobalTracer()
+		logger = log.NewNopLogger()
+		noop   = func() {}
+	)
+
+	tlsConfig, err := clientTLSConfig()
+	if err != nil {
+		return nil, noop, err
+	}
+
+	switch clientTransport {
+	case "http":
+		address := clientAddress
+		if address == "" {
+			address = "localhost" + config.HTTPAddrDefault
+		}
+		svc, err := httpclient.NewTLS(address, tlsConfig, tracer, logger)
+		return svc, noop, err
+	case "grpc":
+		address := clientAddress
+		if address == "" {
+			address = "localhost" + config.GrpcAddrDefault
+		}
+		conn, err := grpcclient.Dial(address, tlsConfig, grpc.WithBlock(), grpc.WithTimeout(clientTimeout))
+		if err != nil {
+			return nil, noop, err
+		}
+		return grpcclient.New(conn, tracer, logger), func() { conn.Close() }, nil
+	}
+	return nil, noop, ErrUnknownTransport
+}
+
+// clientTLSConfig returns the TLS configuration of the client flags, or nil
+// if none is set.
+func clientTLSConfig() (*tls.Config, error) {
+	if !clientTLS && clientCACert == "" && clientCert == "" && clientKey == "" && clientTLSServerName == "" && !clientTLSSkipVerify {
+		return nil, nil
+	}
+	return config.ClientTLSConfig(clientCACert, clientCert, clientKey, clientTLSServerName, clientTLSSkipVerify)
+}
+
+// clientContext returns the context of a request to the Armor server,
+// carrying the bearer token of the client flags. The request times out
+// after --timeout.
+func clientContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), clientTimeout)
+	return clientCredentials(ctx), cancel
+}
+
+// clientStreamContext returns the context of a request whose response is
+// streamed by the Armor server, carrying the bearer token of the client
+// flags. It has no deadline, since the stream lasts as long as the method
+// it reports on.
+func clientStreamContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return clientCredentials(ctx), cancel
+}
+
+func clientCredentials(ctx context.Context) context.Context {
+	token := clientToken
+	if token == "" {
+		token = os.Getenv("ARMOR_TOKEN")
+	}
+	return auth.NewContext(ctx, auth.Credentials{Token: token})
+}
+
+// promptSecret reads a line from in, with the terminal's echo disabled if in
+// is one, after writing prompt to out.
+func promptSecret(in *os.File, out io.Writer, prompt string) (string, error) {
+	fmt.Fprint(out, prompt)
+	if terminal.IsTerminal(int(in.Fd())) {
+		b, err := terminal.ReadPassword(int(in.Fd()))
+		fmt.Fprintln(out)
+		return strings.TrimSpace(string(b)), err
+	}
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimSpace(line), err
+}
+
+// writeOutput writes v to w in the format of the --output flag. Tables list
+// the fields of v, and a table of their own for each list of objects.
+func writeOutput(w io.Writer, v interface{}) error {
+	switch clientOutput {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "yaml", "table":
+	default:
+		return ErrUnknownOutput
+	}
+
+	// Round trip through JSON, so field names and order match the API's.
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+
+	if clientOutput == "yaml" {
+		b, err := yaml.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	}
+	return writeTable(w, doc)
+}
+
+func writeTable(w io.Writer, doc yaml.MapSlice) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE")
+
+	var lists []yaml.MapItem
+	for _, item := range doc {
+		if rows, ok := item.Value.([]interface{}); ok && len(rows) > 0 {
+			if _, ok := rows[0].(yaml.MapSlice); ok {
+				lists = append(lists, item)
+				continue
+			}
+		}
+		fmt.Fprintf(tw, "%v\t%s\n", item.Key, tableValue(item.Value))
+	}
+
+	for _, list := range lists {
+		fmt.Fprintf(tw, "\n%s\n", strings.ToUpper(fmt.Sprint(list.Key)))
+		rows := list.Value.([]interface{})
+		var columns []string
+		for _, c := range rows[0].(yaml.MapSlice) {
+			columns = append(columns, fmt.Sprint(c.Key))
+		}
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+		for _, row := range rows {
+			values := make(map[string]interface{})
+			if m, ok := row.(yaml.MapSlice); ok {
+				for _, c := range m {
+					values[fmt.Sprint(c.Key)] = c.Value
+				}
+			}
+			cells := make([]string, len(columns))
+			for i, c := range columns {
+				cells[i] = tableValue(values[c])
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+	}
+	return tw.Flush()
+}
+
+func tableValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		values := make([]string, len(v))
+		for i := range v {
+			values[i] = tableValue(v[i])
+		}
+		return strings.Join(values, ",")
+	case yaml.MapSlice:
+		var keys []string
+		for _, item := range v {
+			keys = append(keys, fmt.Sprint(item.Key))
+		}
+		sort.Strings(keys)
+		return strings.Join(keys, ",")
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cdwlabs/armor/pkg/proxy/auth"
+	"github.com/cdwlabs/armor/pkg/proxy/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// testInput returns a file to read input from, as if typed on stdin.
+func testInput(t *testing.T, input string) *os.File {
+	r, w, err := os.Pipe()
+	if !assert.NoError(t, err, "not expecting an error when creating a pipe") {
+		t.FailNow()
+	}
+	w.WriteString(input)
+	w.Close()
+	return r
+}
+
+func TestWriteOutput(t *testing.T) {
+	defer func() { clientOutput = "table" }()
+
+	type node struct {
+		Address string `json:"address"`
+		Sealed  bool   `json:"sealed"`
+	}
+	v := struct {
+		Sealed bool     `json:"sealed"`
+		Keys   []string `json:"keys"`
+		Nodes  []node   `json:"nodes"`
+	}{
+		Sealed: true,
+		Keys:   []string{"a1", "b2"},
+		Nodes:  []node{{"https://a:8200", false}, {"https://b:8200", true}},
+	}
+
+	cases := []struct {
+		output string
+		want   string
+	}{
+		{
+			output: "json",
+			want: `{
+  "sealed": true,
+  "keys": [
+    "a1",
+    "b2"
+  ],
+  "nodes": [
+    {
+      "address": "https://a:8200",
+      "sealed": false
+    },
+    {
+      "address": "https://b:8200",
+      "sealed": true
+    }
+  ]
+}
+`,
+		},
+		{
+			output: "yaml",
+			want: `sealed: true
+keys:
+- a1
+- b2
+nodes:
+- address: https://a:8200
+  sealed: false
+- address: https://b:8200
+  sealed: true
+`,
+		},
+		{
+			output: "table",
+			want: `KEY     VALUE
+sealed  true
+keys    a1,b2
+
+NODES
+ADDRESS         SEALED
+https://a:8200  false
+https://b:8200  true
+`,
+		},
+	}
+
+	for _, c := range cases {
+		clientOutput = c.output
+		var buf bytes.Buffer
+		err := writeOutput(&buf, v)
+		assert.NoError(t, err, "not expecting an error writing %s", c.output)
+		assert.Equal(t, c.want, buf.String(), "expecting a match on %s output", c.output)
+	}
+
+	clientOutput = "xml"
+	err := writeOutput(&bytes.Buffer{}, v)
+	assert.Equal(t, ErrUnknownOutput, err, "expecting an error for an unknown output")
+}
+
+func TestPromptSecret(t *testing.T) {
+	var out bytes.Buffer
+	secret, err := promptSecret(testInput(t, " 4d9e2b1ac3 \n"), &out, "Unseal key: ")
+	assert.NoError(t, err, "not expecting an error reading a line")
+	assert.Equal(t, "4d9e2b1ac3", secret, "expecting the line without surrounding spaces")
+	assert.Equal(t, "Unseal key: ", out.String(), "expecting the prompt")
+
+	secret, err = promptSecret(testInput(t, "4d9e2b1ac3"), &out, "Unseal key: ")
+	assert.NoError(t, err, "not expecting an error reading a line without newline")
+	assert.Equal(t, "4d9e2b1ac3", secret, "expecting the line without newline")
+
+	_, err = promptSecret(testInput(t, ""), &out, "Unseal key: ")
+	assert.Error(t, err, "expecting an error without input")
+}
+
+func TestClientContext(t *testing.T) {
+	os.Setenv("ARMOR_TOKEN", "s.packers")
+	defer os.Unsetenv("ARMOR_TOKEN")
+
+	ctx, cancel := clientContext()
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if assert.True(t, ok, "expecting a deadline for a request") {
+		assert.WithinDuration(t, time.Now().Add(clientTimeout), deadline, time.Second, "expecting the request to time out after --timeout")
+	}
+	assert.Equal(t, "s.packers", auth.FromContext(ctx).Token, "expecting the token of the environment")
+
+	ctx, cancel = clientStreamContext()
+	defer cancel()
+	_, ok = ctx.Deadline()
+	assert.False(t, ok, "not expecting a deadline for a streamed request")
+	assert.Equal(t, "s.packers", auth.FromContext(ctx).Token, "expecting the token of the environment")
+}
+
+func TestInitOptions(t *testing.T) {
+	err := initCmd.Flags().Parse([]string{
+		"--secret-shares", "3",
+		"--secret-threshold", "2",
+		"--recovery-shares", "1",
+		"--root-token-holder-email", "aaron.rodgers@packers.com",
+		"--secret-key-holder-emails", "david.bakhtiari@packers.com,bryan.bulaga@packers.com",
+		"--secret-key-holder-emails", "jordy.nelson@packers.com",
+		"--recovery-key-holder-emails", "clay.matthews@packers.com",
+		"--cluster", "prod",
+	})
+	assert.NoError(t, err, "not expecting an error parsing init flags")
+	defer func() { clientCluster = "" }()
+
+	assert.Equal(t, service.InitOptions{
+		SecretShares:            3,
+		SecretThreshold:         2,
+		RecoveryShares:          1,
+		RootTokenHolderEmail:    "aaron.rodgers@packers.com",
+		SecretKeyHolderEmails:   []string{"david.bakhtiari@packers.com", "bryan.bulaga@packers.com", "jordy.nelson@packers.com"},
+		RecoveryKeyHolderEmails: []string{"clay.matthews@packers.com"},
+		Cluster:                 "prod",
+	}, initOptions(), "expecting the init request of the flags")
+}
+
+func TestUnsealOptions(t *testing.T) {
+	err := unsealCmd.Flags().Parse([]string{"--all-nodes", "--cluster", "prod"})
+	assert.NoError(t, err, "not expecting an error parsing unseal flags")
+	defer func() { clientCluster, unsealAllNodes, unsealReset = "", false, false }()
+
+	var out bytes.Buffer
+	opts, err := unsealOptions(testInput(t, "4d9e2b1ac3\n"), &out)
+	assert.NoError(t, err, "not expecting an error reading the unseal key")
+	assert.Equal(t, service.UnsealOptions{Key: "4d9e2b1ac3", Cluster: "prod", AllNodes: true}, opts, "expecting the unseal request of the flags")
+
+	_, err = unsealOptions(testInput(t, "\n"), &out)
+	assert.Equal(t, ErrUnsealKeyEmpty, err, "expecting an error for an empty unseal key")
+
+	err = unsealCmd.Flags().Parse([]string{"--reset"})
+	assert.NoError(t, err, "not expecting an error parsing unseal flags")
+	out.Reset()
+	opts, err = unsealOptions(testInput(t, ""), &out)
+	assert.NoError(t, err, "not expecting an error resetting the unseal")
+	assert.True(t, opts.Reset, "expecting a reset")
+	assert.Empty(t, out.String(), "not expecting a prompt for a reset")
+}
+
+func TestConfigureOptions(t *testing.T) {
+	os.Setenv("VAULT_TOKEN", "s.vault")
+	defer os.Unsetenv("VAULT_TOKEN")
+
+	err := configureCmd.Flags().Parse([]string{"--url", "https://config.packers.com/vault.tgz"})
+	assert.NoError(t, err, "not expecting an error parsing configure flags")
+	defer func() { configureURL, configureToken = "", "" }()
+
+	assert.Equal(t, service.ConfigOptions{URL: "https://config.packers.com/vault.tgz", Token: "s.vault"}, configureOptions(), "expecting the token of the environment")
+
+	err = configureCmd.Flags().Parse([]string{"--vault-token", "s.flag"})
+	assert.NoError(t, err, "not expecting an error parsing configure flags")
+	assert.Equal(t, "s.flag", configureOptions().Token, "expecting the token of the flag")
+}
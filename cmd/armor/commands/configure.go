@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cdwlabs/armor/pkg/proxy/service"
+	"github.com/spf13/cobra"
+)
+
+// Flags that are specific to the configure command.
+var (
+	configureURL   string
+	configureToken string
+	configureQuiet bool
+)
+
+var configureCmd = &cobra.Command{
+	Use:   "configure",
+	Short: "configure the mounts, auth backends and policies of a Vault cluster",
+	Long: `configure has an Armor server fetch a configuration archive, laid out in
+the data/sys/... tree that export writes, and apply it to a Vault cluster.
+Its progress is reported on stderr as each phase completes.`,
+	RunE: Configure,
+}
+
+func init() {
+	addClientFlags(configureCmd)
+	configureCmd.Flags().StringVar(&configureURL, "url", "", "URL of the configuration archive fetched by the Armor server.\n")
+	configureCmd.Flags().StringVar(&configureToken, "vault-token", "", "Vault token used to configure Vault. Defaults to the VAULT_TOKEN environment variable.\n")
+	configureCmd.Flags().BoolVarP(&configureQuiet, "quiet", "q", false, "Do not report the progress of the configuration on stderr.\n")
+}
+
+// Configure applies a configuration archive to the Vault cluster and writes
+// the resulting configuration.
+func Configure(cmd *cobra.Command, args []string) error {
+	opts := configureOptions()
+
+	svc, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	ctx, cancel := clientStreamContext()
+	defer cancel()
+
+	var events service.ConfigEventFunc
+	if !configureQuiet {
+		events = func(e service.ConfigEvent) { writeConfigEvent(os.Stderr, e) }
+	}
+	state, err := svc.ConfigureStream(ctx, opts, events)
+	if err != nil {
+		return err
+	}
+	return writeOutput(os.Stdout, state)
+}
+
+// configureOptions returns the configure request of the configure flags.
+func configureOptions() service.ConfigOptions {
+	token := configureToken
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+
+	return service.ConfigOptions{
+		URL:     configureURL,
+		Token:   token,
+		Cluster: clientCluster,
+	}
+}
+
+// writeConfigEvent writes a line reporting the progress of a configuration.
+func writeConfigEvent(w io.Writer, e service.ConfigEvent) {
+	step := string(e.Phase)
+	if e.Category != "" {
+		step += " " + e.Category
+	}
+	if e.Action != "" {
+		step += " " + e.Action
+	}
+	if len(e.Paths) > 0 {
+		step += " " + strings.Join(e.Paths, ",")
+	}
+
+	status := "ok"
+	if e.Failed {
+		status = "failed: " + e.Err
+	}
+	fmt.Fprintf(w, "%s: %s\n", step, status)
+}
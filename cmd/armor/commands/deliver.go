@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"fmt"
+
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/backend/delivery"
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/cdwlabs/armor/pkg/proxy/service"
+	"github.com/spf13/cobra"
+)
+
+// Flags that are specific to the deliver command.
+var (
+	deliverEmail       string
+	deliverUndelivered bool
+	deliverCluster     string
+)
+
+var deliverCmd = &cobra.Command{
+	Use:   "deliver",
+	Short: "deliver root, unseal and recovery tokens to their holders",
+	Long: `deliver emails each holder of the Vault server's root, unseal and recovery tokens
+their own, PGP encrypted token, using the configured --delivery-method, and
+records when it was delivered.
+
+Tokens are delivered after init when --delivery-method is set; use deliver to
+retry failed deliveries or to deliver again. Revoked tokens are never delivered.`,
+	RunE: Deliver,
+}
+
+func init() {
+	deliverCmd.Flags().StringVar(&deliverEmail, "email", "", "Only deliver the tokens held by this email address.\n")
+	deliverCmd.Flags().BoolVar(&deliverUndelivered, "undelivered", false, "Only deliver tokens which were never delivered.\n")
+	deliverCmd.Flags().StringVar(&deliverCluster, "cluster", "", "Name of the Vault cluster whose tokens are delivered. (default --default-cluster)\n")
+}
+
+// Deliver sends the tokens of the Vault server to their holders.
+func Deliver(cmd *cobra.Command, args []string) error {
+	var err error
+	cfg, err = config.BindWithCobra(ArmorCmd)
+	if err != nil {
+		return err
+	}
+
+	store, err := dbackend.TokenHolders()
+	if err != nil {
+		return err
+	}
+
+	deliverer, err := delivery.New(store, cfg)
+	if err != nil {
+		return err
+	}
+	if deliverer == nil {
+		return fmt.Errorf("token delivery is not enabled; set --delivery-method")
+	}
+
+	_, clusterID, err := service.NewClusterClient(deliverCluster)
+	if err != nil {
+		return err
+	}
+
+	var all []*dbackend.TokenHolder
+	if deliverEmail != "" {
+		all, err = store.ListByEmail(deliverEmail)
+	} else {
+		all, err = store.List()
+	}
+	if err != nil {
+		return err
+	}
+
+	holders := []*dbackend.TokenHolder{}
+	for _, tokenHolder := range all {
+		if tokenHolder.ClusterID != clusterID || tokenHolder.DateRevoked != "" {
+			continue
+		}
+		if deliverUndelivered && tokenHolder.DateDelivered != "" {
+			continue
+		}
+		holders = append(holders, tokenHolder)
+	}
+
+	err = deliverer.Deliver(holders)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("delivered %d tokens of cluster id %s\n", len(holders), clusterID)
+	return nil
+}
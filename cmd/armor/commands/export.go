@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/cdwlabs/armor/pkg/proxy/service"
+	"github.com/spf13/cobra"
+)
+
+// Flags that are specific to the export command.
+var (
+	exportDest           string
+	exportToken          string
+	exportIncludeBuiltin bool
+	exportCluster        string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export the configuration of a Vault server",
+	Long: `export reads the mounts, mount tunings, auth backends and policies
+of an unsealed Vault server. These are written to a local directory in
+the same data/sys/... layout that Armor's configure operation consumes.
+
+Configuring Vault with the exported directory is a no-op.`,
+	RunE: Export,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportDest, "dest", ".", "Directory the exported data/sys/... tree is written to. It must not already contain a data directory.\n")
+	exportCmd.Flags().StringVar(&exportToken, "token", "", "Vault token used to read the configuration. Defaults to the VAULT_TOKEN environment variable.\n")
+	exportCmd.Flags().StringVar(&exportCluster, "cluster", "", "Name of the Vault cluster to export. (default --default-cluster)\n")
+	exportCmd.Flags().BoolVar(&exportIncludeBuiltin, "include-builtin", false, "Include the mounts, auth backends and policies that come with every Vault server (e.g. secret/, token/ and default). The sys/ mount and root policy are never exported.\n")
+}
+
+// Export writes the configuration of the Vault server to a local directory.
+func Export(cmd *cobra.Command, args []string) error {
+	var err error
+	cfg, err = config.BindWithCobra(ArmorCmd)
+	if err != nil {
+		return err
+	}
+
+	token := exportToken
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+
+	opts := service.ExportOptions{
+		Dest:           exportDest,
+		Token:          token,
+		IncludeBuiltin: exportIncludeBuiltin,
+		Cluster:        exportCluster,
+	}
+
+	state, err := service.Export(opts)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(state)
+}
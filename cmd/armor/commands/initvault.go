@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/cdwlabs/armor/pkg/proxy/service"
+	"github.com/spf13/cobra"
+)
+
+// Flags that are specific to the init command.
+var (
+	initSecretShares      int
+	initSecretThreshold   int
+	initStoredShares      int
+	initPGPKeys           []string
+	initRecoveryShares    int
+	initRecoveryThreshold int
+	initRecoveryPGPKeys   []string
+	initRootTokenPGPKey   string
+	initRootTokenHolder   string
+	initSecretKeyHolders  []string
+	initRecoveryHolders   []string
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "initialize a Vault cluster",
+	Long: `init initializes a Vault cluster through an Armor server and writes the
+unseal keys and root token it returns, which are encrypted when PGP keys
+are given.`,
+	RunE: Init,
+}
+
+func init() {
+	addClientFlags(initCmd)
+	initCmd.Flags().IntVar(&initSecretShares, "secret-shares", 5, "Number of unseal key shares to split the master key into.\n")
+	initCmd.Flags().IntVar(&initSecretThreshold, "secret-threshold", 3, "Number of unseal key shares required to unseal Vault.\n")
+	initCmd.Flags().IntVar(&initStoredShares, "stored-shares", 0, "Number of unseal key shares stored by an HSM backed Vault.\n")
+	initCmd.Flags().StringSliceVar(&initPGPKeys, "pgp-keys", nil, "Base64 encoded PGP public keys encrypting each unseal key share.\n")
+	initCmd.Flags().IntVar(&initRecoveryShares, "recovery-shares", 0, "Number of recovery key shares of an auto-unsealed Vault.\n")
+	initCmd.Flags().IntVar(&initRecoveryThreshold, "recovery-threshold", 0, "Number of recovery key shares required to recover an auto-unsealed Vault.\n")
+	initCmd.Flags().StringSliceVar(&initRecoveryPGPKeys, "recovery-pgp-keys", nil, "Base64 encoded PGP public keys encrypting each recovery key share.\n")
+	initCmd.Flags().StringVar(&initRootTokenPGPKey, "root-token-pgp-key", "", "Base64 encoded PGP public key encrypting the root token.\n")
+	initCmd.Flags().StringVar(&initRootTokenHolder, "root-token-holder-email", "", "Email address of the root token's holder.\n")
+	initCmd.Flags().StringSliceVar(&initSecretKeyHolders, "secret-key-holder-emails", nil, "Email addresses of the unseal key shares' holders.\n")
+	initCmd.Flags().StringSliceVar(&initRecoveryHolders, "recovery-key-holder-emails", nil, "Email addresses of the recovery key shares' holders.\n")
+}
+
+// Init initializes the Vault cluster and writes its keys.
+func Init(cmd *cobra.Command, args []string) error {
+	opts := initOptions()
+
+	svc, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	ctx, cancel := clientContext()
+	defer cancel()
+
+	keys, err := svc.Init(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return writeOutput(os.Stdout, keys)
+}
+
+// initOptions returns the init request of the init flags.
+func initOptions() service.InitOptions {
+	return service.InitOptions{
+		SecretShares:            initSecretShares,
+		SecretThreshold:         initSecretThreshold,
+		StoredShares:            initStoredShares,
+		PGPKeys:                 initPGPKeys,
+		RecoveryShares:          initRecoveryShares,
+		RecoveryThreshold:       initRecoveryThreshold,
+		RecoveryPGPKeys:         initRecoveryPGPKeys,
+		RootTokenPGPKey:         initRootTokenPGPKey,
+		RootTokenHolderEmail:    initRootTokenHolder,
+		SecretKeyHolderEmails:   initSecretKeyHolders,
+		RecoveryKeyHolderEmails: initRecoveryHolders,
+		Cluster:                 clientCluster,
+	}
+}
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"fmt"
+
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/cdwlabs/armor/pkg/proxy/service"
+	"github.com/spf13/cobra"
+)
+
+// Flags that are specific to the migrate-token-holders command.
+var (
+	migrateCluster string
+)
+
+var migrateTokenHoldersCmd = &cobra.Command{
+	Use:   "migrate-token-holders",
+	Short: "migrate token holders to the cluster-keyed table",
+	Long: `migrate-token-holders copies the root and unseal token holders kept by
+earlier releases of Armor, in a DynamoDB table keyed by email address alone,
+into the table keyed by Vault cluster, token type and share index.
+
+Token holders are migrated under the id of the --cluster, which defaults to
+the --default-cluster. A cluster's id is the cluster_id set in the clusters
+file, or else its address; a default cluster missing from the clusters file
+uses the --vault-cluster-id, or else the Vault server address. The legacy
+table is left in place; delete it once the migrated token holders have been
+verified.`,
+	RunE: MigrateTokenHolders,
+}
+
+func init() {
+	migrateTokenHoldersCmd.Flags().StringVar(&migrateCluster, "cluster", "", "Name of the Vault cluster the token holders are migrated to. (default --default-cluster)\n")
+}
+
+// MigrateTokenHolders copies legacy token holders into the configured
+// TokenHolderStore.
+func MigrateTokenHolders(cmd *cobra.Command, args []string) error {
+	var err error
+	cfg, err = config.BindWithCobra(ArmorCmd)
+	if err != nil {
+		return err
+	}
+
+	store, err := dbackend.TokenHolders()
+	if err != nil {
+		return err
+	}
+
+	migrator, ok := store.(dbackend.TokenHolderMigrator)
+	if !ok {
+		return fmt.Errorf("token holder store %q has no legacy token holders to migrate", cfg.GetString("token_holder_store"))
+	}
+
+	_, clusterID, err := service.NewClusterClient(migrateCluster)
+	if err != nil {
+		return err
+	}
+
+	n, err := migrator.MigrateLegacy(clusterID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("migrated %d token holders from %s to %s under cluster id %s\n", n, dbackend.LegacyTokenHolderTableName(), dbackend.TokenHolderTableName(), clusterID)
+	return nil
+}
+
+var reencryptTokenHoldersCmd = &cobra.Command{
+	Use:   "reencrypt-token-holders",
+	Short: "re-encrypt root and unseal tokens under the current master key",
+	Long: `reencrypt-token-holders encrypts every root and unseal token with a new
+data key under the current --token-encryption master key. Tokens kept in
+plaintext are encrypted as well.
+
+To rotate a file master key, set --token-encryption-key-file to the new key
+and --token-encryption-previous-key-files to the old one, re-encrypt, and
+then drop the old key. KMS keys may be rotated the same way, by changing
+--token-encryption-kms-key-id.`,
+	RunE: ReEncryptTokenHolders,
+}
+
+// ReEncryptTokenHolders re-encrypts the tokens of the configured
+// TokenHolderStore.
+func ReEncryptTokenHolders(cmd *cobra.Command, args []string) error {
+	var err error
+	cfg, err = config.BindWithCobra(ArmorCmd)
+	if err != nil {
+		return err
+	}
+
+	store, err := dbackend.TokenHolders()
+	if err != nil {
+		return err
+	}
+
+	encrypted, ok := store.(*dbackend.EncryptedStore)
+	if !ok {
+		return fmt.Errorf("token encryption is not enabled; set --token-encryption")
+	}
+
+	n, err := encrypted.ReEncrypt()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("re-encrypted %d token holders\n", n)
+	return nil
+}
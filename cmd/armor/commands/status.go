@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/cdwlabs/armor/pkg/proxy/service"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "show whether a Vault cluster is initialized and sealed",
+	Long: `status asks an Armor server whether its Vault cluster is initialized
+and, if it is, whether it is sealed.`,
+	RunE: Status,
+}
+
+var sealStatusCmd = &cobra.Command{
+	Use:   "seal-status",
+	Short: "show the seal status of every node of a Vault cluster",
+	Long: `seal-status asks an Armor server for the seal status of its Vault
+cluster: whether it is sealed, the unseal progress towards the key
+threshold, and the status of each node of an HA cluster.`,
+	RunE: SealStatus,
+}
+
+func init() {
+	addClientFlags(statusCmd)
+	addClientFlags(sealStatusCmd)
+}
+
+// statusOutput is the output of the status command. The seal status is only
+// known once Vault is initialized.
+type statusOutput struct {
+	Initialized bool `json:"initialized"`
+	*service.SealState
+}
+
+// Status writes whether the Vault cluster is initialized and sealed.
+func Status(cmd *cobra.Command, args []string) error {
+	svc, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	ctx, cancel := clientContext()
+	defer cancel()
+
+	var out statusOutput
+	out.Initialized, err = svc.InitStatus(ctx, clientCluster)
+	if err != nil {
+		return err
+	}
+	if out.Initialized {
+		state, err := svc.SealStatus(ctx, clientCluster)
+		if err != nil {
+			return err
+		}
+		state.Nodes = nil
+		out.SealState = &state
+	}
+	return writeOutput(os.Stdout, out)
+}
+
+// SealStatus writes the seal status of the Vault cluster and its nodes.
+func SealStatus(cmd *cobra.Command, args []string) error {
+	svc, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	ctx, cancel := clientContext()
+	defer cancel()
+
+	state, err := svc.SealStatus(ctx, clientCluster)
+	if err != nil {
+		return err
+	}
+	return writeOutput(os.Stdout, state)
+}
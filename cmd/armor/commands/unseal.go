@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/cdwlabs/armor/pkg/proxy/service"
+	"github.com/spf13/cobra"
+)
+
+// ErrUnsealKeyEmpty is returned when no unseal key is entered.
+var ErrUnsealKeyEmpty = errors.New("unseal key is empty")
+
+// Flags that are specific to the unseal command.
+var (
+	unsealReset    bool
+	unsealAllNodes bool
+)
+
+var unsealCmd = &cobra.Command{
+	Use:   "unseal",
+	Short: "submit an unseal key to a Vault cluster",
+	Long: `unseal prompts for an unseal key, without echoing it, and submits it to
+a Vault cluster through an Armor server. The key is read from stdin when it
+is not a terminal. Keys are never accepted as arguments, which would leave
+them in the shell's history.`,
+	RunE: Unseal,
+}
+
+func init() {
+	addClientFlags(unsealCmd)
+	unsealCmd.Flags().BoolVar(&unsealReset, "reset", false, "Discard the unseal keys submitted so far instead of submitting one.\n")
+	unsealCmd.Flags().BoolVar(&unsealAllNodes, "all-nodes", false, "Submit the key to every sealed node of an HA cluster.\n")
+}
+
+// Unseal submits an unseal key to the Vault cluster and writes its seal
+// status.
+func Unseal(cmd *cobra.Command, args []string) error {
+	opts, err := unsealOptions(os.Stdin, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	svc, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	ctx, cancel := clientContext()
+	defer cancel()
+
+	state, err := svc.Unseal(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return writeOutput(os.Stdout, state)
+}
+
+// unsealOptions returns the unseal request of the unseal flags, whose key is
+// prompted for on out and read from in, unless the unseal is reset.
+func unsealOptions(in *os.File, out io.Writer) (service.UnsealOptions, error) {
+	opts := service.UnsealOptions{
+		Reset:    unsealReset,
+		Cluster:  clientCluster,
+		AllNodes: unsealAllNodes,
+	}
+	if opts.Reset {
+		return opts, nil
+	}
+
+	key, err := promptSecret(in, out, "Unseal key (will be hidden): ")
+	if err != nil {
+		return opts, err
+	}
+	if key == "" {
+		return opts, ErrUnsealKeyEmpty
+	}
+	opts.Key = key
+	return opts, nil
+}
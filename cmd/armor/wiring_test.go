@@ -110,7 +110,7 @@ func TestHTTPWiring(t *testing.T) {
 	// Assemble the service endpoints
 	ctx := context.Background()
 	svc := service.New(log.NewNopLogger(), discard.NewCounter(), discard.NewHistogram())
-	eps := endpoints.New(svc, log.NewNopLogger(), discard.NewHistogram(), opentracing.GlobalTracer())
+	eps := endpoints.New(svc, log.NewNopLogger(), discard.NewHistogram(), opentracing.GlobalTracer(), nil)
 	mux := vaulthttp.NewHandler(ctx, eps, opentracing.GlobalTracer(), log.NewNopLogger())
 
 	// Start the HTTP version of our Vault proxy service
@@ -121,7 +121,7 @@ func TestHTTPWiring(t *testing.T) {
 	assert.NoError(t, err, "not expecting an error when creating http client")
 
 	// Get init status for uninitialized vault
-	status, err := client.InitStatus(ctx)
+	status, err := client.InitStatus(ctx, "")
 	assert.NoError(t, err, "not expecting an error when calling http initstatus")
 	assert.False(t, status, "expecting InitStatus to return false")
 
@@ -183,12 +183,12 @@ func TestHTTPWiring(t *testing.T) {
 	keythree := initValues.Keys[2]
 
 	// Get init status for initialized vault
-	status, err = client.InitStatus(ctx)
+	status, err = client.InitStatus(ctx, "")
 	assert.NoError(t, err, "not expecting an error when calling http initstatus")
 	assert.True(t, status, "expecting InitStatus to return true")
 
 	// Get seal status for initialized, but unsealed vault
-	state, err := client.SealStatus(ctx)
+	state, err := client.SealStatus(ctx, "")
 	assert.NoError(t, err, "not expecting an error when calling http sealstatus")
 	assert.True(t, state.Sealed, "expecting sealed state of true")
 	assert.True(t, 3 == state.T, "expecting threshold value of 3")
@@ -367,7 +367,7 @@ func TestGRPCWiring(t *testing.T) {
 	// Assemble the service endpoints
 	grpcAddr := ":9082"
 	svc := service.New(log.NewNopLogger(), discard.NewCounter(), discard.NewHistogram())
-	eps := endpoints.New(svc, log.NewNopLogger(), discard.NewHistogram(), opentracing.GlobalTracer())
+	eps := endpoints.New(svc, log.NewNopLogger(), discard.NewHistogram(), opentracing.GlobalTracer(), nil)
 
 	// Start the gRPC version of our Vault proxy service
 	ln, err := net.Listen("tcp", grpcAddr)
@@ -389,7 +389,7 @@ func TestGRPCWiring(t *testing.T) {
 	client := grpcclient.New(conn, opentracing.GlobalTracer(), log.NewNopLogger())
 
 	// Get init status for uninitialized vault
-	status, err := client.InitStatus(ctx)
+	status, err := client.InitStatus(ctx, "")
 	assert.NoError(t, err, "not expecting an error when calling grpc initstatus")
 	assert.False(t, status, "expecting InitStatus to return false")
 
@@ -452,12 +452,12 @@ func TestGRPCWiring(t *testing.T) {
 	keythree := initValues.Keys[2]
 
 	// Get init status for initialized vault
-	status, err = client.InitStatus(ctx)
+	status, err = client.InitStatus(ctx, "")
 	assert.NoError(t, err, "not expecting an error when calling grpc initstatus")
 	assert.True(t, status, "expecting InitStatus to return true")
 
 	// Get seal status for initialized, but unsealed vault
-	state, err := client.SealStatus(ctx)
+	state, err := client.SealStatus(ctx, "")
 	assert.NoError(t, err, "not expecting an error when calling grpc sealstatus")
 	assert.True(t, state.Sealed, "expecting sealed state of true")
 	assert.True(t, 3 == state.T, "expecting threshold value of 3")
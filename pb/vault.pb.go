@@ -15,17 +15,39 @@ It has these top-level messages:
 	InitResponse
 	SealStatusRequest
 	SealStatusResponse
+	SealStatusEvent
 	UnsealRequest
 	UnsealResponse
 	Status
 	SealStatus
+	NodeSealStatus
 	ConfigureRequest
 	ConfigureResponse
+	ConfigureEvent
 	ConfigStatus
 	MountOutput
 	MountConfigOutput
 	AuthMountOutput
 	AuthConfigOutput
+	ListTokenHoldersRequest
+	GetTokenHoldersRequest
+	ReassignTokenHolderRequest
+	RevokeTokenHolderRequest
+	TokenHoldersResponse
+	TokenHolderResponse
+	TokenHolderKey
+	TokenHolder
+	ValidationError
+	StartUnsealCeremonyRequest
+	GetUnsealCeremonyRequest
+	SubmitUnsealShareRequest
+	AbortUnsealCeremonyRequest
+	CeremonyResponse
+	Ceremony
+	CeremonyHolder
+	ListClustersRequest
+	ClustersResponse
+	Cluster
 */
 package pb
 
@@ -49,8 +71,8 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
-// The request message is currently empty, as this request is empty on Vault.
 type InitStatusRequest struct {
+	Cluster string `protobuf:"bytes,1,opt,name=cluster" json:"cluster,omitempty"`
 }
 
 func (m *InitStatusRequest) Reset()                    { *m = InitStatusRequest{} }
@@ -77,16 +99,18 @@ func (m *InitStatusResponse) GetStatus() *Status {
 }
 
 type InitRequest struct {
-	SecretShares          uint32   `protobuf:"varint,1,opt,name=secret_shares,json=secretShares" json:"secret_shares,omitempty"`
-	SecretThreshold       uint32   `protobuf:"varint,2,opt,name=secret_threshold,json=secretThreshold" json:"secret_threshold,omitempty"`
-	StoredShares          uint32   `protobuf:"varint,3,opt,name=stored_shares,json=storedShares" json:"stored_shares,omitempty"`
-	PgpKeys               []string `protobuf:"bytes,4,rep,name=pgp_keys,json=pgpKeys" json:"pgp_keys,omitempty"`
-	RecoveryShares        uint32   `protobuf:"varint,5,opt,name=recovery_shares,json=recoveryShares" json:"recovery_shares,omitempty"`
-	RecoveryThreshold     uint32   `protobuf:"varint,6,opt,name=recovery_threshold,json=recoveryThreshold" json:"recovery_threshold,omitempty"`
-	RecoveryPgpKeys       []string `protobuf:"bytes,7,rep,name=recovery_pgp_keys,json=recoveryPgpKeys" json:"recovery_pgp_keys,omitempty"`
-	RootTokenPgpKey       string   `protobuf:"bytes,8,opt,name=root_token_pgp_key,json=rootTokenPgpKey" json:"root_token_pgp_key,omitempty"`
-	RootTokenHolderEmail  string   `protobuf:"bytes,9,opt,name=root_token_holder_email,json=rootTokenHolderEmail" json:"root_token_holder_email,omitempty"`
-	SecretKeyHolderEmails []string `protobuf:"bytes,10,rep,name=secret_key_holder_emails,json=secretKeyHolderEmails" json:"secret_key_holder_emails,omitempty"`
+	SecretShares            uint32   `protobuf:"varint,1,opt,name=secret_shares,json=secretShares" json:"secret_shares,omitempty"`
+	SecretThreshold         uint32   `protobuf:"varint,2,opt,name=secret_threshold,json=secretThreshold" json:"secret_threshold,omitempty"`
+	StoredShares            uint32   `protobuf:"varint,3,opt,name=stored_shares,json=storedShares" json:"stored_shares,omitempty"`
+	PgpKeys                 []string `protobuf:"bytes,4,rep,name=pgp_keys,json=pgpKeys" json:"pgp_keys,omitempty"`
+	RecoveryShares          uint32   `protobuf:"varint,5,opt,name=recovery_shares,json=recoveryShares" json:"recovery_shares,omitempty"`
+	RecoveryThreshold       uint32   `protobuf:"varint,6,opt,name=recovery_threshold,json=recoveryThreshold" json:"recovery_threshold,omitempty"`
+	RecoveryPgpKeys         []string `protobuf:"bytes,7,rep,name=recovery_pgp_keys,json=recoveryPgpKeys" json:"recovery_pgp_keys,omitempty"`
+	RootTokenPgpKey         string   `protobuf:"bytes,8,opt,name=root_token_pgp_key,json=rootTokenPgpKey" json:"root_token_pgp_key,omitempty"`
+	RootTokenHolderEmail    string   `protobuf:"bytes,9,opt,name=root_token_holder_email,json=rootTokenHolderEmail" json:"root_token_holder_email,omitempty"`
+	SecretKeyHolderEmails   []string `protobuf:"bytes,10,rep,name=secret_key_holder_emails,json=secretKeyHolderEmails" json:"secret_key_holder_emails,omitempty"`
+	RecoveryKeyHolderEmails []string `protobuf:"bytes,11,rep,name=recovery_key_holder_emails,json=recoveryKeyHolderEmails" json:"recovery_key_holder_emails,omitempty"`
+	Cluster                 string   `protobuf:"bytes,12,opt,name=cluster" json:"cluster,omitempty"`
 }
 
 func (m *InitRequest) Reset()                    { *m = InitRequest{} }
@@ -95,12 +119,13 @@ func (*InitRequest) ProtoMessage()               {}
 func (*InitRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
 type InitResponse struct {
-	Keys               []string `protobuf:"bytes,1,rep,name=keys" json:"keys,omitempty"`
-	KeysBase64         []string `protobuf:"bytes,2,rep,name=keys_base64,json=keysBase64" json:"keys_base64,omitempty"`
-	RecoveryKeys       []string `protobuf:"bytes,3,rep,name=recovery_keys,json=recoveryKeys" json:"recovery_keys,omitempty"`
-	RecoveryKeysBase64 []string `protobuf:"bytes,4,rep,name=recovery_keys_base64,json=recoveryKeysBase64" json:"recovery_keys_base64,omitempty"`
-	RootToken          string   `protobuf:"bytes,5,opt,name=root_token,json=rootToken" json:"root_token,omitempty"`
-	Err                string   `protobuf:"bytes,6,opt,name=err" json:"err,omitempty"`
+	Keys               []string           `protobuf:"bytes,1,rep,name=keys" json:"keys,omitempty"`
+	KeysBase64         []string           `protobuf:"bytes,2,rep,name=keys_base64,json=keysBase64" json:"keys_base64,omitempty"`
+	RecoveryKeys       []string           `protobuf:"bytes,3,rep,name=recovery_keys,json=recoveryKeys" json:"recovery_keys,omitempty"`
+	RecoveryKeysBase64 []string           `protobuf:"bytes,4,rep,name=recovery_keys_base64,json=recoveryKeysBase64" json:"recovery_keys_base64,omitempty"`
+	RootToken          string             `protobuf:"bytes,5,opt,name=root_token,json=rootToken" json:"root_token,omitempty"`
+	Err                string             `protobuf:"bytes,6,opt,name=err" json:"err,omitempty"`
+	Errors             []*ValidationError `protobuf:"bytes,7,rep,name=errors" json:"errors,omitempty"`
 }
 
 func (m *InitResponse) Reset()                    { *m = InitResponse{} }
@@ -108,8 +133,15 @@ func (m *InitResponse) String() string            { return proto.CompactTextStri
 func (*InitResponse) ProtoMessage()               {}
 func (*InitResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
-// The request message is currently empty, as this request is empty on Vault.
+func (m *InitResponse) GetErrors() []*ValidationError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
 type SealStatusRequest struct {
+	Cluster string `protobuf:"bytes,1,opt,name=cluster" json:"cluster,omitempty"`
 }
 
 func (m *SealStatusRequest) Reset()                    { *m = SealStatusRequest{} }
@@ -134,15 +166,38 @@ func (m *SealStatusResponse) GetSealStatus() *SealStatus {
 	return nil
 }
 
+// SealStatusEvent reports the seal status of a cluster watched through
+// WatchSealStatus. Heartbeats repeat the last seal status; time is RFC 3339.
+type SealStatusEvent struct {
+	SealStatus *SealStatus `protobuf:"bytes,1,opt,name=seal_status,json=sealStatus" json:"seal_status,omitempty"`
+	Leader     string      `protobuf:"bytes,2,opt,name=leader" json:"leader,omitempty"`
+	Heartbeat  bool        `protobuf:"varint,3,opt,name=heartbeat" json:"heartbeat,omitempty"`
+	Time       string      `protobuf:"bytes,4,opt,name=time" json:"time,omitempty"`
+}
+
+func (m *SealStatusEvent) Reset()                    { *m = SealStatusEvent{} }
+func (m *SealStatusEvent) String() string            { return proto.CompactTextString(m) }
+func (*SealStatusEvent) ProtoMessage()               {}
+func (*SealStatusEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *SealStatusEvent) GetSealStatus() *SealStatus {
+	if m != nil {
+		return m.SealStatus
+	}
+	return nil
+}
+
 type UnsealRequest struct {
-	Key    string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
-	Reset_ bool   `protobuf:"varint,2,opt,name=reset" json:"reset,omitempty"`
+	Key      string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Reset_   bool   `protobuf:"varint,2,opt,name=reset" json:"reset,omitempty"`
+	Cluster  string `protobuf:"bytes,3,opt,name=cluster" json:"cluster,omitempty"`
+	AllNodes bool   `protobuf:"varint,4,opt,name=all_nodes,json=allNodes" json:"all_nodes,omitempty"`
 }
 
 func (m *UnsealRequest) Reset()                    { *m = UnsealRequest{} }
 func (m *UnsealRequest) String() string            { return proto.CompactTextString(m) }
 func (*UnsealRequest) ProtoMessage()               {}
-func (*UnsealRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*UnsealRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 type UnsealResponse struct {
 	SealStatus *SealStatus `protobuf:"bytes,1,opt,name=seal_status,json=sealStatus" json:"seal_status,omitempty"`
@@ -152,7 +207,7 @@ type UnsealResponse struct {
 func (m *UnsealResponse) Reset()                    { *m = UnsealResponse{} }
 func (m *UnsealResponse) String() string            { return proto.CompactTextString(m) }
 func (*UnsealResponse) ProtoMessage()               {}
-func (*UnsealResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*UnsealResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *UnsealResponse) GetSealStatus() *SealStatus {
 	if m != nil {
@@ -169,43 +224,69 @@ type Status struct {
 func (m *Status) Reset()                    { *m = Status{} }
 func (m *Status) String() string            { return proto.CompactTextString(m) }
 func (*Status) ProtoMessage()               {}
-func (*Status) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*Status) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 //       Seal status of Vault
 type SealStatus struct {
-	Sealed      bool   `protobuf:"varint,1,opt,name=sealed" json:"sealed,omitempty"`
-	T           uint32 `protobuf:"varint,2,opt,name=t" json:"t,omitempty"`
-	N           uint32 `protobuf:"varint,3,opt,name=n" json:"n,omitempty"`
-	Progress    uint32 `protobuf:"varint,4,opt,name=progress" json:"progress,omitempty"`
-	Version     string `protobuf:"bytes,5,opt,name=version" json:"version,omitempty"`
-	ClusterName string `protobuf:"bytes,6,opt,name=cluster_name,json=clusterName" json:"cluster_name,omitempty"`
-	ClusterId   string `protobuf:"bytes,7,opt,name=cluster_id,json=clusterId" json:"cluster_id,omitempty"`
+	Sealed      bool              `protobuf:"varint,1,opt,name=sealed" json:"sealed,omitempty"`
+	T           uint32            `protobuf:"varint,2,opt,name=t" json:"t,omitempty"`
+	N           uint32            `protobuf:"varint,3,opt,name=n" json:"n,omitempty"`
+	Progress    uint32            `protobuf:"varint,4,opt,name=progress" json:"progress,omitempty"`
+	Version     string            `protobuf:"bytes,5,opt,name=version" json:"version,omitempty"`
+	ClusterName string            `protobuf:"bytes,6,opt,name=cluster_name,json=clusterName" json:"cluster_name,omitempty"`
+	ClusterId   string            `protobuf:"bytes,7,opt,name=cluster_id,json=clusterId" json:"cluster_id,omitempty"`
+	Nodes       []*NodeSealStatus `protobuf:"bytes,8,rep,name=nodes" json:"nodes,omitempty"`
 }
 
 func (m *SealStatus) Reset()                    { *m = SealStatus{} }
 func (m *SealStatus) String() string            { return proto.CompactTextString(m) }
 func (*SealStatus) ProtoMessage()               {}
-func (*SealStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*SealStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *SealStatus) GetNodes() []*NodeSealStatus {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+// Seal status of one node of a Vault cluster; err is set if it couldn't be reached
+type NodeSealStatus struct {
+	Address  string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Sealed   bool   `protobuf:"varint,2,opt,name=sealed" json:"sealed,omitempty"`
+	T        uint32 `protobuf:"varint,3,opt,name=t" json:"t,omitempty"`
+	N        uint32 `protobuf:"varint,4,opt,name=n" json:"n,omitempty"`
+	Progress uint32 `protobuf:"varint,5,opt,name=progress" json:"progress,omitempty"`
+	Version  string `protobuf:"bytes,6,opt,name=version" json:"version,omitempty"`
+	Err      string `protobuf:"bytes,7,opt,name=err" json:"err,omitempty"`
+}
+
+func (m *NodeSealStatus) Reset()                    { *m = NodeSealStatus{} }
+func (m *NodeSealStatus) String() string            { return proto.CompactTextString(m) }
+func (*NodeSealStatus) ProtoMessage()               {}
+func (*NodeSealStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 type ConfigureRequest struct {
-	Url   string `protobuf:"bytes,1,opt,name=url" json:"url,omitempty"`
-	Token string `protobuf:"bytes,2,opt,name=token" json:"token,omitempty"`
+	Url     string `protobuf:"bytes,1,opt,name=url" json:"url,omitempty"`
+	Token   string `protobuf:"bytes,2,opt,name=token" json:"token,omitempty"`
+	Cluster string `protobuf:"bytes,3,opt,name=cluster" json:"cluster,omitempty"`
 }
 
 func (m *ConfigureRequest) Reset()                    { *m = ConfigureRequest{} }
 func (m *ConfigureRequest) String() string            { return proto.CompactTextString(m) }
 func (*ConfigureRequest) ProtoMessage()               {}
-func (*ConfigureRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*ConfigureRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 type ConfigureResponse struct {
-	ConfigStatus *ConfigStatus `protobuf:"bytes,1,opt,name=config_status,json=configStatus" json:"config_status,omitempty"`
-	Err          string        `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	ConfigStatus *ConfigStatus      `protobuf:"bytes,1,opt,name=config_status,json=configStatus" json:"config_status,omitempty"`
+	Err          string             `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	Errors       []*ValidationError `protobuf:"bytes,3,rep,name=errors" json:"errors,omitempty"`
 }
 
 func (m *ConfigureResponse) Reset()                    { *m = ConfigureResponse{} }
 func (m *ConfigureResponse) String() string            { return proto.CompactTextString(m) }
 func (*ConfigureResponse) ProtoMessage()               {}
-func (*ConfigureResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*ConfigureResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *ConfigureResponse) GetConfigStatus() *ConfigStatus {
 	if m != nil {
@@ -214,17 +295,61 @@ func (m *ConfigureResponse) GetConfigStatus() *ConfigStatus {
 	return nil
 }
 
+func (m *ConfigureResponse) GetErrors() []*ValidationError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+// ConfigureEvent reports the progress of a ConfigureStream call. The last
+// event of every call is the done phase, carrying the config status unless
+// the call failed.
+type ConfigureEvent struct {
+	ConfigId     string             `protobuf:"bytes,1,opt,name=config_id,json=configId" json:"config_id,omitempty"`
+	Phase        string             `protobuf:"bytes,2,opt,name=phase" json:"phase,omitempty"`
+	Category     string             `protobuf:"bytes,3,opt,name=category" json:"category,omitempty"`
+	Action       string             `protobuf:"bytes,4,opt,name=action" json:"action,omitempty"`
+	Paths        []string           `protobuf:"bytes,5,rep,name=paths" json:"paths,omitempty"`
+	Failed       bool               `protobuf:"varint,6,opt,name=failed" json:"failed,omitempty"`
+	Err          string             `protobuf:"bytes,7,opt,name=err" json:"err,omitempty"`
+	Errors       []*ValidationError `protobuf:"bytes,8,rep,name=errors" json:"errors,omitempty"`
+	ConfigStatus *ConfigStatus      `protobuf:"bytes,9,opt,name=config_status,json=configStatus" json:"config_status,omitempty"`
+}
+
+func (m *ConfigureEvent) Reset()                    { *m = ConfigureEvent{} }
+func (m *ConfigureEvent) String() string            { return proto.CompactTextString(m) }
+func (*ConfigureEvent) ProtoMessage()               {}
+func (*ConfigureEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *ConfigureEvent) GetErrors() []*ValidationError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+func (m *ConfigureEvent) GetConfigStatus() *ConfigStatus {
+	if m != nil {
+		return m.ConfigStatus
+	}
+	return nil
+}
+
 type ConfigStatus struct {
 	ConfigId string                      `protobuf:"bytes,1,opt,name=config_id,json=configId" json:"config_id,omitempty"`
 	Mounts   map[string]*MountOutput     `protobuf:"bytes,2,rep,name=mounts" json:"mounts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Auths    map[string]*AuthMountOutput `protobuf:"bytes,3,rep,name=auths" json:"auths,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Policies []string                    `protobuf:"bytes,4,rep,name=policies" json:"policies,omitempty"`
+	// json state of categories without a field of their own, keyed by
+	// category prefix (e.g. /sys/plugins/catalog/)
+	Categories map[string][]byte `protobuf:"bytes,5,rep,name=categories" json:"categories,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *ConfigStatus) Reset()                    { *m = ConfigStatus{} }
 func (m *ConfigStatus) String() string            { return proto.CompactTextString(m) }
 func (*ConfigStatus) ProtoMessage()               {}
-func (*ConfigStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*ConfigStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
 func (m *ConfigStatus) GetMounts() map[string]*MountOutput {
 	if m != nil {
@@ -240,6 +365,13 @@ func (m *ConfigStatus) GetAuths() map[string]*AuthMountOutput {
 	return nil
 }
 
+func (m *ConfigStatus) GetCategories() map[string][]byte {
+	if m != nil {
+		return m.Categories
+	}
+	return nil
+}
+
 type MountOutput struct {
 	Type        string             `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
 	Description string             `protobuf:"bytes,2,opt,name=description" json:"description,omitempty"`
@@ -249,7 +381,7 @@ type MountOutput struct {
 func (m *MountOutput) Reset()                    { *m = MountOutput{} }
 func (m *MountOutput) String() string            { return proto.CompactTextString(m) }
 func (*MountOutput) ProtoMessage()               {}
-func (*MountOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*MountOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 func (m *MountOutput) GetConfig() *MountConfigOutput {
 	if m != nil {
@@ -266,7 +398,7 @@ type MountConfigOutput struct {
 func (m *MountConfigOutput) Reset()                    { *m = MountConfigOutput{} }
 func (m *MountConfigOutput) String() string            { return proto.CompactTextString(m) }
 func (*MountConfigOutput) ProtoMessage()               {}
-func (*MountConfigOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*MountConfigOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 type AuthMountOutput struct {
 	Type        string            `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
@@ -277,7 +409,7 @@ type AuthMountOutput struct {
 func (m *AuthMountOutput) Reset()                    { *m = AuthMountOutput{} }
 func (m *AuthMountOutput) String() string            { return proto.CompactTextString(m) }
 func (*AuthMountOutput) ProtoMessage()               {}
-func (*AuthMountOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*AuthMountOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *AuthMountOutput) GetConfig() *AuthConfigOutput {
 	if m != nil {
@@ -294,7 +426,302 @@ type AuthConfigOutput struct {
 func (m *AuthConfigOutput) Reset()                    { *m = AuthConfigOutput{} }
 func (m *AuthConfigOutput) String() string            { return proto.CompactTextString(m) }
 func (*AuthConfigOutput) ProtoMessage()               {}
-func (*AuthConfigOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*AuthConfigOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+type ListTokenHoldersRequest struct {
+	ClusterId string `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId" json:"cluster_id,omitempty"`
+}
+
+func (m *ListTokenHoldersRequest) Reset()                    { *m = ListTokenHoldersRequest{} }
+func (m *ListTokenHoldersRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListTokenHoldersRequest) ProtoMessage()               {}
+func (*ListTokenHoldersRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+type GetTokenHoldersRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+}
+
+func (m *GetTokenHoldersRequest) Reset()                    { *m = GetTokenHoldersRequest{} }
+func (m *GetTokenHoldersRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetTokenHoldersRequest) ProtoMessage()               {}
+func (*GetTokenHoldersRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+type ReassignTokenHolderRequest struct {
+	Key   *TokenHolderKey `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Email string          `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+}
+
+func (m *ReassignTokenHolderRequest) Reset()                    { *m = ReassignTokenHolderRequest{} }
+func (m *ReassignTokenHolderRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReassignTokenHolderRequest) ProtoMessage()               {}
+func (*ReassignTokenHolderRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *ReassignTokenHolderRequest) GetKey() *TokenHolderKey {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+type RevokeTokenHolderRequest struct {
+	Key *TokenHolderKey `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *RevokeTokenHolderRequest) Reset()                    { *m = RevokeTokenHolderRequest{} }
+func (m *RevokeTokenHolderRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevokeTokenHolderRequest) ProtoMessage()               {}
+func (*RevokeTokenHolderRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *RevokeTokenHolderRequest) GetKey() *TokenHolderKey {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+type TokenHoldersResponse struct {
+	TokenHolders []*TokenHolder     `protobuf:"bytes,1,rep,name=token_holders,json=tokenHolders" json:"token_holders,omitempty"`
+	Err          string             `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	Errors       []*ValidationError `protobuf:"bytes,3,rep,name=errors" json:"errors,omitempty"`
+}
+
+func (m *TokenHoldersResponse) Reset()                    { *m = TokenHoldersResponse{} }
+func (m *TokenHoldersResponse) String() string            { return proto.CompactTextString(m) }
+func (*TokenHoldersResponse) ProtoMessage()               {}
+func (*TokenHoldersResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *TokenHoldersResponse) GetTokenHolders() []*TokenHolder {
+	if m != nil {
+		return m.TokenHolders
+	}
+	return nil
+}
+
+func (m *TokenHoldersResponse) GetErrors() []*ValidationError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+type TokenHolderResponse struct {
+	TokenHolder *TokenHolder       `protobuf:"bytes,1,opt,name=token_holder,json=tokenHolder" json:"token_holder,omitempty"`
+	Err         string             `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	Errors      []*ValidationError `protobuf:"bytes,3,rep,name=errors" json:"errors,omitempty"`
+}
+
+func (m *TokenHolderResponse) Reset()                    { *m = TokenHolderResponse{} }
+func (m *TokenHolderResponse) String() string            { return proto.CompactTextString(m) }
+func (*TokenHolderResponse) ProtoMessage()               {}
+func (*TokenHolderResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *TokenHolderResponse) GetTokenHolder() *TokenHolder {
+	if m != nil {
+		return m.TokenHolder
+	}
+	return nil
+}
+
+func (m *TokenHolderResponse) GetErrors() []*ValidationError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+// Identifies a single root or unseal token of a Vault cluster
+type TokenHolderKey struct {
+	ClusterId  string `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId" json:"cluster_id,omitempty"`
+	TokenType  string `protobuf:"bytes,2,opt,name=token_type,json=tokenType" json:"token_type,omitempty"`
+	ShareIndex uint32 `protobuf:"varint,3,opt,name=share_index,json=shareIndex" json:"share_index,omitempty"`
+}
+
+func (m *TokenHolderKey) Reset()                    { *m = TokenHolderKey{} }
+func (m *TokenHolderKey) String() string            { return proto.CompactTextString(m) }
+func (*TokenHolderKey) ProtoMessage()               {}
+func (*TokenHolderKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+// Who holds a token; the token itself is never included
+type TokenHolder struct {
+	Key             *TokenHolderKey `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Email           string          `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+	DateCreated     string          `protobuf:"bytes,3,opt,name=date_created,json=dateCreated" json:"date_created,omitempty"`
+	DateInitialized string          `protobuf:"bytes,4,opt,name=date_initialized,json=dateInitialized" json:"date_initialized,omitempty"`
+	DateDelivered   string          `protobuf:"bytes,5,opt,name=date_delivered,json=dateDelivered" json:"date_delivered,omitempty"`
+	DateRevoked     string          `protobuf:"bytes,6,opt,name=date_revoked,json=dateRevoked" json:"date_revoked,omitempty"`
+	PgpFingerprint  string          `protobuf:"bytes,7,opt,name=pgp_fingerprint,json=pgpFingerprint" json:"pgp_fingerprint,omitempty"`
+	KeyId           string          `protobuf:"bytes,8,opt,name=key_id,json=keyId" json:"key_id,omitempty"`
+}
+
+func (m *TokenHolder) Reset()                    { *m = TokenHolder{} }
+func (m *TokenHolder) String() string            { return proto.CompactTextString(m) }
+func (*TokenHolder) ProtoMessage()               {}
+func (*TokenHolder) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *TokenHolder) GetKey() *TokenHolderKey {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+// Details of a single problem found while validating a request
+type ValidationError struct {
+	Field   string `protobuf:"bytes,1,opt,name=field" json:"field,omitempty"`
+	File    string `protobuf:"bytes,2,opt,name=file" json:"file,omitempty"`
+	Offset  int64  `protobuf:"varint,3,opt,name=offset" json:"offset,omitempty"`
+	Message string `protobuf:"bytes,4,opt,name=message" json:"message,omitempty"`
+}
+
+func (m *ValidationError) Reset()                    { *m = ValidationError{} }
+func (m *ValidationError) String() string            { return proto.CompactTextString(m) }
+func (*ValidationError) ProtoMessage()               {}
+func (*ValidationError) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+type StartUnsealCeremonyRequest struct {
+	Cluster string `protobuf:"bytes,1,opt,name=cluster" json:"cluster,omitempty"`
+}
+
+func (m *StartUnsealCeremonyRequest) Reset()                    { *m = StartUnsealCeremonyRequest{} }
+func (m *StartUnsealCeremonyRequest) String() string            { return proto.CompactTextString(m) }
+func (*StartUnsealCeremonyRequest) ProtoMessage()               {}
+func (*StartUnsealCeremonyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+type GetUnsealCeremonyRequest struct {
+	Id      string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Cluster string `protobuf:"bytes,2,opt,name=cluster" json:"cluster,omitempty"`
+}
+
+func (m *GetUnsealCeremonyRequest) Reset()                    { *m = GetUnsealCeremonyRequest{} }
+func (m *GetUnsealCeremonyRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetUnsealCeremonyRequest) ProtoMessage()               {}
+func (*GetUnsealCeremonyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+type SubmitUnsealShareRequest struct {
+	CeremonyId string `protobuf:"bytes,1,opt,name=ceremony_id,json=ceremonyId" json:"ceremony_id,omitempty"`
+	Email      string `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+	Key        string `protobuf:"bytes,3,opt,name=key" json:"key,omitempty"`
+	Cluster    string `protobuf:"bytes,4,opt,name=cluster" json:"cluster,omitempty"`
+}
+
+func (m *SubmitUnsealShareRequest) Reset()                    { *m = SubmitUnsealShareRequest{} }
+func (m *SubmitUnsealShareRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubmitUnsealShareRequest) ProtoMessage()               {}
+func (*SubmitUnsealShareRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+type AbortUnsealCeremonyRequest struct {
+	Id      string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Cluster string `protobuf:"bytes,2,opt,name=cluster" json:"cluster,omitempty"`
+}
+
+func (m *AbortUnsealCeremonyRequest) Reset()                    { *m = AbortUnsealCeremonyRequest{} }
+func (m *AbortUnsealCeremonyRequest) String() string            { return proto.CompactTextString(m) }
+func (*AbortUnsealCeremonyRequest) ProtoMessage()               {}
+func (*AbortUnsealCeremonyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+type CeremonyResponse struct {
+	Ceremony *Ceremony          `protobuf:"bytes,1,opt,name=ceremony" json:"ceremony,omitempty"`
+	Err      string             `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	Errors   []*ValidationError `protobuf:"bytes,3,rep,name=errors" json:"errors,omitempty"`
+}
+
+func (m *CeremonyResponse) Reset()                    { *m = CeremonyResponse{} }
+func (m *CeremonyResponse) String() string            { return proto.CompactTextString(m) }
+func (*CeremonyResponse) ProtoMessage()               {}
+func (*CeremonyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *CeremonyResponse) GetCeremony() *Ceremony {
+	if m != nil {
+		return m.Ceremony
+	}
+	return nil
+}
+
+func (m *CeremonyResponse) GetErrors() []*ValidationError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+// Status of an unseal ceremony; sealed and progress are read from Vault
+type Ceremony struct {
+	ClusterId   string            `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId" json:"cluster_id,omitempty"`
+	Id          string            `protobuf:"bytes,2,opt,name=id" json:"id,omitempty"`
+	State       string            `protobuf:"bytes,3,opt,name=state" json:"state,omitempty"`
+	Threshold   uint32            `protobuf:"varint,4,opt,name=threshold" json:"threshold,omitempty"`
+	Progress    uint32            `protobuf:"varint,5,opt,name=progress" json:"progress,omitempty"`
+	Sealed      bool              `protobuf:"varint,6,opt,name=sealed" json:"sealed,omitempty"`
+	DateStarted string            `protobuf:"bytes,7,opt,name=date_started,json=dateStarted" json:"date_started,omitempty"`
+	DateExpires string            `protobuf:"bytes,8,opt,name=date_expires,json=dateExpires" json:"date_expires,omitempty"`
+	DateEnded   string            `protobuf:"bytes,9,opt,name=date_ended,json=dateEnded" json:"date_ended,omitempty"`
+	Holders     []*CeremonyHolder `protobuf:"bytes,10,rep,name=holders" json:"holders,omitempty"`
+}
+
+func (m *Ceremony) Reset()                    { *m = Ceremony{} }
+func (m *Ceremony) String() string            { return proto.CompactTextString(m) }
+func (*Ceremony) ProtoMessage()               {}
+func (*Ceremony) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *Ceremony) GetHolders() []*CeremonyHolder {
+	if m != nil {
+		return m.Holders
+	}
+	return nil
+}
+
+// Whether an unseal token holder took part in a ceremony
+type CeremonyHolder struct {
+	Email         string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	Shares        uint32 `protobuf:"varint,2,opt,name=shares" json:"shares,omitempty"`
+	Submitted     uint32 `protobuf:"varint,3,opt,name=submitted" json:"submitted,omitempty"`
+	Verified      bool   `protobuf:"varint,4,opt,name=verified" json:"verified,omitempty"`
+	DateSubmitted string `protobuf:"bytes,5,opt,name=date_submitted,json=dateSubmitted" json:"date_submitted,omitempty"`
+}
+
+func (m *CeremonyHolder) Reset()                    { *m = CeremonyHolder{} }
+func (m *CeremonyHolder) String() string            { return proto.CompactTextString(m) }
+func (*CeremonyHolder) ProtoMessage()               {}
+func (*CeremonyHolder) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+// The request message is empty, every managed cluster is listed.
+type ListClustersRequest struct {
+}
+
+func (m *ListClustersRequest) Reset()                    { *m = ListClustersRequest{} }
+func (m *ListClustersRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListClustersRequest) ProtoMessage()               {}
+func (*ListClustersRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+type ClustersResponse struct {
+	Clusters []*Cluster `protobuf:"bytes,1,rep,name=clusters" json:"clusters,omitempty"`
+	Err      string     `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+}
+
+func (m *ClustersResponse) Reset()                    { *m = ClustersResponse{} }
+func (m *ClustersResponse) String() string            { return proto.CompactTextString(m) }
+func (*ClustersResponse) ProtoMessage()               {}
+func (*ClustersResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *ClustersResponse) GetClusters() []*Cluster {
+	if m != nil {
+		return m.Clusters
+	}
+	return nil
+}
+
+// A Vault cluster Armor manages
+type Cluster struct {
+	Name      string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Address   string `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	ClusterId string `protobuf:"bytes,3,opt,name=cluster_id,json=clusterId" json:"cluster_id,omitempty"`
+	Default   bool   `protobuf:"varint,4,opt,name=default" json:"default,omitempty"`
+}
+
+func (m *Cluster) Reset()                    { *m = Cluster{} }
+func (m *Cluster) String() string            { return proto.CompactTextString(m) }
+func (*Cluster) ProtoMessage()               {}
+func (*Cluster) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
 
 func init() {
 	proto.RegisterType((*InitStatusRequest)(nil), "pb.InitStatusRequest")
@@ -303,17 +730,39 @@ func init() {
 	proto.RegisterType((*InitResponse)(nil), "pb.InitResponse")
 	proto.RegisterType((*SealStatusRequest)(nil), "pb.SealStatusRequest")
 	proto.RegisterType((*SealStatusResponse)(nil), "pb.SealStatusResponse")
+	proto.RegisterType((*SealStatusEvent)(nil), "pb.SealStatusEvent")
 	proto.RegisterType((*UnsealRequest)(nil), "pb.UnsealRequest")
 	proto.RegisterType((*UnsealResponse)(nil), "pb.UnsealResponse")
 	proto.RegisterType((*Status)(nil), "pb.Status")
 	proto.RegisterType((*SealStatus)(nil), "pb.SealStatus")
+	proto.RegisterType((*NodeSealStatus)(nil), "pb.NodeSealStatus")
 	proto.RegisterType((*ConfigureRequest)(nil), "pb.ConfigureRequest")
 	proto.RegisterType((*ConfigureResponse)(nil), "pb.ConfigureResponse")
+	proto.RegisterType((*ConfigureEvent)(nil), "pb.ConfigureEvent")
 	proto.RegisterType((*ConfigStatus)(nil), "pb.ConfigStatus")
 	proto.RegisterType((*MountOutput)(nil), "pb.MountOutput")
 	proto.RegisterType((*MountConfigOutput)(nil), "pb.MountConfigOutput")
 	proto.RegisterType((*AuthMountOutput)(nil), "pb.AuthMountOutput")
 	proto.RegisterType((*AuthConfigOutput)(nil), "pb.AuthConfigOutput")
+	proto.RegisterType((*ListTokenHoldersRequest)(nil), "pb.ListTokenHoldersRequest")
+	proto.RegisterType((*GetTokenHoldersRequest)(nil), "pb.GetTokenHoldersRequest")
+	proto.RegisterType((*ReassignTokenHolderRequest)(nil), "pb.ReassignTokenHolderRequest")
+	proto.RegisterType((*RevokeTokenHolderRequest)(nil), "pb.RevokeTokenHolderRequest")
+	proto.RegisterType((*TokenHoldersResponse)(nil), "pb.TokenHoldersResponse")
+	proto.RegisterType((*TokenHolderResponse)(nil), "pb.TokenHolderResponse")
+	proto.RegisterType((*TokenHolderKey)(nil), "pb.TokenHolderKey")
+	proto.RegisterType((*TokenHolder)(nil), "pb.TokenHolder")
+	proto.RegisterType((*ValidationError)(nil), "pb.ValidationError")
+	proto.RegisterType((*StartUnsealCeremonyRequest)(nil), "pb.StartUnsealCeremonyRequest")
+	proto.RegisterType((*GetUnsealCeremonyRequest)(nil), "pb.GetUnsealCeremonyRequest")
+	proto.RegisterType((*SubmitUnsealShareRequest)(nil), "pb.SubmitUnsealShareRequest")
+	proto.RegisterType((*AbortUnsealCeremonyRequest)(nil), "pb.AbortUnsealCeremonyRequest")
+	proto.RegisterType((*CeremonyResponse)(nil), "pb.CeremonyResponse")
+	proto.RegisterType((*Ceremony)(nil), "pb.Ceremony")
+	proto.RegisterType((*CeremonyHolder)(nil), "pb.CeremonyHolder")
+	proto.RegisterType((*ListClustersRequest)(nil), "pb.ListClustersRequest")
+	proto.RegisterType((*ClustersResponse)(nil), "pb.ClustersResponse")
+	proto.RegisterType((*Cluster)(nil), "pb.Cluster")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -336,6 +785,10 @@ type VaultClient interface {
 	// SealStatus retrieves the output from a GET to /sys/seal-status
 	// Returns the seal status of the Vault.
 	SealStatus(ctx context.Context, in *SealStatusRequest, opts ...grpc.CallOption) (*SealStatusResponse, error)
+	// WatchSealStatus streams the seal status of a Vault cluster: once
+	// every node was polled, on every change (sealed/unsealed, progress,
+	// leader), and as heartbeats while nothing changes.
+	WatchSealStatus(ctx context.Context, in *SealStatusRequest, opts ...grpc.CallOption) (Vault_WatchSealStatusClient, error)
 	// Unseal retrieves the output from a PUT to /sys/unseal
 	// Enter a single master key share to progress the unsealing of the Vault.
 	// If the threshold number of master key shares is reached, Vault will attempt to
@@ -346,6 +799,35 @@ type VaultClient interface {
 	// convention, these are json files located at some URL (e.g. git or
 	// aws s3).
 	Configure(ctx context.Context, in *ConfigureRequest, opts ...grpc.CallOption) (*ConfigureResponse, error)
+	// ConfigureStream applies a set of configuration files to Vault like
+	// Configure, streaming an event per phase: fetch, categorize, the
+	// plan of each category, each action applied or failed, and done.
+	ConfigureStream(ctx context.Context, in *ConfigureRequest, opts ...grpc.CallOption) (Vault_ConfigureStreamClient, error)
+	// ListTokenHolders lists the holders of the root and unseal tokens of
+	// a Vault cluster, or of every cluster if no cluster id is given.
+	// Tokens are never returned.
+	ListTokenHolders(ctx context.Context, in *ListTokenHoldersRequest, opts ...grpc.CallOption) (*TokenHoldersResponse, error)
+	// GetTokenHolders lists every token held by an email address.
+	GetTokenHolders(ctx context.Context, in *GetTokenHoldersRequest, opts ...grpc.CallOption) (*TokenHoldersResponse, error)
+	// ReassignTokenHolder transfers custody of a token to another email
+	// address.
+	ReassignTokenHolder(ctx context.Context, in *ReassignTokenHolderRequest, opts ...grpc.CallOption) (*TokenHolderResponse, error)
+	// RevokeTokenHolder marks a token as revoked.
+	RevokeTokenHolder(ctx context.Context, in *RevokeTokenHolderRequest, opts ...grpc.CallOption) (*TokenHolderResponse, error)
+	// StartUnsealCeremony opens a session in which the unseal token
+	// holders of a sealed Vault cluster submit their keys in turn.
+	StartUnsealCeremony(ctx context.Context, in *StartUnsealCeremonyRequest, opts ...grpc.CallOption) (*CeremonyResponse, error)
+	// GetUnsealCeremony returns the status of a ceremony by token holder.
+	GetUnsealCeremony(ctx context.Context, in *GetUnsealCeremonyRequest, opts ...grpc.CallOption) (*CeremonyResponse, error)
+	// SubmitUnsealShare verifies a token holder's unseal key against the
+	// stored share hash, and submits it to Vault.
+	SubmitUnsealShare(ctx context.Context, in *SubmitUnsealShareRequest, opts ...grpc.CallOption) (*CeremonyResponse, error)
+	// AbortUnsealCeremony ends a ceremony, and resets Vault's unseal
+	// progress.
+	AbortUnsealCeremony(ctx context.Context, in *AbortUnsealCeremonyRequest, opts ...grpc.CallOption) (*CeremonyResponse, error)
+	// ListClusters lists the Vault clusters Armor manages. Every other
+	// request targets the cluster it names, or the default cluster.
+	ListClusters(ctx context.Context, in *ListClustersRequest, opts ...grpc.CallOption) (*ClustersResponse, error)
 }
 
 type vaultClient struct {
@@ -383,6 +865,38 @@ func (c *vaultClient) SealStatus(ctx context.Context, in *SealStatusRequest, opt
 	return out, nil
 }
 
+func (c *vaultClient) WatchSealStatus(ctx context.Context, in *SealStatusRequest, opts ...grpc.CallOption) (Vault_WatchSealStatusClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Vault_serviceDesc.Streams[0], c.cc, "/pb.Vault/WatchSealStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &vaultWatchSealStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Vault_WatchSealStatusClient interface {
+	Recv() (*SealStatusEvent, error)
+	grpc.ClientStream
+}
+
+type vaultWatchSealStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *vaultWatchSealStatusClient) Recv() (*SealStatusEvent, error) {
+	m := new(SealStatusEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *vaultClient) Unseal(ctx context.Context, in *UnsealRequest, opts ...grpc.CallOption) (*UnsealResponse, error) {
 	out := new(UnsealResponse)
 	err := grpc.Invoke(ctx, "/pb.Vault/Unseal", in, out, c.cc, opts...)
@@ -401,6 +915,119 @@ func (c *vaultClient) Configure(ctx context.Context, in *ConfigureRequest, opts
 	return out, nil
 }
 
+func (c *vaultClient) ConfigureStream(ctx context.Context, in *ConfigureRequest, opts ...grpc.CallOption) (Vault_ConfigureStreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Vault_serviceDesc.Streams[1], c.cc, "/pb.Vault/ConfigureStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &vaultConfigureStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Vault_ConfigureStreamClient interface {
+	Recv() (*ConfigureEvent, error)
+	grpc.ClientStream
+}
+
+type vaultConfigureStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *vaultConfigureStreamClient) Recv() (*ConfigureEvent, error) {
+	m := new(ConfigureEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *vaultClient) ListTokenHolders(ctx context.Context, in *ListTokenHoldersRequest, opts ...grpc.CallOption) (*TokenHoldersResponse, error) {
+	out := new(TokenHoldersResponse)
+	err := grpc.Invoke(ctx, "/pb.Vault/ListTokenHolders", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) GetTokenHolders(ctx context.Context, in *GetTokenHoldersRequest, opts ...grpc.CallOption) (*TokenHoldersResponse, error) {
+	out := new(TokenHoldersResponse)
+	err := grpc.Invoke(ctx, "/pb.Vault/GetTokenHolders", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) ReassignTokenHolder(ctx context.Context, in *ReassignTokenHolderRequest, opts ...grpc.CallOption) (*TokenHolderResponse, error) {
+	out := new(TokenHolderResponse)
+	err := grpc.Invoke(ctx, "/pb.Vault/ReassignTokenHolder", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) RevokeTokenHolder(ctx context.Context, in *RevokeTokenHolderRequest, opts ...grpc.CallOption) (*TokenHolderResponse, error) {
+	out := new(TokenHolderResponse)
+	err := grpc.Invoke(ctx, "/pb.Vault/RevokeTokenHolder", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) StartUnsealCeremony(ctx context.Context, in *StartUnsealCeremonyRequest, opts ...grpc.CallOption) (*CeremonyResponse, error) {
+	out := new(CeremonyResponse)
+	err := grpc.Invoke(ctx, "/pb.Vault/StartUnsealCeremony", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) GetUnsealCeremony(ctx context.Context, in *GetUnsealCeremonyRequest, opts ...grpc.CallOption) (*CeremonyResponse, error) {
+	out := new(CeremonyResponse)
+	err := grpc.Invoke(ctx, "/pb.Vault/GetUnsealCeremony", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) SubmitUnsealShare(ctx context.Context, in *SubmitUnsealShareRequest, opts ...grpc.CallOption) (*CeremonyResponse, error) {
+	out := new(CeremonyResponse)
+	err := grpc.Invoke(ctx, "/pb.Vault/SubmitUnsealShare", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) AbortUnsealCeremony(ctx context.Context, in *AbortUnsealCeremonyRequest, opts ...grpc.CallOption) (*CeremonyResponse, error) {
+	out := new(CeremonyResponse)
+	err := grpc.Invoke(ctx, "/pb.Vault/AbortUnsealCeremony", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) ListClusters(ctx context.Context, in *ListClustersRequest, opts ...grpc.CallOption) (*ClustersResponse, error) {
+	out := new(ClustersResponse)
+	err := grpc.Invoke(ctx, "/pb.Vault/ListClusters", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Vault service
 
 type VaultServer interface {
@@ -413,6 +1040,10 @@ type VaultServer interface {
 	// SealStatus retrieves the output from a GET to /sys/seal-status
 	// Returns the seal status of the Vault.
 	SealStatus(context.Context, *SealStatusRequest) (*SealStatusResponse, error)
+	// WatchSealStatus streams the seal status of a Vault cluster: once
+	// every node was polled, on every change (sealed/unsealed, progress,
+	// leader), and as heartbeats while nothing changes.
+	WatchSealStatus(*SealStatusRequest, Vault_WatchSealStatusServer) error
 	// Unseal retrieves the output from a PUT to /sys/unseal
 	// Enter a single master key share to progress the unsealing of the Vault.
 	// If the threshold number of master key shares is reached, Vault will attempt to
@@ -423,6 +1054,35 @@ type VaultServer interface {
 	// convention, these are json files located at some URL (e.g. git or
 	// aws s3).
 	Configure(context.Context, *ConfigureRequest) (*ConfigureResponse, error)
+	// ConfigureStream applies a set of configuration files to Vault like
+	// Configure, streaming an event per phase: fetch, categorize, the
+	// plan of each category, each action applied or failed, and done.
+	ConfigureStream(*ConfigureRequest, Vault_ConfigureStreamServer) error
+	// ListTokenHolders lists the holders of the root and unseal tokens of
+	// a Vault cluster, or of every cluster if no cluster id is given.
+	// Tokens are never returned.
+	ListTokenHolders(context.Context, *ListTokenHoldersRequest) (*TokenHoldersResponse, error)
+	// GetTokenHolders lists every token held by an email address.
+	GetTokenHolders(context.Context, *GetTokenHoldersRequest) (*TokenHoldersResponse, error)
+	// ReassignTokenHolder transfers custody of a token to another email
+	// address.
+	ReassignTokenHolder(context.Context, *ReassignTokenHolderRequest) (*TokenHolderResponse, error)
+	// RevokeTokenHolder marks a token as revoked.
+	RevokeTokenHolder(context.Context, *RevokeTokenHolderRequest) (*TokenHolderResponse, error)
+	// StartUnsealCeremony opens a session in which the unseal token
+	// holders of a sealed Vault cluster submit their keys in turn.
+	StartUnsealCeremony(context.Context, *StartUnsealCeremonyRequest) (*CeremonyResponse, error)
+	// GetUnsealCeremony returns the status of a ceremony by token holder.
+	GetUnsealCeremony(context.Context, *GetUnsealCeremonyRequest) (*CeremonyResponse, error)
+	// SubmitUnsealShare verifies a token holder's unseal key against the
+	// stored share hash, and submits it to Vault.
+	SubmitUnsealShare(context.Context, *SubmitUnsealShareRequest) (*CeremonyResponse, error)
+	// AbortUnsealCeremony ends a ceremony, and resets Vault's unseal
+	// progress.
+	AbortUnsealCeremony(context.Context, *AbortUnsealCeremonyRequest) (*CeremonyResponse, error)
+	// ListClusters lists the Vault clusters Armor manages. Every other
+	// request targets the cluster it names, or the default cluster.
+	ListClusters(context.Context, *ListClustersRequest) (*ClustersResponse, error)
 }
 
 func RegisterVaultServer(s *grpc.Server, srv VaultServer) {
@@ -483,6 +1143,27 @@ func _Vault_SealStatus_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Vault_WatchSealStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SealStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VaultServer).WatchSealStatus(m, &vaultWatchSealStatusServer{stream})
+}
+
+type Vault_WatchSealStatusServer interface {
+	Send(*SealStatusEvent) error
+	grpc.ServerStream
+}
+
+type vaultWatchSealStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *vaultWatchSealStatusServer) Send(m *SealStatusEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Vault_Unseal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UnsealRequest)
 	if err := dec(in); err != nil {
@@ -519,6 +1200,189 @@ func _Vault_Configure_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Vault_ConfigureStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ConfigureRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VaultServer).ConfigureStream(m, &vaultConfigureStreamServer{stream})
+}
+
+type Vault_ConfigureStreamServer interface {
+	Send(*ConfigureEvent) error
+	grpc.ServerStream
+}
+
+type vaultConfigureStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *vaultConfigureStreamServer) Send(m *ConfigureEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Vault_ListTokenHolders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTokenHoldersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).ListTokenHolders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Vault/ListTokenHolders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).ListTokenHolders(ctx, req.(*ListTokenHoldersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_GetTokenHolders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenHoldersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).GetTokenHolders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Vault/GetTokenHolders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).GetTokenHolders(ctx, req.(*GetTokenHoldersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_ReassignTokenHolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReassignTokenHolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).ReassignTokenHolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Vault/ReassignTokenHolder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).ReassignTokenHolder(ctx, req.(*ReassignTokenHolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_RevokeTokenHolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeTokenHolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).RevokeTokenHolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Vault/RevokeTokenHolder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).RevokeTokenHolder(ctx, req.(*RevokeTokenHolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_StartUnsealCeremony_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartUnsealCeremonyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).StartUnsealCeremony(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Vault/StartUnsealCeremony",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).StartUnsealCeremony(ctx, req.(*StartUnsealCeremonyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_GetUnsealCeremony_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUnsealCeremonyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).GetUnsealCeremony(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Vault/GetUnsealCeremony",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).GetUnsealCeremony(ctx, req.(*GetUnsealCeremonyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_SubmitUnsealShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitUnsealShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).SubmitUnsealShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Vault/SubmitUnsealShare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).SubmitUnsealShare(ctx, req.(*SubmitUnsealShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_AbortUnsealCeremony_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbortUnsealCeremonyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).AbortUnsealCeremony(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Vault/AbortUnsealCeremony",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).AbortUnsealCeremony(ctx, req.(*AbortUnsealCeremonyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_ListClusters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClustersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).ListClusters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Vault/ListClusters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).ListClusters(ctx, req.(*ListClustersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Vault_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.Vault",
 	HandlerType: (*VaultServer)(nil),
@@ -543,74 +1407,190 @@ var _Vault_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Configure",
 			Handler:    _Vault_Configure_Handler,
 		},
+		{
+			MethodName: "ListTokenHolders",
+			Handler:    _Vault_ListTokenHolders_Handler,
+		},
+		{
+			MethodName: "GetTokenHolders",
+			Handler:    _Vault_GetTokenHolders_Handler,
+		},
+		{
+			MethodName: "ReassignTokenHolder",
+			Handler:    _Vault_ReassignTokenHolder_Handler,
+		},
+		{
+			MethodName: "RevokeTokenHolder",
+			Handler:    _Vault_RevokeTokenHolder_Handler,
+		},
+		{
+			MethodName: "StartUnsealCeremony",
+			Handler:    _Vault_StartUnsealCeremony_Handler,
+		},
+		{
+			MethodName: "GetUnsealCeremony",
+			Handler:    _Vault_GetUnsealCeremony_Handler,
+		},
+		{
+			MethodName: "SubmitUnsealShare",
+			Handler:    _Vault_SubmitUnsealShare_Handler,
+		},
+		{
+			MethodName: "AbortUnsealCeremony",
+			Handler:    _Vault_AbortUnsealCeremony_Handler,
+		},
+		{
+			MethodName: "ListClusters",
+			Handler:    _Vault_ListClusters_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchSealStatus",
+			Handler:       _Vault_WatchSealStatus_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ConfigureStream",
+			Handler:       _Vault_ConfigureStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: fileDescriptor0,
 }
 
 func init() { proto.RegisterFile("vault.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 976 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x56, 0x51, 0x73, 0xdb, 0x44,
-	0x10, 0xae, 0xec, 0xd8, 0xb1, 0x57, 0x76, 0x6c, 0x5f, 0x9c, 0x22, 0x5c, 0x18, 0x82, 0x18, 0x86,
-	0xb4, 0xa5, 0x81, 0x9a, 0x96, 0x32, 0x99, 0xe9, 0x03, 0x30, 0x9d, 0x21, 0x2d, 0x05, 0x46, 0x09,
-	0xf0, 0xc2, 0x8c, 0x46, 0xb6, 0xb7, 0x89, 0x26, 0xb2, 0x24, 0xee, 0x4e, 0x99, 0x9a, 0x7f, 0xc3,
-	0xbf, 0xe0, 0x89, 0x3f, 0xd0, 0x3f, 0xc5, 0xdc, 0xde, 0x9d, 0x24, 0x3b, 0x7e, 0x62, 0xf2, 0xe4,
-	0xbb, 0x6f, 0xbf, 0xfd, 0xf6, 0x6e, 0x77, 0x6f, 0x2d, 0x70, 0xaf, 0xa3, 0x22, 0x91, 0xc7, 0x39,
-	0xcf, 0x64, 0xc6, 0x1a, 0xf9, 0xcc, 0xdf, 0x87, 0xd1, 0x69, 0x1a, 0xcb, 0x33, 0x19, 0xc9, 0x42,
-	0x04, 0xf8, 0x67, 0x81, 0x42, 0xfa, 0x2f, 0x81, 0xd5, 0x41, 0x91, 0x67, 0xa9, 0x40, 0xe6, 0x43,
-	0x5b, 0x10, 0xe2, 0x39, 0x87, 0xce, 0x91, 0x3b, 0x85, 0xe3, 0x7c, 0x76, 0x6c, 0x38, 0xc6, 0xc2,
-	0x86, 0xd0, 0x44, 0xce, 0xbd, 0xc6, 0xa1, 0x73, 0xd4, 0x0d, 0xd4, 0xd2, 0xff, 0xb7, 0x09, 0xae,
-	0x12, 0x33, 0xda, 0xec, 0x13, 0xe8, 0x0b, 0x9c, 0x73, 0x94, 0xa1, 0xb8, 0x8c, 0x38, 0x6a, 0xb1,
-	0x7e, 0xd0, 0xd3, 0xe0, 0x19, 0x61, 0xec, 0x3e, 0x0c, 0x0d, 0x49, 0x5e, 0x72, 0x14, 0x97, 0x59,
-	0xb2, 0x20, 0xcd, 0x7e, 0x30, 0xd0, 0xf8, 0xb9, 0x85, 0x49, 0x4f, 0x66, 0x1c, 0x17, 0x56, 0xaf,
-	0x69, 0xf4, 0x08, 0x34, 0x7a, 0xef, 0x43, 0x27, 0xbf, 0xc8, 0xc3, 0x2b, 0x5c, 0x09, 0x6f, 0xe7,
-	0xb0, 0x79, 0xd4, 0x0d, 0x76, 0xf3, 0x8b, 0xfc, 0x15, 0xae, 0x04, 0xfb, 0x0c, 0x06, 0x1c, 0xe7,
-	0xd9, 0x35, 0xf2, 0x95, 0x55, 0x68, 0x91, 0xc2, 0x9e, 0x85, 0x8d, 0xc6, 0x23, 0x60, 0x25, 0xb1,
-	0x3a, 0x55, 0x9b, 0xb8, 0x23, 0x6b, 0xa9, 0xce, 0xf5, 0x00, 0x4a, 0x30, 0x2c, 0x63, 0xef, 0x52,
-	0xec, 0x32, 0xe0, 0x2f, 0xe6, 0x0c, 0x0f, 0x81, 0xf1, 0x2c, 0x93, 0xa1, 0xcc, 0xae, 0x30, 0xb5,
-	0x6c, 0xaf, 0x43, 0x49, 0x1c, 0x28, 0xcb, 0xb9, 0x32, 0x68, 0x36, 0x7b, 0x0a, 0xef, 0xd5, 0xc8,
-	0x2a, 0x16, 0xf2, 0x10, 0x97, 0x51, 0x9c, 0x78, 0x5d, 0xf2, 0x18, 0x97, 0x1e, 0x3f, 0x90, 0xf1,
-	0x85, 0xb2, 0xb1, 0x67, 0xe0, 0x99, 0x94, 0x5e, 0xe1, 0x6a, 0xcd, 0x4d, 0x78, 0x40, 0xc7, 0x3a,
-	0xd0, 0xf6, 0x57, 0xb8, 0xaa, 0xf9, 0x09, 0xff, 0x9d, 0x03, 0x3d, 0x5d, 0x40, 0xd3, 0x07, 0x0c,
-	0x76, 0xe8, 0x32, 0x0e, 0x79, 0xd1, 0x9a, 0x7d, 0x04, 0xae, 0xfa, 0x0d, 0x67, 0x91, 0xc0, 0xaf,
-	0x9f, 0x78, 0x0d, 0x32, 0x81, 0x82, 0xbe, 0x23, 0x44, 0x95, 0xa9, 0x4c, 0x07, 0x79, 0x37, 0x89,
-	0xd2, 0xb3, 0x20, 0xe5, 0xe1, 0x4b, 0x18, 0xaf, 0x91, 0xac, 0x9c, 0x2e, 0x19, 0xab, 0x73, 0x8d,
-	0xec, 0x87, 0x00, 0x55, 0x32, 0xa8, 0x70, 0xdd, 0xa0, 0x5b, 0xde, 0xdf, 0xb6, 0x63, 0xbb, 0x6a,
-	0xc7, 0x7d, 0x18, 0x9d, 0x61, 0x94, 0xac, 0xf7, 0xfb, 0xef, 0xc0, 0xea, 0xa0, 0xb9, 0xe7, 0x17,
-	0xe0, 0x0a, 0x8c, 0x92, 0x70, 0xad, 0xe9, 0xf7, 0xa8, 0xe9, 0x2b, 0x32, 0x88, 0x72, 0xbd, 0xa5,
-	0xf9, 0x9f, 0x41, 0xff, 0xd7, 0x54, 0x31, 0x6c, 0xf7, 0x0f, 0xa1, 0xa9, 0x4a, 0xeb, 0x68, 0xca,
-	0x15, 0xae, 0xd8, 0x18, 0x5a, 0x1c, 0x05, 0x4a, 0x72, 0xeb, 0x04, 0x7a, 0xe3, 0x9f, 0xc1, 0x9e,
-	0x75, 0xbc, 0xbd, 0xd3, 0x3c, 0x80, 0xb6, 0xb1, 0x1d, 0x82, 0x1b, 0xa7, 0xb1, 0x8c, 0xa3, 0x24,
-	0xfe, 0x0b, 0x17, 0x24, 0xd6, 0x09, 0xea, 0x90, 0xff, 0x8f, 0x03, 0x50, 0x09, 0xb3, 0xbb, 0xd0,
-	0x56, 0xd2, 0x25, 0xd7, 0xec, 0x58, 0x0f, 0x1c, 0x69, 0x5e, 0xa6, 0x23, 0xd5, 0x2e, 0x35, 0xef,
-	0xcf, 0x49, 0xd9, 0x04, 0x3a, 0x39, 0xcf, 0x2e, 0x38, 0x0a, 0xf5, 0xe8, 0x14, 0x58, 0xee, 0x99,
-	0x07, 0xbb, 0xd7, 0xc8, 0x45, 0x9c, 0xd9, 0xa2, 0xd9, 0x2d, 0xfb, 0x18, 0x7a, 0xf3, 0xa4, 0x10,
-	0x12, 0x79, 0x98, 0x46, 0x4b, 0x34, 0xb5, 0x73, 0x0d, 0xf6, 0x53, 0xb4, 0x44, 0x55, 0x74, 0x4b,
-	0x89, 0x17, 0xde, 0xae, 0x2e, 0xba, 0x41, 0x4e, 0x17, 0xfe, 0x09, 0x0c, 0xbf, 0xcf, 0xd2, 0x37,
-	0xf1, 0x45, 0xc1, 0xb1, 0x96, 0xf7, 0x82, 0x27, 0x36, 0xef, 0x05, 0x4f, 0x54, 0xde, 0x75, 0xd3,
-	0xe8, 0x04, 0xe9, 0x8d, 0xff, 0x07, 0x8c, 0x6a, 0xbe, 0x26, 0xf5, 0x4f, 0xa1, 0x3f, 0x27, 0x70,
-	0x3d, 0xf9, 0x43, 0x95, 0x7c, 0xcd, 0x36, 0xe9, 0xef, 0xcd, 0x6b, 0xbb, 0x2d, 0x05, 0x78, 0xd7,
-	0x80, 0x5e, 0xdd, 0x81, 0xdd, 0x83, 0xae, 0x51, 0x8e, 0x17, 0xe6, 0x70, 0x1d, 0x0d, 0x9c, 0x2e,
-	0xd8, 0x13, 0x68, 0x2f, 0xb3, 0x22, 0x95, 0x82, 0x9e, 0x93, 0x3b, 0xfd, 0x60, 0x33, 0xde, 0xf1,
-	0x6b, 0x32, 0xbf, 0x48, 0x25, 0x5f, 0x05, 0x86, 0xcb, 0x1e, 0x43, 0x2b, 0x2a, 0xe4, 0xa5, 0x7e,
-	0x60, 0xee, 0xf4, 0xde, 0x0d, 0xa7, 0x6f, 0x95, 0x55, 0xfb, 0x68, 0x26, 0x15, 0x2a, 0x4b, 0xe2,
-	0x79, 0x8c, 0x76, 0x3a, 0x96, 0xfb, 0xc9, 0x4b, 0x70, 0x6b, 0x51, 0xb6, 0xf4, 0xef, 0xa7, 0xd0,
-	0xba, 0x8e, 0x92, 0x02, 0xe9, 0x9e, 0xee, 0x74, 0xa0, 0xe2, 0x91, 0xc7, 0xcf, 0x85, 0xcc, 0x0b,
-	0x19, 0x68, 0xeb, 0x49, 0xe3, 0x1b, 0x67, 0xf2, 0x1a, 0xa0, 0x0a, 0xbe, 0x45, 0xea, 0xfe, 0xba,
-	0xd4, 0xbe, 0x92, 0x52, 0x0e, 0xdb, 0xe5, 0x7c, 0x6e, 0x8e, 0xa6, 0x2d, 0x6a, 0x2c, 0xc9, 0x55,
-	0x8e, 0x46, 0x90, 0xd6, 0xaa, 0xcf, 0x17, 0x28, 0xe6, 0x3c, 0xce, 0xa5, 0x6a, 0x35, 0x5d, 0x8a,
-	0x3a, 0xc4, 0x1e, 0x41, 0x5b, 0x27, 0x9c, 0xfa, 0xd6, 0x9d, 0x1e, 0x94, 0xe7, 0xd7, 0x49, 0x33,
-	0x61, 0x0d, 0xc9, 0x9f, 0xc3, 0xe8, 0x86, 0x51, 0x8d, 0xfa, 0x05, 0xbe, 0x51, 0x7f, 0xac, 0x61,
-	0x82, 0x91, 0xc0, 0x50, 0xca, 0xc4, 0xfc, 0xad, 0x0d, 0x8c, 0xe1, 0x47, 0x85, 0x9f, 0xcb, 0x84,
-	0xf9, 0xd0, 0x5f, 0x46, 0x6f, 0x6b, 0x3c, 0xfd, 0x78, 0xdc, 0x65, 0xf4, 0xd6, 0x72, 0xfc, 0x02,
-	0x06, 0x1b, 0xd7, 0xfe, 0x9f, 0x97, 0xfb, 0x7c, 0xe3, 0x72, 0x63, 0x9b, 0xd1, 0xad, 0x77, 0x9b,
-	0xc1, 0x70, 0xd3, 0x76, 0xdb, 0x57, 0x9b, 0xfe, 0xdd, 0x80, 0xd6, 0x6f, 0xca, 0x8b, 0x3d, 0x07,
-	0xa8, 0xbe, 0x31, 0x18, 0xa5, 0xfd, 0xc6, 0x87, 0xc8, 0xe4, 0xee, 0x26, 0xac, 0x5f, 0xa4, 0x7f,
-	0x87, 0x3d, 0x84, 0x1d, 0x85, 0xb3, 0x81, 0x65, 0x58, 0x97, 0x61, 0x05, 0x94, 0xe4, 0xe7, 0x6b,
-	0xb3, 0xec, 0x60, 0x63, 0x68, 0xd6, 0x63, 0xdd, 0xfc, 0x1b, 0xf0, 0xef, 0xb0, 0xc7, 0xd0, 0xd6,
-	0xc3, 0x98, 0x8d, 0x14, 0x67, 0x6d, 0xa2, 0x4f, 0x58, 0x1d, 0x2a, 0x5d, 0x4e, 0xa0, 0x5b, 0xce,
-	0x11, 0x36, 0xae, 0xde, 0x60, 0x35, 0x92, 0x26, 0x07, 0x1b, 0xa8, 0xf5, 0x9d, 0xb5, 0xe9, 0xeb,
-	0xec, 0xab, 0xff, 0x02, 0x00, 0x00, 0xff, 0xff, 0xb2, 0xa5, 0xed, 0x39, 0xac, 0x09, 0x00, 0x00,
+	// 2074 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0xcd, 0x72, 0x1b, 0xc7,
+	0x11, 0x16, 0x00, 0x02, 0x04, 0x7a, 0xf1, 0xc7, 0x21, 0x29, 0x22, 0x90, 0x12, 0x29, 0x9b, 0xb8,
+	0x2c, 0x5b, 0x16, 0x63, 0x33, 0xb2, 0xe3, 0x72, 0x4a, 0x65, 0x2b, 0x34, 0xad, 0xd0, 0x12, 0x1d,
+	0xd7, 0x82, 0xb1, 0x73, 0x43, 0x2d, 0xb1, 0x4d, 0x70, 0x8b, 0x8b, 0x5d, 0x64, 0x76, 0xc0, 0x12,
+	0x92, 0xca, 0x3d, 0xa9, 0xe4, 0x9a, 0x53, 0x0e, 0xa9, 0x5c, 0xf2, 0x04, 0x79, 0x8c, 0x9c, 0xf2,
+	0x08, 0x79, 0x8a, 0x1c, 0x53, 0x3d, 0x3f, 0xbb, 0xb3, 0xf8, 0x11, 0x2d, 0xd3, 0x27, 0xa2, 0x7b,
+	0xba, 0xbf, 0xe9, 0xee, 0xe9, 0x99, 0xee, 0x5e, 0x82, 0x73, 0xe5, 0xcf, 0x22, 0xb1, 0x3f, 0xe5,
+	0x89, 0x48, 0x58, 0x79, 0x7a, 0xe6, 0x3e, 0x82, 0xad, 0xe3, 0x38, 0x14, 0x03, 0xe1, 0x8b, 0x59,
+	0xea, 0xe1, 0x6f, 0x67, 0x98, 0x0a, 0xd6, 0x83, 0xcd, 0x51, 0x34, 0x4b, 0x05, 0xf2, 0x5e, 0xe9,
+	0x7e, 0xe9, 0x41, 0xc3, 0x33, 0xa4, 0xfb, 0x39, 0x30, 0x5b, 0x3c, 0x9d, 0x26, 0x71, 0x8a, 0xcc,
+	0x85, 0x5a, 0x2a, 0x39, 0x52, 0xdc, 0x39, 0x80, 0xfd, 0xe9, 0xd9, 0xbe, 0x96, 0xd1, 0x2b, 0xac,
+	0x0b, 0x15, 0xe4, 0xbc, 0x57, 0x96, 0x78, 0xf4, 0xd3, 0xfd, 0xeb, 0x06, 0x38, 0x04, 0x66, 0x76,
+	0xfd, 0x11, 0xb4, 0x52, 0x1c, 0x71, 0x14, 0xc3, 0xf4, 0xc2, 0xe7, 0xa8, 0xc0, 0x5a, 0x5e, 0x53,
+	0x31, 0x07, 0x92, 0xc7, 0xde, 0x82, 0xae, 0x16, 0x12, 0x17, 0x1c, 0xd3, 0x8b, 0x24, 0x0a, 0x24,
+	0x66, 0xcb, 0xeb, 0x28, 0xfe, 0xa9, 0x61, 0x4b, 0x3c, 0x91, 0x70, 0x0c, 0x0c, 0x5e, 0x45, 0xe3,
+	0x49, 0xa6, 0xc6, 0xfb, 0x1e, 0xd4, 0xa7, 0xe3, 0xe9, 0xf0, 0x12, 0xe7, 0x69, 0x6f, 0xe3, 0x7e,
+	0x85, 0x7c, 0x9d, 0x8e, 0xa7, 0xcf, 0x71, 0x9e, 0xb2, 0x37, 0xa1, 0xc3, 0x71, 0x94, 0x5c, 0x21,
+	0x9f, 0x1b, 0x84, 0xaa, 0x44, 0x68, 0x1b, 0xb6, 0xc6, 0x78, 0x04, 0x2c, 0x13, 0xcc, 0xad, 0xaa,
+	0x49, 0xd9, 0x2d, 0xb3, 0x92, 0xdb, 0xf5, 0x36, 0x64, 0xcc, 0x61, 0xb6, 0xf7, 0xa6, 0xdc, 0x3b,
+	0xdb, 0xf0, 0x4b, 0x6d, 0xc3, 0x43, 0x60, 0x3c, 0x49, 0xc4, 0x50, 0x24, 0x97, 0x18, 0x1b, 0xe9,
+	0x5e, 0x5d, 0x06, 0xb1, 0x43, 0x2b, 0xa7, 0xb4, 0xa0, 0xa4, 0xd9, 0xfb, 0xb0, 0x67, 0x09, 0xd3,
+	0x5e, 0xc8, 0x87, 0x38, 0xf1, 0xc3, 0xa8, 0xd7, 0x90, 0x1a, 0x3b, 0x99, 0xc6, 0x2f, 0xe5, 0xe2,
+	0x11, 0xad, 0xb1, 0x9f, 0x41, 0x4f, 0x87, 0xf4, 0x12, 0xe7, 0x05, 0xb5, 0xb4, 0x07, 0xd2, 0xac,
+	0x5d, 0xb5, 0xfe, 0x1c, 0xe7, 0x96, 0x5e, 0xca, 0x7e, 0x0e, 0xfd, 0xcc, 0x91, 0x65, 0x55, 0x47,
+	0xaa, 0xee, 0x19, 0x89, 0x45, 0x65, 0x2b, 0xc7, 0x9a, 0xc5, 0x1c, 0xfb, 0x5f, 0x09, 0x9a, 0x2a,
+	0x2f, 0x74, 0x7a, 0x31, 0xd8, 0x90, 0x31, 0x2a, 0x49, 0x44, 0xf9, 0x9b, 0xdd, 0x03, 0x87, 0xfe,
+	0x0e, 0xcf, 0xfc, 0x14, 0x3f, 0x78, 0xdc, 0x2b, 0xcb, 0x25, 0x20, 0xd6, 0x2f, 0x24, 0x87, 0x4e,
+	0xdf, 0x36, 0x8e, 0x4e, 0x9f, 0x44, 0x9a, 0x96, 0x3d, 0x29, 0x7b, 0x17, 0x76, 0x0a, 0x42, 0x06,
+	0x4e, 0x65, 0x02, 0xb3, 0x65, 0x35, 0xec, 0xf7, 0x01, 0xf2, 0x18, 0xcb, 0x7c, 0x68, 0x78, 0x8d,
+	0x2c, 0xac, 0x26, 0xcb, 0x6b, 0x59, 0x96, 0xb3, 0x87, 0x50, 0x43, 0xce, 0x13, 0xae, 0x8e, 0xd8,
+	0x39, 0xd8, 0xa6, 0xbb, 0xf1, 0x95, 0x1f, 0x85, 0x81, 0x2f, 0xc2, 0x24, 0x3e, 0xa2, 0x35, 0x4f,
+	0x8b, 0xd0, 0x6d, 0x1c, 0xa0, 0x1f, 0x7d, 0xd3, 0xdb, 0xf8, 0x35, 0x30, 0x5b, 0x5c, 0x87, 0xeb,
+	0x27, 0xe0, 0xa4, 0xe8, 0x47, 0xc3, 0xc2, 0x95, 0x6c, 0xcb, 0x2b, 0x99, 0x0b, 0x43, 0x9a, 0xfd,
+	0x5e, 0x71, 0x35, 0xff, 0x52, 0x82, 0x4e, 0x2e, 0x7c, 0x74, 0x85, 0xb1, 0x78, 0x7d, 0xd8, 0xdb,
+	0x50, 0x8b, 0xd0, 0x0f, 0xd0, 0x20, 0x6b, 0x8a, 0xdd, 0x85, 0xc6, 0x05, 0xfa, 0x5c, 0x9c, 0xa1,
+	0x2f, 0xe4, 0x9d, 0xac, 0x7b, 0x39, 0x83, 0x0e, 0x5b, 0x84, 0x13, 0xec, 0x6d, 0x48, 0x1d, 0xf9,
+	0xdb, 0x8d, 0xa1, 0xf5, 0xeb, 0x98, 0x90, 0x4d, 0x48, 0xba, 0x50, 0xa1, 0x7b, 0xa0, 0xc2, 0x41,
+	0x3f, 0xd9, 0x0e, 0x54, 0x39, 0xa6, 0x28, 0xe4, 0x5e, 0x75, 0x4f, 0x11, 0x76, 0xe8, 0x2a, 0x85,
+	0xd0, 0xb1, 0x3b, 0xd0, 0xf0, 0xa3, 0x68, 0x18, 0x27, 0x01, 0xa6, 0x72, 0xaf, 0xba, 0x57, 0xf7,
+	0xa3, 0xe8, 0x0b, 0xa2, 0xdd, 0x01, 0xb4, 0xcd, 0x7e, 0xdf, 0x5d, 0x4c, 0xdf, 0x86, 0x9a, 0x5e,
+	0xbb, 0x0f, 0x4e, 0x18, 0x87, 0x22, 0xf4, 0xa3, 0xf0, 0x77, 0x18, 0x48, 0xb0, 0xba, 0x67, 0xb3,
+	0xdc, 0xff, 0x96, 0x00, 0x06, 0x85, 0x48, 0x12, 0x74, 0x26, 0xab, 0x29, 0xd6, 0x84, 0x92, 0xd0,
+	0xaf, 0x5f, 0x49, 0x10, 0x15, 0xeb, 0x37, 0xae, 0x14, 0xb3, 0x3e, 0xd4, 0xa7, 0x3c, 0x19, 0x73,
+	0x4c, 0x95, 0x7f, 0x2d, 0x2f, 0xa3, 0x29, 0x2c, 0x57, 0xc8, 0xd3, 0x30, 0x31, 0x19, 0x6c, 0x48,
+	0xf6, 0x43, 0x68, 0xea, 0x08, 0x0d, 0x63, 0x7f, 0x82, 0x3a, 0x91, 0x1d, 0xcd, 0xfb, 0xc2, 0x9f,
+	0x20, 0xdd, 0x00, 0x23, 0x12, 0x06, 0xbd, 0x4d, 0x75, 0x03, 0x34, 0xe7, 0x38, 0x60, 0x0f, 0xa0,
+	0xaa, 0x82, 0x5a, 0x97, 0xe9, 0xce, 0x28, 0x46, 0x14, 0x55, 0x2b, 0x4e, 0x4a, 0xc0, 0xfd, 0x67,
+	0x09, 0xda, 0xc5, 0x15, 0x32, 0xcc, 0x0f, 0x02, 0x69, 0xb3, 0x4e, 0x75, 0x4d, 0x5a, 0x21, 0x28,
+	0x2f, 0x87, 0xa0, 0x52, 0x08, 0xc1, 0xc6, 0xaa, 0x10, 0x54, 0xd7, 0x87, 0xa0, 0x56, 0x0c, 0x81,
+	0x3e, 0xb9, 0xcd, 0xfc, 0xe4, 0x4e, 0xa1, 0x7b, 0x98, 0xc4, 0xe7, 0xe1, 0x78, 0xc6, 0xd1, 0xca,
+	0xc0, 0x19, 0x8f, 0x4c, 0x06, 0xce, 0x78, 0x44, 0x19, 0xa8, 0x1e, 0x05, 0x75, 0xe6, 0x8a, 0x58,
+	0x9f, 0x81, 0xee, 0x9f, 0x4a, 0xb0, 0x65, 0xc1, 0xea, 0x44, 0x7b, 0x1f, 0x5a, 0x23, 0xc9, 0x2c,
+	0xa6, 0x5a, 0x97, 0xc2, 0xa8, 0xa4, 0x75, 0x10, 0x9b, 0x23, 0x8b, 0x5a, 0x4e, 0x37, 0xeb, 0xdd,
+	0xa9, 0x5c, 0xff, 0xee, 0xfc, 0xad, 0x0c, 0xed, 0xcc, 0x16, 0x75, 0xdd, 0xef, 0x40, 0x43, 0x1b,
+	0x12, 0x06, 0xda, 0xcd, 0xba, 0x62, 0x1c, 0x07, 0xe4, 0xeb, 0xf4, 0xc2, 0x4f, 0xd1, 0xf8, 0x2a,
+	0x09, 0x8a, 0xf7, 0xc8, 0x17, 0x38, 0x4e, 0xf8, 0x5c, 0x3b, 0x9b, 0xd1, 0x74, 0x7e, 0xfe, 0x88,
+	0x36, 0xd6, 0x17, 0x5b, 0x53, 0x12, 0xc9, 0x17, 0x17, 0x74, 0x40, 0x15, 0x89, 0x44, 0x04, 0x49,
+	0x9f, 0xfb, 0x21, 0x9d, 0x76, 0x4d, 0x9d, 0xb6, 0xa2, 0x96, 0xcf, 0xc6, 0x72, 0xb3, 0x7e, 0xad,
+	0x9b, 0xcb, 0xc1, 0x6d, 0x7c, 0x93, 0xe0, 0xba, 0xff, 0xae, 0x40, 0xd3, 0x5e, 0x7e, 0x75, 0x6c,
+	0x1e, 0x43, 0x6d, 0x92, 0xcc, 0x62, 0x91, 0xca, 0xa2, 0xe4, 0x1c, 0xdc, 0x5d, 0x44, 0xdf, 0x3f,
+	0x91, 0xcb, 0x47, 0xb1, 0xe0, 0x73, 0x4f, 0xcb, 0xb2, 0xf7, 0xa0, 0xea, 0xcf, 0xc4, 0x85, 0x39,
+	0xad, 0x3b, 0x4b, 0x4a, 0x4f, 0x69, 0x55, 0xe9, 0x28, 0x49, 0x99, 0xde, 0x49, 0x14, 0x8e, 0x42,
+	0x34, 0xad, 0x4b, 0x46, 0xb3, 0x4f, 0x00, 0x74, 0xe8, 0x43, 0x54, 0xb1, 0x75, 0x0e, 0xee, 0x2f,
+	0x61, 0x1e, 0x66, 0x22, 0x0a, 0xd8, 0xd2, 0xe9, 0x7f, 0x0e, 0x8e, 0x65, 0xe7, 0x8a, 0x17, 0xf7,
+	0x0d, 0xa8, 0x5e, 0xf9, 0xd1, 0x4c, 0xe5, 0x80, 0x73, 0xd0, 0x21, 0x74, 0xa9, 0xf1, 0xab, 0x99,
+	0x98, 0xce, 0x84, 0xa7, 0x56, 0x3f, 0x2a, 0x7f, 0x58, 0xea, 0x9f, 0x00, 0xe4, 0xe6, 0xaf, 0x80,
+	0x7a, 0xab, 0x08, 0x25, 0xcf, 0x90, 0x14, 0xd6, 0xc0, 0x3d, 0x81, 0xce, 0x82, 0xe5, 0xab, 0x0b,
+	0x42, 0x8e, 0xd9, 0xb4, 0xd4, 0x5d, 0x0e, 0x8e, 0x05, 0x2c, 0x0b, 0xce, 0x7c, 0x8a, 0x5a, 0x57,
+	0xfe, 0xa6, 0x17, 0x3a, 0xc0, 0x74, 0xc4, 0xc3, 0xa9, 0x4c, 0x59, 0x95, 0xe5, 0x36, 0x8b, 0x3d,
+	0x82, 0x9a, 0x3a, 0x71, 0x99, 0xe9, 0xce, 0xc1, 0x6e, 0xe6, 0xbe, 0x8a, 0xb0, 0xb6, 0x5a, 0x0b,
+	0xb9, 0x23, 0xd8, 0x5a, 0x5a, 0xa4, 0x46, 0x30, 0xc0, 0x73, 0x6a, 0xc8, 0x87, 0x11, 0xfa, 0x29,
+	0x0e, 0x85, 0x88, 0x74, 0xd3, 0xdb, 0xd1, 0x0b, 0x2f, 0x88, 0x7f, 0x2a, 0x22, 0xe6, 0x42, 0x6b,
+	0xe2, 0xbf, 0xb4, 0xe4, 0xd4, 0xb3, 0xef, 0x4c, 0xfc, 0x97, 0x46, 0xc6, 0x9d, 0x41, 0x67, 0x21,
+	0x6a, 0xdf, 0xd2, 0xb9, 0x77, 0x16, 0x9c, 0xdb, 0x31, 0x07, 0xb2, 0xd2, 0xb7, 0x33, 0xe8, 0x2e,
+	0xae, 0x7d, 0xe7, 0xae, 0x7d, 0x08, 0x7b, 0x2f, 0xc2, 0xd4, 0xee, 0x5d, 0xb3, 0xf6, 0xa8, 0x58,
+	0x8f, 0x4a, 0x0b, 0xf5, 0xc8, 0xdd, 0x87, 0xdb, 0xcf, 0x70, 0xa5, 0xe2, 0x0e, 0x54, 0x55, 0x73,
+	0xac, 0x74, 0x14, 0xe1, 0xfe, 0x06, 0xfa, 0x1e, 0xfa, 0x69, 0x1a, 0x8e, 0x63, 0x4b, 0xc9, 0xe8,
+	0xfc, 0x38, 0xcf, 0x33, 0x5d, 0xdb, 0x2c, 0xa1, 0xe7, 0x38, 0xcf, 0x72, 0x4f, 0x21, 0x97, 0x6d,
+	0xe4, 0x4f, 0xa0, 0xe7, 0xe1, 0x55, 0x72, 0x89, 0xdf, 0x16, 0xd7, 0xfd, 0x73, 0x09, 0x76, 0x8a,
+	0x9e, 0xe8, 0xaa, 0xf1, 0x18, 0x5a, 0x76, 0xd3, 0xaf, 0x5a, 0x65, 0x7d, 0x27, 0xed, 0xdd, 0x9a,
+	0xc2, 0xd2, 0xbe, 0x69, 0xd1, 0xf8, 0x63, 0x09, 0xb6, 0x0b, 0xae, 0x68, 0x63, 0x0e, 0xa0, 0x69,
+	0x1b, 0xa3, 0x9d, 0x5a, 0xb2, 0xc5, 0xb1, 0x6c, 0xb9, 0xa9, 0x29, 0x09, 0xb4, 0x8b, 0xf1, 0xba,
+	0x26, 0x2b, 0x68, 0x59, 0xd9, 0x28, 0x6f, 0x87, 0xda, 0xb6, 0x21, 0x39, 0xa7, 0x74, 0x45, 0xee,
+	0x81, 0x23, 0x27, 0xbe, 0x61, 0x18, 0x07, 0xf8, 0x52, 0xf7, 0x17, 0x20, 0x59, 0xc7, 0xc4, 0x71,
+	0xff, 0x51, 0x06, 0xc7, 0xda, 0xf1, 0x26, 0x79, 0x41, 0x3d, 0x57, 0xe0, 0x0b, 0x1c, 0x8e, 0x38,
+	0xfa, 0x02, 0x03, 0x5d, 0x3a, 0x1d, 0xe2, 0x1d, 0x2a, 0x16, 0x4d, 0xbd, 0x52, 0xc4, 0x6e, 0x1b,
+	0x55, 0x1d, 0xed, 0x10, 0xff, 0x38, 0x67, 0xb3, 0x37, 0xa0, 0x2d, 0x45, 0x03, 0x8c, 0xc2, 0x2b,
+	0xe4, 0x18, 0xe8, 0x16, 0xaf, 0x45, 0xdc, 0x4f, 0x0d, 0x33, 0xdb, 0x94, 0xcb, 0x8c, 0x0c, 0x4c,
+	0xa3, 0x47, 0x3c, 0x95, 0xa4, 0x01, 0xcd, 0xbf, 0x34, 0x70, 0x9e, 0x87, 0xf1, 0x18, 0xf9, 0x94,
+	0x87, 0xb1, 0xd0, 0x85, 0xb7, 0x3d, 0x1d, 0x4f, 0x3f, 0xcb, 0xb9, 0x6c, 0x17, 0x6a, 0x34, 0xfe,
+	0x85, 0x81, 0x1e, 0x4c, 0xab, 0x97, 0x38, 0x3f, 0x0e, 0xdc, 0x09, 0x74, 0x16, 0xce, 0x8b, 0x02,
+	0x70, 0x1e, 0x62, 0x64, 0x0e, 0x44, 0x11, 0xf4, 0x48, 0x9d, 0x87, 0x91, 0x39, 0x06, 0xf9, 0x9b,
+	0x3a, 0x80, 0xe4, 0xfc, 0x3c, 0x45, 0xd5, 0xdc, 0x55, 0x3c, 0x4d, 0x51, 0x3f, 0x35, 0xc1, 0x34,
+	0xf5, 0xc7, 0x66, 0x42, 0x30, 0xa4, 0xfb, 0x01, 0xf4, 0x07, 0xc2, 0xe7, 0x42, 0x75, 0xee, 0x87,
+	0xc8, 0x71, 0x92, 0xc4, 0xf3, 0xeb, 0x87, 0xa8, 0x4f, 0xa1, 0xf7, 0x0c, 0xd7, 0x68, 0xb5, 0xa1,
+	0x9c, 0x65, 0x4f, 0x39, 0x0c, 0x6c, 0x94, 0x72, 0x11, 0xe5, 0x0f, 0xd0, 0x1b, 0xcc, 0xce, 0x26,
+	0xa1, 0x06, 0x92, 0x5f, 0x06, 0x0c, 0xca, 0x3d, 0x70, 0x46, 0x1a, 0x38, 0x4f, 0x46, 0x30, 0x2c,
+	0xd5, 0x4e, 0xad, 0xc8, 0x0b, 0x5d, 0xd3, 0x2a, 0x79, 0x4d, 0xb3, 0xb6, 0xdf, 0x28, 0x6e, 0xff,
+	0x19, 0xf4, 0x9f, 0x9e, 0x25, 0xfc, 0xc6, 0x6e, 0xfc, 0x1e, 0xba, 0xb9, 0xb2, 0xbe, 0xcf, 0x0f,
+	0xa0, 0x6e, 0x6c, 0xd5, 0x09, 0xde, 0x94, 0x9d, 0x84, 0x91, 0xcb, 0x56, 0x6f, 0x7a, 0x8b, 0xff,
+	0x55, 0x86, 0xba, 0x41, 0xbd, 0xee, 0x02, 0x2b, 0x97, 0xca, 0x99, 0x4b, 0x3b, 0x50, 0x4d, 0x85,
+	0x2f, 0x50, 0x87, 0x4b, 0x11, 0x34, 0x6a, 0xe6, 0x1f, 0x64, 0xd4, 0x5c, 0x90, 0x33, 0x5e, 0x39,
+	0x1f, 0xe4, 0xf3, 0x46, 0xad, 0x30, 0x6f, 0x98, 0x7b, 0x93, 0x52, 0xaa, 0xa1, 0x99, 0x7f, 0xe4,
+	0xbd, 0x19, 0x28, 0x56, 0x26, 0x82, 0x2f, 0xa7, 0x21, 0x97, 0x83, 0x50, 0x26, 0x72, 0xa4, 0x58,
+	0xe4, 0x9c, 0x12, 0x89, 0x03, 0x0c, 0xf4, 0xc7, 0x99, 0x86, 0x14, 0x20, 0x06, 0x7b, 0x07, 0x36,
+	0xcd, 0x43, 0x0e, 0xf9, 0x14, 0x65, 0x42, 0xa3, 0xdf, 0x4f, 0x23, 0xe2, 0xfe, 0xbd, 0x04, 0xed,
+	0xe2, 0xda, 0xea, 0xd2, 0x26, 0x7d, 0x52, 0xdf, 0xb1, 0x54, 0x85, 0xd5, 0x14, 0x45, 0x29, 0x95,
+	0xb9, 0x6b, 0x5e, 0x9f, 0x96, 0x97, 0x33, 0x28, 0x4a, 0x57, 0xc8, 0xc3, 0xf3, 0x50, 0xbf, 0x39,
+	0x75, 0x2f, 0xa3, 0xb3, 0xc7, 0x26, 0x57, 0xb7, 0x1e, 0x9b, 0x81, 0x61, 0xba, 0xbb, 0xb0, 0x4d,
+	0xd5, 0xfb, 0x50, 0x9d, 0x9e, 0x29, 0xc0, 0xee, 0x09, 0x74, 0x73, 0x96, 0x4e, 0xb6, 0x37, 0xa1,
+	0xae, 0x0f, 0xd9, 0x14, 0x31, 0x47, 0xfa, 0xae, 0x78, 0x5e, 0xb6, 0xb8, 0x62, 0xc0, 0x9e, 0xc2,
+	0xa6, 0x16, 0xa3, 0x17, 0x45, 0x8e, 0xaf, 0xba, 0xed, 0xa1, 0xdf, 0xf6, 0x6c, 0x59, 0x2e, 0xce,
+	0x96, 0xc5, 0x54, 0xab, 0x2c, 0xa6, 0x5a, 0x0f, 0x36, 0x75, 0xcb, 0xa2, 0xfd, 0x37, 0xe4, 0xc1,
+	0x7f, 0xea, 0x50, 0xfd, 0x8a, 0x7e, 0xb1, 0x27, 0x00, 0xf9, 0x77, 0x51, 0x26, 0x9b, 0xc1, 0xa5,
+	0xcf, 0xaa, 0xfd, 0xdb, 0x8b, 0x6c, 0xe5, 0xb3, 0x7b, 0x8b, 0x3d, 0x84, 0x0d, 0xe2, 0xb3, 0x8e,
+	0x91, 0x30, 0x2a, 0xdd, 0x9c, 0x91, 0x09, 0x3f, 0x29, 0x7c, 0x1b, 0xd8, 0x5d, 0xf8, 0x08, 0x61,
+	0xef, 0xb5, 0xfc, 0x71, 0xc8, 0xbd, 0xc5, 0x9e, 0x42, 0xe7, 0x6b, 0x5f, 0x8c, 0x2e, 0xae, 0xc7,
+	0xd8, 0x2e, 0xb2, 0xe5, 0x5c, 0xe8, 0xde, 0x7a, 0xb7, 0xc4, 0xde, 0x83, 0x9a, 0x7a, 0x68, 0xd8,
+	0x16, 0x89, 0x14, 0xbe, 0xcd, 0xf4, 0x99, 0xcd, 0xca, 0x76, 0xfd, 0x08, 0x1a, 0xd9, 0x80, 0xc9,
+	0x76, 0xf2, 0x49, 0x24, 0x1f, 0xa9, 0xfb, 0xbb, 0x0b, 0xdc, 0x4c, 0xf7, 0x63, 0xe8, 0x64, 0xec,
+	0x81, 0xe0, 0xe8, 0x4f, 0xd6, 0x20, 0xb0, 0x02, 0x37, 0xb7, 0xf7, 0x39, 0x74, 0x17, 0xbb, 0x47,
+	0x26, 0x27, 0xac, 0x35, 0x3d, 0x65, 0xbf, 0xb7, 0x50, 0xc1, 0xed, 0xf8, 0x1d, 0x43, 0x67, 0xa1,
+	0xa1, 0x64, 0x7d, 0x12, 0x7f, 0x86, 0xaf, 0x0d, 0xf5, 0x25, 0x6c, 0xaf, 0xe8, 0x35, 0xd9, 0x0f,
+	0x48, 0x65, 0x7d, 0x13, 0xda, 0xdf, 0x5b, 0x80, 0xb4, 0x10, 0x5f, 0xc0, 0xd6, 0x52, 0x8f, 0xc9,
+	0xee, 0x2a, 0xbc, 0xd5, 0xad, 0xe7, 0xab, 0xd0, 0x4e, 0x60, 0x7b, 0x45, 0x49, 0x55, 0xf6, 0xad,
+	0xaf, 0xb5, 0xfd, 0x9d, 0x42, 0x79, 0xb0, 0x23, 0xb7, 0xb5, 0x54, 0x69, 0x95, 0x71, 0xcf, 0xf0,
+	0xf5, 0xa1, 0x96, 0xca, 0xad, 0x82, 0x5a, 0x57, 0x85, 0xd7, 0x42, 0x9d, 0xc0, 0xf6, 0x8a, 0xd2,
+	0xa9, 0x9c, 0x5c, 0x5f, 0x53, 0xd7, 0xc2, 0x7d, 0x0c, 0x4d, 0xfb, 0xad, 0x63, 0x7b, 0x26, 0xcf,
+	0x16, 0x5e, 0x3f, 0x0d, 0xb0, 0xf0, 0xfe, 0xb9, 0xb7, 0xce, 0x6a, 0xf2, 0x9f, 0x33, 0x3f, 0xfd,
+	0xff, 0x00, 0x3b, 0x24, 0x6f, 0x7c, 0xab, 0x19, 0x00, 0x00,
 }
@@ -0,0 +1,137 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"time"
+)
+
+// ceremonyIDAttrNm is the DynamoDB range key of unseal ceremonies.
+const ceremonyIDAttrNm string = "ceremonyId"
+
+// unseal ceremony errors
+var (
+	ErrCeremonyNotFound = errors.New("unseal ceremony not found")
+	ErrCeremonyConflict = errors.New("unseal ceremony was changed concurrently")
+)
+
+// The states of a Ceremony.
+const (
+	// CeremonyOpen is the state of a ceremony accepting unseal keys.
+	CeremonyOpen string = "open"
+	// CeremonyUnsealed is recorded once Vault was unsealed.
+	CeremonyUnsealed string = "unsealed"
+	// CeremonyAborted is recorded when a ceremony is aborted.
+	CeremonyAborted string = "aborted"
+	// CeremonyExpired is recorded when an open ceremony is found past its
+	// expiry.
+	CeremonyExpired string = "expired"
+)
+
+// CeremonyParticipant records an unseal key submitted during a ceremony.
+type CeremonyParticipant struct {
+	Email         string `json:"email" dynamodbav:"email"`                  // token holder who submitted the key
+	ShareIndex    int    `json:"share_index" dynamodbav:"shareIndex"`       // share the key matched; only meaningful if verified
+	Verified      bool   `json:"verified" dynamodbav:"verified"`            // whether the key matched the holder's stored share hash
+	DateSubmitted string `json:"date_submitted" dynamodbav:"dateSubmitted"` // date the key was submitted to Vault
+}
+
+// Ceremony is a guided unseal of a Vault cluster: token holders submit
+// their unseal keys in turn, and Armor records who participated.
+type Ceremony struct {
+	ClusterID    string                `json:"cluster_id" dynamodbav:"clusterId"`                          // vault cluster being unsealed
+	ID           string                `json:"id" dynamodbav:"ceremonyId"`                                 // random id, unique within the cluster
+	State        string                `json:"state" dynamodbav:"state"`                                   // open, unsealed, aborted or expired
+	Threshold    int                   `json:"threshold" dynamodbav:"threshold"`                           // number of keys Vault requires
+	DateStarted  string                `json:"date_started" dynamodbav:"dateStarted"`                      // date the ceremony was started
+	DateExpires  string                `json:"date_expires" dynamodbav:"dateExpires"`                      // date an open ceremony expires
+	DateEnded    string                `json:"date_ended,omitempty" dynamodbav:"dateEnded,omitempty"`      // date the ceremony left the open state
+	Participants []CeremonyParticipant `json:"participants,omitempty" dynamodbav:"participants,omitempty"` // in order of submission
+	Version      int                   `json:"version" dynamodbav:"version"`                               // incremented on every write, for conditional writes
+}
+
+// Expired reports whether an open ceremony is past its expiry by now.
+func (c *Ceremony) Expired(now time.Time) bool {
+	expires, err := time.Parse(time.RFC3339, c.DateExpires)
+	if err != nil {
+		return true
+	}
+	return !now.Before(expires)
+}
+
+// Participant returns the participant with the email address, if any.
+func (c *Ceremony) Participant(email string) (CeremonyParticipant, bool) {
+	for _, p := range c.Participants {
+		if p.Email == email {
+			return p, true
+		}
+	}
+	return CeremonyParticipant{}, false
+}
+
+// CeremonyStore keeps the unseal ceremonies of each Vault cluster. Writes
+// are conditional, like those of InitRecordStore.
+type CeremonyStore interface {
+	// GetCeremony returns the cluster's ceremony, or ErrCeremonyNotFound.
+	GetCeremony(clusterID, id string) (*Ceremony, error)
+
+	// ListCeremonies returns the cluster's ceremonies, oldest first.
+	ListCeremonies(clusterID string) ([]*Ceremony, error)
+
+	// PutCeremony writes c, provided the stored ceremony still is prev, or
+	// doesn't exist if prev is nil. Otherwise ErrCeremonyConflict is
+	// returned. c.Version is set to follow prev's.
+	PutCeremony(c, prev *Ceremony) error
+}
+
+// nextCeremonyVersion returns the version c gets when replacing prev.
+func nextCeremonyVersion(prev *Ceremony) int {
+	if prev == nil {
+		return 1
+	}
+	return prev.Version + 1
+}
+
+// ceremonyMatches reports whether the current ceremony (nil if there is
+// none) is the expected one.
+func ceremonyMatches(current, expected *Ceremony) bool {
+	if current == nil || expected == nil {
+		return current == nil && expected == nil
+	}
+	return current.Version == expected.Version
+}
+
+type byDateStarted []*Ceremony
+
+func (c byDateStarted) Len() int      { return len(c) }
+func (c byDateStarted) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c byDateStarted) Less(i, j int) bool {
+	if c[i].DateStarted != c[j].DateStarted {
+		return c[i].DateStarted < c[j].DateStarted
+	}
+	return c[i].ID < c[j].ID
+}
+
+func sortCeremonies(ceremonies []*Ceremony) {
+	sort.Sort(byDateStarted(ceremonies))
+}
+
+// ShareHash returns the hex encoded SHA-256 hash of an unseal key, which is
+// kept with the key's token holder so keys submitted during a ceremony can be
+// verified. Vault accepts keys hex or base64 encoded, so the hash is of the
+// decoded key.
+func ShareHash(key string) string {
+	raw, err := hex.DecodeString(key)
+	if err != nil {
+		raw, err = base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			raw = []byte(key)
+		}
+	}
+
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,649 @@
+package data
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DynamoDBStore is a TokenHolderStore kept in the AWS DynamoDB table named by
+// TokenHolderTableName.
+type DynamoDBStore struct{}
+
+// NewDynamoDBStore creates a DynamoDBStore. The DynamoDB client is created
+// per request from Armor's default AWS session.
+func NewDynamoDBStore() *DynamoDBStore {
+	return &DynamoDBStore{}
+}
+
+// Put implements TokenHolderStore.
+func (s *DynamoDBStore) Put(tokenHolder *TokenHolder) error {
+	if err := tokenHolder.validateForPut(); err != nil {
+		return err
+	}
+
+	item, err := dynamodbattribute.MarshalMap(tokenHolder)
+	if err != nil {
+		return err
+	}
+	item[tokenKeyAttrNm] = &dynamodb.AttributeValue{
+		S: aws.String(tokenHolder.Key().String()),
+	}
+
+	params := &dynamodb.PutItemInput{
+		TableName: aws.String(TokenHolderTableName()),
+		Item:      item,
+	}
+
+	svc := NewDynamoDBClient()
+	_, err = svc.PutItem(params)
+
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			switch awsErr.Code() {
+			case "ConditionalCheckFailedException":
+				// A condition specified in the operation could not be evaluated.
+				return err
+			case "ProvisionedThroughputExceededException":
+				// Your request rate is too high. The AWS SDKs for DynamoDB
+				// automatically retry requests that receive this exception. Your
+				// request is eventually successful, unless your retry queue is too
+				// large to finish. Reduce the frequency of requests and use
+				// exponential backoff.
+				return err
+			case "ResourceNotFoundException":
+				// The operation tried to access a nonexistent table or index. The
+				// resource might not be specified correctly, or its status might not
+				// be ACTIVE.
+				return err
+			case "ItemCollectionSizeLimitExceededException":
+				// An item collection is too large. This exception is only returned
+				// for tables that have one or more local secondary indexes.
+				return err
+			case "InternalServerError":
+				// An error occurred on the server side.
+				return err
+			default:
+				return err
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Get implements TokenHolderStore.
+func (s *DynamoDBStore) Get(key TokenHolderKey) (*TokenHolder, error) {
+	svc := NewDynamoDBClient()
+
+	params := &dynamodb.GetItemInput{
+		Key:            tokenHolderItemKey(key),
+		TableName:      aws.String(TokenHolderTableName()),
+		ConsistentRead: aws.Bool(true),
+	}
+
+	list, err := svc.GetItem(params)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			switch awsErr.Code() {
+			case "ResourceNotFoundException":
+				//   The operation tried to access a nonexistent table or index. The
+				//   resource might not be specified correctly, or its status might not
+				//   be ACTIVE.
+				return nil, err
+			case "ProvisionedThroughputExceededException":
+				// Your request rate is too high. The AWS SDKs for DynamoDB
+				// automatically retry requests that receive this exception. Your
+				// request is eventually successful, unless your retry queue is too
+				// large to finish.
+				return nil, err
+			case "InternalServerError":
+				// An error occurred on the server side.
+				return nil, err
+			default:
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	if len(list.Item) == 0 {
+		return nil, ErrTokenHolderNotFound
+	}
+
+	tokenHolder := &TokenHolder{}
+	err = dynamodbattribute.UnmarshalMap(list.Item, tokenHolder)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenHolder, nil
+}
+
+// ListByEmail implements TokenHolderStore. The email index is eventually
+// consistent, so a token holder which was just put may be missing.
+func (s *DynamoDBStore) ListByEmail(email string) ([]*TokenHolder, error) {
+	svc := NewDynamoDBClient()
+
+	params := &dynamodb.QueryInput{
+		TableName:              aws.String(TokenHolderTableName()),
+		IndexName:              aws.String(emailIndexNm),
+		KeyConditionExpression: aws.String("#email = :email"),
+		ExpressionAttributeNames: map[string]*string{
+			"#email": aws.String(emailAttrNm),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":email": {
+				S: aws.String(email),
+			},
+		},
+	}
+
+	holders := []*TokenHolder{}
+	var unmarshalErr error
+	err := svc.QueryPages(params, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		holders, unmarshalErr = appendTokenHolders(holders, page.Items)
+		return unmarshalErr == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	sort.Sort(byTokenHolderKey(holders))
+	return holders, nil
+}
+
+// ListByCluster implements TokenHolderStore. The cluster id is the table's
+// hash key, so this is a consistent query.
+func (s *DynamoDBStore) ListByCluster(clusterID string) ([]*TokenHolder, error) {
+	svc := NewDynamoDBClient()
+
+	params := &dynamodb.QueryInput{
+		TableName:              aws.String(TokenHolderTableName()),
+		ConsistentRead:         aws.Bool(true),
+		KeyConditionExpression: aws.String("#clusterId = :clusterId"),
+		ExpressionAttributeNames: map[string]*string{
+			"#clusterId": aws.String(clusterIDAttrNm),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":clusterId": {
+				S: aws.String(clusterID),
+			},
+		},
+	}
+
+	holders := []*TokenHolder{}
+	var unmarshalErr error
+	err := svc.QueryPages(params, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		holders, unmarshalErr = appendTokenHolders(holders, page.Items)
+		return unmarshalErr == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	// items are already sorted by the range key, but not every record may
+	// carry the tokenKey attribute
+	sort.Sort(byTokenHolderKey(holders))
+	return holders, nil
+}
+
+// List implements TokenHolderStore. The table is scanned, which is fine for
+// the handful of token holders a Vault instance has.
+func (s *DynamoDBStore) List() ([]*TokenHolder, error) {
+	holders, err := scanTokenHolders(TokenHolderTableName())
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(byTokenHolderKey(holders))
+	return holders, nil
+}
+
+// Delete implements TokenHolderStore.
+func (s *DynamoDBStore) Delete(key TokenHolderKey) error {
+	svc := NewDynamoDBClient()
+
+	params := &dynamodb.DeleteItemInput{
+		Key:       tokenHolderItemKey(key),
+		TableName: aws.String(TokenHolderTableName()),
+	}
+
+	_, err := svc.DeleteItem(params)
+	return err
+}
+
+// EnsureSchema implements TokenHolderStore. The token holder table is created
+// if it doesn't exist.
+func (s *DynamoDBStore) EnsureSchema() error {
+	exists, err := TokenHolderTableExists()
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		err = CreateTokenHolderTable()
+		if err != nil {
+			return err
+		}
+	}
+
+	exists, err = tableExists(InitRecordTableName())
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		err = CreateInitRecordTable()
+		if err != nil {
+			return err
+		}
+	}
+
+	exists, err = tableExists(CeremonyTableName())
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return CreateCeremonyTable()
+	}
+
+	return nil
+}
+
+// GetInitRecord implements InitRecordStore.
+func (s *DynamoDBStore) GetInitRecord(clusterID string) (*InitRecord, error) {
+	svc := NewDynamoDBClient()
+
+	params := &dynamodb.GetItemInput{
+		Key:            initRecordItemKey(clusterID),
+		TableName:      aws.String(InitRecordTableName()),
+		ConsistentRead: aws.Bool(true),
+	}
+
+	resp, err := svc.GetItem(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Item) == 0 {
+		return nil, ErrInitRecordNotFound
+	}
+
+	rec := &InitRecord{}
+	err = dynamodbattribute.UnmarshalMap(resp.Item, rec)
+	if err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+// PutInitRecord implements InitRecordStore. The write is conditional on the
+// record's version, so racing replicas can't both succeed.
+func (s *DynamoDBStore) PutInitRecord(rec, prev *InitRecord) error {
+	next := *rec
+	next.Version = nextInitRecordVersion(prev)
+
+	item, err := dynamodbattribute.MarshalMap(&next)
+	if err != nil {
+		return err
+	}
+
+	params := &dynamodb.PutItemInput{
+		TableName: aws.String(InitRecordTableName()),
+		Item:      item,
+	}
+	if prev == nil {
+		params.ConditionExpression = aws.String("attribute_not_exists(#clusterId)")
+		params.ExpressionAttributeNames = map[string]*string{
+			"#clusterId": aws.String(clusterIDAttrNm),
+		}
+	} else {
+		params.ConditionExpression, params.ExpressionAttributeNames, params.ExpressionAttributeValues = initRecordVersionCondition(prev.Version)
+	}
+
+	svc := NewDynamoDBClient()
+	_, err = svc.PutItem(params)
+	if err != nil {
+		return initRecordError(err)
+	}
+
+	rec.Version = next.Version
+	return nil
+}
+
+// DeleteInitRecord implements InitRecordStore.
+func (s *DynamoDBStore) DeleteInitRecord(rec *InitRecord) error {
+	params := &dynamodb.DeleteItemInput{
+		Key:       initRecordItemKey(rec.ClusterID),
+		TableName: aws.String(InitRecordTableName()),
+	}
+	params.ConditionExpression, params.ExpressionAttributeNames, params.ExpressionAttributeValues = initRecordVersionCondition(rec.Version)
+
+	svc := NewDynamoDBClient()
+	_, err := svc.DeleteItem(params)
+	return initRecordError(err)
+}
+
+// initRecordItemKey maps a cluster id to the init record table's primary key.
+func initRecordItemKey(clusterID string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		clusterIDAttrNm: {
+			S: aws.String(clusterID),
+		},
+	}
+}
+
+// initRecordVersionCondition makes a write conditional on the stored
+// record's version.
+func initRecordVersionCondition(version int) (*string, map[string]*string, map[string]*dynamodb.AttributeValue) {
+	names := map[string]*string{
+		"#version": aws.String(initRecordVersionAttrNm),
+	}
+	values := map[string]*dynamodb.AttributeValue{
+		":version": {
+			N: aws.String(strconv.Itoa(version)),
+		},
+	}
+	return aws.String("#version = :version"), names, values
+}
+
+// initRecordError translates a failed condition to ErrInitRecordConflict.
+func initRecordError(err error) error {
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "ConditionalCheckFailedException" {
+		return ErrInitRecordConflict
+	}
+	return err
+}
+
+// CreateInitRecordTable creates the table named by InitRecordTableName,
+// keyed by cluster id, like CreateTokenHolderTable.
+func CreateInitRecordTable() error {
+	svc := NewDynamoDBClient()
+
+	params := &dynamodb.CreateTableInput{
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String(clusterIDAttrNm),
+				AttributeType: aws.String("S"),
+			},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String(clusterIDAttrNm),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		TableName: aws.String(InitRecordTableName()),
+	}
+
+	return createTable(svc, params)
+}
+
+// MigrateLegacy implements TokenHolderMigrator. Token holders are copied from
+// the table named by LegacyTokenHolderTableName, which was keyed by email
+// address alone, into the table named by TokenHolderTableName. The legacy
+// table didn't record which share each person holds, so unseal tokens are
+// assigned share indexes in order of email address. Vault accepts unseal
+// keys in any order, so this only affects reporting.
+func (s *DynamoDBStore) MigrateLegacy(clusterID string) (int, error) {
+	if clusterID == "" {
+		return 0, ErrTokenHolderKeyUnset
+	}
+
+	exists, err := tableExists(LegacyTokenHolderTableName())
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	err = s.EnsureSchema()
+	if err != nil {
+		return 0, err
+	}
+
+	legacy, err := scanTokenHolders(LegacyTokenHolderTableName())
+	if err != nil {
+		return 0, err
+	}
+	sort.Sort(byEmail(legacy))
+
+	now := time.Now().Format(time.RFC3339)
+	next := make(map[string]int)
+	for _, tokenHolder := range legacy {
+		tokenHolder.ClusterID = clusterID
+		tokenHolder.ShareIndex = next[tokenHolder.TokenType]
+		next[tokenHolder.TokenType]++
+		if tokenHolder.DateCreated == "" {
+			tokenHolder.DateCreated = now
+		}
+
+		err = s.Put(tokenHolder)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(legacy), nil
+}
+
+// tokenHolderItemKey maps a TokenHolderKey to the table's primary key.
+func tokenHolderItemKey(key TokenHolderKey) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		clusterIDAttrNm: {
+			S: aws.String(key.ClusterID),
+		},
+		tokenKeyAttrNm: {
+			S: aws.String(key.String()),
+		},
+	}
+}
+
+// scanTokenHolders returns every token holder of the named table.
+func scanTokenHolders(table string) ([]*TokenHolder, error) {
+	svc := NewDynamoDBClient()
+
+	params := &dynamodb.ScanInput{
+		TableName:      aws.String(table),
+		ConsistentRead: aws.Bool(true),
+	}
+
+	holders := []*TokenHolder{}
+	var unmarshalErr error
+	err := svc.ScanPages(params, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		holders, unmarshalErr = appendTokenHolders(holders, page.Items)
+		return unmarshalErr == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	return holders, nil
+}
+
+func appendTokenHolders(holders []*TokenHolder, items []map[string]*dynamodb.AttributeValue) ([]*TokenHolder, error) {
+	for _, item := range items {
+		tokenHolder := &TokenHolder{}
+		err := dynamodbattribute.UnmarshalMap(item, tokenHolder)
+		if err != nil {
+			return holders, err
+		}
+		holders = append(holders, tokenHolder)
+	}
+	return holders, nil
+}
+
+// byEmail sorts token holders by email address.
+type byEmail []*TokenHolder
+
+func (h byEmail) Len() int           { return len(h) }
+func (h byEmail) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h byEmail) Less(i, j int) bool { return h[i].Email < h[j].Email }
+
+// GetCeremony implements CeremonyStore.
+func (s *DynamoDBStore) GetCeremony(clusterID, id string) (*Ceremony, error) {
+	svc := NewDynamoDBClient()
+
+	params := &dynamodb.GetItemInput{
+		Key:            ceremonyItemKey(clusterID, id),
+		TableName:      aws.String(CeremonyTableName()),
+		ConsistentRead: aws.Bool(true),
+	}
+
+	resp, err := svc.GetItem(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Item) == 0 {
+		return nil, ErrCeremonyNotFound
+	}
+
+	c := &Ceremony{}
+	err = dynamodbattribute.UnmarshalMap(resp.Item, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// ListCeremonies implements CeremonyStore. The cluster id is the table's hash
+// key, so this is a consistent query.
+func (s *DynamoDBStore) ListCeremonies(clusterID string) ([]*Ceremony, error) {
+	svc := NewDynamoDBClient()
+
+	params := &dynamodb.QueryInput{
+		TableName:              aws.String(CeremonyTableName()),
+		ConsistentRead:         aws.Bool(true),
+		KeyConditionExpression: aws.String("#clusterId = :clusterId"),
+		ExpressionAttributeNames: map[string]*string{
+			"#clusterId": aws.String(clusterIDAttrNm),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":clusterId": {
+				S: aws.String(clusterID),
+			},
+		},
+	}
+
+	ceremonies := []*Ceremony{}
+	var unmarshalErr error
+	err := svc.QueryPages(params, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			c := &Ceremony{}
+			unmarshalErr = dynamodbattribute.UnmarshalMap(item, c)
+			if unmarshalErr != nil {
+				return false
+			}
+			ceremonies = append(ceremonies, c)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	sortCeremonies(ceremonies)
+	return ceremonies, nil
+}
+
+// PutCeremony implements CeremonyStore. The write is conditional on the
+// ceremony's version, like PutInitRecord.
+func (s *DynamoDBStore) PutCeremony(c, prev *Ceremony) error {
+	next := *c
+	next.Version = nextCeremonyVersion(prev)
+
+	item, err := dynamodbattribute.MarshalMap(&next)
+	if err != nil {
+		return err
+	}
+
+	params := &dynamodb.PutItemInput{
+		TableName: aws.String(CeremonyTableName()),
+		Item:      item,
+	}
+	if prev == nil {
+		params.ConditionExpression = aws.String("attribute_not_exists(#clusterId)")
+		params.ExpressionAttributeNames = map[string]*string{
+			"#clusterId": aws.String(clusterIDAttrNm),
+		}
+	} else {
+		params.ConditionExpression, params.ExpressionAttributeNames, params.ExpressionAttributeValues = initRecordVersionCondition(prev.Version)
+	}
+
+	svc := NewDynamoDBClient()
+	_, err = svc.PutItem(params)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "ConditionalCheckFailedException" {
+			return ErrCeremonyConflict
+		}
+		return err
+	}
+
+	c.Version = next.Version
+	return nil
+}
+
+// ceremonyItemKey maps a cluster and ceremony id to the ceremony table's
+// primary key.
+func ceremonyItemKey(clusterID, id string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		clusterIDAttrNm: {
+			S: aws.String(clusterID),
+		},
+		ceremonyIDAttrNm: {
+			S: aws.String(id),
+		},
+	}
+}
+
+// CreateCeremonyTable creates the table named by CeremonyTableName, keyed by
+// cluster id and ceremony id.
+func CreateCeremonyTable() error {
+	svc := NewDynamoDBClient()
+
+	params := &dynamodb.CreateTableInput{
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String(clusterIDAttrNm),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String(ceremonyIDAttrNm),
+				AttributeType: aws.String("S"),
+			},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String(clusterIDAttrNm),
+				KeyType:       aws.String("HASH"),
+			},
+			{
+				AttributeName: aws.String(ceremonyIDAttrNm),
+				KeyType:       aws.String("RANGE"),
+			},
+		},
+		TableName: aws.String(CeremonyTableName()),
+	}
+
+	return createTable(svc, params)
+}
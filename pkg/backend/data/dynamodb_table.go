@@ -0,0 +1,170 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/cdwlabs/armor/pkg/config"
+)
+
+// The billing modes of the DynamoDB tables Armor creates, which may be
+// selected through Armor's dynamodb_billing_mode configuration.
+const (
+	ProvisionedBillingMode string = "provisioned"
+	OnDemandBillingMode    string = "on_demand"
+)
+
+// ErrBillingModeUnknown is returned when dynamodb_billing_mode names no known
+// billing mode.
+var ErrBillingModeUnknown = errors.New("unknown dynamodb billing mode")
+
+// TableOptions are the configurable settings of the DynamoDB tables Armor
+// creates. They only apply when a table is created.
+type TableOptions struct {
+	BillingMode   string // provisioned or on_demand
+	ReadCapacity  int64  // provisioned read capacity units, of the table and its indexes
+	WriteCapacity int64  // provisioned write capacity units, of the table and its indexes
+	PITR          bool   // enable point-in-time-recovery
+	KMSKeyID      string // KMS key encrypting the table, instead of a DynamoDB owned key
+}
+
+// NewTableOptions returns the TableOptions set by Armor's dynamodb_*
+// configuration.
+func NewTableOptions() (TableOptions, error) {
+	opts := TableOptions{
+		BillingMode:   config.Config().GetString("dynamodb_billing_mode"),
+		ReadCapacity:  config.Config().GetInt64("dynamodb_read_capacity"),
+		WriteCapacity: config.Config().GetInt64("dynamodb_write_capacity"),
+		PITR:          config.Config().GetBool("dynamodb_pitr"),
+		KMSKeyID:      config.Config().GetString("dynamodb_kms_key_id"),
+	}
+
+	switch opts.BillingMode {
+	case ProvisionedBillingMode, "":
+	case OnDemandBillingMode:
+	default:
+		return TableOptions{}, fmt.Errorf("%s: %q", ErrBillingModeUnknown.Error(), opts.BillingMode)
+	}
+
+	return opts, nil
+}
+
+// createTableInput is dynamodb.CreateTableInput, plus the table settings
+// DynamoDB added after the vendored SDK was released. The SDK's JSON-RPC
+// protocol marshals it like its own input shapes.
+type createTableInput struct {
+	_ struct{} `type:"structure"`
+
+	AttributeDefinitions   []*dynamodb.AttributeDefinition  `type:"list" required:"true"`
+	BillingMode            *string                          `type:"string"`
+	GlobalSecondaryIndexes []*dynamodb.GlobalSecondaryIndex `type:"list"`
+	KeySchema              []*dynamodb.KeySchemaElement     `min:"1" type:"list" required:"true"`
+	ProvisionedThroughput  *dynamodb.ProvisionedThroughput  `type:"structure"`
+	SSESpecification       *sseSpecification                `type:"structure"`
+	TableName              *string                          `min:"3" type:"string" required:"true"`
+}
+
+type sseSpecification struct {
+	_ struct{} `type:"structure"`
+
+	Enabled        *bool   `type:"boolean"`
+	KMSMasterKeyID *string `locationName:"KMSMasterKeyId" type:"string"`
+	SSEType        *string `type:"string"`
+}
+
+type updateContinuousBackupsInput struct {
+	_ struct{} `type:"structure"`
+
+	PointInTimeRecoverySpecification *pointInTimeRecoverySpecification `type:"structure" required:"true"`
+	TableName                        *string                           `min:"3" type:"string" required:"true"`
+}
+
+type pointInTimeRecoverySpecification struct {
+	_ struct{} `type:"structure"`
+
+	PointInTimeRecoveryEnabled *bool `type:"boolean" required:"true"`
+}
+
+// newCreateTableInput applies opts to the table described by params.
+func newCreateTableInput(params *dynamodb.CreateTableInput, opts TableOptions) *createTableInput {
+	input := &createTableInput{
+		AttributeDefinitions:   params.AttributeDefinitions,
+		GlobalSecondaryIndexes: params.GlobalSecondaryIndexes,
+		KeySchema:              params.KeySchema,
+		TableName:              params.TableName,
+	}
+
+	if opts.BillingMode == OnDemandBillingMode {
+		input.BillingMode = aws.String("PAY_PER_REQUEST")
+		for _, index := range input.GlobalSecondaryIndexes {
+			index.ProvisionedThroughput = nil
+		}
+	} else {
+		// BillingMode is left out, so DynamoDB Local releases predating
+		// on-demand billing still accept the request
+		input.ProvisionedThroughput = &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(opts.ReadCapacity),
+			WriteCapacityUnits: aws.Int64(opts.WriteCapacity),
+		}
+		for _, index := range input.GlobalSecondaryIndexes {
+			index.ProvisionedThroughput = &dynamodb.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(opts.ReadCapacity),
+				WriteCapacityUnits: aws.Int64(opts.WriteCapacity),
+			}
+		}
+	}
+
+	if opts.KMSKeyID != "" {
+		input.SSESpecification = &sseSpecification{
+			Enabled:        aws.Bool(true),
+			KMSMasterKeyID: aws.String(opts.KMSKeyID),
+			SSEType:        aws.String("KMS"),
+		}
+	}
+
+	return input
+}
+
+// createTable creates the table described by params, with the settings of
+// Armor's dynamodb_* configuration, and waits until it exists.
+// Point-in-time-recovery is enabled once the table is active.
+func createTable(svc *dynamodb.DynamoDB, params *dynamodb.CreateTableInput) error {
+	opts, err := NewTableOptions()
+	if err != nil {
+		return err
+	}
+
+	op := &request.Operation{
+		Name:       "CreateTable",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+	err = svc.NewRequest(op, newCreateTableInput(params, opts), &dynamodb.CreateTableOutput{}).Send()
+	if err != nil {
+		return err
+	}
+
+	waitParams := &dynamodb.DescribeTableInput{
+		TableName: params.TableName,
+	}
+
+	err = svc.WaitUntilTableExists(waitParams)
+	if err != nil || !opts.PITR {
+		return err
+	}
+
+	op = &request.Operation{
+		Name:       "UpdateContinuousBackups",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+	input := &updateContinuousBackupsInput{
+		PointInTimeRecoverySpecification: &pointInTimeRecoverySpecification{
+			PointInTimeRecoveryEnabled: aws.Bool(true),
+		},
+		TableName: params.TableName,
+	}
+	return svc.NewRequest(op, input, &struct{}{}).Send()
+}
@@ -0,0 +1,79 @@
+package data
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func testTableParams() *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String(emailAttrNm),
+				AttributeType: aws.String("S"),
+			},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String(clusterIDAttrNm),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String(emailIndexNm),
+			},
+		},
+		TableName: aws.String("StageTokenHolders"),
+	}
+}
+
+func TestNewTableOptions(t *testing.T) {
+	opts, err := NewTableOptions()
+	assert.NoError(t, err, "not expecting an error with default table options")
+	assert.Equal(t, ProvisionedBillingMode, opts.BillingMode, "expecting provisioned billing by default")
+	assert.Equal(t, int64(1), opts.ReadCapacity, "expecting a read capacity of 1 by default")
+	assert.Equal(t, int64(1), opts.WriteCapacity, "expecting a write capacity of 1 by default")
+
+	os.Setenv(config.DynamoDBBillingModeEnvVar, "free")
+	defer os.Unsetenv(config.DynamoDBBillingModeEnvVar)
+	_, err = NewTableOptions()
+	assert.Error(t, err, "expecting an error with an unknown billing mode")
+}
+
+func TestNewCreateTableInput_Provisioned(t *testing.T) {
+	opts := TableOptions{
+		BillingMode:   ProvisionedBillingMode,
+		ReadCapacity:  5,
+		WriteCapacity: 2,
+	}
+	input := newCreateTableInput(testTableParams(), opts)
+
+	assert.Nil(t, input.BillingMode, "not expecting a billing mode for provisioned tables")
+	assert.Equal(t, int64(5), aws.Int64Value(input.ProvisionedThroughput.ReadCapacityUnits), "expecting the configured read capacity")
+	assert.Equal(t, int64(2), aws.Int64Value(input.ProvisionedThroughput.WriteCapacityUnits), "expecting the configured write capacity")
+	assert.Equal(t, int64(5), aws.Int64Value(input.GlobalSecondaryIndexes[0].ProvisionedThroughput.ReadCapacityUnits), "expecting the index to get the configured read capacity")
+	assert.Nil(t, input.SSESpecification, "not expecting sse without a kms key")
+}
+
+func TestNewCreateTableInput_OnDemand(t *testing.T) {
+	opts := TableOptions{
+		BillingMode: OnDemandBillingMode,
+		KMSKeyID:    "alias/armor",
+	}
+	input := newCreateTableInput(testTableParams(), opts)
+
+	assert.Nil(t, input.ProvisionedThroughput, "not expecting throughput for on-demand tables")
+	assert.Nil(t, input.GlobalSecondaryIndexes[0].ProvisionedThroughput, "not expecting index throughput for on-demand tables")
+
+	b, err := jsonutil.BuildJSON(input)
+	assert.NoError(t, err, "not expecting an error when marshaling create table input")
+	assert.Contains(t, string(b), `"BillingMode":"PAY_PER_REQUEST"`, "expecting on-demand billing to be requested")
+	assert.Contains(t, string(b), `"SSESpecification":{"Enabled":true,"KMSMasterKeyId":"alias/armor","SSEType":"KMS"}`, "expecting kms encryption to be requested")
+	assert.Contains(t, string(b), `"TableName":"StageTokenHolders"`, "expecting the table name")
+}
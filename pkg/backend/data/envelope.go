@@ -0,0 +1,303 @@
+package data
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrTokenEncryptionUnknown is returned when token_encryption names no known
+// KeyProvider implementation.
+var ErrTokenEncryptionUnknown = errors.New("unknown token encryption")
+
+// The KeyProvider implementations which may be selected through Armor's
+// token_encryption configuration. Tokens are kept in plaintext if
+// token_encryption is empty.
+const (
+	KMSTokenEncryption  string = "kms"
+	FileTokenEncryption string = "file"
+)
+
+// KeyProvider generates and decrypts the data keys used to envelope encrypt
+// tokens. Data keys are encrypted by a master key, which never leaves the
+// provider.
+type KeyProvider interface {
+	// GenerateDataKey returns a new 256-bit data key, both in plaintext and
+	// encrypted by the current master key, together with the id of that
+	// master key.
+	GenerateDataKey() (keyID string, plaintext, encrypted []byte, err error)
+
+	// DecryptDataKey decrypts a data key encrypted by the master key with the
+	// given id.
+	DecryptDataKey(keyID string, encrypted []byte) ([]byte, error)
+}
+
+// EncryptedStore is a TokenHolderStore which envelope encrypts tokens before
+// they reach another TokenHolderStore: each token is encrypted by its own data
+// key, which is kept, encrypted by a KeyProvider's master key, alongside the
+// token. Token holders without a KeyID are passed through in plaintext, so
+// tokens persisted before encryption was enabled remain readable until they
+// are re-encrypted.
+type EncryptedStore struct {
+	store TokenHolderStore
+	keys  KeyProvider
+}
+
+// NewEncryptedStore creates an EncryptedStore on top of store.
+func NewEncryptedStore(store TokenHolderStore, keys KeyProvider) *EncryptedStore {
+	return &EncryptedStore{store: store, keys: keys}
+}
+
+// MetadataStore returns the store to read token holders from when their
+// tokens aren't needed: the store beneath an EncryptedStore, whose tokens are
+// left encrypted rather than decrypted with a data key per token holder, or
+// else store itself.
+func MetadataStore(store TokenHolderStore) TokenHolderStore {
+	if encrypted, ok := store.(*EncryptedStore); ok {
+		return encrypted.store
+	}
+	return store
+}
+
+// Put implements TokenHolderStore.
+func (s *EncryptedStore) Put(tokenHolder *TokenHolder) error {
+	encrypted, err := s.encrypt(tokenHolder)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(encrypted)
+}
+
+// Get implements TokenHolderStore.
+func (s *EncryptedStore) Get(key TokenHolderKey) (*TokenHolder, error) {
+	tokenHolder, err := s.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(tokenHolder)
+}
+
+// ListByEmail implements TokenHolderStore.
+func (s *EncryptedStore) ListByEmail(email string) ([]*TokenHolder, error) {
+	holders, err := s.store.ListByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptAll(holders)
+}
+
+// ListByCluster implements TokenHolderStore.
+func (s *EncryptedStore) ListByCluster(clusterID string) ([]*TokenHolder, error) {
+	holders, err := s.store.ListByCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptAll(holders)
+}
+
+// List implements TokenHolderStore.
+func (s *EncryptedStore) List() ([]*TokenHolder, error) {
+	holders, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptAll(holders)
+}
+
+// Delete implements TokenHolderStore.
+func (s *EncryptedStore) Delete(key TokenHolderKey) error {
+	return s.store.Delete(key)
+}
+
+// EnsureSchema implements TokenHolderStore.
+func (s *EncryptedStore) EnsureSchema() error {
+	return s.store.EnsureSchema()
+}
+
+// GetInitRecord implements InitRecordStore. Init records hold no secrets, so
+// they are kept as is.
+func (s *EncryptedStore) GetInitRecord(clusterID string) (*InitRecord, error) {
+	return s.store.GetInitRecord(clusterID)
+}
+
+// PutInitRecord implements InitRecordStore.
+func (s *EncryptedStore) PutInitRecord(rec, prev *InitRecord) error {
+	return s.store.PutInitRecord(rec, prev)
+}
+
+// DeleteInitRecord implements InitRecordStore.
+func (s *EncryptedStore) DeleteInitRecord(rec *InitRecord) error {
+	return s.store.DeleteInitRecord(rec)
+}
+
+// GetCeremony implements CeremonyStore. Ceremonies hold no secrets, so they
+// are passed through.
+func (s *EncryptedStore) GetCeremony(clusterID, id string) (*Ceremony, error) {
+	return s.store.GetCeremony(clusterID, id)
+}
+
+// ListCeremonies implements CeremonyStore.
+func (s *EncryptedStore) ListCeremonies(clusterID string) ([]*Ceremony, error) {
+	return s.store.ListCeremonies(clusterID)
+}
+
+// PutCeremony implements CeremonyStore.
+func (s *EncryptedStore) PutCeremony(c, prev *Ceremony) error {
+	return s.store.PutCeremony(c, prev)
+}
+
+// MigrateLegacy implements TokenHolderMigrator. The legacy token holders,
+// which were kept in plaintext, are encrypted once they are migrated.
+func (s *EncryptedStore) MigrateLegacy(clusterID string) (int, error) {
+	migrator, ok := s.store.(TokenHolderMigrator)
+	if !ok {
+		return 0, fmt.Errorf("token holder store %T has no legacy token holders to migrate", s.store)
+	}
+
+	n, err := migrator.MigrateLegacy(clusterID)
+	if err != nil {
+		return n, err
+	}
+
+	_, err = s.ReEncrypt()
+	return n, err
+}
+
+// ReEncrypt encrypts every token with a new data key under the current master
+// key, and returns the number of token holders re-encrypted. Use it after
+// rotating the master key, while the previous master key is still available
+// for decryption, or to encrypt tokens persisted in plaintext.
+func (s *EncryptedStore) ReEncrypt() (int, error) {
+	holders, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, tokenHolder := range holders {
+		err = s.Put(tokenHolder)
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(holders), nil
+}
+
+// encrypt returns a copy of tokenHolder whose token is encrypted by a new
+// data key. The token is bound to the token holder's key, so an encrypted
+// token copied to another record fails to decrypt.
+func (s *EncryptedStore) encrypt(tokenHolder *TokenHolder) (*TokenHolder, error) {
+	encrypted := *tokenHolder
+	encrypted.KeyID = ""
+	encrypted.DataKey = ""
+	if tokenHolder.Token == "" {
+		return &encrypted, nil
+	}
+
+	keyID, dataKey, encryptedDataKey, err := s.keys.GenerateDataKey()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := seal(dataKey, []byte(tokenHolder.Token), tokenHolderAAD(tokenHolder))
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted.Token = base64.StdEncoding.EncodeToString(token)
+	encrypted.KeyID = keyID
+	encrypted.DataKey = base64.StdEncoding.EncodeToString(encryptedDataKey)
+	return &encrypted, nil
+}
+
+// decrypt returns tokenHolder with its token decrypted. KeyID is kept, so
+// callers can tell which master key protects the token.
+func (s *EncryptedStore) decrypt(tokenHolder *TokenHolder) (*TokenHolder, error) {
+	if tokenHolder.KeyID == "" {
+		return tokenHolder, nil
+	}
+
+	encryptedDataKey, err := base64.StdEncoding.DecodeString(tokenHolder.DataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := s.keys.DecryptDataKey(tokenHolder.KeyID, encryptedDataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := base64.StdEncoding.DecodeString(tokenHolder.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := open(dataKey, token, tokenHolderAAD(tokenHolder))
+	if err != nil {
+		return nil, err
+	}
+
+	tokenHolder.Token = string(plaintext)
+	tokenHolder.DataKey = ""
+	return tokenHolder, nil
+}
+
+func (s *EncryptedStore) decryptAll(holders []*TokenHolder) ([]*TokenHolder, error) {
+	for i, tokenHolder := range holders {
+		decrypted, err := s.decrypt(tokenHolder)
+		if err != nil {
+			return nil, err
+		}
+		holders[i] = decrypted
+	}
+	return holders, nil
+}
+
+func tokenHolderAAD(tokenHolder *TokenHolder) []byte {
+	key := tokenHolder.Key()
+	return []byte(key.ClusterID + "/" + key.String())
+}
+
+// seal encrypts plaintext with AES-256-GCM. The random nonce is prepended to
+// the ciphertext.
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// open decrypts ciphertext produced by seal.
+func open(key, ciphertext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce := ciphertext[:gcm.NonceSize()]
+	return gcm.Open(nil, nonce, ciphertext[gcm.NonceSize():], aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,169 @@
+package data
+
+import (
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeMasterKey(t *testing.T, dir, name string, b byte) string {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = b
+	}
+
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600)
+	assert.NoError(t, err, "not expecting an error when writing master key")
+	return path
+}
+
+func TestEncryptedStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "armor-keys")
+	assert.NoError(t, err, "not expecting an error when creating key dir")
+	defer os.RemoveAll(dir)
+
+	oldKey := writeMasterKey(t, dir, "old.key", 1)
+	newKey := writeMasterKey(t, dir, "new.key", 2)
+
+	oldKeys, err := NewFileKeyProvider(oldKey)
+	if !assert.NoError(t, err, "not expecting an error when reading master key") {
+		return
+	}
+
+	inner := NewMemoryStore()
+	store := NewEncryptedStore(inner, oldKeys)
+
+	root := NewTokenHolder()
+	root.ClusterID = "packers"
+	root.Email = "aaron.rodgers@packers.com"
+	root.Token = "5f4dcc3b5aa765d61d8327deb882cf99"
+	root.TokenType = RootTokenType
+	err = store.Put(root)
+	assert.NoError(t, err, "not expecting an error when putting root token holder")
+	assert.Equal(t, "", root.KeyID, "expecting the caller's token holder to be left alone")
+
+	raw, err := inner.Get(root.Key())
+	assert.NoError(t, err, "not expecting an error when getting raw token holder")
+	assert.NotEqual(t, root.Token, raw.Token, "expecting the token to be encrypted at rest")
+	assert.True(t, strings.HasPrefix(raw.KeyID, "file:"), "expecting the master key id in the record")
+	assert.NotEmpty(t, raw.DataKey, "expecting the encrypted data key in the record")
+	oldKeyID := raw.KeyID
+
+	found, err := store.Get(root.Key())
+	assert.NoError(t, err, "not expecting an error when getting root token holder")
+	assert.Equal(t, root.Token, found.Token, "expecting the token to be decrypted")
+
+	// an encrypted token moved to another record must not decrypt
+	moved := *raw
+	moved.TokenType = UnsealTokenType
+	err = inner.Put(&moved)
+	assert.NoError(t, err, "not expecting an error when putting raw token holder")
+	_, err = store.Get(moved.Key())
+	assert.Error(t, err, "expecting an error when decrypting a moved token")
+	inner.Delete(moved.Key())
+
+	// tokens persisted before encryption was enabled pass through
+	plain := NewTokenHolder()
+	plain.ClusterID = "packers"
+	plain.Email = "david.bakhtiari@packers.com"
+	plain.Token = "7c6a180b36896a0a8c02787eeafb0e4c"
+	plain.TokenType = UnsealTokenType
+	err = inner.Put(plain)
+	assert.NoError(t, err, "not expecting an error when putting plaintext token holder")
+	found, err = store.Get(plain.Key())
+	assert.NoError(t, err, "not expecting an error when getting plaintext token holder")
+	assert.Equal(t, plain.Token, found.Token, "expecting the plaintext token")
+
+	// rotate the master key
+	rotated, err := NewFileKeyProvider(newKey, oldKey)
+	if !assert.NoError(t, err, "not expecting an error when reading master keys") {
+		return
+	}
+	store = NewEncryptedStore(inner, rotated)
+	n, err := store.ReEncrypt()
+	assert.NoError(t, err, "not expecting an error when re-encrypting")
+	assert.Equal(t, 2, n, "expecting every token holder to be re-encrypted")
+
+	holders, err := inner.List()
+	assert.NoError(t, err, "not expecting an error when listing raw token holders")
+	for _, h := range holders {
+		assert.NotEqual(t, oldKeyID, h.KeyID, "expecting the new master key id")
+		assert.True(t, strings.HasPrefix(h.KeyID, "file:"), "expecting every token to be encrypted")
+	}
+
+	newKeys, err := NewFileKeyProvider(newKey)
+	if !assert.NoError(t, err, "not expecting an error when reading master key") {
+		return
+	}
+	holders, err = NewEncryptedStore(inner, newKeys).List()
+	assert.NoError(t, err, "not expecting an error when decrypting without the old master key")
+	if assert.Len(t, holders, 2, "expecting both token holders") {
+		assert.Equal(t, root.Token, holders[0].Token, "expecting the root token")
+		assert.Equal(t, plain.Token, holders[1].Token, "expecting the unseal token")
+	}
+
+	_, err = NewEncryptedStore(inner, oldKeys).List()
+	assert.Error(t, err, "expecting an error when the master key is missing")
+}
+
+// countingKeyProvider counts the data keys decrypted by a KeyProvider.
+type countingKeyProvider struct {
+	KeyProvider
+	decrypted int
+}
+
+func (p *countingKeyProvider) DecryptDataKey(keyID string, encrypted []byte) ([]byte, error) {
+	p.decrypted++
+	return p.KeyProvider.DecryptDataKey(keyID, encrypted)
+}
+
+func TestMetadataStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "armor-keys")
+	assert.NoError(t, err, "not expecting an error when creating key dir")
+	defer os.RemoveAll(dir)
+
+	fileKeys, err := NewFileKeyProvider(writeMasterKey(t, dir, "master.key", 1))
+	if !assert.NoError(t, err, "not expecting an error when reading master key") {
+		return
+	}
+	keys := &countingKeyProvider{KeyProvider: fileKeys}
+
+	inner := NewMemoryStore()
+	store := NewEncryptedStore(inner, keys)
+	root := NewTokenHolder()
+	root.ClusterID = "packers"
+	root.Email = "aaron.rodgers@packers.com"
+	root.Token = "5f4dcc3b5aa765d61d8327deb882cf99"
+	root.TokenType = RootTokenType
+	err = store.Put(root)
+	assert.NoError(t, err, "not expecting an error when putting root token holder")
+
+	holders, err := MetadataStore(store).List()
+	assert.NoError(t, err, "not expecting an error when listing token holders")
+	if assert.Len(t, holders, 1, "expecting the root token holder") {
+		assert.Equal(t, root.Email, holders[0].Email, "expecting the token holder's metadata")
+		assert.NotEqual(t, root.Token, holders[0].Token, "expecting the token to stay encrypted")
+	}
+	assert.Equal(t, 0, keys.decrypted, "not expecting data keys to be decrypted")
+
+	assert.Equal(t, inner, MetadataStore(inner), "expecting an unencrypted store to be read as is")
+}
+
+func TestNewFileKeyProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "armor-keys")
+	assert.NoError(t, err, "not expecting an error when creating key dir")
+	defer os.RemoveAll(dir)
+
+	short := filepath.Join(dir, "short.key")
+	ioutil.WriteFile(short, []byte(base64.StdEncoding.EncodeToString([]byte("too short"))), 0600)
+	_, err = NewFileKeyProvider(short)
+	assert.Error(t, err, "expecting an error for a short master key")
+
+	_, err = NewFileKeyProvider(filepath.Join(dir, "missing.key"))
+	assert.Error(t, err, "expecting an error for a missing master key")
+}
@@ -0,0 +1,96 @@
+package data
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// master key errors
+var (
+	ErrMasterKeyInvalid  = errors.New("master key must be 32 base64 encoded bytes")
+	ErrMasterKeyNotFound = errors.New("master key not found")
+)
+
+// FileKeyProvider is a KeyProvider whose master keys are read from local
+// files, for on-prem installs and testing. Each file holds a base64 encoded,
+// 32 byte key, e.g. created by:
+//
+//	head -c 32 /dev/urandom | base64 > armor.key
+//
+// A master key is identified by "file:" and the start of its SHA-256.
+type FileKeyProvider struct {
+	current string
+	keys    map[string][]byte
+}
+
+// NewFileKeyProvider reads the current master key from path. Data keys
+// encrypted by any of the previous master keys may still be decrypted, which
+// allows tokens to be re-encrypted after the master key is rotated.
+func NewFileKeyProvider(path string, previous ...string) (*FileKeyProvider, error) {
+	p := &FileKeyProvider{keys: make(map[string][]byte)}
+
+	for _, prev := range previous {
+		_, err := p.add(prev)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := p.add(path)
+	if err != nil {
+		return nil, err
+	}
+	p.current = current
+
+	return p, nil
+}
+
+func (p *FileKeyProvider) add(path string) (string, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil || len(key) != 32 {
+		return "", fmt.Errorf("%s: %s", path, ErrMasterKeyInvalid.Error())
+	}
+
+	sum := sha256.Sum256(key)
+	id := "file:" + hex.EncodeToString(sum[:8])
+	p.keys[id] = key
+	return id, nil
+}
+
+// GenerateDataKey implements KeyProvider.
+func (p *FileKeyProvider) GenerateDataKey() (string, []byte, []byte, error) {
+	dataKey := make([]byte, 32)
+	_, err := io.ReadFull(rand.Reader, dataKey)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	encrypted, err := seal(p.keys[p.current], dataKey, []byte(p.current))
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return p.current, dataKey, encrypted, nil
+}
+
+// DecryptDataKey implements KeyProvider.
+func (p *FileKeyProvider) DecryptDataKey(keyID string, encrypted []byte) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %q", ErrMasterKeyNotFound.Error(), keyID)
+	}
+
+	return open(key, encrypted, []byte(keyID))
+}
@@ -0,0 +1,407 @@
+package data
+
+import (
+	"encoding/json"
+	"github.com/boltdb/bolt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// token holders are kept in a bucket per cluster, keyed by token type and
+// share index. Bucket names are fixed, unlike the DynamoDB table names.
+var tokenHoldersBucket = []byte("TokenHolderShares")
+
+// init records are keyed by cluster
+var initRecordsBucket = []byte("InitRecords")
+
+// unseal ceremonies are kept in a bucket per cluster, keyed by id
+var ceremoniesBucket = []byte("UnsealCeremonies")
+
+// FileStore is a TokenHolderStore kept in a local, embedded key/value file
+// (i.e. BoltDB). It lets Armor run without AWS, e.g. on-prem.
+type FileStore struct {
+	db *bolt.DB
+}
+
+// NewFileStore opens (or creates) the store at path. The file is locked for
+// as long as the store is open, so only a single Armor process may use it.
+func NewFileStore(path string) (*FileStore, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileStore{db: db}, nil
+}
+
+// Close releases the store's file.
+func (s *FileStore) Close() error {
+	return s.db.Close()
+}
+
+// Put implements TokenHolderStore.
+func (s *FileStore) Put(tokenHolder *TokenHolder) error {
+	if err := tokenHolder.validateForPut(); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(tokenHolder)
+	if err != nil {
+		return err
+	}
+
+	key := tokenHolder.Key()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(tokenHoldersBucket)
+		if err != nil {
+			return err
+		}
+		cluster, err := b.CreateBucketIfNotExists([]byte(key.ClusterID))
+		if err != nil {
+			return err
+		}
+		return cluster.Put([]byte(key.String()), raw)
+	})
+}
+
+// Get implements TokenHolderStore.
+func (s *FileStore) Get(key TokenHolderKey) (*TokenHolder, error) {
+	var tokenHolder *TokenHolder
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cluster := clusterBucket(tx, key.ClusterID)
+		if cluster == nil {
+			return ErrTokenHolderNotFound
+		}
+
+		raw := cluster.Get([]byte(key.String()))
+		if raw == nil {
+			return ErrTokenHolderNotFound
+		}
+
+		tokenHolder = &TokenHolder{}
+		return json.Unmarshal(raw, tokenHolder)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenHolder, nil
+}
+
+// ListByEmail implements TokenHolderStore.
+func (s *FileStore) ListByEmail(email string) ([]*TokenHolder, error) {
+	return s.list(func(tokenHolder *TokenHolder) bool {
+		return tokenHolder.Email == email
+	})
+}
+
+// ListByCluster implements TokenHolderStore.
+func (s *FileStore) ListByCluster(clusterID string) ([]*TokenHolder, error) {
+	holders := []*TokenHolder{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cluster := clusterBucket(tx, clusterID)
+		if cluster == nil {
+			return nil
+		}
+
+		return cluster.ForEach(func(_, raw []byte) error {
+			tokenHolder := &TokenHolder{}
+			err := json.Unmarshal(raw, tokenHolder)
+			if err != nil {
+				return err
+			}
+			holders = append(holders, tokenHolder)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return holders, nil
+}
+
+// List implements TokenHolderStore.
+func (s *FileStore) List() ([]*TokenHolder, error) {
+	return s.list(func(*TokenHolder) bool { return true })
+}
+
+// list walks every cluster's bucket. Bolt keeps buckets and keys sorted, so
+// token holders come back sorted by key.
+func (s *FileStore) list(match func(*TokenHolder) bool) ([]*TokenHolder, error) {
+	holders := []*TokenHolder{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(tokenHoldersBucket)
+		if b == nil {
+			return nil
+		}
+
+		return b.ForEach(func(clusterID, _ []byte) error {
+			cluster := b.Bucket(clusterID)
+			if cluster == nil {
+				return nil
+			}
+
+			return cluster.ForEach(func(_, raw []byte) error {
+				tokenHolder := &TokenHolder{}
+				err := json.Unmarshal(raw, tokenHolder)
+				if err != nil {
+					return err
+				}
+				if match(tokenHolder) {
+					holders = append(holders, tokenHolder)
+				}
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return holders, nil
+}
+
+// Delete implements TokenHolderStore.
+func (s *FileStore) Delete(key TokenHolderKey) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		cluster := clusterBucket(tx, key.ClusterID)
+		if cluster == nil {
+			return nil
+		}
+		return cluster.Delete([]byte(key.String()))
+	})
+}
+
+// clusterBucket returns the bucket of the given cluster's token holders, or
+// nil if there is none.
+func clusterBucket(tx *bolt.Tx, clusterID string) *bolt.Bucket {
+	b := tx.Bucket(tokenHoldersBucket)
+	if b == nil {
+		return nil
+	}
+	return b.Bucket([]byte(clusterID))
+}
+
+// EnsureSchema implements TokenHolderStore.
+func (s *FileStore) EnsureSchema() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(tokenHoldersBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(initRecordsBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(ceremoniesBucket)
+		return err
+	})
+}
+
+// GetInitRecord implements InitRecordStore.
+func (s *FileStore) GetInitRecord(clusterID string) (*InitRecord, error) {
+	var rec *InitRecord
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		rec, err = currentInitRecord(tx, clusterID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, ErrInitRecordNotFound
+	}
+
+	return rec, nil
+}
+
+// PutInitRecord implements InitRecordStore. Bolt serializes writable
+// transactions, so the check and the write are atomic.
+func (s *FileStore) PutInitRecord(rec, prev *InitRecord) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		current, err := currentInitRecord(tx, rec.ClusterID)
+		if err != nil {
+			return err
+		}
+		if !initRecordMatches(current, prev) {
+			return ErrInitRecordConflict
+		}
+
+		b, err := tx.CreateBucketIfNotExists(initRecordsBucket)
+		if err != nil {
+			return err
+		}
+
+		next := *rec
+		next.Version = nextInitRecordVersion(prev)
+		raw, err := json.Marshal(&next)
+		if err != nil {
+			return err
+		}
+		err = b.Put([]byte(rec.ClusterID), raw)
+		if err != nil {
+			return err
+		}
+
+		rec.Version = next.Version
+		return nil
+	})
+}
+
+// DeleteInitRecord implements InitRecordStore.
+func (s *FileStore) DeleteInitRecord(rec *InitRecord) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		current, err := currentInitRecord(tx, rec.ClusterID)
+		if err != nil {
+			return err
+		}
+		if !initRecordMatches(current, rec) {
+			return ErrInitRecordConflict
+		}
+		return tx.Bucket(initRecordsBucket).Delete([]byte(rec.ClusterID))
+	})
+}
+
+// currentInitRecord returns the cluster's init record, or nil if there is
+// none.
+func currentInitRecord(tx *bolt.Tx, clusterID string) (*InitRecord, error) {
+	b := tx.Bucket(initRecordsBucket)
+	if b == nil {
+		return nil, nil
+	}
+
+	raw := b.Get([]byte(clusterID))
+	if raw == nil {
+		return nil, nil
+	}
+
+	rec := &InitRecord{}
+	err := json.Unmarshal(raw, rec)
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// GetCeremony implements CeremonyStore.
+func (s *FileStore) GetCeremony(clusterID, id string) (*Ceremony, error) {
+	var c *Ceremony
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		c, err = currentCeremony(tx, clusterID, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCeremonyNotFound
+	}
+
+	return c, nil
+}
+
+// ListCeremonies implements CeremonyStore.
+func (s *FileStore) ListCeremonies(clusterID string) ([]*Ceremony, error) {
+	var ceremonies []*Ceremony
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(ceremoniesBucket)
+		if root == nil {
+			return nil
+		}
+		b := root.Bucket([]byte(clusterID))
+		if b == nil {
+			return nil
+		}
+
+		return b.ForEach(func(_, raw []byte) error {
+			var c Ceremony
+			err := json.Unmarshal(raw, &c)
+			if err != nil {
+				return err
+			}
+			ceremonies = append(ceremonies, &c)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortCeremonies(ceremonies)
+	return ceremonies, nil
+}
+
+// PutCeremony implements CeremonyStore, atomically like PutInitRecord.
+func (s *FileStore) PutCeremony(c, prev *Ceremony) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		current, err := currentCeremony(tx, c.ClusterID, c.ID)
+		if err != nil {
+			return err
+		}
+		if !ceremonyMatches(current, prev) {
+			return ErrCeremonyConflict
+		}
+
+		root, err := tx.CreateBucketIfNotExists(ceremoniesBucket)
+		if err != nil {
+			return err
+		}
+		b, err := root.CreateBucketIfNotExists([]byte(c.ClusterID))
+		if err != nil {
+			return err
+		}
+
+		next := *c
+		next.Version = nextCeremonyVersion(prev)
+		raw, err := json.Marshal(&next)
+		if err != nil {
+			return err
+		}
+		err = b.Put([]byte(c.ID), raw)
+		if err != nil {
+			return err
+		}
+
+		c.Version = next.Version
+		return nil
+	})
+}
+
+// currentCeremony returns the cluster's ceremony, or nil if there is none.
+func currentCeremony(tx *bolt.Tx, clusterID, id string) (*Ceremony, error) {
+	root := tx.Bucket(ceremoniesBucket)
+	if root == nil {
+		return nil, nil
+	}
+	b := root.Bucket([]byte(clusterID))
+	if b == nil {
+		return nil, nil
+	}
+
+	raw := b.Get([]byte(id))
+	if raw == nil {
+		return nil, nil
+	}
+
+	var c Ceremony
+	err := json.Unmarshal(raw, &c)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,176 @@
+package data
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/cdwlabs/armor/pkg/config"
+	"golang.org/x/crypto/openpgp"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// holder key errors
+var (
+	ErrHolderKeyNotFound  = errors.New("no PGP key registered for token holder")
+	ErrHolderKeyAmbiguous = errors.New("several PGP keys registered for token holder")
+)
+
+// HolderKey is the PGP public key of a token holder.
+type HolderKey struct {
+	Email       string // token holder's email address, lower case
+	Fingerprint string // upper case hex fingerprint of the primary key
+	Vault       string // base64 encoded binary public key, as Vault accepts in pgp_keys
+}
+
+// HolderKeyRegistry maps token holder email addresses to their PGP public
+// keys, so tokens can be PGP encrypted for their holders by Vault.
+type HolderKeyRegistry interface {
+	// Lookup returns the key of the given email address, or
+	// ErrHolderKeyNotFound.
+	Lookup(email string) (*HolderKey, error)
+}
+
+// DirKeyRegistry is a HolderKeyRegistry read from a local keyring directory.
+// Every file in the directory holds one or more armored (or binary) PGP public
+// keys, e.g. as exported by `gpg --armor --export`. Keys are registered under
+// the email addresses of their user ids; file names don't matter.
+type DirKeyRegistry struct {
+	keys map[string]*HolderKey
+}
+
+// NewDirKeyRegistry reads every key in dir. Sub-directories and hidden files
+// are skipped.
+func NewDirKeyRegistry(dir string) (*DirKeyRegistry, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &DirKeyRegistry{keys: make(map[string]*HolderKey)}
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, f.Name())
+		err = r.addFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err.Error())
+		}
+	}
+
+	return r, nil
+}
+
+func (r *DirKeyRegistry) addFile(path string) error {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	entities, err := readKeyRing(raw)
+	if err != nil {
+		return err
+	}
+
+	for _, entity := range entities {
+		var buf bytes.Buffer
+		err = entity.Serialize(&buf)
+		if err != nil {
+			return err
+		}
+
+		fingerprint := strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint[:]))
+		for _, id := range entity.Identities {
+			email := strings.ToLower(id.UserId.Email)
+			if email == "" {
+				continue
+			}
+
+			if existing, ok := r.keys[email]; ok && existing.Fingerprint != fingerprint {
+				return fmt.Errorf("%s: %s", ErrHolderKeyAmbiguous.Error(), email)
+			}
+
+			r.keys[email] = &HolderKey{
+				Email:       email,
+				Fingerprint: fingerprint,
+				Vault:       base64.StdEncoding.EncodeToString(buf.Bytes()),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Lookup implements HolderKeyRegistry. Email addresses are matched case
+// insensitively.
+func (r *DirKeyRegistry) Lookup(email string) (*HolderKey, error) {
+	key, ok := r.keys[strings.ToLower(email)]
+	if !ok {
+		return nil, fmt.Errorf("%s: %s", ErrHolderKeyNotFound.Error(), email)
+	}
+	return key, nil
+}
+
+// PGPKeyFingerprint returns the fingerprint of a key as accepted by Vault's
+// pgp_keys, i.e. a base64 encoded binary public key, or "keybase:<user>".
+// Keybase users are returned as is, since their key is fetched by Vault. An
+// empty string is returned if the key can't be read.
+func PGPKeyFingerprint(key string) string {
+	if strings.HasPrefix(key, "keybase:") {
+		return key
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return ""
+	}
+
+	entities, err := readKeyRing(raw)
+	if err != nil || len(entities) == 0 {
+		return ""
+	}
+
+	return strings.ToUpper(hex.EncodeToString(entities[0].PrimaryKey.Fingerprint[:]))
+}
+
+// readKeyRing reads armored or binary public keys.
+func readKeyRing(raw []byte) (openpgp.EntityList, error) {
+	if bytes.Contains(raw, []byte("-----BEGIN PGP")) {
+		return openpgp.ReadArmoredKeyRing(bytes.NewReader(raw))
+	}
+	return openpgp.ReadKeyRing(bytes.NewReader(raw))
+}
+
+var (
+	defaultHolderKeys   HolderKeyRegistry
+	defaultHolderKeysMu sync.Mutex
+)
+
+// HolderKeys returns the HolderKeyRegistry read from Armor's pgp_keyring_dir.
+// It returns nil if pgp_keyring_dir isn't set. The registry is read the first
+// time HolderKeys is called.
+func HolderKeys() (HolderKeyRegistry, error) {
+	defaultHolderKeysMu.Lock()
+	defer defaultHolderKeysMu.Unlock()
+
+	if defaultHolderKeys == nil {
+		dir := config.Config().GetString("pgp_keyring_dir")
+		if dir == "" {
+			return nil, nil
+		}
+
+		registry, err := NewDirKeyRegistry(dir)
+		if err != nil {
+			return nil, err
+		}
+		defaultHolderKeys = registry
+	}
+
+	return defaultHolderKeys, nil
+}
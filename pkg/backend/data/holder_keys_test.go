@@ -0,0 +1,67 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeHolderKey(t *testing.T, path, name, email string) {
+	entity, err := openpgp.NewEntity(name, "", email, nil)
+	if !assert.NoError(t, err, "not expecting an error when generating a PGP key") {
+		return
+	}
+
+	// self-sign the user id, which only happens on serializing the private key
+	err = entity.SerializePrivate(ioutil.Discard, nil)
+	assert.NoError(t, err, "not expecting an error when signing key")
+
+	f, err := os.Create(path)
+	if !assert.NoError(t, err, "not expecting an error when creating key file") {
+		return
+	}
+	defer f.Close()
+
+	w, err := armor.Encode(f, openpgp.PublicKeyType, nil)
+	if !assert.NoError(t, err, "not expecting an error when armoring key") {
+		return
+	}
+	assert.NoError(t, entity.Serialize(w), "not expecting an error when serializing key")
+	assert.NoError(t, w.Close(), "not expecting an error when closing armor")
+}
+
+func TestDirKeyRegistry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "armor-keyring")
+	assert.NoError(t, err, "not expecting an error when creating keyring dir")
+	defer os.RemoveAll(dir)
+
+	writeHolderKey(t, filepath.Join(dir, "rodgers.asc"), "Aaron Rodgers", "Aaron.Rodgers@packers.com")
+	writeHolderKey(t, filepath.Join(dir, "bakhtiari.asc"), "David Bakhtiari", "david.bakhtiari@packers.com")
+	os.Mkdir(filepath.Join(dir, "archive"), 0700)
+
+	registry, err := NewDirKeyRegistry(dir)
+	if !assert.NoError(t, err, "not expecting an error when reading keyring dir") {
+		return
+	}
+
+	key, err := registry.Lookup("aaron.rodgers@packers.com")
+	if assert.NoError(t, err, "expecting email addresses to match case insensitively") {
+		assert.Len(t, key.Fingerprint, 40, "expecting a hex fingerprint")
+		assert.Equal(t, key.Fingerprint, PGPKeyFingerprint(key.Vault), "expecting Vault's key to carry the same fingerprint")
+	}
+
+	_, err = registry.Lookup("bryan.bulaga@packers.com")
+	assert.Error(t, err, "expecting an error for an unregistered email address")
+
+	assert.Equal(t, "keybase:arodgers", PGPKeyFingerprint("keybase:arodgers"), "expecting keybase users as is")
+	assert.Equal(t, "", PGPKeyFingerprint("not a key"), "expecting no fingerprint for an unreadable key")
+
+	// a second key for the same email address is ambiguous
+	writeHolderKey(t, filepath.Join(dir, "rodgers-new.asc"), "Aaron Rodgers", "aaron.rodgers@packers.com")
+	_, err = NewDirKeyRegistry(dir)
+	assert.Error(t, err, "expecting an error for several keys of the same email address")
+}
@@ -0,0 +1,82 @@
+package data
+
+import (
+	"errors"
+	"time"
+)
+
+// initRecordVersionAttrNm is the DynamoDB attribute holding
+// InitRecord.Version.
+const initRecordVersionAttrNm string = "version"
+
+// init record errors
+var (
+	ErrInitRecordNotFound = errors.New("init record not found")
+	ErrInitRecordConflict = errors.New("init record was changed concurrently")
+)
+
+// The states of an InitRecord.
+const (
+	// InitPending is recorded before Vault is asked to initialize, and kept
+	// until every token holder was persisted.
+	InitPending string = "pending"
+	// InitFinished is recorded once every token holder was persisted.
+	InitFinished string = "finished"
+)
+
+// InitRecord tracks the initialization of a Vault cluster through Armor. It
+// doubles as a lease: while a pending record's lease is live, no other Armor
+// replica may initialize the cluster. A pending record whose lease expired
+// while Vault is initialized is a half-finished init, i.e. Armor stopped
+// between initializing Vault and persisting every token holder.
+type InitRecord struct {
+	ClusterID    string `json:"cluster_id" dynamodbav:"clusterId"`                           // vault cluster being initialized
+	State        string `json:"state" dynamodbav:"state"`                                    // either pending or finished
+	Owner        string `json:"owner" dynamodbav:"owner"`                                    // armor process which started the init
+	DateStarted  string `json:"date_started" dynamodbav:"dateStarted"`                       // date the init was started
+	DateFinished string `json:"date_finished,omitempty" dynamodbav:"dateFinished,omitempty"` // date every token holder was persisted
+	LeaseExpires string `json:"lease_expires,omitempty" dynamodbav:"leaseExpires,omitempty"` // date the owner's lease on a pending init lapses
+	Version      int    `json:"version" dynamodbav:"version"`                                // incremented on every write, for conditional writes
+}
+
+// LeaseExpired reports whether the lease on a pending record lapsed by now.
+func (rec *InitRecord) LeaseExpired(now time.Time) bool {
+	expires, err := time.Parse(time.RFC3339, rec.LeaseExpires)
+	if err != nil {
+		return true
+	}
+	return !now.Before(expires)
+}
+
+// InitRecordStore keeps an InitRecord per Vault cluster. Writes are
+// conditional, so only one of several racing writers succeeds.
+type InitRecordStore interface {
+	// GetInitRecord returns the cluster's record, or ErrInitRecordNotFound.
+	GetInitRecord(clusterID string) (*InitRecord, error)
+
+	// PutInitRecord writes rec, provided the cluster's record still is prev,
+	// or doesn't exist if prev is nil. Otherwise ErrInitRecordConflict is
+	// returned. rec.Version is set to follow prev's.
+	PutInitRecord(rec, prev *InitRecord) error
+
+	// DeleteInitRecord removes rec, provided it wasn't changed since.
+	// Otherwise ErrInitRecordConflict is returned.
+	DeleteInitRecord(rec *InitRecord) error
+}
+
+// nextInitRecordVersion returns the version rec gets when replacing prev.
+func nextInitRecordVersion(prev *InitRecord) int {
+	if prev == nil {
+		return 1
+	}
+	return prev.Version + 1
+}
+
+// initRecordMatches reports whether the current record (nil if there is
+// none) is the expected one.
+func initRecordMatches(current, expected *InitRecord) bool {
+	if current == nil || expected == nil {
+		return current == nil && expected == nil
+	}
+	return current.Version == expected.Version
+}
@@ -0,0 +1,51 @@
+package data
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/cdwlabs/armor/pkg/config"
+)
+
+// KMSKeyProvider is a KeyProvider whose master key is an AWS KMS customer
+// master key. Records keep the ARN of the key which encrypted their data key.
+type KMSKeyProvider struct {
+	keyID string
+}
+
+// NewKMSKeyProvider creates a KMSKeyProvider generating data keys under the
+// given KMS key id, ARN or alias (e.g. "alias/armor"). The KMS client is
+// created per request from Armor's default AWS session.
+func NewKMSKeyProvider(keyID string) *KMSKeyProvider {
+	return &KMSKeyProvider{keyID: keyID}
+}
+
+// GenerateDataKey implements KeyProvider.
+func (p *KMSKeyProvider) GenerateDataKey() (string, []byte, []byte, error) {
+	svc := kms.New(config.AWSSession())
+
+	out, err := svc.GenerateDataKey(&kms.GenerateDataKeyInput{
+		KeyId:   aws.String(p.keyID),
+		KeySpec: aws.String(kms.DataKeySpecAes256),
+	})
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return aws.StringValue(out.KeyId), out.Plaintext, out.CiphertextBlob, nil
+}
+
+// DecryptDataKey implements KeyProvider. KMS finds the master key from the
+// encrypted data key itself, so any key the caller may use for decryption
+// works, including the key in use before a rotation.
+func (p *KMSKeyProvider) DecryptDataKey(_ string, encrypted []byte) ([]byte, error) {
+	svc := kms.New(config.AWSSession())
+
+	out, err := svc.Decrypt(&kms.DecryptInput{
+		CiphertextBlob: encrypted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Plaintext, nil
+}
@@ -0,0 +1,196 @@
+package data
+
+import (
+	"sort"
+	"sync"
+)
+
+// MemoryStore is a TokenHolderStore kept in memory. Token holders are lost
+// when Armor exits, so it is only suitable for development and testing.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	holders map[TokenHolderKey]TokenHolder
+	inits   map[string]InitRecord
+	cers    map[string]map[string]Ceremony // by cluster, then id
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		holders: make(map[TokenHolderKey]TokenHolder),
+		inits:   make(map[string]InitRecord),
+		cers:    make(map[string]map[string]Ceremony),
+	}
+}
+
+// Put implements TokenHolderStore.
+func (s *MemoryStore) Put(tokenHolder *TokenHolder) error {
+	if err := tokenHolder.validateForPut(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.holders[tokenHolder.Key()] = *tokenHolder
+	return nil
+}
+
+// Get implements TokenHolderStore.
+func (s *MemoryStore) Get(key TokenHolderKey) (*TokenHolder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tokenHolder, ok := s.holders[key]
+	if !ok {
+		return nil, ErrTokenHolderNotFound
+	}
+	return &tokenHolder, nil
+}
+
+// ListByEmail implements TokenHolderStore.
+func (s *MemoryStore) ListByEmail(email string) ([]*TokenHolder, error) {
+	return s.list(func(tokenHolder *TokenHolder) bool {
+		return tokenHolder.Email == email
+	})
+}
+
+// ListByCluster implements TokenHolderStore.
+func (s *MemoryStore) ListByCluster(clusterID string) ([]*TokenHolder, error) {
+	return s.list(func(tokenHolder *TokenHolder) bool {
+		return tokenHolder.ClusterID == clusterID
+	})
+}
+
+// List implements TokenHolderStore.
+func (s *MemoryStore) List() ([]*TokenHolder, error) {
+	return s.list(func(*TokenHolder) bool { return true })
+}
+
+func (s *MemoryStore) list(match func(*TokenHolder) bool) ([]*TokenHolder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	holders := []*TokenHolder{}
+	for _, tokenHolder := range s.holders {
+		tokenHolder := tokenHolder
+		if match(&tokenHolder) {
+			holders = append(holders, &tokenHolder)
+		}
+	}
+	sort.Sort(byTokenHolderKey(holders))
+	return holders, nil
+}
+
+// Delete implements TokenHolderStore.
+func (s *MemoryStore) Delete(key TokenHolderKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.holders, key)
+	return nil
+}
+
+// EnsureSchema implements TokenHolderStore. There is nothing to create.
+func (s *MemoryStore) EnsureSchema() error {
+	return nil
+}
+
+// GetInitRecord implements InitRecordStore.
+func (s *MemoryStore) GetInitRecord(clusterID string) (*InitRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.inits[clusterID]
+	if !ok {
+		return nil, ErrInitRecordNotFound
+	}
+	return &rec, nil
+}
+
+// PutInitRecord implements InitRecordStore.
+func (s *MemoryStore) PutInitRecord(rec, prev *InitRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !initRecordMatches(s.currentInitRecord(rec.ClusterID), prev) {
+		return ErrInitRecordConflict
+	}
+	rec.Version = nextInitRecordVersion(prev)
+	s.inits[rec.ClusterID] = *rec
+	return nil
+}
+
+// DeleteInitRecord implements InitRecordStore.
+func (s *MemoryStore) DeleteInitRecord(rec *InitRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !initRecordMatches(s.currentInitRecord(rec.ClusterID), rec) {
+		return ErrInitRecordConflict
+	}
+	delete(s.inits, rec.ClusterID)
+	return nil
+}
+
+// currentInitRecord must be called with s.mu held.
+func (s *MemoryStore) currentInitRecord(clusterID string) *InitRecord {
+	rec, ok := s.inits[clusterID]
+	if !ok {
+		return nil
+	}
+	return &rec
+}
+
+// GetCeremony implements CeremonyStore.
+func (s *MemoryStore) GetCeremony(clusterID, id string) (*Ceremony, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c := s.currentCeremony(clusterID, id)
+	if c == nil {
+		return nil, ErrCeremonyNotFound
+	}
+	return c, nil
+}
+
+// ListCeremonies implements CeremonyStore.
+func (s *MemoryStore) ListCeremonies(clusterID string) ([]*Ceremony, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ceremonies := make([]*Ceremony, 0, len(s.cers[clusterID]))
+	for _, c := range s.cers[clusterID] {
+		ceremony := c
+		ceremonies = append(ceremonies, &ceremony)
+	}
+	sortCeremonies(ceremonies)
+	return ceremonies, nil
+}
+
+// PutCeremony implements CeremonyStore.
+func (s *MemoryStore) PutCeremony(c, prev *Ceremony) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !ceremonyMatches(s.currentCeremony(c.ClusterID, c.ID), prev) {
+		return ErrCeremonyConflict
+	}
+	c.Version = nextCeremonyVersion(prev)
+
+	if s.cers[c.ClusterID] == nil {
+		s.cers[c.ClusterID] = make(map[string]Ceremony)
+	}
+	stored := *c
+	stored.Participants = append([]CeremonyParticipant(nil), c.Participants...)
+	s.cers[c.ClusterID][c.ID] = stored
+	return nil
+}
+
+// currentCeremony must be called with s.mu held.
+func (s *MemoryStore) currentCeremony(clusterID, id string) *Ceremony {
+	c, ok := s.cers[clusterID][id]
+	if !ok {
+		return nil
+	}
+	c.Participants = append([]CeremonyParticipant(nil), c.Participants...)
+	return &c
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/cdwlabs/armor/pkg/config"
 	"gopkg.in/go-playground/validator.v9"
 	"time"
 )
@@ -19,21 +19,31 @@ var (
 // validation errors
 var (
 	ErrTokenHolderEmailUnset   = errors.New("token holder email address not set")
+	ErrTokenHolderKeyUnset     = errors.New("token holder cluster id or token type not set")
 	ErrTokenHolderTooManyItems = errors.New("token holder get/query/scan returned too many items")
 	ErrGetItemOutputMissingKey = errors.New("GetItemOutput missing expected key")
 	ErrAttributeValueMissing   = errors.New("expected AttributeValue is missing")
 	ErrTokenHolderNotFound     = errors.New("token holder not found")
 )
 
-// TokenHolder identifies the person (by email address) who possesses either
-// a root token or an unseal token
+// TokenHolder identifies the person (by email address) who possesses a root,
+// unseal or recovery token. A token holder is keyed by the Vault
+// cluster, token type and share index, so the same person may hold several
+// tokens, for one or more clusters.
 type TokenHolder struct {
-	Email           string `json:"email" dynamodbav:"email" validate:"required,email"`      // token holder is identified by email address
-	Token           string `json:"token" dynamodbav:"token,omitempty"`                      // actual token
-	TokenType       string `json:"token_type" dynamodbav:"tokenType,omitempty"`             // either root or unseal token
-	DateCreated     string `json:"date_created" dynamodbav:"dateCreated,omitempty"`         // date token holder was identified
-	DateInitialized string `json:"date_initialized" dynamodbav:"dateInitialized,omitempty"` // date Vault was initialized
-	DateDelivered   string `json:"date_delivered" dynamodbav:"dateDelivered,omitempty"`     // date last delivered to token holder
+	ClusterID       string `json:"cluster_id" dynamodbav:"clusterId" validate:"required"`           // vault cluster the token belongs to
+	ShareIndex      int    `json:"share_index" dynamodbav:"shareIndex"`                             // index of the unseal key share; 0 for the root token
+	Email           string `json:"email" dynamodbav:"email" validate:"required,email"`              // token holder is identified by email address
+	Token           string `json:"token" dynamodbav:"token,omitempty"`                              // actual token
+	TokenType       string `json:"token_type" dynamodbav:"tokenType,omitempty"`                     // root, unseal or recovery token
+	DateCreated     string `json:"date_created" dynamodbav:"dateCreated,omitempty"`                 // date token holder was identified
+	DateInitialized string `json:"date_initialized" dynamodbav:"dateInitialized,omitempty"`         // date Vault was initialized
+	DateDelivered   string `json:"date_delivered" dynamodbav:"dateDelivered,omitempty"`             // date last delivered to token holder
+	DateRevoked     string `json:"date_revoked,omitempty" dynamodbav:"dateRevoked,omitempty"`       // date token was marked revoked; empty if token is live
+	PGPFingerprint  string `json:"pgp_fingerprint,omitempty" dynamodbav:"pgpFingerprint,omitempty"` // PGP key Vault encrypted the token for; empty if token isn't PGP encrypted
+	KeyID           string `json:"key_id,omitempty" dynamodbav:"keyId,omitempty"`                   // master key which encrypted the data key; empty if token is plaintext
+	DataKey         string `json:"data_key,omitempty" dynamodbav:"dataKey,omitempty"`               // token's data key, encrypted by the master key
+	TokenHash       string `json:"token_hash,omitempty" dynamodbav:"tokenHash,omitempty"`           // see ShareHash; empty if the token is PGP encrypted
 }
 
 const (
@@ -43,11 +53,69 @@ const (
 	// Vault documentation refers to this as a secret key. There should multiple
 	// tokens of this type.
 	UnsealTokenType string = "unseal"
+	// RecoveryTokenType is the constant used to identify recovery key type.
+	// Vault only issues recovery keys when it is sealed by an HSM or KMS
+	// (i.e. auto-unseal); they authorize operations such as generating a root
+	// token in place of unseal keys.
+	RecoveryTokenType string = "recovery"
 )
 
+// TokenHolderKey uniquely identifies a token held by a TokenHolder.
+type TokenHolderKey struct {
+	ClusterID  string
+	TokenType  string
+	ShareIndex int
+}
+
+// String renders the key's token type and share index such that keys of the
+// same cluster sort by type, then index, e.g. "unseal#002".
+func (k TokenHolderKey) String() string {
+	return fmt.Sprintf("%s#%03d", k.TokenType, k.ShareIndex)
+}
+
+// validate checks that the key identifies a single token.
+func (k TokenHolderKey) validate() error {
+	if k.ClusterID == "" || k.TokenType == "" {
+		return ErrTokenHolderKeyUnset
+	}
+	return nil
+}
+
+// Key returns the key of the token held by the TokenHolder.
+func (tokenHolder *TokenHolder) Key() TokenHolderKey {
+	return TokenHolderKey{
+		ClusterID:  tokenHolder.ClusterID,
+		TokenType:  tokenHolder.TokenType,
+		ShareIndex: tokenHolder.ShareIndex,
+	}
+}
+
+// validateForPut checks that a TokenHolder may be persisted.
+func (tokenHolder *TokenHolder) validateForPut() error {
+	if tokenHolder.Email == "" {
+		return ErrTokenHolderEmailUnset
+	}
+	return tokenHolder.Key().validate()
+}
+
+// byTokenHolderKey sorts token holders by cluster, token type and share index.
+type byTokenHolderKey []*TokenHolder
+
+func (h byTokenHolderKey) Len() int      { return len(h) }
+func (h byTokenHolderKey) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h byTokenHolderKey) Less(i, j int) bool {
+	if h[i].ClusterID != h[j].ClusterID {
+		return h[i].ClusterID < h[j].ClusterID
+	}
+	return h[i].Key().String() < h[j].Key().String()
+}
+
 // These constants are used to map DynamoDB AttributeValue's to TokenHolder
 // struct
 const (
+	clusterIDAttrNm       string = "clusterId"
+	tokenKeyAttrNm        string = "tokenKey"
+	emailIndexNm          string = "email-index"
 	emailAttrNm           string = "email"
 	tokenAttrNm           string = "token"
 	tokenTypeAttrNm       string = "token_type"
@@ -63,6 +131,8 @@ func NewTokenHolder() *TokenHolder {
 	rfc := t.Format(time.RFC3339)
 
 	hldr := &TokenHolder{
+		ClusterID:       "",
+		ShareIndex:      0,
 		Email:           "",
 		Token:           "",
 		TokenType:       "",
@@ -80,126 +150,59 @@ func (tokenHolder *TokenHolder) Validate() error {
 	validate = validator.New()
 	err := validate.Struct(tokenHolder)
 	if err != nil {
-		validationerr := ""
-		for _, err := range err.(validator.ValidationErrors) {
-			//fmt.Printf("Error: ActualTag: %s", err.ActualTag())
-			//fmt.Printf("Error: Field: %s", err.Field())
-			//fmt.Printf("Error: Kind: %s", err.Kind())
-			//fmt.Printf("Error: Namespace: %s", err.Namespace())
-			//fmt.Printf("Error: Param: %s", err.Param())
-			//fmt.Printf("Error: StructNamespace: %s", err.StructNamespace())
-			//fmt.Printf("Error: StructField: %s", err.StructField())
-			//fmt.Printf("Error: Tag: %s", err.Tag())
-			validationerr = fmt.Sprintf("%s validation failed on '%s' check", err.Namespace(), err.Tag())
-			break
-		}
-
-		if validationerr != "" {
-			return fmt.Errorf(validationerr)
-		}
-		return fmt.Errorf("Invalid token holder")
+		return config.NewValidationErrors(err)
 	}
 
 	return nil
 }
 
-// PutItem persists a TokenHolder in AWS DynamoDB.
+// PutItem persists a TokenHolder in the TokenHolderStore selected by Armor's
+// configuration.
 func (tokenHolder *TokenHolder) PutItem() error {
-	item, err := dynamodbattribute.MarshalMap(tokenHolder)
-	//item, err := tokenHolder.attributeValues()
-	if err != nil {
-		return err
-	}
-
-	params := &dynamodb.PutItemInput{
-		TableName: aws.String(TokenHolderTableName()),
-		Item:      item,
-	}
-
-	svc := NewDynamoDBClient()
-	resp, err := svc.PutItem(params)
-
+	store, err := TokenHolders()
 	if err != nil {
-		if awsErr, ok := err.(awserr.Error); ok {
-			switch awsErr.Code() {
-			case "ConditionalCheckFailedException":
-				// A condition specified in the operation could not be evaluated.
-				return err
-			case "ProvisionedThroughputExceededException":
-				// Your request rate is too high. The AWS SDKs for DynamoDB
-				// automatically retry requests that receive this exception. Your
-				// request is eventually successful, unless your retry queue is too
-				// large to finish. Reduce the frequency of requests and use
-				// exponential backoff.
-				return err
-			case "ResourceNotFoundException":
-				// The operation tried to access a nonexistent table or index. The
-				// resource might not be specified correctly, or its status might not
-				// be ACTIVE.
-				return err
-			case "ItemCollectionSizeLimitExceededException":
-				// An item collection is too large. This exception is only returned
-				// for tables that have one or more local secondary indexes.
-				return err
-			case "InternalServerError":
-				// An error occurred on the server side.
-				return err
-			default:
-				return err
-			}
-		}
 		return err
 	}
 
-	err = dynamodbattribute.UnmarshalMap(resp.Attributes, tokenHolder)
-	//err = tokenHolder.refreshWithAttributeValues(resp.Attributes)
-
-	return err
+	return store.Put(tokenHolder)
 }
 
-// GetItem populates TokenHolder with data from AWS DynamoDB.
+// GetItem populates TokenHolder with data from the TokenHolderStore selected
+// by Armor's configuration. The token holder is looked up by its key when
+// ClusterID and TokenType are set, otherwise by email address; the latter
+// fails with ErrTokenHolderTooManyItems if the person holds several tokens.
 func (tokenHolder *TokenHolder) GetItem() error {
-	svc := NewDynamoDBClient()
-
-	params := &dynamodb.GetItemInput{
-		Key: map[string]*dynamodb.AttributeValue{
-			emailAttrNm: {
-				S: aws.String(tokenHolder.Email),
-			},
-		},
-		TableName:      aws.String(TokenHolderTableName()),
-		ConsistentRead: aws.Bool(true),
-	}
-
-	list, err := svc.GetItem(params)
+	store, err := TokenHolders()
 	if err != nil {
-		if awsErr, ok := err.(awserr.Error); ok {
-			switch awsErr.Code() {
-			case "ResourceNotFoundException":
-				//   The operation tried to access a nonexistent table or index. The
-				//   resource might not be specified correctly, or its status might not
-				//   be ACTIVE.
-				return err
-			case "ProvisionedThroughputExceededException":
-				// Your request rate is too high. The AWS SDKs for DynamoDB
-				// automatically retry requests that receive this exception. Your
-				// request is eventually successful, unless your retry queue is too
-				// large to finish.
-				return err
-			case "InternalServerError":
-				// An error occurred on the server side.
-				return err
-			default:
-				return err
-			}
-		}
 		return err
 	}
 
-	err = dynamodbattribute.UnmarshalMap(list.Item, tokenHolder)
-	//err = tokenHolder.refreshWithAttributeValues(list.Item)
+	var found *TokenHolder
+	if tokenHolder.Key().validate() == nil {
+		found, err = store.Get(tokenHolder.Key())
+		if err != nil {
+			return err
+		}
+	} else {
+		if tokenHolder.Email == "" {
+			return ErrTokenHolderEmailUnset
+		}
+		holders, err := store.ListByEmail(tokenHolder.Email)
+		if err != nil {
+			return err
+		}
+		switch len(holders) {
+		case 0:
+			return ErrTokenHolderNotFound
+		case 1:
+			found = holders[0]
+		default:
+			return ErrTokenHolderTooManyItems
+		}
+	}
 
-	return err
+	*tokenHolder = *found
+	return nil
 }
 
 // Map a TokenHolder to DynamoDB AttributeValues. This is useful during PutItem
@@ -275,10 +278,15 @@ func (tokenHolder *TokenHolder) refreshWithAttributeValues(dynoAttrs map[string]
 // TokenHolderTableExists checks for the existence of the Token Holder table.
 // It is intended to be used for health & readiness checks, and bootstrapping.
 func TokenHolderTableExists() (bool, error) {
+	return tableExists(TokenHolderTableName())
+}
+
+// tableExists checks for the existence of the named table.
+func tableExists(name string) (bool, error) {
 	svc := NewDynamoDBClient()
 
 	params := &dynamodb.DescribeTableInput{
-		TableName: aws.String(TokenHolderTableName()),
+		TableName: aws.String(name),
 	}
 
 	_, err := svc.DescribeTable(params)
@@ -299,14 +307,26 @@ func TokenHolderTableExists() (bool, error) {
 	return true, nil
 }
 
-// CreateTokenHolderTable creates the Token Holder table. It assumes the table
-// does not exist. Call during readiness check or as part of some initial
-// bootstrap step.
+// CreateTokenHolderTable creates the Token Holder table, with the billing
+// mode, throughput, point-in-time-recovery and encryption of Armor's
+// dynamodb_* configuration. It assumes the table does not exist. Call during
+// readiness check or as part of some initial bootstrap step.
 func CreateTokenHolderTable() error {
 	svc := NewDynamoDBClient()
 
+	// Items are keyed by cluster (hash) and "<token type>#<share index>"
+	// (range). Email is a global secondary index so a person's tokens can be
+	// found across clusters.
 	params := &dynamodb.CreateTableInput{
 		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String(clusterIDAttrNm),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String(tokenKeyAttrNm),
+				AttributeType: aws.String("S"),
+			},
 			{
 				AttributeName: aws.String(emailAttrNm),
 				AttributeType: aws.String("S"),
@@ -314,18 +334,32 @@ func CreateTokenHolderTable() error {
 		},
 		KeySchema: []*dynamodb.KeySchemaElement{
 			{
-				AttributeName: aws.String(emailAttrNm),
+				AttributeName: aws.String(clusterIDAttrNm),
 				KeyType:       aws.String("HASH"),
 			},
+			{
+				AttributeName: aws.String(tokenKeyAttrNm),
+				KeyType:       aws.String("RANGE"),
+			},
 		},
-		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
-			ReadCapacityUnits:  aws.Int64(1),
-			WriteCapacityUnits: aws.Int64(1),
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String(emailIndexNm),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String(emailAttrNm),
+						KeyType:       aws.String("HASH"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
 		},
 		TableName: aws.String(TokenHolderTableName()),
 	}
 
-	_, err := svc.CreateTable(params)
+	err := createTable(svc, params)
 
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok {
@@ -350,12 +384,7 @@ func CreateTokenHolderTable() error {
 		return err
 	}
 
-	waitParams := &dynamodb.DescribeTableInput{
-		TableName: aws.String(TokenHolderTableName()),
-	}
-
-	err = svc.WaitUntilTableExists(waitParams)
-	return err
+	return nil
 }
 
 // DeleteTokenHolderTable deletes the Token Holder table. It assumes the table
@@ -0,0 +1,133 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cdwlabs/armor/pkg/config"
+	"sync"
+)
+
+// ErrTokenHolderStoreUnknown is returned when token_holder_store names no known
+// TokenHolderStore implementation.
+var ErrTokenHolderStoreUnknown = errors.New("unknown token holder store")
+
+// The TokenHolderStore implementations which may be selected through Armor's
+// token_holder_store configuration.
+const (
+	DynamoDBTokenHolderStore string = "dynamodb"
+	FileTokenHolderStore     string = "file"
+	MemoryTokenHolderStore   string = "memory"
+)
+
+// TokenHolderStore persists the holders of Vault's root and unseal tokens.
+// Token holders are keyed by TokenHolderKey (i.e. cluster, token type and
+// share index); email address is an indexed attribute.
+type TokenHolderStore interface {
+	// Put creates or replaces the token holder with the same key.
+	Put(tokenHolder *TokenHolder) error
+
+	// Get returns the token holder with the given key, or
+	// ErrTokenHolderNotFound.
+	Get(key TokenHolderKey) (*TokenHolder, error)
+
+	// ListByEmail returns every token held by the given email address,
+	// across clusters.
+	ListByEmail(email string) ([]*TokenHolder, error)
+
+	// ListByCluster returns every token holder of the given cluster, sorted
+	// by key.
+	ListByCluster(clusterID string) ([]*TokenHolder, error)
+
+	// List returns every token holder, sorted by key.
+	List() ([]*TokenHolder, error)
+
+	// Delete removes the token holder with the given key. Deleting a missing
+	// token holder is not an error.
+	Delete(key TokenHolderKey) error
+
+	// EnsureSchema creates whatever the store needs (e.g. a DynamoDB table)
+	// if it doesn't exist yet. It is intended to be used for readiness checks
+	// and bootstrapping.
+	EnsureSchema() error
+
+	// Init records are kept alongside the token holders, so that Init can
+	// rely on the configured store for its lease.
+	InitRecordStore
+
+	// Unseal ceremonies are kept alongside the token holders, whose shares
+	// they verify.
+	CeremonyStore
+}
+
+// TokenHolderMigrator is implemented by stores which may hold token holders
+// written by earlier releases of Armor, keyed by email address alone.
+type TokenHolderMigrator interface {
+	// MigrateLegacy copies legacy token holders into the store under the
+	// given cluster id, and returns the number of token holders copied.
+	// Legacy records are left in place.
+	MigrateLegacy(clusterID string) (int, error)
+}
+
+var (
+	defaultStore   TokenHolderStore
+	defaultStoreMu sync.Mutex
+)
+
+// TokenHolders returns the TokenHolderStore selected by Armor's
+// token_holder_store configuration, encrypting tokens as selected by
+// token_encryption. The store is created the first time TokenHolders is
+// called.
+func TokenHolders() (TokenHolderStore, error) {
+	defaultStoreMu.Lock()
+	defer defaultStoreMu.Unlock()
+
+	if defaultStore == nil {
+		cfg := config.Config()
+		store, err := NewTokenHolderStore(cfg.GetString("token_holder_store"), cfg.GetString("token_holder_store_path"))
+		if err != nil {
+			return nil, err
+		}
+
+		keys, err := NewKeyProvider(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if keys != nil {
+			store = NewEncryptedStore(store, keys)
+		}
+
+		defaultStore = store
+	}
+
+	return defaultStore, nil
+}
+
+// NewTokenHolderStore creates a TokenHolderStore of the given kind. The path
+// is only used by the file store.
+func NewTokenHolderStore(kind, path string) (TokenHolderStore, error) {
+	switch kind {
+	case DynamoDBTokenHolderStore, "":
+		return NewDynamoDBStore(), nil
+	case FileTokenHolderStore:
+		return NewFileStore(path)
+	case MemoryTokenHolderStore:
+		return NewMemoryStore(), nil
+	}
+
+	return nil, fmt.Errorf("%s: %q", ErrTokenHolderStoreUnknown.Error(), kind)
+}
+
+// NewKeyProvider creates the KeyProvider selected by Armor's token_encryption
+// configuration. It returns nil if tokens are not to be encrypted.
+func NewKeyProvider(cfg config.Provider) (KeyProvider, error) {
+	switch kind := cfg.GetString("token_encryption"); kind {
+	case "":
+		return nil, nil
+	case KMSTokenEncryption:
+		return NewKMSKeyProvider(cfg.GetString("token_encryption_kms_key_id")), nil
+	case FileTokenEncryption:
+		return NewFileKeyProvider(cfg.GetString("token_encryption_key_file"), cfg.GetStringSlice("token_encryption_previous_key_files")...)
+	default:
+		return nil, fmt.Errorf("%s: %q", ErrTokenEncryptionUnknown.Error(), kind)
+	}
+}
@@ -0,0 +1,216 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testTokenHolderStore(t *testing.T, store TokenHolderStore) {
+	err := store.EnsureSchema()
+	assert.NoError(t, err, "not expecting an error when ensuring store schema")
+
+	holders, err := store.List()
+	assert.NoError(t, err, "not expecting an error when listing an empty store")
+	assert.Len(t, holders, 0, "expecting an empty store")
+
+	rootKey := TokenHolderKey{ClusterID: "packers", TokenType: RootTokenType}
+	_, err = store.Get(rootKey)
+	assert.Equal(t, ErrTokenHolderNotFound, err, "expecting not found for a missing token holder")
+
+	root := NewTokenHolder()
+	root.ClusterID = "packers"
+	root.Email = "aaron.rodgers@packers.com"
+	root.Token = "5f4dcc3b5aa765d61d8327deb882cf99"
+	root.TokenType = RootTokenType
+	err = store.Put(root)
+	assert.NoError(t, err, "not expecting an error when putting root token holder")
+
+	// the root token holder also holds an unseal share, of two clusters
+	unseal := NewTokenHolder()
+	unseal.ClusterID = "packers"
+	unseal.ShareIndex = 1
+	unseal.Email = root.Email
+	unseal.Token = "7c6a180b36896a0a8c02787eeafb0e4c"
+	unseal.TokenType = UnsealTokenType
+	err = store.Put(unseal)
+	assert.NoError(t, err, "not expecting an error when putting unseal token holder")
+
+	other := NewTokenHolder()
+	other.ClusterID = "bears"
+	other.Email = root.Email
+	other.Token = "6cb75f652a9b52798eb6cf2201057c73"
+	other.TokenType = UnsealTokenType
+	err = store.Put(other)
+	assert.NoError(t, err, "not expecting an error when putting another cluster's token holder")
+
+	share := NewTokenHolder()
+	share.ClusterID = "packers"
+	share.Email = "david.bakhtiari@packers.com"
+	share.Token = "819b0643d6b89dc9b579fdfc9094f28e"
+	share.TokenType = UnsealTokenType
+	err = store.Put(share)
+	assert.NoError(t, err, "not expecting an error when putting unseal token holder")
+
+	err = store.Put(NewTokenHolder())
+	assert.Equal(t, ErrTokenHolderEmailUnset, err, "expecting an error when putting a token holder without email")
+
+	noKey := NewTokenHolder()
+	noKey.Email = "bryan.bulaga@packers.com"
+	err = store.Put(noKey)
+	assert.Equal(t, ErrTokenHolderKeyUnset, err, "expecting an error when putting a token holder without cluster id or type")
+
+	found, err := store.Get(rootKey)
+	assert.NoError(t, err, "not expecting an error when getting root token holder")
+	assert.Equal(t, root, found, "expecting a match on root token holder")
+
+	found, err = store.Get(unseal.Key())
+	assert.NoError(t, err, "not expecting an error when getting unseal token holder")
+	assert.Equal(t, unseal, found, "expecting the unseal share not to overwrite the root token")
+
+	holders, err = store.ListByEmail(root.Email)
+	assert.NoError(t, err, "not expecting an error when listing token holders by email")
+	assert.Equal(t, []*TokenHolder{other, root, unseal}, holders, "expecting every token of the email address, sorted by key")
+
+	holders, err = store.List()
+	assert.NoError(t, err, "not expecting an error when listing token holders")
+	assert.Equal(t, []*TokenHolder{other, root, share, unseal}, holders, "expecting token holders sorted by cluster, type and share index")
+
+	holders, err = store.ListByCluster("packers")
+	assert.NoError(t, err, "not expecting an error when listing token holders by cluster")
+	assert.Equal(t, []*TokenHolder{root, share, unseal}, holders, "expecting only the cluster's token holders, sorted by key")
+
+	holders, err = store.ListByCluster("vikings")
+	assert.NoError(t, err, "not expecting an error when listing an unknown cluster")
+	assert.Len(t, holders, 0, "expecting no token holders of an unknown cluster")
+
+	err = store.Delete(rootKey)
+	assert.NoError(t, err, "not expecting an error when deleting root token holder")
+	err = store.Delete(rootKey)
+	assert.NoError(t, err, "not expecting an error when deleting a missing token holder")
+
+	_, err = store.Get(rootKey)
+	assert.Equal(t, ErrTokenHolderNotFound, err, "expecting not found for a deleted token holder")
+
+	_, err = store.Get(unseal.Key())
+	assert.NoError(t, err, "expecting the unseal share to outlive the deleted root token")
+}
+
+func testInitRecordStore(t *testing.T, store TokenHolderStore) {
+	_, err := store.GetInitRecord("packers")
+	assert.Equal(t, ErrInitRecordNotFound, err, "expecting not found for a cluster without init record")
+
+	pending := &InitRecord{ClusterID: "packers", State: InitPending, Owner: "armor-1"}
+	err = store.PutInitRecord(pending, nil)
+	assert.NoError(t, err, "not expecting an error when creating an init record")
+	assert.Equal(t, 1, pending.Version, "expecting the first version")
+
+	racer := &InitRecord{ClusterID: "packers", State: InitPending, Owner: "armor-2"}
+	err = store.PutInitRecord(racer, nil)
+	assert.Equal(t, ErrInitRecordConflict, err, "expecting a conflict when another replica creates the init record")
+
+	found, err := store.GetInitRecord("packers")
+	assert.NoError(t, err, "not expecting an error when getting the init record")
+	assert.Equal(t, pending, found, "expecting the first writer's init record")
+
+	finished := *pending
+	finished.State = InitFinished
+	err = store.PutInitRecord(&finished, pending)
+	assert.NoError(t, err, "not expecting an error when replacing the init record")
+	assert.Equal(t, 2, finished.Version, "expecting the version to follow the replaced record")
+
+	err = store.PutInitRecord(racer, pending)
+	assert.Equal(t, ErrInitRecordConflict, err, "expecting a conflict when replacing a stale init record")
+
+	err = store.DeleteInitRecord(pending)
+	assert.Equal(t, ErrInitRecordConflict, err, "expecting a conflict when deleting a stale init record")
+
+	err = store.DeleteInitRecord(&finished)
+	assert.NoError(t, err, "not expecting an error when deleting the init record")
+
+	_, err = store.GetInitRecord("packers")
+	assert.Equal(t, ErrInitRecordNotFound, err, "expecting not found for a deleted init record")
+}
+
+func testCeremonyStore(t *testing.T, store TokenHolderStore) {
+	_, err := store.GetCeremony("packers", "first")
+	assert.Equal(t, ErrCeremonyNotFound, err, "expecting not found for an unknown ceremony")
+
+	ceremonies, err := store.ListCeremonies("packers")
+	assert.NoError(t, err, "not expecting an error when listing a cluster without ceremonies")
+	assert.Empty(t, ceremonies, "not expecting ceremonies")
+
+	second := &Ceremony{ClusterID: "packers", ID: "second", State: CeremonyOpen, DateStarted: "2017-01-02T00:00:00Z"}
+	err = store.PutCeremony(second, nil)
+	assert.NoError(t, err, "not expecting an error when creating a ceremony")
+	first := &Ceremony{ClusterID: "packers", ID: "first", State: CeremonyAborted, DateStarted: "2017-01-01T00:00:00Z"}
+	err = store.PutCeremony(first, nil)
+	assert.NoError(t, err, "not expecting an error when creating a ceremony")
+	other := &Ceremony{ClusterID: "bears", ID: "first", State: CeremonyOpen}
+	err = store.PutCeremony(other, nil)
+	assert.NoError(t, err, "not expecting an error when creating another cluster's ceremony")
+
+	err = store.PutCeremony(&Ceremony{ClusterID: "packers", ID: "second"}, nil)
+	assert.Equal(t, ErrCeremonyConflict, err, "expecting a conflict when the ceremony exists")
+
+	ceremonies, err = store.ListCeremonies("packers")
+	assert.NoError(t, err, "not expecting an error when listing ceremonies")
+	if assert.Len(t, ceremonies, 2, "expecting only the cluster's ceremonies") {
+		assert.Equal(t, "first", ceremonies[0].ID, "expecting the oldest ceremony first")
+	}
+
+	unsealed := *second
+	unsealed.State = CeremonyUnsealed
+	unsealed.Participants = []CeremonyParticipant{{Email: "aaron.rodgers@packers.com", ShareIndex: 1, Verified: true}}
+	err = store.PutCeremony(&unsealed, second)
+	assert.NoError(t, err, "not expecting an error when replacing a ceremony")
+	assert.Equal(t, 2, unsealed.Version, "expecting the version to follow the replaced ceremony")
+
+	err = store.PutCeremony(second, second)
+	assert.Equal(t, ErrCeremonyConflict, err, "expecting a conflict when replacing a stale ceremony")
+
+	found, err := store.GetCeremony("packers", "second")
+	assert.NoError(t, err, "not expecting an error when getting a ceremony")
+	assert.Equal(t, &unsealed, found, "expecting the replaced ceremony")
+}
+
+func TestShareHash(t *testing.T) {
+	hexKey := "5f4dcc3b5aa765d61d8327deb882cf99"
+	b64Key := "X03MO1qnZdYdgyfeuILPmQ=="
+	assert.Equal(t, ShareHash(hexKey), ShareHash(b64Key), "expecting the hash of the decoded key")
+	assert.NotEqual(t, ShareHash(hexKey), ShareHash("6cb75f652a9b52798eb6cf2201057c73"), "expecting keys to hash differently")
+}
+
+func TestTokenHolderKey(t *testing.T) {
+	key := TokenHolderKey{ClusterID: "packers", TokenType: UnsealTokenType, ShareIndex: 2}
+	assert.Equal(t, "unseal#002", key.String(), "expecting type and zero padded share index")
+	assert.NoError(t, key.validate(), "not expecting an error for a complete key")
+
+	key.ClusterID = ""
+	assert.Equal(t, ErrTokenHolderKeyUnset, key.validate(), "expecting an error without cluster id")
+}
+
+func TestMemoryStore(t *testing.T) {
+	testTokenHolderStore(t, NewMemoryStore())
+	testInitRecordStore(t, NewMemoryStore())
+	testCeremonyStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "armor-store")
+	assert.NoError(t, err, "not expecting an error when creating store dir")
+	defer os.RemoveAll(dir)
+
+	store, err := NewTokenHolderStore(FileTokenHolderStore, filepath.Join(dir, "db", "token_holders.db"))
+	if assert.NoError(t, err, "not expecting an error when opening file store") {
+		defer store.(*FileStore).Close()
+		testTokenHolderStore(t, store)
+		testInitRecordStore(t, store)
+		testCeremonyStore(t, store)
+	}
+
+	_, err = NewTokenHolderStore("mongodb", "")
+	assert.Error(t, err, "expecting an error for an unknown store")
+}
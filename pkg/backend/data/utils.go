@@ -1,22 +1,55 @@
 package data
 
 import (
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/cdwlabs/armor/pkg/config"
 )
 
 const (
-	tableTokenHolders string = "TokenHolders"
+	legacyTableTokenHolders string = "TokenHolders"
 )
 
 // TokenHolderTableName is the name of the table that tracks individuals
-// responsible for keeping Vault's root and unseal tokens.
+// responsible for keeping Vault's root and unseal tokens. It is set by
+// Armor's dynamodb_token_holder_table configuration.
 func TokenHolderTableName() string {
-	return tableTokenHolders
+	return config.Config().GetString("dynamodb_token_holder_table")
 }
 
-// NewDynamoDBClient uses default Session to create a DynamoDB client.
+// LegacyTokenHolderTableName is the name of the table used by earlier
+// releases of Armor, where token holders were keyed by email address alone.
+// See DynamoDBStore.MigrateLegacy.
+func LegacyTokenHolderTableName() string {
+	return legacyTableTokenHolders
+}
+
+// InitRecordTableName is the name of the table that tracks the
+// initialization of each Vault cluster. See InitRecordStore. It is set by
+// Armor's dynamodb_init_record_table configuration.
+func InitRecordTableName() string {
+	return config.Config().GetString("dynamodb_init_record_table")
+}
+
+// CeremonyTableName is the name of the table that tracks unseal ceremonies.
+// See CeremonyStore. It is set by Armor's dynamodb_ceremony_table
+// configuration.
+func CeremonyTableName() string {
+	return config.Config().GetString("dynamodb_ceremony_table")
+}
+
+// NewDynamoDBClient uses default Session to create a DynamoDB client. The
+// endpoint and region are overridden by Armor's dynamodb_endpoint and
+// dynamodb_region configuration, if set.
 func NewDynamoDBClient() *dynamodb.DynamoDB {
-	svc := dynamodb.New(config.AWSSession())
+	cfg := aws.NewConfig()
+	if endpoint := config.Config().GetString("dynamodb_endpoint"); endpoint != "" {
+		cfg = cfg.WithEndpoint(endpoint)
+	}
+	if region := config.Config().GetString("dynamodb_region"); region != "" {
+		cfg = cfg.WithRegion(region)
+	}
+
+	svc := dynamodb.New(config.AWSSession(), cfg)
 	return svc
 }
@@ -0,0 +1,251 @@
+// Package delivery sends Vault's root and unseal tokens to their holders by
+// email. Each holder receives only their own token, PGP encrypted for them.
+package delivery
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/config"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+// delivery errors
+var (
+	ErrDeliveryMethodUnknown = errors.New("unknown delivery method")
+	ErrDeliveryFromUnset     = errors.New("delivery from address not set")
+	ErrTokenNotEncrypted     = errors.New("refusing to deliver a token which isn't PGP encrypted")
+	ErrTokenRevoked          = errors.New("refusing to deliver a revoked token")
+)
+
+// The Mailer implementations which may be selected through Armor's
+// delivery_method configuration. Tokens are not delivered if delivery_method
+// is empty.
+const (
+	SMTPDeliveryMethod string = "smtp"
+	SESDeliveryMethod  string = "ses"
+)
+
+// Message is a plain text email.
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends a Message.
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// The templates used when delivery_subject_template or delivery_body_template
+// aren't set. Templates are executed with a TemplateData.
+const (
+	DefaultSubjectTemplate = `Your Vault {{.TokenHolder.TokenType}} token for {{.TokenHolder.ClusterID}}`
+	DefaultBodyTemplate    = `Hello {{.TokenHolder.Email}},
+
+You hold a Vault {{.TokenHolder.TokenType}} token for {{.TokenHolder.ClusterID}}.
+{{if eq .TokenHolder.TokenType "unseal"}}It is share {{.TokenHolder.ShareIndex}} of the keys needed to unseal Vault.
+{{end}}{{if eq .TokenHolder.TokenType "recovery"}}It is share {{.TokenHolder.ShareIndex}} of Vault's recovery keys.
+{{end}}
+The token is encrypted for your PGP key {{.TokenHolder.PGPFingerprint}}.
+Save the message below to a file and decrypt it with:
+
+    gpg --decrypt token.asc
+
+{{.Armored}}
+Keep the token safe and don't forward this email.
+`
+)
+
+// TemplateData is what subject and body templates are executed with.
+type TemplateData struct {
+	TokenHolder *dbackend.TokenHolder
+	Armored     string // the PGP encrypted token, ASCII armored
+}
+
+// Deliverer sends tokens to their holders and records when they were
+// delivered.
+type Deliverer struct {
+	Store    dbackend.TokenHolderStore
+	Mailer   Mailer
+	From     string
+	Subject  *template.Template
+	Body     *template.Template
+	Retries  int           // number of retries after a failed send
+	Interval time.Duration // wait between retries
+}
+
+// New creates the Deliverer selected by Armor's delivery_method
+// configuration, recording deliveries in store. It returns nil if tokens are
+// not to be delivered.
+func New(store dbackend.TokenHolderStore, cfg config.Provider) (*Deliverer, error) {
+	mailer, err := NewMailer(cfg)
+	if mailer == nil || err != nil {
+		return nil, err
+	}
+
+	from := cfg.GetString("delivery_from")
+	if from == "" {
+		return nil, ErrDeliveryFromUnset
+	}
+
+	subject, err := loadTemplate("subject", cfg.GetString("delivery_subject_template"), DefaultSubjectTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := loadTemplate("body", cfg.GetString("delivery_body_template"), DefaultBodyTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Deliverer{
+		Store:    store,
+		Mailer:   mailer,
+		From:     from,
+		Subject:  subject,
+		Body:     body,
+		Retries:  cfg.GetInt("delivery_retries"),
+		Interval: cfg.GetDuration("delivery_retry_interval"),
+	}, nil
+}
+
+// NewMailer creates the Mailer selected by Armor's delivery_method
+// configuration. It returns nil if delivery_method is empty.
+func NewMailer(cfg config.Provider) (Mailer, error) {
+	switch method := cfg.GetString("delivery_method"); method {
+	case "":
+		return nil, nil
+	case SMTPDeliveryMethod:
+		return NewSMTPMailer(cfg.GetString("smtp_address"), cfg.GetString("smtp_username"), cfg.GetString("smtp_password")), nil
+	case SESDeliveryMethod:
+		return NewSESMailer(), nil
+	default:
+		return nil, fmt.Errorf("%s: %q", ErrDeliveryMethodUnknown.Error(), method)
+	}
+}
+
+// loadTemplate parses the template file at path, or text if path is empty.
+func loadTemplate(name, path, text string) (*template.Template, error) {
+	if path != "" {
+		return template.ParseFiles(path)
+	}
+	return template.New(name).Parse(text)
+}
+
+// Deliver sends each token holder their token, and records DateDelivered once
+// it was sent. Every token holder is attempted; the error names those whose
+// delivery failed.
+func (d *Deliverer) Deliver(holders []*dbackend.TokenHolder) error {
+	var failed []string
+
+	for _, tokenHolder := range holders {
+		err := d.deliver(tokenHolder)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s): %s", tokenHolder.Email, tokenHolder.Key().String(), err.Error()))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("token delivery failed for %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+func (d *Deliverer) deliver(tokenHolder *dbackend.TokenHolder) error {
+	msg, err := d.message(tokenHolder)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = d.Mailer.Send(msg)
+		if err == nil || attempt >= d.Retries {
+			break
+		}
+		time.Sleep(d.Interval)
+	}
+	if err != nil {
+		return err
+	}
+
+	tokenHolder.DateDelivered = time.Now().Format(time.RFC3339)
+	return d.Store.Put(tokenHolder)
+}
+
+// message renders the email delivering tokenHolder's token.
+func (d *Deliverer) message(tokenHolder *dbackend.TokenHolder) (*Message, error) {
+	if tokenHolder.DateRevoked != "" {
+		return nil, ErrTokenRevoked
+	}
+	if tokenHolder.PGPFingerprint == "" {
+		return nil, ErrTokenNotEncrypted
+	}
+
+	armored, err := armorToken(tokenHolder)
+	if err != nil {
+		return nil, err
+	}
+
+	data := TemplateData{TokenHolder: tokenHolder, Armored: armored}
+
+	var subject, body bytes.Buffer
+	err = d.Subject.Execute(&subject, data)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Body.Execute(&body, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		From:    d.From,
+		To:      tokenHolder.Email,
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    body.String(),
+	}, nil
+}
+
+// armorToken turns a PGP encrypted token into an ASCII armored PGP message.
+// Vault returns an encrypted root token base64 encoded, but encrypted unseal
+// and recovery shares hex encoded.
+func armorToken(tokenHolder *dbackend.TokenHolder) (string, error) {
+	var (
+		raw []byte
+		err error
+	)
+	if tokenHolder.TokenType == dbackend.RootTokenType {
+		raw, err = base64.StdEncoding.DecodeString(tokenHolder.Token)
+	} else {
+		raw, err = hex.DecodeString(tokenHolder.Token)
+	}
+	if err != nil {
+		return "", ErrTokenNotEncrypted
+	}
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, "PGP MESSAGE", nil)
+	if err != nil {
+		return "", err
+	}
+	_, err = w.Write(raw)
+	if err != nil {
+		return "", err
+	}
+	err = w.Close()
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String() + "\n", nil
+}
@@ -0,0 +1,243 @@
+package delivery
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+// smtpStandIn is a local SMTP server which keeps the messages it receives.
+// The first failures transactions are rejected, to exercise retries.
+type smtpStandIn struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	failures int
+	messages []string
+	rcpts    []string
+}
+
+func newSMTPStandIn(t *testing.T, failures int) *smtpStandIn {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err, "not expecting an error when listening") {
+		t.FailNow()
+	}
+
+	s := &smtpStandIn{ln: ln, failures: failures}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP stand-in")
+	var rcpt string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM"):
+			s.mu.Lock()
+			fail := s.failures > 0
+			if fail {
+				s.failures--
+			}
+			s.mu.Unlock()
+			if fail {
+				reply("451 try again later")
+				continue
+			}
+			reply("250 ok")
+		case strings.HasPrefix(cmd, "RCPT TO"):
+			rcpt = strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>")
+			reply("250 ok")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var msg []string
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				msg = append(msg, l)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, strings.Join(msg, ""))
+			s.rcpts = append(s.rcpts, rcpt)
+			s.mu.Unlock()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// encryptToken PGP encrypts token for entity, like Vault does for the PGP
+// keys of an Init request.
+func encryptToken(t *testing.T, entity *openpgp.Entity, token string) []byte {
+	var buf bytes.Buffer
+	w, err := openpgp.Encrypt(&buf, []*openpgp.Entity{entity}, nil, nil, nil)
+	if !assert.NoError(t, err, "not expecting an error when encrypting token") {
+		t.FailNow()
+	}
+	w.Write([]byte(token))
+	assert.NoError(t, w.Close(), "not expecting an error when encrypting token")
+	return buf.Bytes()
+}
+
+// decryptMessage decrypts the armored token of a delivered message with
+// entity's private key.
+func decryptMessage(t *testing.T, entity *openpgp.Entity, message string) string {
+	block, err := armor.Decode(strings.NewReader(strings.Replace(message, "\r\n", "\n", -1)))
+	if !assert.NoError(t, err, "expecting an armored token") {
+		return ""
+	}
+	md, err := openpgp.ReadMessage(block.Body, openpgp.EntityList{entity}, nil, nil)
+	if !assert.NoError(t, err, "expecting the token to be encrypted for its holder") {
+		return ""
+	}
+	token, err := ioutil.ReadAll(md.UnverifiedBody)
+	assert.NoError(t, err, "not expecting an error when decrypting token")
+	return string(token)
+}
+
+func TestDeliverer_Deliver(t *testing.T) {
+	server := newSMTPStandIn(t, 1)
+	defer server.ln.Close()
+
+	entity, err := openpgp.NewEntity("David Bakhtiari", "", "david.bakhtiari@packers.com", nil)
+	if !assert.NoError(t, err, "not expecting an error when generating a PGP key") {
+		t.FailNow()
+	}
+	// prefer SHA-256, RIPEMD-160 being assumed otherwise and not compiled in
+	for _, id := range entity.Identities {
+		id.SelfSignature.PreferredHash = []uint8{8}
+	}
+	// self-sign the user id, which only happens on serializing the private key
+	assert.NoError(t, entity.SerializePrivate(ioutil.Discard, nil), "not expecting an error when signing key")
+
+	store := dbackend.NewMemoryStore()
+	encrypted := dbackend.NewTokenHolder()
+	encrypted.ClusterID = "packers"
+	encrypted.Email = "david.bakhtiari@packers.com"
+	encrypted.TokenType = dbackend.UnsealTokenType
+	encrypted.ShareIndex = 1
+	encrypted.Token = hex.EncodeToString(encryptToken(t, entity, "4d9e2b1ac3"))
+	encrypted.PGPFingerprint = "0123456789ABCDEF0123456789ABCDEF01234567"
+
+	root := dbackend.NewTokenHolder()
+	root.ClusterID = "packers"
+	root.Email = "aaron.rodgers@packers.com"
+	root.TokenType = dbackend.RootTokenType
+	root.Token = base64.StdEncoding.EncodeToString(encryptToken(t, entity, "6cb75f65-2a9b-5279-8eb6-cf2201057c73"))
+	root.PGPFingerprint = "0123456789ABCDEF0123456789ABCDEF01234567"
+
+	plaintext := dbackend.NewTokenHolder()
+	plaintext.ClusterID = "packers"
+	plaintext.Email = "bryan.bulaga@packers.com"
+	plaintext.TokenType = dbackend.UnsealTokenType
+	plaintext.ShareIndex = 2
+	plaintext.Token = "7c6a180b36896a0a8c02787eeafb0e4c"
+
+	d := &Deliverer{
+		Store:    store,
+		Mailer:   NewSMTPMailer(server.ln.Addr().String(), "", ""),
+		From:     "armor@packers.com",
+		Subject:  template.Must(template.New("subject").Parse(DefaultSubjectTemplate)),
+		Body:     template.Must(template.New("body").Parse(DefaultBodyTemplate)),
+		Retries:  2,
+		Interval: time.Millisecond,
+	}
+
+	err = d.Deliver([]*dbackend.TokenHolder{encrypted, plaintext, root})
+	if assert.Error(t, err, "expecting an error for the plaintext token") {
+		assert.Contains(t, err.Error(), plaintext.Email, "expecting the failed holder to be named")
+		assert.NotContains(t, err.Error(), encrypted.Email, "not expecting the delivered holder to be named")
+		assert.NotContains(t, err.Error(), root.Email, "not expecting the delivered holder to be named")
+	}
+
+	if assert.Len(t, server.messages, 2, "expecting a message per encrypted token after a retry") {
+		assert.Equal(t, encrypted.Email, server.rcpts[0], "expecting the message to go to the holder")
+		assert.Contains(t, server.messages[0], "Subject: Your Vault unseal token for packers", "expecting the default subject")
+		assert.Contains(t, server.messages[0], "-----BEGIN PGP MESSAGE-----", "expecting an armored share")
+		assert.NotContains(t, server.messages[0], plaintext.Token, "not expecting another holder's token")
+		assert.Equal(t, "4d9e2b1ac3", decryptMessage(t, entity, server.messages[0]), "expecting the hex encoded share to be armored")
+
+		assert.Equal(t, root.Email, server.rcpts[1], "expecting the message to go to the holder")
+		assert.Equal(t, "6cb75f65-2a9b-5279-8eb6-cf2201057c73", decryptMessage(t, entity, server.messages[1]), "expecting the base64 encoded root token to be armored")
+	}
+
+	found, err := store.Get(encrypted.Key())
+	if assert.NoError(t, err, "expecting the delivered holder to be persisted") {
+		assert.NotEmpty(t, found.DateDelivered, "expecting DateDelivered to be recorded")
+	}
+	_, err = store.Get(plaintext.Key())
+	assert.Equal(t, dbackend.ErrTokenHolderNotFound, err, "not expecting the undelivered holder to be persisted")
+
+	// a server which keeps failing exhausts the retries
+	failing := newSMTPStandIn(t, 10)
+	defer failing.ln.Close()
+	d.Mailer = NewSMTPMailer(failing.ln.Addr().String(), "", "")
+	err = d.Deliver([]*dbackend.TokenHolder{encrypted})
+	assert.Error(t, err, "expecting an error once retries are exhausted")
+	assert.Equal(t, 7, failing.failures, "expecting the first attempt and two retries")
+}
+
+func TestNew(t *testing.T) {
+	d, err := New(dbackend.NewMemoryStore(), config.Config())
+	assert.NoError(t, err, "not expecting an error without delivery method")
+	assert.Nil(t, d, "expecting no delivery without delivery method")
+
+	os.Setenv(config.DeliveryMethodEnvVar, "pigeon")
+	defer os.Unsetenv(config.DeliveryMethodEnvVar)
+	_, err = New(dbackend.NewMemoryStore(), config.Config())
+	assert.Error(t, err, "expecting an error for an unknown delivery method")
+
+	os.Setenv(config.DeliveryMethodEnvVar, SMTPDeliveryMethod)
+	_, err = New(dbackend.NewMemoryStore(), config.Config())
+	assert.Equal(t, ErrDeliveryFromUnset, err, "expecting an error without from address")
+
+	os.Setenv(config.DeliveryFromEnvVar, "armor@packers.com")
+	defer os.Unsetenv(config.DeliveryFromEnvVar)
+	d, err = New(dbackend.NewMemoryStore(), config.Config())
+	if assert.NoError(t, err, "not expecting an error for smtp delivery") {
+		assert.Equal(t, config.DeliveryRetriesDefault, d.Retries, "expecting the default retries")
+		assert.Equal(t, config.DeliveryRetryIntervalDefault, d.Interval, "expecting the default retry interval")
+	}
+}
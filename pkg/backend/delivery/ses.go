@@ -0,0 +1,42 @@
+package delivery
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/cdwlabs/armor/pkg/config"
+)
+
+// SESMailer is a Mailer sending through AWS SES. The from address must be
+// verified in SES.
+type SESMailer struct{}
+
+// NewSESMailer creates an SESMailer. The SES client is created per message
+// from Armor's default AWS session.
+func NewSESMailer() *SESMailer {
+	return &SESMailer{}
+}
+
+// Send implements Mailer.
+func (m *SESMailer) Send(msg *Message) error {
+	svc := ses.New(config.AWSSession())
+
+	_, err := svc.SendEmail(&ses.SendEmailInput{
+		Source: aws.String(msg.From),
+		Destination: &ses.Destination{
+			ToAddresses: []*string{aws.String(msg.To)},
+		},
+		Message: &ses.Message{
+			Subject: &ses.Content{
+				Charset: aws.String("UTF-8"),
+				Data:    aws.String(msg.Subject),
+			},
+			Body: &ses.Body{
+				Text: &ses.Content{
+					Charset: aws.String("UTF-8"),
+					Data:    aws.String(msg.Body),
+				},
+			},
+		},
+	})
+	return err
+}
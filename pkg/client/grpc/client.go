@@ -2,14 +2,20 @@
 package grpc
 
 import (
+	"crypto/tls"
+	"io"
 	"time"
 
 	jujuratelimit "github.com/juju/ratelimit"
 	stdopentracing "github.com/opentracing/opentracing-go"
 	"github.com/sony/gobreaker"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/cdwlabs/armor/pb"
+	"github.com/cdwlabs/armor/pkg/proxy/auth"
 	vaultendpoints "github.com/cdwlabs/armor/pkg/proxy/endpoints"
 	vaultgrpc "github.com/cdwlabs/armor/pkg/proxy/grpc"
 	vaultservice "github.com/cdwlabs/armor/pkg/proxy/service"
@@ -21,6 +27,17 @@ import (
 	grpctransport "github.com/go-kit/kit/transport/grpc"
 )
 
+// Dial connects to the Vault proxy gRPC server at address, over TLS configured
+// by tlsConfig unless nil.
+func Dial(address string, tlsConfig *tls.Config, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if tlsConfig != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	return grpc.Dial(address, opts...)
+}
+
 // New returns a Vault proxy Service backed by a gRPC client connection.  It is
 // the responsibility of the caller to dial, and later close, the connection.
 func New(conn *grpc.ClientConn, tracer stdopentracing.Tracer, logger log.Logger) vaultservice.Service {
@@ -40,7 +57,7 @@ func New(conn *grpc.ClientConn, tracer stdopentracing.Tracer, logger log.Logger)
 			vaultgrpc.EncodeInitStatusRequest,
 			vaultgrpc.DecodeInitStatusResponse,
 			pb.InitStatusResponse{},
-			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger)),
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
 		).Endpoint()
 		initStatusEndpoint = opentracing.TraceClient(tracer, "InitStatus")(initStatusEndpoint)
 		initStatusEndpoint = limiter(initStatusEndpoint)
@@ -59,7 +76,7 @@ func New(conn *grpc.ClientConn, tracer stdopentracing.Tracer, logger log.Logger)
 			vaultgrpc.EncodeInitRequest,
 			vaultgrpc.DecodeInitResponse,
 			pb.InitResponse{},
-			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger)),
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
 		).Endpoint()
 		initEndpoint = opentracing.TraceClient(tracer, "Init")(initEndpoint)
 		initEndpoint = limiter(initEndpoint)
@@ -78,7 +95,7 @@ func New(conn *grpc.ClientConn, tracer stdopentracing.Tracer, logger log.Logger)
 			vaultgrpc.EncodeSealStatusRequest,
 			vaultgrpc.DecodeSealStatusResponse,
 			pb.SealStatusResponse{},
-			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger)),
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
 		).Endpoint()
 		sealStatusEndpoint = opentracing.TraceClient(tracer, "SealStatus")(sealStatusEndpoint)
 		sealStatusEndpoint = limiter(sealStatusEndpoint)
@@ -88,6 +105,21 @@ func New(conn *grpc.ClientConn, tracer stdopentracing.Tracer, logger log.Logger)
 		}))(sealStatusEndpoint)
 	}
 
+	var watchSealStatusEndpoint endpoint.Endpoint
+	{
+		watchSealStatusEndpoint = makeWatchSealStatusEndpoint(
+			pb.NewVaultClient(conn),
+			opentracing.ToGRPCRequest(tracer, logger),
+			auth.ContextToGRPC(),
+		)
+		watchSealStatusEndpoint = opentracing.TraceClient(tracer, "WatchSealStatus")(watchSealStatusEndpoint)
+		watchSealStatusEndpoint = limiter(watchSealStatusEndpoint)
+		watchSealStatusEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "WatchSealStatus",
+			Timeout: 30 * time.Second,
+		}))(watchSealStatusEndpoint)
+	}
+
 	var unsealEndpoint endpoint.Endpoint
 	{
 		unsealEndpoint = grpctransport.NewClient(
@@ -97,7 +129,7 @@ func New(conn *grpc.ClientConn, tracer stdopentracing.Tracer, logger log.Logger)
 			vaultgrpc.EncodeUnsealRequest,
 			vaultgrpc.DecodeUnsealResponse,
 			pb.UnsealResponse{},
-			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger)),
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
 		).Endpoint()
 		unsealEndpoint = opentracing.TraceClient(tracer, "Unseal")(unsealEndpoint)
 		unsealEndpoint = limiter(unsealEndpoint)
@@ -116,7 +148,7 @@ func New(conn *grpc.ClientConn, tracer stdopentracing.Tracer, logger log.Logger)
 			vaultgrpc.EncodeConfigureRequest,
 			vaultgrpc.DecodeConfigureResponse,
 			pb.ConfigureResponse{},
-			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger)),
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
 		).Endpoint()
 		configureEndpoint = opentracing.TraceClient(tracer, "Configure")(configureEndpoint)
 		configureEndpoint = limiter(configureEndpoint)
@@ -126,11 +158,287 @@ func New(conn *grpc.ClientConn, tracer stdopentracing.Tracer, logger log.Logger)
 		}))(configureEndpoint)
 	}
 
+	var configureStreamEndpoint endpoint.Endpoint
+	{
+		configureStreamEndpoint = makeConfigureStreamEndpoint(
+			pb.NewVaultClient(conn),
+			opentracing.ToGRPCRequest(tracer, logger),
+			auth.ContextToGRPC(),
+		)
+		configureStreamEndpoint = opentracing.TraceClient(tracer, "ConfigureStream")(configureStreamEndpoint)
+		configureStreamEndpoint = limiter(configureStreamEndpoint)
+		configureStreamEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "ConfigureStream",
+			Timeout: 30 * time.Second,
+		}))(configureStreamEndpoint)
+	}
+
+	var listTokenHoldersEndpoint endpoint.Endpoint
+	{
+		listTokenHoldersEndpoint = grpctransport.NewClient(
+			conn,
+			"Vault",
+			"ListTokenHolders",
+			vaultgrpc.EncodeListTokenHoldersRequest,
+			vaultgrpc.DecodeTokenHoldersResponse,
+			pb.TokenHoldersResponse{},
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
+		).Endpoint()
+		listTokenHoldersEndpoint = opentracing.TraceClient(tracer, "ListTokenHolders")(listTokenHoldersEndpoint)
+		listTokenHoldersEndpoint = limiter(listTokenHoldersEndpoint)
+		listTokenHoldersEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "ListTokenHolders",
+			Timeout: 30 * time.Second,
+		}))(listTokenHoldersEndpoint)
+	}
+
+	var getTokenHoldersEndpoint endpoint.Endpoint
+	{
+		getTokenHoldersEndpoint = grpctransport.NewClient(
+			conn,
+			"Vault",
+			"GetTokenHolders",
+			vaultgrpc.EncodeGetTokenHoldersRequest,
+			vaultgrpc.DecodeTokenHoldersResponse,
+			pb.TokenHoldersResponse{},
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
+		).Endpoint()
+		getTokenHoldersEndpoint = opentracing.TraceClient(tracer, "GetTokenHolders")(getTokenHoldersEndpoint)
+		getTokenHoldersEndpoint = limiter(getTokenHoldersEndpoint)
+		getTokenHoldersEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "GetTokenHolders",
+			Timeout: 30 * time.Second,
+		}))(getTokenHoldersEndpoint)
+	}
+
+	var reassignTokenHolderEndpoint endpoint.Endpoint
+	{
+		reassignTokenHolderEndpoint = grpctransport.NewClient(
+			conn,
+			"Vault",
+			"ReassignTokenHolder",
+			vaultgrpc.EncodeReassignTokenHolderRequest,
+			vaultgrpc.DecodeTokenHolderResponse,
+			pb.TokenHolderResponse{},
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
+		).Endpoint()
+		reassignTokenHolderEndpoint = opentracing.TraceClient(tracer, "ReassignTokenHolder")(reassignTokenHolderEndpoint)
+		reassignTokenHolderEndpoint = limiter(reassignTokenHolderEndpoint)
+		reassignTokenHolderEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "ReassignTokenHolder",
+			Timeout: 30 * time.Second,
+		}))(reassignTokenHolderEndpoint)
+	}
+
+	var revokeTokenHolderEndpoint endpoint.Endpoint
+	{
+		revokeTokenHolderEndpoint = grpctransport.NewClient(
+			conn,
+			"Vault",
+			"RevokeTokenHolder",
+			vaultgrpc.EncodeRevokeTokenHolderRequest,
+			vaultgrpc.DecodeTokenHolderResponse,
+			pb.TokenHolderResponse{},
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
+		).Endpoint()
+		revokeTokenHolderEndpoint = opentracing.TraceClient(tracer, "RevokeTokenHolder")(revokeTokenHolderEndpoint)
+		revokeTokenHolderEndpoint = limiter(revokeTokenHolderEndpoint)
+		revokeTokenHolderEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "RevokeTokenHolder",
+			Timeout: 30 * time.Second,
+		}))(revokeTokenHolderEndpoint)
+	}
+
+	var startUnsealCeremonyEndpoint endpoint.Endpoint
+	{
+		startUnsealCeremonyEndpoint = grpctransport.NewClient(
+			conn,
+			"Vault",
+			"StartUnsealCeremony",
+			vaultgrpc.EncodeStartUnsealCeremonyRequest,
+			vaultgrpc.DecodeCeremonyResponse,
+			pb.CeremonyResponse{},
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
+		).Endpoint()
+		startUnsealCeremonyEndpoint = opentracing.TraceClient(tracer, "StartUnsealCeremony")(startUnsealCeremonyEndpoint)
+		startUnsealCeremonyEndpoint = limiter(startUnsealCeremonyEndpoint)
+		startUnsealCeremonyEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "StartUnsealCeremony",
+			Timeout: 30 * time.Second,
+		}))(startUnsealCeremonyEndpoint)
+	}
+
+	var getUnsealCeremonyEndpoint endpoint.Endpoint
+	{
+		getUnsealCeremonyEndpoint = grpctransport.NewClient(
+			conn,
+			"Vault",
+			"GetUnsealCeremony",
+			vaultgrpc.EncodeGetUnsealCeremonyRequest,
+			vaultgrpc.DecodeCeremonyResponse,
+			pb.CeremonyResponse{},
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
+		).Endpoint()
+		getUnsealCeremonyEndpoint = opentracing.TraceClient(tracer, "GetUnsealCeremony")(getUnsealCeremonyEndpoint)
+		getUnsealCeremonyEndpoint = limiter(getUnsealCeremonyEndpoint)
+		getUnsealCeremonyEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "GetUnsealCeremony",
+			Timeout: 30 * time.Second,
+		}))(getUnsealCeremonyEndpoint)
+	}
+
+	var submitUnsealShareEndpoint endpoint.Endpoint
+	{
+		submitUnsealShareEndpoint = grpctransport.NewClient(
+			conn,
+			"Vault",
+			"SubmitUnsealShare",
+			vaultgrpc.EncodeSubmitUnsealShareRequest,
+			vaultgrpc.DecodeCeremonyResponse,
+			pb.CeremonyResponse{},
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
+		).Endpoint()
+		submitUnsealShareEndpoint = opentracing.TraceClient(tracer, "SubmitUnsealShare")(submitUnsealShareEndpoint)
+		submitUnsealShareEndpoint = limiter(submitUnsealShareEndpoint)
+		submitUnsealShareEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "SubmitUnsealShare",
+			Timeout: 30 * time.Second,
+		}))(submitUnsealShareEndpoint)
+	}
+
+	var abortUnsealCeremonyEndpoint endpoint.Endpoint
+	{
+		abortUnsealCeremonyEndpoint = grpctransport.NewClient(
+			conn,
+			"Vault",
+			"AbortUnsealCeremony",
+			vaultgrpc.EncodeAbortUnsealCeremonyRequest,
+			vaultgrpc.DecodeCeremonyResponse,
+			pb.CeremonyResponse{},
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
+		).Endpoint()
+		abortUnsealCeremonyEndpoint = opentracing.TraceClient(tracer, "AbortUnsealCeremony")(abortUnsealCeremonyEndpoint)
+		abortUnsealCeremonyEndpoint = limiter(abortUnsealCeremonyEndpoint)
+		abortUnsealCeremonyEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "AbortUnsealCeremony",
+			Timeout: 30 * time.Second,
+		}))(abortUnsealCeremonyEndpoint)
+	}
+
+	var listClustersEndpoint endpoint.Endpoint
+	{
+		listClustersEndpoint = grpctransport.NewClient(
+			conn,
+			"Vault",
+			"ListClusters",
+			vaultgrpc.EncodeListClustersRequest,
+			vaultgrpc.DecodeClustersResponse,
+			pb.ClustersResponse{},
+			grpctransport.ClientBefore(opentracing.ToGRPCRequest(tracer, logger), auth.ContextToGRPC()),
+		).Endpoint()
+		listClustersEndpoint = opentracing.TraceClient(tracer, "ListClusters")(listClustersEndpoint)
+		listClustersEndpoint = limiter(listClustersEndpoint)
+		listClustersEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "ListClusters",
+			Timeout: 30 * time.Second,
+		}))(listClustersEndpoint)
+	}
+
 	return vaultendpoints.Endpoints{
-		InitStatusEndpoint: initStatusEndpoint,
-		InitEndpoint:       initEndpoint,
-		SealStatusEndpoint: sealStatusEndpoint,
-		UnsealEndpoint:     unsealEndpoint,
-		ConfigureEndpoint:  configureEndpoint,
+		InitStatusEndpoint:          initStatusEndpoint,
+		InitEndpoint:                initEndpoint,
+		SealStatusEndpoint:          sealStatusEndpoint,
+		WatchSealStatusEndpoint:     watchSealStatusEndpoint,
+		UnsealEndpoint:              unsealEndpoint,
+		ConfigureEndpoint:           configureEndpoint,
+		ConfigureStreamEndpoint:     configureStreamEndpoint,
+		ListTokenHoldersEndpoint:    listTokenHoldersEndpoint,
+		GetTokenHoldersEndpoint:     getTokenHoldersEndpoint,
+		ReassignTokenHolderEndpoint: reassignTokenHolderEndpoint,
+		RevokeTokenHolderEndpoint:   revokeTokenHolderEndpoint,
+		StartUnsealCeremonyEndpoint: startUnsealCeremonyEndpoint,
+		GetUnsealCeremonyEndpoint:   getUnsealCeremonyEndpoint,
+		SubmitUnsealShareEndpoint:   submitUnsealShareEndpoint,
+		AbortUnsealCeremonyEndpoint: abortUnsealCeremonyEndpoint,
+		ListClustersEndpoint:        listClustersEndpoint,
+	}
+}
+
+// streamMetadata returns ctx carrying the metadata set by before, as go-kit's
+// unary gRPC client would.
+func streamMetadata(ctx context.Context, before []grpctransport.RequestFunc) context.Context {
+	md := &metadata.MD{}
+	for _, f := range before {
+		ctx = f(ctx, md)
+	}
+	return metadata.NewContext(ctx, *md)
+}
+
+// makeConfigureStreamEndpoint returns an endpoint calling the ConfigureStream
+// RPC, which go-kit's unary gRPC client cannot. The endpoint hands each event
+// to the request's Events as it arrives, and returns the outcome of the done
+// event.
+func makeConfigureStreamEndpoint(client pb.VaultClient, before ...grpctransport.RequestFunc) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(vaultendpoints.ConfigureStreamRequest)
+
+		ctx = streamMetadata(ctx, before)
+		stream, err := client.ConfigureStream(ctx, &pb.ConfigureRequest{
+			Url:     req.URL,
+			Token:   req.Token,
+			Cluster: req.Cluster,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for {
+			event, err := stream.Recv()
+			if err == io.EOF {
+				return nil, io.ErrUnexpectedEOF
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			e := vaultgrpc.DecodeConfigEvent(event)
+			if req.Events != nil {
+				req.Events(e)
+			}
+			if e.Phase == vaultservice.ConfigPhaseDone {
+				state := vaultservice.ConfigState{ConfigID: e.ConfigID}
+				if e.State != nil {
+					state = *e.State
+				}
+				return vaultendpoints.NewConfigureResponse(state, e.Failure()), nil
+			}
+		}
+	}
+}
+
+// makeWatchSealStatusEndpoint returns an endpoint calling the WatchSealStatus
+// RPC, handing each event to the request's Events as it arrives. The watch
+// ends without error once ctx is done.
+func makeWatchSealStatusEndpoint(client pb.VaultClient, before ...grpctransport.RequestFunc) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(vaultendpoints.WatchSealStatusRequest)
+
+		stream, err := client.WatchSealStatus(streamMetadata(ctx, before), &pb.SealStatusRequest{Cluster: req.Cluster})
+		if err != nil {
+			return nil, err
+		}
+
+		for {
+			event, err := stream.Recv()
+			if err == io.EOF || ctx.Err() != nil {
+				return vaultendpoints.WatchSealStatusResponse{}, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			if req.Events != nil {
+				req.Events(vaultgrpc.DecodeSealEvent(event))
+			}
+		}
 	}
 }
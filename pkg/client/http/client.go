@@ -2,6 +2,8 @@
 package http
 
 import (
+	"crypto/tls"
+	"net/http"
 	"net/url"
 	"strings"
 	"time"
@@ -10,6 +12,7 @@ import (
 	stdopentracing "github.com/opentracing/opentracing-go"
 	"github.com/sony/gobreaker"
 
+	"github.com/cdwlabs/armor/pkg/proxy/auth"
 	vaultendpoints "github.com/cdwlabs/armor/pkg/proxy/endpoints"
 	vaulthttp "github.com/cdwlabs/armor/pkg/proxy/http"
 	vaultservice "github.com/cdwlabs/armor/pkg/proxy/service"
@@ -23,14 +26,34 @@ import (
 
 // New creates http client
 func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (vaultservice.Service, error) {
+	return NewTLS(instance, nil, tracer, logger)
+}
+
+// NewTLS creates http client connecting to instance over TLS, configured by
+// tlsConfig, unless nil. Instances without a scheme default to https when
+// tlsConfig is set.
+func NewTLS(instance string, tlsConfig *tls.Config, tracer stdopentracing.Tracer, logger log.Logger) (vaultservice.Service, error) {
 	if !strings.HasPrefix(instance, "http") {
-		instance = "http://" + instance
+		if tlsConfig != nil {
+			instance = "https://" + instance
+		} else {
+			instance = "http://" + instance
+		}
 	}
 	u, err := url.Parse(instance)
 	if err != nil {
 		return nil, err
 	}
 
+	options := []httptransport.ClientOption{
+		httptransport.ClientBefore(opentracing.ToHTTPRequest(tracer, logger), auth.ContextToHTTP()),
+	}
+	if tlsConfig != nil {
+		options = append(options, httptransport.SetClient(&http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		}))
+	}
+
 	// We construct a single ratelimiter middleware, to limit the total outgoing
 	// QPS from this client to all methods on the remote instance.  We also
 	// construct per-endpoint circuitbreaker middleware to demonstrate how that's
@@ -43,9 +66,9 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (vaul
 		initStatusEndpoint = httptransport.NewClient(
 			"GET",
 			copyURL(u, "/init/status"),
-			vaulthttp.EncodeGenericRequest,
+			vaulthttp.EncodeInitStatusRequest,
 			vaulthttp.DecodeInitStatusResponse,
-			httptransport.ClientBefore(opentracing.ToHTTPRequest(tracer, logger)),
+			options...,
 		).Endpoint()
 		initStatusEndpoint = opentracing.TraceClient(tracer, "InitStatus")(initStatusEndpoint)
 		initStatusEndpoint = limiter(initStatusEndpoint)
@@ -62,7 +85,7 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (vaul
 			copyURL(u, "/init"),
 			vaulthttp.EncodeInitRequest,
 			vaulthttp.DecodeInitResponse,
-			httptransport.ClientBefore(opentracing.ToHTTPRequest(tracer, logger)),
+			options...,
 		).Endpoint()
 		initEndpoint = opentracing.TraceClient(tracer, "Init")(initEndpoint)
 		initEndpoint = limiter(initEndpoint)
@@ -77,9 +100,9 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (vaul
 		sealStatusEndpoint = httptransport.NewClient(
 			"GET",
 			copyURL(u, "/seal/status"),
-			vaulthttp.EncodeGenericRequest,
+			vaulthttp.EncodeSealStatusRequest,
 			vaulthttp.DecodeSealStatusResponse,
-			httptransport.ClientBefore(opentracing.ToHTTPRequest(tracer, logger)),
+			options...,
 		).Endpoint()
 		sealStatusEndpoint = opentracing.TraceClient(tracer, "SealStatus")(sealStatusEndpoint)
 		sealStatusEndpoint = limiter(sealStatusEndpoint)
@@ -89,14 +112,32 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (vaul
 		}))(sealStatusEndpoint)
 	}
 
+	var watchSealStatusEndpoint endpoint.Endpoint
+	{
+		watchSealStatusEndpoint = httptransport.NewClient(
+			"GET",
+			copyURL(u, "/seal/status/watch"),
+			vaulthttp.EncodeWatchSealStatusRequest,
+			vaulthttp.DecodeWatchSealStatusResponse,
+			options...,
+		).Endpoint()
+		watchSealStatusEndpoint = vaulthttp.SealEventsToContext(watchSealStatusEndpoint)
+		watchSealStatusEndpoint = opentracing.TraceClient(tracer, "WatchSealStatus")(watchSealStatusEndpoint)
+		watchSealStatusEndpoint = limiter(watchSealStatusEndpoint)
+		watchSealStatusEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "WatchSealStatus",
+			Timeout: 30 * time.Second,
+		}))(watchSealStatusEndpoint)
+	}
+
 	var unsealEndpoint endpoint.Endpoint
 	{
 		unsealEndpoint = httptransport.NewClient(
 			"PUT",
 			copyURL(u, "/unseal"),
-			vaulthttp.EncodeGenericRequest,
+			vaulthttp.EncodeUnsealRequest,
 			vaulthttp.DecodeUnsealResponse,
-			httptransport.ClientBefore(opentracing.ToHTTPRequest(tracer, logger)),
+			options...,
 		).Endpoint()
 		unsealEndpoint = opentracing.TraceClient(tracer, "Unseal")(unsealEndpoint)
 		unsealEndpoint = limiter(unsealEndpoint)
@@ -113,7 +154,7 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (vaul
 			copyURL(u, "/configure"),
 			vaulthttp.EncodeGenericRequest,
 			vaulthttp.DecodeConfigureResponse,
-			httptransport.ClientBefore(opentracing.ToHTTPRequest(tracer, logger)),
+			options...,
 		).Endpoint()
 		configureEndpoint = opentracing.TraceClient(tracer, "Configure")(configureEndpoint)
 		configureEndpoint = limiter(configureEndpoint)
@@ -123,12 +164,194 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (vaul
 		}))(configureEndpoint)
 	}
 
+	var configureStreamEndpoint endpoint.Endpoint
+	{
+		configureStreamEndpoint = httptransport.NewClient(
+			"POST",
+			copyURL(u, "/configure/stream"),
+			vaulthttp.EncodeGenericRequest,
+			vaulthttp.DecodeConfigureStreamResponse,
+			options...,
+		).Endpoint()
+		configureStreamEndpoint = vaulthttp.ConfigEventsToContext(configureStreamEndpoint)
+		configureStreamEndpoint = opentracing.TraceClient(tracer, "ConfigureStream")(configureStreamEndpoint)
+		configureStreamEndpoint = limiter(configureStreamEndpoint)
+		configureStreamEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "ConfigureStream",
+			Timeout: 30 * time.Second,
+		}))(configureStreamEndpoint)
+	}
+
+	var listTokenHoldersEndpoint endpoint.Endpoint
+	{
+		listTokenHoldersEndpoint = httptransport.NewClient(
+			"GET",
+			copyURL(u, "/token-holders"),
+			vaulthttp.EncodeListTokenHoldersRequest,
+			vaulthttp.DecodeTokenHoldersResponse,
+			options...,
+		).Endpoint()
+		listTokenHoldersEndpoint = opentracing.TraceClient(tracer, "ListTokenHolders")(listTokenHoldersEndpoint)
+		listTokenHoldersEndpoint = limiter(listTokenHoldersEndpoint)
+		listTokenHoldersEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "ListTokenHolders",
+			Timeout: 30 * time.Second,
+		}))(listTokenHoldersEndpoint)
+	}
+
+	var getTokenHoldersEndpoint endpoint.Endpoint
+	{
+		getTokenHoldersEndpoint = httptransport.NewClient(
+			"GET",
+			copyURL(u, "/token-holders"),
+			vaulthttp.EncodeGetTokenHoldersRequest,
+			vaulthttp.DecodeTokenHoldersResponse,
+			options...,
+		).Endpoint()
+		getTokenHoldersEndpoint = opentracing.TraceClient(tracer, "GetTokenHolders")(getTokenHoldersEndpoint)
+		getTokenHoldersEndpoint = limiter(getTokenHoldersEndpoint)
+		getTokenHoldersEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "GetTokenHolders",
+			Timeout: 30 * time.Second,
+		}))(getTokenHoldersEndpoint)
+	}
+
+	var reassignTokenHolderEndpoint endpoint.Endpoint
+	{
+		reassignTokenHolderEndpoint = httptransport.NewClient(
+			"POST",
+			copyURL(u, "/token-holders/reassign"),
+			vaulthttp.EncodeReassignTokenHolderRequest,
+			vaulthttp.DecodeTokenHolderResponse,
+			options...,
+		).Endpoint()
+		reassignTokenHolderEndpoint = opentracing.TraceClient(tracer, "ReassignTokenHolder")(reassignTokenHolderEndpoint)
+		reassignTokenHolderEndpoint = limiter(reassignTokenHolderEndpoint)
+		reassignTokenHolderEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "ReassignTokenHolder",
+			Timeout: 30 * time.Second,
+		}))(reassignTokenHolderEndpoint)
+	}
+
+	var revokeTokenHolderEndpoint endpoint.Endpoint
+	{
+		revokeTokenHolderEndpoint = httptransport.NewClient(
+			"POST",
+			copyURL(u, "/token-holders/revoke"),
+			vaulthttp.EncodeRevokeTokenHolderRequest,
+			vaulthttp.DecodeTokenHolderResponse,
+			options...,
+		).Endpoint()
+		revokeTokenHolderEndpoint = opentracing.TraceClient(tracer, "RevokeTokenHolder")(revokeTokenHolderEndpoint)
+		revokeTokenHolderEndpoint = limiter(revokeTokenHolderEndpoint)
+		revokeTokenHolderEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "RevokeTokenHolder",
+			Timeout: 30 * time.Second,
+		}))(revokeTokenHolderEndpoint)
+	}
+
+	var startUnsealCeremonyEndpoint endpoint.Endpoint
+	{
+		startUnsealCeremonyEndpoint = httptransport.NewClient(
+			"POST",
+			copyURL(u, "/unseal/ceremonies"),
+			vaulthttp.EncodeStartUnsealCeremonyRequest,
+			vaulthttp.DecodeCeremonyResponse,
+			options...,
+		).Endpoint()
+		startUnsealCeremonyEndpoint = opentracing.TraceClient(tracer, "StartUnsealCeremony")(startUnsealCeremonyEndpoint)
+		startUnsealCeremonyEndpoint = limiter(startUnsealCeremonyEndpoint)
+		startUnsealCeremonyEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "StartUnsealCeremony",
+			Timeout: 30 * time.Second,
+		}))(startUnsealCeremonyEndpoint)
+	}
+
+	var getUnsealCeremonyEndpoint endpoint.Endpoint
+	{
+		getUnsealCeremonyEndpoint = httptransport.NewClient(
+			"GET",
+			copyURL(u, "/unseal/ceremonies"),
+			vaulthttp.EncodeGetUnsealCeremonyRequest,
+			vaulthttp.DecodeCeremonyResponse,
+			options...,
+		).Endpoint()
+		getUnsealCeremonyEndpoint = opentracing.TraceClient(tracer, "GetUnsealCeremony")(getUnsealCeremonyEndpoint)
+		getUnsealCeremonyEndpoint = limiter(getUnsealCeremonyEndpoint)
+		getUnsealCeremonyEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "GetUnsealCeremony",
+			Timeout: 30 * time.Second,
+		}))(getUnsealCeremonyEndpoint)
+	}
+
+	var submitUnsealShareEndpoint endpoint.Endpoint
+	{
+		submitUnsealShareEndpoint = httptransport.NewClient(
+			"POST",
+			copyURL(u, "/unseal/ceremonies"),
+			vaulthttp.EncodeSubmitUnsealShareRequest,
+			vaulthttp.DecodeCeremonyResponse,
+			options...,
+		).Endpoint()
+		submitUnsealShareEndpoint = opentracing.TraceClient(tracer, "SubmitUnsealShare")(submitUnsealShareEndpoint)
+		submitUnsealShareEndpoint = limiter(submitUnsealShareEndpoint)
+		submitUnsealShareEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "SubmitUnsealShare",
+			Timeout: 30 * time.Second,
+		}))(submitUnsealShareEndpoint)
+	}
+
+	var abortUnsealCeremonyEndpoint endpoint.Endpoint
+	{
+		abortUnsealCeremonyEndpoint = httptransport.NewClient(
+			"DELETE",
+			copyURL(u, "/unseal/ceremonies"),
+			vaulthttp.EncodeAbortUnsealCeremonyRequest,
+			vaulthttp.DecodeCeremonyResponse,
+			options...,
+		).Endpoint()
+		abortUnsealCeremonyEndpoint = opentracing.TraceClient(tracer, "AbortUnsealCeremony")(abortUnsealCeremonyEndpoint)
+		abortUnsealCeremonyEndpoint = limiter(abortUnsealCeremonyEndpoint)
+		abortUnsealCeremonyEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "AbortUnsealCeremony",
+			Timeout: 30 * time.Second,
+		}))(abortUnsealCeremonyEndpoint)
+	}
+
+	var listClustersEndpoint endpoint.Endpoint
+	{
+		listClustersEndpoint = httptransport.NewClient(
+			"GET",
+			copyURL(u, "/clusters"),
+			vaulthttp.EncodeGenericRequest,
+			vaulthttp.DecodeClustersResponse,
+			options...,
+		).Endpoint()
+		listClustersEndpoint = opentracing.TraceClient(tracer, "ListClusters")(listClustersEndpoint)
+		listClustersEndpoint = limiter(listClustersEndpoint)
+		listClustersEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    "ListClusters",
+			Timeout: 30 * time.Second,
+		}))(listClustersEndpoint)
+	}
+
 	return vaultendpoints.Endpoints{
-		InitStatusEndpoint: initStatusEndpoint,
-		InitEndpoint:       initEndpoint,
-		SealStatusEndpoint: sealStatusEndpoint,
-		UnsealEndpoint:     unsealEndpoint,
-		ConfigureEndpoint:  configureEndpoint,
+		InitStatusEndpoint:          initStatusEndpoint,
+		InitEndpoint:                initEndpoint,
+		SealStatusEndpoint:          sealStatusEndpoint,
+		WatchSealStatusEndpoint:     watchSealStatusEndpoint,
+		UnsealEndpoint:              unsealEndpoint,
+		ConfigureEndpoint:           configureEndpoint,
+		ConfigureStreamEndpoint:     configureStreamEndpoint,
+		ListTokenHoldersEndpoint:    listTokenHoldersEndpoint,
+		GetTokenHoldersEndpoint:     getTokenHoldersEndpoint,
+		ReassignTokenHolderEndpoint: reassignTokenHolderEndpoint,
+		RevokeTokenHolderEndpoint:   revokeTokenHolderEndpoint,
+		StartUnsealCeremonyEndpoint: startUnsealCeremonyEndpoint,
+		GetUnsealCeremonyEndpoint:   getUnsealCeremonyEndpoint,
+		SubmitUnsealShareEndpoint:   submitUnsealShareEndpoint,
+		AbortUnsealCeremonyEndpoint: abortUnsealCeremonyEndpoint,
+		ListClustersEndpoint:        listClustersEndpoint,
 	}, nil
 }
 
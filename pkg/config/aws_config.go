@@ -10,9 +10,14 @@ var defaultAWSConfig *aws.Config
 
 var defaultAWSSession *session.Session
 
-// AWSConfig returns the default configuration which is bound to defined
-// configuration at start-up.
+// AWSConfig returns the default configuration. Its credentials are bound to
+// Armor's configuration the first time AWSConfig is called, so it must not be
+// called before flags are parsed. See NewAWSCredentials.
 func AWSConfig() *aws.Config {
+	if defaultAWSConfig == nil {
+		defaultAWSConfig = aws.NewConfig().WithCredentials(NewAWSCredentials())
+	}
+
 	return defaultAWSConfig
 }
 
@@ -37,7 +42,3 @@ func NewAWSSession() (client.ConfigProvider, error) {
 
 	return sess, err
 }
-
-func init() {
-	defaultAWSConfig = aws.NewConfig().WithCredentials(NewArmorAWSCredentials())
-}
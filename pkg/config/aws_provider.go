@@ -1,8 +1,12 @@
 package config
 
 import (
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/defaults"
+	"github.com/aws/aws-sdk-go/aws/session"
 )
 
 // ArmorAWSProviderName is what we're calling the custom AWS Credentials
@@ -76,3 +80,51 @@ func (e *ArmorAWSProvider) Retrieve() (credentials.Value, error) {
 func (e *ArmorAWSProvider) IsExpired() bool {
 	return !e.retrieved
 }
+
+// NewAWSCredentials returns the credentials of Armor's backend. They are
+// retrieved from the first provider of the chain which has any:
+//
+//   - Armor's own config, see ArmorAWSProvider
+//   - AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables
+//   - the shared credentials file, with the profile named by aws_profile or
+//     the AWS_PROFILE environment variable
+//   - a web identity token, e.g. of an EKS service account, see
+//     WebIdentityProvider
+//   - the ECS task role, or the EC2 instance role
+//
+// If aws_assume_role_arn is set, the credentials of the chain are only used
+// to assume that role.
+func NewAWSCredentials() *credentials.Credentials {
+	cfg := Config()
+	remote := defaults.RemoteCredProvider(*defaults.Config(), defaults.Handlers())
+
+	creds := credentials.NewCredentials(&credentials.ChainProvider{
+		VerboseErrors: true,
+		Providers: []credentials.Provider{
+			&ArmorAWSProvider{},
+			&credentials.EnvProvider{},
+			&credentials.SharedCredentialsProvider{Profile: cfg.GetString("aws_profile")},
+			NewWebIdentityProvider(),
+			remote,
+		},
+	})
+
+	roleARN := cfg.GetString("aws_assume_role_arn")
+	if roleARN == "" {
+		return creds
+	}
+
+	sess := session.Must(session.NewSession(aws.NewConfig().WithCredentials(creds)))
+	return stscreds.NewCredentials(sess, roleARN, assumeRoleOptions(cfg))
+}
+
+// assumeRoleOptions sets the session name and external id of the role
+// assumed with the credentials of the chain.
+func assumeRoleOptions(cfg Provider) func(*stscreds.AssumeRoleProvider) {
+	return func(p *stscreds.AssumeRoleProvider) {
+		p.RoleSessionName = cfg.GetString("aws_assume_role_session_name")
+		if externalID := cfg.GetString("aws_assume_role_external_id"); externalID != "" {
+			p.ExternalID = aws.String(externalID)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/stretchr/testify/assert"
+)
+
+// setEnv sets the environment variables of env, unsetting those which are
+// empty, and returns a func restoring their previous values.
+func setEnv(env map[string]string) func() {
+	prev := make(map[string]string, len(env))
+	for k, v := range env {
+		prev[k] = os.Getenv(k)
+		if v == "" {
+			os.Unsetenv(k)
+		} else {
+			os.Setenv(k, v)
+		}
+	}
+	return func() {
+		for k, v := range prev {
+			if v == "" {
+				os.Unsetenv(k)
+			} else {
+				os.Setenv(k, v)
+			}
+		}
+	}
+}
+
+func TestNewAWSCredentials(t *testing.T) {
+	cases := []struct {
+		name        string
+		env         map[string]string
+		provider    string
+		accessKeyID string
+	}{
+		{
+			name: "armor config",
+			env: map[string]string{
+				AWSAccessKeyIDEnvVar:          "AKIDARMOR",
+				AWSSecretAccessKeyEnvVar:      "armor-secret",
+				"AWS_ACCESS_KEY_ID":           "AKIDENV",
+				"AWS_SECRET_ACCESS_KEY":       "env-secret",
+				"AWS_SHARED_CREDENTIALS_FILE": "test-fixtures/aws-credentials",
+			},
+			provider:    ArmorAWSProviderName,
+			accessKeyID: "AKIDARMOR",
+		},
+		{
+			name: "environment without armor keys",
+			env: map[string]string{
+				AWSAccessKeyIDEnvVar:          "AKIDARMOR",
+				"AWS_ACCESS_KEY_ID":           "AKIDENV",
+				"AWS_SECRET_ACCESS_KEY":       "env-secret",
+				"AWS_SHARED_CREDENTIALS_FILE": "test-fixtures/aws-credentials",
+			},
+			provider:    credentials.EnvProviderName,
+			accessKeyID: "AKIDENV",
+		},
+		{
+			name: "shared profile without environment",
+			env: map[string]string{
+				AWSProfileEnvVar:              "armor",
+				"AWS_SHARED_CREDENTIALS_FILE": "test-fixtures/aws-credentials",
+			},
+			provider:    credentials.SharedCredsProviderName,
+			accessKeyID: "AKIDPROFILE",
+		},
+		{
+			name: "shared default profile",
+			env: map[string]string{
+				"AWS_SHARED_CREDENTIALS_FILE": "test-fixtures/aws-credentials",
+			},
+			provider:    credentials.SharedCredsProviderName,
+			accessKeyID: "AKIDDEFAULT",
+		},
+	}
+
+	for _, c := range cases {
+		env := map[string]string{
+			AWSAccessKeyIDEnvVar:          "",
+			AWSSecretAccessKeyEnvVar:      "",
+			AWSProfileEnvVar:              "",
+			AWSAssumeRoleARNEnvVar:        "",
+			"AWS_ACCESS_KEY_ID":           "",
+			"AWS_SECRET_ACCESS_KEY":       "",
+			"AWS_PROFILE":                 "",
+			"AWS_SHARED_CREDENTIALS_FILE": "",
+		}
+		for k, v := range c.env {
+			env[k] = v
+		}
+		restore := setEnv(env)
+
+		value, err := NewAWSCredentials().Get()
+		if assert.NoError(t, err, "not expecting an error for %s", c.name) {
+			assert.Equal(t, c.provider, value.ProviderName, "expecting the provider of %s", c.name)
+			assert.Equal(t, c.accessKeyID, value.AccessKeyID, "expecting the credentials of %s", c.name)
+		}
+
+		restore()
+	}
+}
+
+func TestWebIdentityProvider(t *testing.T) {
+	cases := []struct {
+		name      string
+		tokenFile string
+		roleARN   string
+	}{
+		{name: "neither token file nor role"},
+		{name: "token file without role", tokenFile: "test-fixtures/aws-credentials"},
+		{name: "role without token file", roleARN: "arn:aws:iam::123456789012:role/armor"},
+	}
+
+	for _, c := range cases {
+		restore := setEnv(map[string]string{
+			"AWS_WEB_IDENTITY_TOKEN_FILE": c.tokenFile,
+			"AWS_ROLE_ARN":                c.roleARN,
+		})
+
+		p := NewWebIdentityProvider()
+		assert.Equal(t, c.tokenFile, p.TokenFile, "expecting the token file of the environment")
+		assert.Equal(t, c.roleARN, p.RoleARN, "expecting the role of the environment")
+
+		value, err := p.Retrieve()
+		assert.Equal(t, ErrWebIdentityNotFound, err, "expecting an error for %s", c.name)
+		assert.Equal(t, WebIdentityProviderName, value.ProviderName, "expecting the provider name for %s", c.name)
+
+		restore()
+	}
+
+	p := &WebIdentityProvider{TokenFile: "test-fixtures/missing-token", RoleARN: "arn:aws:iam::123456789012:role/armor"}
+	_, err := p.Retrieve()
+	assert.True(t, os.IsNotExist(err), "expecting an error for a missing token file")
+}
+
+func TestAssumeRoleOptions(t *testing.T) {
+	cases := []struct {
+		name        string
+		env         map[string]string
+		sessionName string
+		externalID  *string
+	}{
+		{
+			name:        "defaults",
+			sessionName: AWSAssumeRoleSessionNameDefault,
+		},
+		{
+			name: "session name and external id",
+			env: map[string]string{
+				AWSAssumeRoleSessionNameEnvVar: "armor-prod",
+				AWSAssumeRoleExternalIDEnvVar:  "lambeau",
+			},
+			sessionName: "armor-prod",
+			externalID:  aws.String("lambeau"),
+		},
+	}
+
+	for _, c := range cases {
+		env := map[string]string{
+			AWSAssumeRoleSessionNameEnvVar: "",
+			AWSAssumeRoleExternalIDEnvVar:  "",
+		}
+		for k, v := range c.env {
+			env[k] = v
+		}
+		restore := setEnv(env)
+
+		p := &stscreds.AssumeRoleProvider{}
+		assumeRoleOptions(Config())(p)
+		assert.Equal(t, c.sessionName, p.RoleSessionName, "expecting the session name of %s", c.name)
+		assert.Equal(t, c.externalID, p.ExternalID, "expecting the external id of %s", c.name)
+
+		restore()
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// WebIdentityProviderName is the name of the web identity credentials
+// provider.
+const WebIdentityProviderName = "WebIdentityProvider"
+
+// ErrWebIdentityNotFound is returned when no web identity token file or role
+// is set in the environment.
+//
+// @readonly
+var ErrWebIdentityNotFound = awserr.New("WebIdentityNotFound", "AWS_WEB_IDENTITY_TOKEN_FILE or AWS_ROLE_ARN not found in environment", nil)
+
+// A WebIdentityProvider retrieves credentials by assuming a role with a web
+// identity token, e.g. the token EKS mounts into pods of a service account
+// annotated with an IAM role.
+//
+// Environment variables used:
+//
+// * Token file: AWS_WEB_IDENTITY_TOKEN_FILE
+// * Role ARN: AWS_ROLE_ARN
+// * Role session name: AWS_ROLE_SESSION_NAME (optional)
+type WebIdentityProvider struct {
+	credentials.Expiry
+
+	TokenFile       string
+	RoleARN         string
+	RoleSessionName string
+
+	// ExpiryWindow lets the credentials expire early, so they are refreshed
+	// before requests fail.
+	ExpiryWindow time.Duration
+}
+
+// NewWebIdentityProvider returns a WebIdentityProvider set from the
+// environment.
+func NewWebIdentityProvider() *WebIdentityProvider {
+	return &WebIdentityProvider{
+		TokenFile:       os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"),
+		RoleARN:         os.Getenv("AWS_ROLE_ARN"),
+		RoleSessionName: os.Getenv("AWS_ROLE_SESSION_NAME"),
+		ExpiryWindow:    time.Minute,
+	}
+}
+
+// Retrieve assumes the role with the token read from the token file. The
+// token file is read on every call, since it is rotated.
+func (p *WebIdentityProvider) Retrieve() (credentials.Value, error) {
+	if p.TokenFile == "" || p.RoleARN == "" {
+		return credentials.Value{ProviderName: WebIdentityProviderName}, ErrWebIdentityNotFound
+	}
+
+	token, err := ioutil.ReadFile(p.TokenFile)
+	if err != nil {
+		return credentials.Value{ProviderName: WebIdentityProviderName}, err
+	}
+
+	sessionName := p.RoleSessionName
+	if sessionName == "" {
+		sessionName = "armor"
+	}
+
+	// the request is authenticated by the token, it's not signed
+	sess, err := session.NewSession(aws.NewConfig().WithCredentials(credentials.AnonymousCredentials))
+	if err != nil {
+		return credentials.Value{ProviderName: WebIdentityProviderName}, err
+	}
+
+	svc := sts.New(sess)
+	out, err := svc.AssumeRoleWithWebIdentity(&sts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(p.RoleARN),
+		RoleSessionName:  aws.String(sessionName),
+		WebIdentityToken: aws.String(string(token)),
+	})
+	if err != nil {
+		return credentials.Value{ProviderName: WebIdentityProviderName}, err
+	}
+
+	p.SetExpiration(aws.TimeValue(out.Credentials.Expiration), p.ExpiryWindow)
+	return credentials.Value{
+		AccessKeyID:     aws.StringValue(out.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(out.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(out.Credentials.SessionToken),
+		ProviderName:    WebIdentityProviderName,
+	}, nil
+}
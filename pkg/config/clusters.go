@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+// ErrClusterAddressUnset is returned for a cluster of the config file without
+// address.
+var ErrClusterAddressUnset = errors.New("vault cluster address not set")
+
+// DefaultClusterName names the Vault cluster set by Armor's vault_*
+// configuration (and the Vault CLI's VAULT_* environment variables). It can be
+// replaced by a cluster of the same name in the config file.
+const DefaultClusterName string = "default"
+
+// Cluster is a named Vault cluster of the clusters section of Armor's config
+// file, e.g.
+//
+//	clusters:
+//	  prod:
+//	    address: https://vault.prod.example.com:8200
+//	    cluster_id: prod
+//	    ca_cert: /etc/armor/prod-ca.pem
+//	    nodes:
+//	      - https://vault-0.prod.example.com:8200
+//	      - https://vault-1.prod.example.com:8200
+//
+// Cluster names are case insensitive, like every other key.
+type Cluster struct {
+	Name          string
+	Address       string   `mapstructure:"address"`
+	Nodes         []string `mapstructure:"nodes"`      // of an HA cluster; the address alone if not set
+	ClusterID     string   `mapstructure:"cluster_id"` // token holders are kept under; the address if not set
+	CACert        string   `mapstructure:"ca_cert"`
+	CAPath        string   `mapstructure:"ca_path"`
+	ClientCert    string   `mapstructure:"client_cert"`
+	ClientKey     string   `mapstructure:"client_key"`
+	TLSServerName string   `mapstructure:"tls_server_name"`
+	SkipVerify    bool     `mapstructure:"skip_verify"`
+}
+
+// Clusters returns the clusters of the config file, by name.
+func Clusters() (map[string]Cluster, error) {
+	return clustersOf(defaultConfig)
+}
+
+func clustersOf(v *viper.Viper) (map[string]Cluster, error) {
+	clusters := make(map[string]Cluster)
+	err := v.UnmarshalKey("clusters", &clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, cluster := range clusters {
+		if cluster.Address == "" {
+			return nil, fmt.Errorf("%s: %q", ErrClusterAddressUnset.Error(), name)
+		}
+		cluster.Name = name
+		clusters[name] = cluster
+	}
+	return clusters, nil
+}
+
+// ClusterNames returns the name of the default cluster, and of every cluster
+// of the config file, sorted.
+func ClusterNames() ([]string, error) {
+	clusters, err := Clusters()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(clusters)+1)
+	if _, ok := clusters[DefaultClusterName]; !ok {
+		names = append(names, DefaultClusterName)
+	}
+	for name := range clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusters(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	err := v.ReadConfig(bytes.NewBufferString(`
+clusters:
+  prod:
+    address: https://vault.prod.packers.com:8200
+    cluster_id: lambeau
+    ca_cert: /etc/armor/prod-ca.pem
+    skip_verify: false
+    nodes:
+      - https://vault-0.prod.packers.com:8200
+      - https://vault-1.prod.packers.com:8200
+  staging:
+    address: https://vault.staging.packers.com:8200
+    skip_verify: true
+`))
+	assert.NoError(t, err, "not expecting an error when reading the config")
+
+	clusters, err := clustersOf(v)
+	assert.NoError(t, err, "not expecting an error when parsing clusters")
+	assert.Equal(t, Cluster{Name: "prod", Address: "https://vault.prod.packers.com:8200", Nodes: []string{"https://vault-0.prod.packers.com:8200", "https://vault-1.prod.packers.com:8200"}, ClusterID: "lambeau", CACert: "/etc/armor/prod-ca.pem"}, clusters["prod"], "expecting every setting of the cluster")
+	assert.Equal(t, Cluster{Name: "staging", Address: "https://vault.staging.packers.com:8200", SkipVerify: true}, clusters["staging"], "expecting every setting of the cluster")
+
+	v.Set("clusters", map[string]interface{}{"prod": map[string]interface{}{"cluster_id": "lambeau"}})
+	_, err = clustersOf(v)
+	assert.Error(t, err, "expecting an error for a cluster without address")
+
+	names, err := ClusterNames()
+	assert.NoError(t, err, "not expecting an error when listing cluster names")
+	assert.Equal(t, []string{DefaultClusterName}, names, "expecting the default cluster when none is configured")
+}
@@ -84,10 +84,46 @@ func viperConfig(appName string) *viper.Viper {
 	v.BindEnv("lightstep_token", LightstepTokenEnvVar)
 	v.SetDefault("lightstep_token", "")
 
+	// listener tls certificate, key and client ca
+	v.BindEnv("tls_cert_file", TLSCertFileEnvVar)
+	v.SetDefault("tls_cert_file", "")
+	v.BindEnv("tls_key_file", TLSKeyFileEnvVar)
+	v.SetDefault("tls_key_file", "")
+	v.BindEnv("tls_client_ca_file", TLSClientCAFileEnvVar)
+	v.SetDefault("tls_client_ca_file", "")
+
+	// require client certificates of the http and grpc listeners
+	v.BindEnv("tls_require_client_cert", TLSRequireClientCertEnvVar)
+	v.SetDefault("tls_require_client_cert", TLSRequireClientCertDefault)
+
+	// require client certificates of the admin listener
+	v.BindEnv("admin_tls_require_client_cert", AdminTLSRequireClientCertEnvVar)
+	v.SetDefault("admin_tls_require_client_cert", AdminTLSRequireClientCertDefault)
+
+	// auth policy file, authorizing the methods of each identity
+	v.BindEnv("auth_policy_file", AuthPolicyFileEnvVar)
+	v.SetDefault("auth_policy_file", "")
+
+	// validate bearer tokens with vault
+	v.BindEnv("auth_vault_tokens", AuthVaultTokensEnvVar)
+	v.SetDefault("auth_vault_tokens", AuthVaultTokensDefault)
+
 	// vault server listen address
 	v.BindEnv("vault_address", VaultAddrEnvVar)
 	v.SetDefault("vault_address", VaultAddrDefault)
 
+	// vault node addresses, of every node of an HA vault cluster
+	v.BindEnv("vault_nodes", VaultNodesEnvVar)
+	v.SetDefault("vault_nodes", []string{})
+
+	// vault cluster identifier used to key token holders
+	v.BindEnv("vault_cluster_id", VaultClusterIDEnvVar)
+	v.SetDefault("vault_cluster_id", "")
+
+	// vault cluster of requests which don't name one
+	v.BindEnv("default_cluster", DefaultClusterEnvVar)
+	v.SetDefault("default_cluster", DefaultClusterName)
+
 	// vault server ca cert
 	v.BindEnv("vault_ca_cert", VaultCACertEnvVar)
 	v.SetDefault("vault_ca_cert", "")
@@ -100,10 +136,130 @@ func viperConfig(appName string) *viper.Viper {
 	v.BindEnv("vault_skip_verify", VaultSkipVerifyEnvVar)
 	v.SetDefault("vault_skip_verify", VaultSkipVerifyDefault)
 
+	// vault server plugin directory
+	v.BindEnv("vault_plugin_dir", VaultPluginDirEnvVar)
+	v.SetDefault("vault_plugin_dir", "")
+
+	// additional vault secret backend types accepted by configure
+	v.BindEnv("vault_mount_types", VaultMountTypesEnvVar)
+	v.SetDefault("vault_mount_types", []string{})
+
+	// additional vault auth backend types accepted by configure
+	v.BindEnv("vault_auth_types", VaultAuthTypesEnvVar)
+	v.SetDefault("vault_auth_types", []string{})
+
 	// armor policy download directory
 	v.BindEnv("policy_config_dir", PolicyConfigPathEnvVar)
 	v.SetDefault("policy_config_dir", PolicyConfigPathDefault)
 
+	// token holder store implementation
+	v.BindEnv("token_holder_store", TokenHolderStoreEnvVar)
+	v.SetDefault("token_holder_store", TokenHolderStoreDefault)
+
+	// token holder file store location
+	v.BindEnv("token_holder_store_path", TokenHolderStorePathEnvVar)
+	v.SetDefault("token_holder_store_path", TokenHolderStorePathDefault)
+
+	// token encryption key provider
+	v.BindEnv("token_encryption", TokenEncryptionEnvVar)
+	v.SetDefault("token_encryption", "")
+
+	// token encryption aws kms master key
+	v.BindEnv("token_encryption_kms_key_id", TokenEncryptionKMSKeyIDEnvVar)
+	v.SetDefault("token_encryption_kms_key_id", "")
+
+	// token encryption local master key file
+	v.BindEnv("token_encryption_key_file", TokenEncryptionKeyFileEnvVar)
+	v.SetDefault("token_encryption_key_file", "")
+
+	// token encryption local master key files in use before rotation
+	v.BindEnv("token_encryption_previous_key_files", TokenEncryptionPreviousKeyFilesEnvVar)
+	v.SetDefault("token_encryption_previous_key_files", []string{})
+
+	// token holder pgp public keys
+	v.BindEnv("pgp_keyring_dir", PGPKeyringDirEnvVar)
+	v.SetDefault("pgp_keyring_dir", "")
+
+	// refuse to init vault without pgp encrypting every token
+	v.BindEnv("enforce_pgp", EnforcePGPEnvVar)
+	v.SetDefault("enforce_pgp", EnforcePGPDefault)
+
+	// lease on a pending vault init
+	v.BindEnv("init_lock_ttl", InitLockTTLEnvVar)
+	v.SetDefault("init_lock_ttl", InitLockTTLDefault)
+
+	// auto-unseal controller
+	v.BindEnv("auto_unseal", AutoUnsealEnvVar)
+	v.SetDefault("auto_unseal", AutoUnsealDefault)
+
+	// auto-unseal seal status poll interval
+	v.BindEnv("auto_unseal_interval", AutoUnsealIntervalEnvVar)
+	v.SetDefault("auto_unseal_interval", AutoUnsealIntervalDefault)
+
+	// auto-unseal attempts allowed per node and window
+	v.BindEnv("auto_unseal_max_attempts", AutoUnsealMaxAttemptsEnvVar)
+	v.SetDefault("auto_unseal_max_attempts", AutoUnsealMaxAttemptsDefault)
+
+	// auto-unseal rate limiting window
+	v.BindEnv("auto_unseal_window", AutoUnsealWindowEnvVar)
+	v.SetDefault("auto_unseal_window", AutoUnsealWindowDefault)
+
+	// auto-unseal alert recipients
+	v.BindEnv("auto_unseal_alert_emails", AutoUnsealAlertEmailsEnvVar)
+	v.SetDefault("auto_unseal_alert_emails", []string{})
+
+	// auto-unseal audit file
+	v.BindEnv("auto_unseal_audit_file", AutoUnsealAuditFileEnvVar)
+	v.SetDefault("auto_unseal_audit_file", "")
+
+	// unseal ceremony expiry
+	v.BindEnv("unseal_ceremony_ttl", UnsealCeremonyTTLEnvVar)
+	v.SetDefault("unseal_ceremony_ttl", UnsealCeremonyTTLDefault)
+
+	// seal status watch poll interval
+	v.BindEnv("seal_watch_interval", SealWatchIntervalEnvVar)
+	v.SetDefault("seal_watch_interval", SealWatchIntervalDefault)
+
+	// seal status watch heartbeat interval
+	v.BindEnv("seal_watch_heartbeat", SealWatchHeartbeatEnvVar)
+	v.SetDefault("seal_watch_heartbeat", SealWatchHeartbeatDefault)
+
+	// token delivery mailer
+	v.BindEnv("delivery_method", DeliveryMethodEnvVar)
+	v.SetDefault("delivery_method", "")
+
+	// token delivery from address
+	v.BindEnv("delivery_from", DeliveryFromEnvVar)
+	v.SetDefault("delivery_from", "")
+
+	// token delivery subject template file
+	v.BindEnv("delivery_subject_template", DeliverySubjectTemplateEnvVar)
+	v.SetDefault("delivery_subject_template", "")
+
+	// token delivery body template file
+	v.BindEnv("delivery_body_template", DeliveryBodyTemplateEnvVar)
+	v.SetDefault("delivery_body_template", "")
+
+	// token delivery retries
+	v.BindEnv("delivery_retries", DeliveryRetriesEnvVar)
+	v.SetDefault("delivery_retries", DeliveryRetriesDefault)
+
+	// token delivery wait between retries
+	v.BindEnv("delivery_retry_interval", DeliveryRetryIntervalEnvVar)
+	v.SetDefault("delivery_retry_interval", DeliveryRetryIntervalDefault)
+
+	// smtp server address
+	v.BindEnv("smtp_address", SMTPAddrEnvVar)
+	v.SetDefault("smtp_address", SMTPAddrDefault)
+
+	// smtp username
+	v.BindEnv("smtp_username", SMTPUsernameEnvVar)
+	v.SetDefault("smtp_username", "")
+
+	// smtp password
+	v.BindEnv("smtp_password", SMTPPasswordEnvVar)
+	v.SetDefault("smtp_password", "")
+
 	// aws access key id
 	v.BindEnv("aws_access_key_id", AWSAccessKeyIDEnvVar)
 	v.SetDefault("aws_access_key_id", "")
@@ -112,6 +268,62 @@ func viperConfig(appName string) *viper.Viper {
 	v.BindEnv("aws_secret_access_key", AWSSecretAccessKeyEnvVar)
 	v.SetDefault("aws_secret_access_key", "")
 
+	// aws shared credentials profile
+	v.BindEnv("aws_profile", AWSProfileEnvVar)
+	v.SetDefault("aws_profile", "")
+
+	// aws role assumed with the credential chain's credentials
+	v.BindEnv("aws_assume_role_arn", AWSAssumeRoleARNEnvVar)
+	v.SetDefault("aws_assume_role_arn", "")
+
+	// aws assumed role session name
+	v.BindEnv("aws_assume_role_session_name", AWSAssumeRoleSessionNameEnvVar)
+	v.SetDefault("aws_assume_role_session_name", AWSAssumeRoleSessionNameDefault)
+
+	// aws assumed role external id
+	v.BindEnv("aws_assume_role_external_id", AWSAssumeRoleExternalIDEnvVar)
+	v.SetDefault("aws_assume_role_external_id", "")
+
+	// dynamodb endpoint, e.g. DynamoDB Local
+	v.BindEnv("dynamodb_endpoint", DynamoDBEndpointEnvVar)
+	v.SetDefault("dynamodb_endpoint", "")
+
+	// dynamodb region, when not the default session's
+	v.BindEnv("dynamodb_region", DynamoDBRegionEnvVar)
+	v.SetDefault("dynamodb_region", "")
+
+	// dynamodb token holder table name
+	v.BindEnv("dynamodb_token_holder_table", DynamoDBTokenHolderTableEnvVar)
+	v.SetDefault("dynamodb_token_holder_table", DynamoDBTokenHolderTableDefault)
+
+	// dynamodb init record table name
+	v.BindEnv("dynamodb_init_record_table", DynamoDBInitRecordTableEnvVar)
+	v.SetDefault("dynamodb_init_record_table", DynamoDBInitRecordTableDefault)
+
+	// DynamoDB unseal ceremony table name
+	v.BindEnv("dynamodb_ceremony_table", DynamoDBCeremonyTableEnvVar)
+	v.SetDefault("dynamodb_ceremony_table", DynamoDBCeremonyTableDefault)
+
+	// dynamodb billing mode of created tables
+	v.BindEnv("dynamodb_billing_mode", DynamoDBBillingModeEnvVar)
+	v.SetDefault("dynamodb_billing_mode", DynamoDBBillingModeDefault)
+
+	// dynamodb provisioned read capacity of created tables
+	v.BindEnv("dynamodb_read_capacity", DynamoDBReadCapacityEnvVar)
+	v.SetDefault("dynamodb_read_capacity", DynamoDBReadCapacityDefault)
+
+	// dynamodb provisioned write capacity of created tables
+	v.BindEnv("dynamodb_write_capacity", DynamoDBWriteCapacityEnvVar)
+	v.SetDefault("dynamodb_write_capacity", DynamoDBWriteCapacityDefault)
+
+	// dynamodb point-in-time-recovery of created tables
+	v.BindEnv("dynamodb_pitr", DynamoDBPITREnvVar)
+	v.SetDefault("dynamodb_pitr", DynamoDBPITRDefault)
+
+	// dynamodb kms key encrypting created tables
+	v.BindEnv("dynamodb_kms_key_id", DynamoDBKMSKeyIDEnvVar)
+	v.SetDefault("dynamodb_kms_key_id", "")
+
 	// armor configuration file location
 	v.BindEnv("cfgFile", ArmorConfigFileEnvVar)
 	v.SetDefault("cfgFile", "")
@@ -126,14 +338,66 @@ func bindCobra(cmd *cobra.Command) bool {
 	defaultConfig.BindPFlag("grpc_address", cmd.PersistentFlags().Lookup("grpc-address"))
 	defaultConfig.BindPFlag("appdash_address", cmd.PersistentFlags().Lookup("appdash-address"))
 	defaultConfig.BindPFlag("lightstep_token", cmd.PersistentFlags().Lookup("lightstep-token"))
+	defaultConfig.BindPFlag("tls_cert_file", cmd.PersistentFlags().Lookup("tls-cert-file"))
+	defaultConfig.BindPFlag("tls_key_file", cmd.PersistentFlags().Lookup("tls-key-file"))
+	defaultConfig.BindPFlag("tls_client_ca_file", cmd.PersistentFlags().Lookup("tls-client-ca-file"))
+	defaultConfig.BindPFlag("tls_require_client_cert", cmd.PersistentFlags().Lookup("tls-require-client-cert"))
+	defaultConfig.BindPFlag("admin_tls_require_client_cert", cmd.PersistentFlags().Lookup("admin-tls-require-client-cert"))
+	defaultConfig.BindPFlag("auth_policy_file", cmd.PersistentFlags().Lookup("auth-policy-file"))
+	defaultConfig.BindPFlag("auth_vault_tokens", cmd.PersistentFlags().Lookup("auth-vault-tokens"))
 	defaultConfig.BindPFlag("vault_address", cmd.PersistentFlags().Lookup("vault-address"))
+	defaultConfig.BindPFlag("vault_nodes", cmd.PersistentFlags().Lookup("vault-nodes"))
+	defaultConfig.BindPFlag("vault_cluster_id", cmd.PersistentFlags().Lookup("vault-cluster-id"))
+	defaultConfig.BindPFlag("default_cluster", cmd.PersistentFlags().Lookup("default-cluster"))
 	defaultConfig.BindPFlag("vault_ca_cert", cmd.PersistentFlags().Lookup("vault-ca-cert"))
 	defaultConfig.BindPFlag("vault_ca_path", cmd.PersistentFlags().Lookup("vault-ca-path"))
 	defaultConfig.BindPFlag("vault_skip_verify", cmd.PersistentFlags().Lookup("vault-skip-verify"))
+	defaultConfig.BindPFlag("vault_plugin_dir", cmd.PersistentFlags().Lookup("vault-plugin-dir"))
 	defaultConfig.BindPFlag("policy_config_dir", cmd.PersistentFlags().Lookup("policy-config-dir"))
 	defaultConfig.BindPFlag("cfgFile", cmd.PersistentFlags().Lookup("config"))
+	defaultConfig.BindPFlag("token_holder_store", cmd.PersistentFlags().Lookup("token-holder-store"))
+	defaultConfig.BindPFlag("token_holder_store_path", cmd.PersistentFlags().Lookup("token-holder-store-path"))
+	defaultConfig.BindPFlag("token_encryption", cmd.PersistentFlags().Lookup("token-encryption"))
+	defaultConfig.BindPFlag("token_encryption_kms_key_id", cmd.PersistentFlags().Lookup("token-encryption-kms-key-id"))
+	defaultConfig.BindPFlag("token_encryption_key_file", cmd.PersistentFlags().Lookup("token-encryption-key-file"))
+	defaultConfig.BindPFlag("token_encryption_previous_key_files", cmd.PersistentFlags().Lookup("token-encryption-previous-key-files"))
+	defaultConfig.BindPFlag("pgp_keyring_dir", cmd.PersistentFlags().Lookup("pgp-keyring-dir"))
+	defaultConfig.BindPFlag("enforce_pgp", cmd.PersistentFlags().Lookup("enforce-pgp"))
+	defaultConfig.BindPFlag("init_lock_ttl", cmd.PersistentFlags().Lookup("init-lock-ttl"))
+	defaultConfig.BindPFlag("auto_unseal", cmd.PersistentFlags().Lookup("auto-unseal"))
+	defaultConfig.BindPFlag("auto_unseal_interval", cmd.PersistentFlags().Lookup("auto-unseal-interval"))
+	defaultConfig.BindPFlag("auto_unseal_max_attempts", cmd.PersistentFlags().Lookup("auto-unseal-max-attempts"))
+	defaultConfig.BindPFlag("auto_unseal_window", cmd.PersistentFlags().Lookup("auto-unseal-window"))
+	defaultConfig.BindPFlag("auto_unseal_alert_emails", cmd.PersistentFlags().Lookup("auto-unseal-alert-emails"))
+	defaultConfig.BindPFlag("auto_unseal_audit_file", cmd.PersistentFlags().Lookup("auto-unseal-audit-file"))
+	defaultConfig.BindPFlag("unseal_ceremony_ttl", cmd.PersistentFlags().Lookup("unseal-ceremony-ttl"))
+	defaultConfig.BindPFlag("seal_watch_interval", cmd.PersistentFlags().Lookup("seal-watch-interval"))
+	defaultConfig.BindPFlag("seal_watch_heartbeat", cmd.PersistentFlags().Lookup("seal-watch-heartbeat"))
+	defaultConfig.BindPFlag("delivery_method", cmd.PersistentFlags().Lookup("delivery-method"))
+	defaultConfig.BindPFlag("delivery_from", cmd.PersistentFlags().Lookup("delivery-from"))
+	defaultConfig.BindPFlag("delivery_subject_template", cmd.PersistentFlags().Lookup("delivery-subject-template"))
+	defaultConfig.BindPFlag("delivery_body_template", cmd.PersistentFlags().Lookup("delivery-body-template"))
+	defaultConfig.BindPFlag("delivery_retries", cmd.PersistentFlags().Lookup("delivery-retries"))
+	defaultConfig.BindPFlag("delivery_retry_interval", cmd.PersistentFlags().Lookup("delivery-retry-interval"))
+	defaultConfig.BindPFlag("smtp_address", cmd.PersistentFlags().Lookup("smtp-address"))
+	defaultConfig.BindPFlag("smtp_username", cmd.PersistentFlags().Lookup("smtp-username"))
+	defaultConfig.BindPFlag("smtp_password", cmd.PersistentFlags().Lookup("smtp-password"))
 	defaultConfig.BindPFlag("aws_access_key_id", cmd.PersistentFlags().Lookup("aws-access-key-id"))
 	defaultConfig.BindPFlag("aws_secret_access_key", cmd.PersistentFlags().Lookup("aws-secret-access-key"))
+	defaultConfig.BindPFlag("aws_profile", cmd.PersistentFlags().Lookup("aws-profile"))
+	defaultConfig.BindPFlag("aws_assume_role_arn", cmd.PersistentFlags().Lookup("aws-assume-role-arn"))
+	defaultConfig.BindPFlag("aws_assume_role_session_name", cmd.PersistentFlags().Lookup("aws-assume-role-session-name"))
+	defaultConfig.BindPFlag("aws_assume_role_external_id", cmd.PersistentFlags().Lookup("aws-assume-role-external-id"))
+	defaultConfig.BindPFlag("dynamodb_endpoint", cmd.PersistentFlags().Lookup("dynamodb-endpoint"))
+	defaultConfig.BindPFlag("dynamodb_region", cmd.PersistentFlags().Lookup("dynamodb-region"))
+	defaultConfig.BindPFlag("dynamodb_token_holder_table", cmd.PersistentFlags().Lookup("dynamodb-token-holder-table"))
+	defaultConfig.BindPFlag("dynamodb_init_record_table", cmd.PersistentFlags().Lookup("dynamodb-init-record-table"))
+	defaultConfig.BindPFlag("dynamodb_ceremony_table", cmd.PersistentFlags().Lookup("dynamodb-ceremony-table"))
+	defaultConfig.BindPFlag("dynamodb_billing_mode", cmd.PersistentFlags().Lookup("dynamodb-billing-mode"))
+	defaultConfig.BindPFlag("dynamodb_read_capacity", cmd.PersistentFlags().Lookup("dynamodb-read-capacity"))
+	defaultConfig.BindPFlag("dynamodb_write_capacity", cmd.PersistentFlags().Lookup("dynamodb-write-capacity"))
+	defaultConfig.BindPFlag("dynamodb_pitr", cmd.PersistentFlags().Lookup("dynamodb-pitr"))
+	defaultConfig.BindPFlag("dynamodb_kms_key_id", cmd.PersistentFlags().Lookup("dynamodb-kms-key-id"))
 	return true
 }
 
@@ -184,12 +448,64 @@ const (
 	// LightstepTokenEnvVar is the env variable set for the LightStep access token
 	LightstepTokenEnvVar string = "ARMOR_LIGHTSTEP_TOKEN"
 
+	// TLSCertFileEnvVar is the env variable set for the certificate served
+	// by the admin, http and gRPC listeners
+	TLSCertFileEnvVar string = "ARMOR_TLS_CERT_FILE"
+
+	// TLSKeyFileEnvVar is the env variable set for the private key of the
+	// listeners' certificate
+	TLSKeyFileEnvVar string = "ARMOR_TLS_KEY_FILE"
+
+	// TLSClientCAFileEnvVar is the env variable set for the CA verifying
+	// client certificates
+	TLSClientCAFileEnvVar string = "ARMOR_TLS_CLIENT_CA_FILE"
+
+	// TLSRequireClientCertDefault is the default for requiring client
+	// certificates of the http and gRPC listeners
+	TLSRequireClientCertDefault bool = false
+
+	// TLSRequireClientCertEnvVar is the env variable set to require client
+	// certificates of the http and gRPC listeners
+	TLSRequireClientCertEnvVar string = "ARMOR_TLS_REQUIRE_CLIENT_CERT"
+
+	// AdminTLSRequireClientCertDefault is the default for requiring client
+	// certificates of the admin listener
+	AdminTLSRequireClientCertDefault bool = false
+
+	// AdminTLSRequireClientCertEnvVar is the env variable set to require
+	// client certificates of the admin listener
+	AdminTLSRequireClientCertEnvVar string = "ARMOR_ADMIN_TLS_REQUIRE_CLIENT_CERT"
+
+	// AuthPolicyFileEnvVar is the env variable set for the policy file
+	// authorizing the methods of each identity
+	AuthPolicyFileEnvVar string = "ARMOR_AUTH_POLICY_FILE"
+
+	// AuthVaultTokensDefault is the default for validating bearer tokens
+	// with Vault
+	AuthVaultTokensDefault bool = false
+
+	// AuthVaultTokensEnvVar is the env variable set to validate bearer
+	// tokens with Vault
+	AuthVaultTokensEnvVar string = "ARMOR_AUTH_VAULT_TOKENS"
+
 	// VaultAddrDefault is the default Vault server address
 	VaultAddrDefault string = "https://127.0.0.1:8200"
 
 	// VaultAddrEnvVar is the env variable set for the Vault server address
 	VaultAddrEnvVar string = "ARMOR_VAULT_ADDRESS"
 
+	// VaultNodesEnvVar is the env variable set for the addresses of every
+	// node of the Vault cluster
+	VaultNodesEnvVar string = "ARMOR_VAULT_NODES"
+
+	// VaultClusterIDEnvVar is the env variable set for the identifier under
+	// which the Vault server's token holders are kept
+	VaultClusterIDEnvVar string = "ARMOR_VAULT_CLUSTER_ID"
+
+	// DefaultClusterEnvVar is the env variable set for the Vault cluster of
+	// requests which don't name one
+	DefaultClusterEnvVar string = "ARMOR_DEFAULT_CLUSTER"
+
 	// VaultCACertEnvVar is the env variable set for the Vault server CA cert
 	VaultCACertEnvVar string = "ARMOR_VAULT_CA_CERT"
 
@@ -203,6 +519,18 @@ const (
 	// skipping
 	VaultSkipVerifyEnvVar string = "ARMOR_VAULT_SKIP_VERIFY"
 
+	// VaultPluginDirEnvVar is the env variable set for a local copy of the
+	// Vault server's plugin directory
+	VaultPluginDirEnvVar string = "ARMOR_VAULT_PLUGIN_DIR"
+
+	// VaultMountTypesEnvVar is the env variable set for additional secret
+	// backend types (e.g. plugins) accepted in configure's /sys/mounts/
+	VaultMountTypesEnvVar string = "ARMOR_VAULT_MOUNT_TYPES"
+
+	// VaultAuthTypesEnvVar is the env variable set for additional auth backend
+	// types (e.g. plugins) accepted in configure's /sys/auth/
+	VaultAuthTypesEnvVar string = "ARMOR_VAULT_AUTH_TYPES"
+
 	// PolicyConfigPathDefault is the default for Armor's policy config destination path
 	PolicyConfigPathDefault string = "/tmp/armor/policy"
 
@@ -213,6 +541,169 @@ const (
 	// ArmorConfigFileEnvVar is the env variable set for Armor's config file
 	ArmorConfigFileEnvVar string = "ARMOR_CONFIG"
 
+	// TokenHolderStoreDefault is the default store of root and unseal token
+	// holders
+	TokenHolderStoreDefault string = "dynamodb"
+
+	// TokenHolderStoreEnvVar is the env variable set to select the store of
+	// root and unseal token holders (i.e. dynamodb, file or memory)
+	TokenHolderStoreEnvVar string = "ARMOR_TOKEN_HOLDER_STORE"
+
+	// TokenHolderStorePathDefault is the default location of the token holder
+	// file store
+	TokenHolderStorePathDefault string = "/var/lib/armor/token_holders.db"
+
+	// TokenHolderStorePathEnvVar is the env variable set for the location of
+	// the token holder file store
+	TokenHolderStorePathEnvVar string = "ARMOR_TOKEN_HOLDER_STORE_PATH"
+
+	// TokenEncryptionEnvVar is the env variable set to select the provider
+	// of master keys encrypting root and unseal tokens at rest (i.e. kms or
+	// file)
+	TokenEncryptionEnvVar string = "ARMOR_TOKEN_ENCRYPTION"
+
+	// TokenEncryptionKMSKeyIDEnvVar is the env variable set for the AWS KMS
+	// key encrypting root and unseal tokens at rest
+	TokenEncryptionKMSKeyIDEnvVar string = "ARMOR_TOKEN_ENCRYPTION_KMS_KEY_ID"
+
+	// TokenEncryptionKeyFileEnvVar is the env variable set for the local
+	// master key file encrypting root and unseal tokens at rest
+	TokenEncryptionKeyFileEnvVar string = "ARMOR_TOKEN_ENCRYPTION_KEY_FILE"
+
+	// TokenEncryptionPreviousKeyFilesEnvVar is the env variable set for the
+	// local master key files in use before the master key was rotated
+	TokenEncryptionPreviousKeyFilesEnvVar string = "ARMOR_TOKEN_ENCRYPTION_PREVIOUS_KEY_FILES"
+
+	// PGPKeyringDirEnvVar is the env variable set for the directory of token
+	// holders' PGP public keys
+	PGPKeyringDirEnvVar string = "ARMOR_PGP_KEYRING_DIR"
+
+	// EnforcePGPDefault is the default for refusing to init Vault without PGP
+	// encrypting every token
+	EnforcePGPDefault bool = false
+
+	// EnforcePGPEnvVar is the env variable set to refuse to init Vault
+	// without PGP encrypting every token
+	EnforcePGPEnvVar string = "ARMOR_ENFORCE_PGP"
+
+	// InitLockTTLDefault is the default lease an Armor replica holds on
+	// a pending Vault init
+	InitLockTTLDefault time.Duration = 5 * time.Minute
+
+	// InitLockTTLEnvVar is the env variable set for the lease an Armor
+	// replica holds on a pending Vault init
+	InitLockTTLEnvVar string = "ARMOR_INIT_LOCK_TTL"
+
+	// AutoUnsealDefault is the default for running the auto-unseal controller
+	AutoUnsealDefault bool = false
+
+	// AutoUnsealEnvVar is the env variable set to run the auto-unseal
+	// controller, which unseals Vault with the unseal tokens in the token
+	// holder store
+	AutoUnsealEnvVar string = "ARMOR_AUTO_UNSEAL"
+
+	// AutoUnsealIntervalDefault is the default interval between seal status
+	// polls of the auto-unseal controller
+	AutoUnsealIntervalDefault time.Duration = 30 * time.Second
+
+	// AutoUnsealIntervalEnvVar is the env variable set for the interval
+	// between seal status polls of the auto-unseal controller
+	AutoUnsealIntervalEnvVar string = "ARMOR_AUTO_UNSEAL_INTERVAL"
+
+	// AutoUnsealMaxAttemptsDefault is the default number of auto-unseal
+	// attempts allowed per Vault node within the window
+	AutoUnsealMaxAttemptsDefault int = 3
+
+	// AutoUnsealMaxAttemptsEnvVar is the env variable set for the number of
+	// auto-unseal attempts allowed per Vault node within the window
+	AutoUnsealMaxAttemptsEnvVar string = "ARMOR_AUTO_UNSEAL_MAX_ATTEMPTS"
+
+	// AutoUnsealWindowDefault is the default window auto-unseal attempts are
+	// counted in
+	AutoUnsealWindowDefault time.Duration = time.Hour
+
+	// AutoUnsealWindowEnvVar is the env variable set for the window
+	// auto-unseal attempts are counted in
+	AutoUnsealWindowEnvVar string = "ARMOR_AUTO_UNSEAL_WINDOW"
+
+	// AutoUnsealAlertEmailsEnvVar is the env variable set for the email
+	// addresses alerted of auto-unseal attempts, using delivery_method
+	AutoUnsealAlertEmailsEnvVar string = "ARMOR_AUTO_UNSEAL_ALERT_EMAILS"
+
+	// AutoUnsealAuditFileEnvVar is the env variable set for the file audit
+	// records of the unseal tokens used by the auto-unseal controller are
+	// appended to
+	AutoUnsealAuditFileEnvVar string = "ARMOR_AUTO_UNSEAL_AUDIT_FILE"
+
+	// UnsealCeremonyTTLDefault is the default time an unseal ceremony stays
+	// open
+	UnsealCeremonyTTLDefault time.Duration = time.Hour
+
+	// UnsealCeremonyTTLEnvVar is the env variable set for the time an unseal
+	// ceremony stays open
+	UnsealCeremonyTTLEnvVar string = "ARMOR_UNSEAL_CEREMONY_TTL"
+
+	// SealWatchIntervalDefault is the default interval between seal status
+	// polls of the Vault nodes watched through WatchSealStatus
+	SealWatchIntervalDefault time.Duration = 5 * time.Second
+
+	// SealWatchIntervalEnvVar is the env variable set for the interval
+	// between seal status polls of the Vault nodes watched through
+	// WatchSealStatus
+	SealWatchIntervalEnvVar string = "ARMOR_SEAL_WATCH_INTERVAL"
+
+	// SealWatchHeartbeatDefault is the default time after which watchers of
+	// an unchanged seal status are sent a heartbeat
+	SealWatchHeartbeatDefault time.Duration = 15 * time.Second
+
+	// SealWatchHeartbeatEnvVar is the env variable set for the time after
+	// which watchers of an unchanged seal status are sent a heartbeat
+	SealWatchHeartbeatEnvVar string = "ARMOR_SEAL_WATCH_HEARTBEAT"
+
+	// DeliveryMethodEnvVar is the env variable set to select how tokens are
+	// delivered to their holders (i.e. smtp or ses)
+	DeliveryMethodEnvVar string = "ARMOR_DELIVERY_METHOD"
+
+	// DeliveryFromEnvVar is the env variable set for the from address of
+	// token delivery emails
+	DeliveryFromEnvVar string = "ARMOR_DELIVERY_FROM"
+
+	// DeliverySubjectTemplateEnvVar is the env variable set for the template
+	// file of token delivery email subjects
+	DeliverySubjectTemplateEnvVar string = "ARMOR_DELIVERY_SUBJECT_TEMPLATE"
+
+	// DeliveryBodyTemplateEnvVar is the env variable set for the template
+	// file of token delivery email bodies
+	DeliveryBodyTemplateEnvVar string = "ARMOR_DELIVERY_BODY_TEMPLATE"
+
+	// DeliveryRetriesDefault is the default number of retries of a failed
+	// token delivery
+	DeliveryRetriesDefault int = 3
+
+	// DeliveryRetriesEnvVar is the env variable set for the number of
+	// retries of a failed token delivery
+	DeliveryRetriesEnvVar string = "ARMOR_DELIVERY_RETRIES"
+
+	// DeliveryRetryIntervalDefault is the default wait between token delivery
+	// retries
+	DeliveryRetryIntervalDefault time.Duration = 5 * time.Second
+
+	// DeliveryRetryIntervalEnvVar is the env variable set for the wait
+	// between token delivery retries
+	DeliveryRetryIntervalEnvVar string = "ARMOR_DELIVERY_RETRY_INTERVAL"
+
+	// SMTPAddrDefault is the default SMTP server address
+	SMTPAddrDefault string = "127.0.0.1:25"
+
+	// SMTPAddrEnvVar is the env variable set for the SMTP server address
+	SMTPAddrEnvVar string = "ARMOR_SMTP_ADDRESS"
+
+	// SMTPUsernameEnvVar is the env variable set for the SMTP username
+	SMTPUsernameEnvVar string = "ARMOR_SMTP_USERNAME"
+
+	// SMTPPasswordEnvVar is the env variable set for the SMTP password
+	SMTPPasswordEnvVar string = "ARMOR_SMTP_PASSWORD"
+
 	// AWSAccessKeyIDEnvVar is the env variable set to define the
 	// AWS_ACCESS_KEY_ID for Armor's backend (e.g. SES, DYNAMO, etc.)
 	AWSAccessKeyIDEnvVar string = "ARMOR_AWS_ACCESS_KEY_ID"
@@ -220,4 +711,92 @@ const (
 	// AWSSecretAccessKeyEnvVar is the env variable set to define the
 	// AWS_SECRET_ACCESS_KEY for Armor's backend (e.g. SES, DYNAMO, etc.)
 	AWSSecretAccessKeyEnvVar string = "ARMOR_AWS_SECRET_ACCESS_KEY"
+
+	// AWSProfileEnvVar is the env variable set for the profile of the AWS
+	// shared credentials file used by Armor's backend
+	AWSProfileEnvVar string = "ARMOR_AWS_PROFILE"
+
+	// AWSAssumeRoleARNEnvVar is the env variable set for the AWS role Armor's
+	// backend assumes
+	AWSAssumeRoleARNEnvVar string = "ARMOR_AWS_ASSUME_ROLE_ARN"
+
+	// AWSAssumeRoleSessionNameDefault is the default session name of the
+	// assumed AWS role
+	AWSAssumeRoleSessionNameDefault string = "armor"
+
+	// AWSAssumeRoleSessionNameEnvVar is the env variable set for the session
+	// name of the assumed AWS role
+	AWSAssumeRoleSessionNameEnvVar string = "ARMOR_AWS_ASSUME_ROLE_SESSION_NAME"
+
+	// AWSAssumeRoleExternalIDEnvVar is the env variable set for the external
+	// id required to assume the AWS role
+	AWSAssumeRoleExternalIDEnvVar string = "ARMOR_AWS_ASSUME_ROLE_EXTERNAL_ID"
+
+	// DynamoDBEndpointEnvVar is the env variable set to override the DynamoDB
+	// endpoint, e.g. to use DynamoDB Local
+	DynamoDBEndpointEnvVar string = "ARMOR_DYNAMODB_ENDPOINT"
+
+	// DynamoDBRegionEnvVar is the env variable set to override the AWS region
+	// of the DynamoDB tables
+	DynamoDBRegionEnvVar string = "ARMOR_DYNAMODB_REGION"
+
+	// DynamoDBTokenHolderTableDefault is the default name of the DynamoDB
+	// token holder table
+	DynamoDBTokenHolderTableDefault string = "TokenHolderShares"
+
+	// DynamoDBTokenHolderTableEnvVar is the env variable set for the name of
+	// the DynamoDB token holder table
+	DynamoDBTokenHolderTableEnvVar string = "ARMOR_DYNAMODB_TOKEN_HOLDER_TABLE"
+
+	// DynamoDBInitRecordTableDefault is the default name of the DynamoDB init
+	// record table
+	DynamoDBInitRecordTableDefault string = "InitRecords"
+
+	// DynamoDBInitRecordTableEnvVar is the env variable set for the name of
+	// the DynamoDB init record table
+	DynamoDBInitRecordTableEnvVar string = "ARMOR_DYNAMODB_INIT_RECORD_TABLE"
+
+	// DynamoDBCeremonyTableDefault is the default name of the DynamoDB unseal
+	// ceremony table
+	DynamoDBCeremonyTableDefault string = "UnsealCeremonies"
+
+	// DynamoDBCeremonyTableEnvVar is the env variable set for the name of the
+	// DynamoDB unseal ceremony table
+	DynamoDBCeremonyTableEnvVar string = "ARMOR_DYNAMODB_CEREMONY_TABLE"
+
+	// DynamoDBBillingModeDefault is the default billing mode of the DynamoDB
+	// tables Armor creates
+	DynamoDBBillingModeDefault string = "provisioned"
+
+	// DynamoDBBillingModeEnvVar is the env variable set for the billing mode
+	// of the DynamoDB tables Armor creates (i.e. provisioned or on_demand)
+	DynamoDBBillingModeEnvVar string = "ARMOR_DYNAMODB_BILLING_MODE"
+
+	// DynamoDBReadCapacityDefault is the default provisioned read capacity of
+	// the DynamoDB tables Armor creates
+	DynamoDBReadCapacityDefault int64 = 1
+
+	// DynamoDBReadCapacityEnvVar is the env variable set for the provisioned
+	// read capacity of the DynamoDB tables Armor creates
+	DynamoDBReadCapacityEnvVar string = "ARMOR_DYNAMODB_READ_CAPACITY"
+
+	// DynamoDBWriteCapacityDefault is the default provisioned write capacity
+	// of the DynamoDB tables Armor creates
+	DynamoDBWriteCapacityDefault int64 = 1
+
+	// DynamoDBWriteCapacityEnvVar is the env variable set for the provisioned
+	// write capacity of the DynamoDB tables Armor creates
+	DynamoDBWriteCapacityEnvVar string = "ARMOR_DYNAMODB_WRITE_CAPACITY"
+
+	// DynamoDBPITRDefault is the default for enabling point-in-time-recovery
+	// on the DynamoDB tables Armor creates
+	DynamoDBPITRDefault bool = false
+
+	// DynamoDBPITREnvVar is the env variable set to enable point-in-time-recovery
+	// on the DynamoDB tables Armor creates
+	DynamoDBPITREnvVar string = "ARMOR_DYNAMODB_PITR"
+
+	// DynamoDBKMSKeyIDEnvVar is the env variable set for the AWS KMS key
+	// encrypting the DynamoDB tables Armor creates
+	DynamoDBKMSKeyIDEnvVar string = "ARMOR_DYNAMODB_KMS_KEY_ID"
 )
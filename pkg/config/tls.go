@@ -0,0 +1,117 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+var (
+	// ErrTLSKeyFileUnset is returned when a certificate is configured
+	// without its private key, or the other way around.
+	ErrTLSKeyFileUnset = errors.New("tls certificate and key files must be set together")
+
+	// ErrTLSClientCAUnset is returned when client certificates are required
+	// without a CA to verify them.
+	ErrTLSClientCAUnset = errors.New("tls client ca file must be set to require client certificates")
+
+	// ErrTLSNoCertificates is returned for a CA file without any PEM
+	// encoded certificate.
+	ErrTLSNoCertificates = errors.New("no pem encoded certificates found")
+)
+
+// ListenerTLSConfig returns the TLS configuration of Armor's listeners, set
+// by its tls_* configuration, or nil if no certificate is set. Clients must
+// present a certificate signed by the tls_client_ca_file if
+// requireClientCert is set; otherwise one is verified if presented.
+func ListenerTLSConfig(requireClientCert bool) (*tls.Config, error) {
+	certFile := defaultConfig.GetString("tls_cert_file")
+	keyFile := defaultConfig.GetString("tls_key_file")
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	return ServerTLSConfig(certFile, keyFile, defaultConfig.GetString("tls_client_ca_file"), requireClientCert)
+}
+
+// ServerTLSConfig returns the TLS configuration of a listener presenting the
+// certificate of certFile. Client certificates are verified against the CAs of
+// clientCAFile, if set.
+func ServerTLSConfig(certFile, keyFile, clientCAFile string, requireClientCert bool) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, ErrTLSKeyFileUnset
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCAFile == "" {
+		if requireClientCert {
+			return nil, ErrTLSClientCAUnset
+		}
+		return tlsConfig, nil
+	}
+
+	tlsConfig.ClientCAs, err = certPool(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	if requireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// ClientTLSConfig returns the TLS configuration of a client of Armor. The
+// server certificate is verified against the CAs of caFile, or the system's
+// if unset. The certificate of certFile is presented to servers requiring
+// one.
+func ClientTLSConfig(caFile, certFile, keyFile, serverName string, skipVerify bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: skipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if caFile != "" {
+		pool, err := certPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, ErrTLSKeyFileUnset
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+func certPool(caFile string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%s: %q", ErrTLSNoCertificates.Error(), caFile)
+	}
+	return pool, nil
+}
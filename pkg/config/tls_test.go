@@ -0,0 +1,100 @@
+package config
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testKeys = "../proxy/test-fixtures/keys/"
+
+// The fixture certificates expired in 2021, so handshakes are pinned to a
+// time they were valid.
+var testKeysTime = func() time.Time { return time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC) }
+
+func TestServerTLSConfig(t *testing.T) {
+	_, err := ServerTLSConfig(testKeys+"cert.pem", "", "", false)
+	assert.Equal(t, ErrTLSKeyFileUnset, err, "expecting an error for a certificate without its key")
+
+	_, err = ServerTLSConfig(testKeys+"cert.pem", testKeys+"cert-key.pem", "", true)
+	assert.Equal(t, ErrTLSClientCAUnset, err, "expecting an error when requiring client certs without a ca")
+
+	_, err = ServerTLSConfig(testKeys+"cert.pem", testKeys+"cert-key.pem", testKeys+"cert-key.pem", false)
+	assert.Error(t, err, "expecting an error for a client ca file without certificates")
+
+	tlsConfig, err := ServerTLSConfig(testKeys+"cert.pem", testKeys+"cert-key.pem", "", false)
+	assert.NoError(t, err, "not expecting an error without a client ca")
+	assert.Equal(t, tls.NoClientCert, tlsConfig.ClientAuth, "not expecting client certs without a client ca")
+
+	tlsConfig, err = ServerTLSConfig(testKeys+"cert.pem", testKeys+"cert-key.pem", testKeys+"ca-cert.pem", false)
+	assert.NoError(t, err, "not expecting an error with a client ca")
+	assert.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth, "expecting client certs verified if given")
+
+	tlsConfig, err = ServerTLSConfig(testKeys+"cert.pem", testKeys+"cert-key.pem", testKeys+"ca-cert.pem", true)
+	assert.NoError(t, err, "not expecting an error when requiring client certs")
+	assert.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth, "expecting client certs required")
+}
+
+func TestClientTLSConfig(t *testing.T) {
+	_, err := ClientTLSConfig(testKeys+"ca-cert.pem", testKeys+"client.pem", "", "", false)
+	assert.Equal(t, ErrTLSKeyFileUnset, err, "expecting an error for a certificate without its key")
+
+	_, err = ClientTLSConfig(testKeys+"missing-ca.pem", "", "", "", false)
+	assert.Error(t, err, "expecting an error for a missing ca file")
+
+	tlsConfig, err := ClientTLSConfig(testKeys+"ca-cert.pem", testKeys+"client.pem", testKeys+"client-key.pem", "localhost", false)
+	assert.NoError(t, err, "not expecting an error for a client certificate")
+	assert.Len(t, tlsConfig.Certificates, 1, "expecting the client certificate")
+	assert.Equal(t, "localhost", tlsConfig.ServerName, "expecting the server name")
+}
+
+func TestMutualTLSHandshake(t *testing.T) {
+	serverConfig, err := ServerTLSConfig(testKeys+"cert.pem", testKeys+"cert-key.pem", testKeys+"ca-cert.pem", true)
+	assert.NoError(t, err, "not expecting an error for the server config")
+	serverConfig.Time = testKeysTime
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	if !assert.NoError(t, err, "not expecting an error when listening") {
+		return
+	}
+	defer ln.Close()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("armor"))
+			conn.Close()
+		}
+	}()
+
+	dial := func(certFile, keyFile string) (string, error) {
+		clientConfig, err := ClientTLSConfig(testKeys+"ca-cert.pem", certFile, keyFile, "localhost", false)
+		if err != nil {
+			return "", err
+		}
+		clientConfig.Time = testKeysTime
+
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", ln.Addr().String(), clientConfig)
+		if err != nil {
+			return "", err
+		}
+		defer conn.Close()
+
+		b, err := ioutil.ReadAll(conn)
+		return string(b), err
+	}
+
+	msg, err := dial(testKeys+"client.pem", testKeys+"client-key.pem")
+	assert.NoError(t, err, "not expecting an error when presenting a client certificate")
+	assert.Equal(t, "armor", msg, "expecting the server's message")
+
+	_, err = dial("", "")
+	assert.Error(t, err, "expecting an error without a client certificate")
+}
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"gopkg.in/go-playground/validator.v9"
+	"strings"
 )
 
 var defaultValidator *validator.Validate
@@ -14,3 +16,53 @@ func Validator() *validator.Validate {
 func init() {
 	defaultValidator = validator.New()
 }
+
+// ValidationError describes a single problem found while validating
+// a request. Problems with a request's fields identify the Field; problems
+// with submitted configuration files identify the File and, for malformed
+// json, the byte Offset of the problem.
+type ValidationError struct {
+	Field   string `json:"field,omitempty"`
+	File    string `json:"file,omitempty"`
+	Offset  int64  `json:"offset,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	if e.File != "" && e.Offset > 0 {
+		return fmt.Sprintf("%s (offset %d): %s", e.File, e.Offset, e.Message)
+	} else if e.File != "" {
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+	return e.Message
+}
+
+// ValidationErrors collects every problem found while validating a request,
+// so that they can all be reported at once.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, v := range e {
+		msgs = append(msgs, v.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// NewValidationErrors translates the errors returned by Validate.Struct into
+// ValidationErrors. Any other error is returned as a single ValidationError.
+func NewValidationErrors(err error) ValidationErrors {
+	fielderrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return ValidationErrors{{Message: err.Error()}}
+	}
+
+	errs := make(ValidationErrors, 0, len(fielderrs))
+	for _, fe := range fielderrs {
+		errs = append(errs, ValidationError{
+			Field:   fe.Namespace(),
+			Message: fmt.Sprintf("%s validation failed on '%s' check", fe.Namespace(), fe.Tag()),
+		})
+	}
+	return errs
+}
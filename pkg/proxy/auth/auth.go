@@ -0,0 +1,141 @@
+// Package auth authenticates callers of the Vault proxy service, by their
+// client certificate, a static bearer token or a Vault token, and authorizes
+// the methods they may call with a policy file.
+package auth
+
+import (
+	"errors"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	// Anonymous is the identity of callers presenting no credentials.
+	Anonymous = "anonymous"
+
+	// Everyone matches every authenticated identity, and every method, in a
+	// policy.
+	Everyone = "*"
+)
+
+var (
+	// ErrUnauthenticated is returned when a caller presents invalid
+	// credentials, or none to a method anonymous callers may not call.
+	ErrUnauthenticated = errors.New("unauthenticated")
+
+	// ErrPermissionDenied is returned when the policy does not allow an
+	// authenticated caller to call a method.
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// Identity is who an authenticated caller is. Name is prefixed by how the
+// caller authenticated: "cert:" and the client certificate's common name,
+// "token:" and the static token's name, or "vault:" and the Vault token's
+// display name. Groups are further names the policy may allow, such as
+// "vault-policy:" and each policy of a Vault token.
+type Identity struct {
+	Name   string
+	Groups []string
+}
+
+// Credentials are what a caller presented with a request.
+type Credentials struct {
+	// Token is the bearer token of the request.
+	Token string
+
+	// CommonName is the common name of the client certificate, verified
+	// against the listener's client CA.
+	CommonName string
+}
+
+// TokenLookup returns the identity of a token that is not one of the policy's
+// static tokens.
+type TokenLookup func(token string) (Identity, error)
+
+// Authorizer authenticates callers and authorizes their methods by a Policy.
+type Authorizer struct {
+	policy Policy
+	tokens map[string]string
+	lookup TokenLookup
+}
+
+// New returns an Authorizer of policy. Bearer tokens other than the policy's
+// static tokens are looked up with lookup, or refused if nil.
+func New(policy Policy, lookup TokenLookup) *Authorizer {
+	tokens := make(map[string]string, len(policy.Tokens))
+	for name, token := range policy.Tokens {
+		tokens[token] = name
+	}
+	return &Authorizer{
+		policy: policy,
+		tokens: tokens,
+		lookup: lookup,
+	}
+}
+
+// Authenticate returns the identity of the caller presenting c. A bearer
+// token takes precedence over a client certificate.
+func (a *Authorizer) Authenticate(c Credentials) (Identity, error) {
+	switch {
+	case c.Token != "":
+		if name, ok := a.tokens[c.Token]; ok {
+			return Identity{Name: "token:" + name}, nil
+		}
+		if a.lookup == nil {
+			return Identity{}, ErrUnauthenticated
+		}
+		return a.lookup(c.Token)
+	case c.CommonName != "":
+		return Identity{Name: "cert:" + c.CommonName}, nil
+	}
+	return Identity{Name: Anonymous}, nil
+}
+
+// Authorize returns an error unless the policy allows id to call method.
+func (a *Authorizer) Authorize(id Identity, method string) error {
+	names := append([]string{id.Name}, id.Groups...)
+	if id.Name != Anonymous {
+		names = append(names, Everyone)
+	}
+	for _, name := range names {
+		if a.policy.allows(name, method) {
+			return nil
+		}
+	}
+
+	if id.Name == Anonymous {
+		return ErrUnauthenticated
+	}
+	return ErrPermissionDenied
+}
+
+type contextKey int
+
+const (
+	credentialsKey contextKey = iota
+	identityKey
+)
+
+// NewContext returns a context carrying the credentials of a request.
+func NewContext(ctx context.Context, c Credentials) context.Context {
+	return context.WithValue(ctx, credentialsKey, c)
+}
+
+// FromContext returns the credentials carried by ctx, if any.
+func FromContext(ctx context.Context) Credentials {
+	c, _ := ctx.Value(credentialsKey).(Credentials)
+	return c
+}
+
+// NewIdentityContext returns a context carrying the identity of an
+// authenticated caller.
+func NewIdentityContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey, id)
+}
+
+// IdentityFromContext returns the identity of the caller carried by ctx, if
+// authenticated.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey).(Identity)
+	return id, ok
+}
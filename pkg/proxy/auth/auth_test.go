@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const ciToken = "8f3e0f4c-2a41-4bc0-9b4f-5b1f3a0c6d2e"
+
+func TestLoadPolicy(t *testing.T) {
+	p, err := LoadPolicy("test-fixtures/policy.yaml")
+	assert.NoError(t, err, "not expecting an error for a valid policy")
+	assert.Equal(t, ciToken, p.Tokens["ci"], "expecting the static tokens")
+	assert.Equal(t, []string{"*"}, p.Allow["cert:ops.packers.com"], "expecting identities containing dots")
+
+	_, err = LoadPolicy("test-fixtures/duplicate-tokens.yaml")
+	assert.Error(t, err, "expecting an error for duplicate static tokens")
+
+	_, err = LoadPolicy("test-fixtures/missing.yaml")
+	assert.Error(t, err, "expecting an error for a missing policy")
+}
+
+func TestAuthorizer(t *testing.T) {
+	p, err := LoadPolicy("test-fixtures/policy.yaml")
+	assert.NoError(t, err, "not expecting an error for a valid policy")
+
+	lookup := func(token string) (Identity, error) {
+		if token != "vault-token" {
+			return Identity{}, ErrUnauthenticated
+		}
+		return Identity{Name: "vault:ldap-bart", Groups: []string{"vault-policy:armor-admin"}}, nil
+	}
+	a := New(p, lookup)
+
+	tests := []struct {
+		creds  Credentials
+		method string
+		id     string
+		err    error
+	}{
+		{Credentials{}, "SealStatus", Anonymous, nil},
+		{Credentials{}, "Init", Anonymous, ErrUnauthenticated},
+		{Credentials{Token: ciToken}, "Configure", "token:ci", nil},
+		{Credentials{Token: ciToken}, "ListClusters", "token:ci", nil},
+		{Credentials{Token: ciToken}, "Init", "token:ci", ErrPermissionDenied},
+		{Credentials{CommonName: "ops.packers.com"}, "Init", "cert:ops.packers.com", nil},
+		{Credentials{CommonName: "fan.packers.com"}, "Init", "cert:fan.packers.com", ErrPermissionDenied},
+		{Credentials{Token: ciToken, CommonName: "ops.packers.com"}, "Init", "token:ci", ErrPermissionDenied},
+		{Credentials{Token: "vault-token"}, "Init", "vault:ldap-bart", nil},
+	}
+	for _, tt := range tests {
+		id, err := a.Authenticate(tt.creds)
+		assert.NoError(t, err, fmt.Sprintf("not expecting an error authenticating %v", tt.creds))
+		assert.Equal(t, tt.id, id.Name, fmt.Sprintf("expecting the identity of %v", tt.creds))
+		assert.Equal(t, tt.err, a.Authorize(id, tt.method), fmt.Sprintf("expecting %v calling %s as %s", tt.err, tt.method, id.Name))
+	}
+
+	_, err = a.Authenticate(Credentials{Token: "bogus"})
+	assert.Equal(t, ErrUnauthenticated, err, "expecting an error for an unknown token")
+
+	_, err = New(p, nil).Authenticate(Credentials{Token: "vault-token"})
+	assert.Equal(t, ErrUnauthenticated, err, "expecting an error for a non static token without a lookup")
+}
+
+func TestHTTPToContext(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/seal/status", nil)
+	r.Header.Set("Authorization", "Bearer "+ciToken)
+	r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "ops.packers.com"}}}}}
+	c := FromContext(HTTPToContext()(context.Background(), r))
+	assert.Equal(t, ciToken, c.Token, "expecting the bearer token")
+	assert.Equal(t, "ops.packers.com", c.CommonName, "expecting the verified client certificate")
+
+	r, _ = http.NewRequest("GET", "/seal/status", nil)
+	r.Header.Set("X-Vault-Token", "vault-token")
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "ops.packers.com"}}}}
+	c = FromContext(HTTPToContext()(context.Background(), r))
+	assert.Equal(t, "vault-token", c.Token, "expecting the vault token")
+	assert.Equal(t, "", c.CommonName, "not expecting an unverified client certificate")
+}
+
+func TestGRPCToContext(t *testing.T) {
+	ctx := metadata.NewContext(context.Background(), metadata.Pairs(authorizationKey, "Bearer "+ciToken, peerCommonNameKey, "ops.packers.com"))
+	md, _ := metadata.FromContext(PeerToMetadata(ctx))
+	c := FromContext(GRPCToContext()(context.Background(), &md))
+	assert.Equal(t, ciToken, c.Token, "expecting the bearer token")
+	assert.Equal(t, "", c.CommonName, "not expecting a common name set by the caller")
+
+	ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "fan.packers.com"}}}},
+	}}})
+	md, _ = metadata.FromContext(PeerToMetadata(ctx))
+	c = FromContext(GRPCToContext()(context.Background(), &md))
+	assert.Equal(t, "fan.packers.com", c.CommonName, "expecting the verified client certificate")
+}
+
+func TestVaultTokenIdentity(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors":["permission denied"]}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"display_name":"ldap-bart","policies":["default","armor-admin"]}}`)
+	}))
+	defer srv.Close()
+
+	newClient := func() *vaultapi.Client {
+		cfg := vaultapi.DefaultConfig()
+		cfg.Address = srv.URL
+		client, err := vaultapi.NewClient(cfg)
+		assert.NoError(t, err, "not expecting an error creating a vault client")
+		return client
+	}
+
+	id, err := vaultTokenIdentity(newClient(), "vault-token")
+	assert.NoError(t, err, "not expecting an error for a valid token")
+	assert.Equal(t, Identity{Name: "vault:ldap-bart", Groups: []string{"vault-policy:default", "vault-policy:armor-admin"}}, id, "expecting the token's identity")
+
+	_, err = vaultTokenIdentity(newClient(), "bogus")
+	assert.Equal(t, ErrUnauthenticated, err, "expecting an error for an invalid token")
+}
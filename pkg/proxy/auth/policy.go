@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	// ErrPolicyTokenEmpty is returned for a static token without a value.
+	ErrPolicyTokenEmpty = errors.New("static token is empty")
+
+	// ErrPolicyTokenDuplicate is returned when two static tokens share a
+	// value.
+	ErrPolicyTokenDuplicate = errors.New("static token is not unique")
+)
+
+// Policy maps identities to the methods they may call. It is read from a YAML
+// or JSON file such as:
+//
+//	tokens:
+//	  ci: 8f3e0f4c-2a41-4bc0-9b4f-5b1f3a0c6d2e
+//	allow:
+//	  anonymous: [InitStatus, SealStatus]
+//	  "*": [InitStatus, SealStatus, Unseal, ListClusters]
+//	  cert:ops-admin: ["*"]
+//	  token:ci: [Configure]
+//	  vault-policy:armor-admin: ["*"]
+type Policy struct {
+	// Tokens are static bearer tokens, by name.
+	Tokens map[string]string `yaml:"tokens" json:"tokens"`
+
+	// Allow are the methods each identity may call.
+	Allow map[string][]string `yaml:"allow" json:"allow"`
+}
+
+// LoadPolicy reads the policy file at path.
+func LoadPolicy(path string) (Policy, error) {
+	var p Policy
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return p, err
+	}
+	if err := yaml.Unmarshal(b, &p); err != nil {
+		return p, fmt.Errorf("%s: %v", path, err)
+	}
+
+	seen := make(map[string]string, len(p.Tokens))
+	for name, token := range p.Tokens {
+		if token == "" {
+			return p, fmt.Errorf("%s: %q", ErrPolicyTokenEmpty.Error(), name)
+		}
+		if other, ok := seen[token]; ok {
+			return p, fmt.Errorf("%s: %q and %q", ErrPolicyTokenDuplicate.Error(), other, name)
+		}
+		seen[token] = name
+	}
+	return p, nil
+}
+
+func (p Policy) allows(identity, method string) bool {
+	for _, m := range p.Allow[identity] {
+		if m == method || m == Everyone {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+
+	grpctransport "github.com/go-kit/kit/transport/grpc"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	// authorizationKey is the gRPC metadata key of the bearer token.
+	authorizationKey = "authorization"
+
+	// peerCommonNameKey is the gRPC metadata key carrying the common name of
+	// the verified client certificate from the gRPC context to the endpoint
+	// context. It is always overwritten, so callers cannot set it.
+	peerCommonNameKey = "armor-peer-common-name"
+)
+
+// HTTPToContext returns a RequestFunc moving the bearer token, of the
+// Authorization or X-Vault-Token header, and the verified client certificate
+// of a request to its context.
+func HTTPToContext() httptransport.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		c := Credentials{
+			Token:      bearerToken(r.Header.Get("Authorization")),
+			CommonName: commonName(r.TLS),
+		}
+		if c.Token == "" {
+			c.Token = r.Header.Get("X-Vault-Token")
+		}
+		return NewContext(ctx, c)
+	}
+}
+
+// GRPCToContext returns a RequestFunc moving the bearer token of the
+// authorization metadata, and the client certificate set by PeerToMetadata,
+// to the request context.
+func GRPCToContext() grpctransport.RequestFunc {
+	return func(ctx context.Context, md *metadata.MD) context.Context {
+		var c Credentials
+		if v := (*md)[authorizationKey]; len(v) > 0 {
+			c.Token = bearerToken(v[0])
+		}
+		if v := (*md)[peerCommonNameKey]; len(v) > 0 {
+			c.CommonName = v[0]
+		}
+		return NewContext(ctx, c)
+	}
+}
+
+// PeerToMetadata returns a gRPC context whose metadata carries the common
+// name of the peer's verified client certificate. go-kit's gRPC server hands
+// endpoints only the request metadata, not the gRPC context with its peer.
+func PeerToMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	delete(md, peerCommonNameKey)
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if cn := commonName(&info.State); cn != "" {
+				md[peerCommonNameKey] = []string{cn}
+			}
+		}
+	}
+	return metadata.NewContext(ctx, md)
+}
+
+func bearerToken(header string) string {
+	const prefix = "bearer "
+	if len(header) > len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+		return strings.TrimSpace(header[len(prefix):])
+	}
+	return ""
+}
+
+func commonName(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return state.VerifiedChains[0][0].Subject.CommonName
+}
+
+// ContextToHTTP returns a RequestFunc setting the Authorization header of an
+// outgoing request to the bearer token of its context, if any.
+func ContextToHTTP() httptransport.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		if c := FromContext(ctx); c.Token != "" {
+			r.Header.Set("Authorization", "Bearer "+c.Token)
+		}
+		return ctx
+	}
+}
+
+// ContextToGRPC returns a RequestFunc setting the authorization metadata of
+// an outgoing request to the bearer token of its context, if any.
+func ContextToGRPC() grpctransport.RequestFunc {
+	return func(ctx context.Context, md *metadata.MD) context.Context {
+		if c := FromContext(ctx); c.Token != "" {
+			(*md)[authorizationKey] = []string{"Bearer " + c.Token}
+		}
+		return ctx
+	}
+}
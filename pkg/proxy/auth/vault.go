@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/cdwlabs/armor/pkg/proxy/service"
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// VaultTokenLookup is a TokenLookup validating tokens with Vault's
+// auth/token/lookup-self on the default cluster. The identity is named after
+// the token's display name, in the groups of its policies.
+func VaultTokenLookup(token string) (Identity, error) {
+	client, _, err := service.NewClusterClient("")
+	if err != nil {
+		return Identity{}, err
+	}
+	return vaultTokenIdentity(client, token)
+}
+
+func vaultTokenIdentity(client *vaultapi.Client, token string) (Identity, error) {
+	client.SetToken(token)
+	r := client.NewRequest("GET", "/v1/auth/token/lookup-self")
+	resp, err := client.RawRequest(r)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized) {
+			return Identity{}, ErrUnauthenticated
+		}
+		return Identity{}, err
+	}
+
+	secret, err := vaultapi.ParseSecret(resp.Body)
+	if err != nil {
+		return Identity{}, err
+	}
+	if secret == nil || secret.Data == nil {
+		return Identity{}, ErrUnauthenticated
+	}
+
+	id := Identity{Name: "vault:"}
+	if name, ok := secret.Data["display_name"].(string); ok {
+		id.Name += name
+	}
+	if policies, ok := secret.Data["policies"].([]interface{}); ok {
+		for _, p := range policies {
+			if p, ok := p.(string); ok {
+				id.Groups = append(id.Groups, "vault-policy:"+p)
+			}
+		}
+	}
+	return id, nil
+}
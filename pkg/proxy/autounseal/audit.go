@@ -0,0 +1,141 @@
+package autounseal
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/backend/delivery"
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/go-kit/kit/log"
+)
+
+// AuditRecord records an unseal token submitted to Vault by the controller.
+// The token itself is left out.
+type AuditRecord struct {
+	Time       string `json:"time"`
+	Node       string `json:"node"`
+	ClusterID  string `json:"cluster_id"`
+	TokenType  string `json:"token_type"`
+	ShareIndex int    `json:"share_index"`
+	Email      string `json:"email"` // holder of the token
+}
+
+func newAuditRecord(node Node, share *dbackend.TokenHolder, now time.Time) AuditRecord {
+	return AuditRecord{
+		Time:       now.Format(time.RFC3339),
+		Node:       node.Address,
+		ClusterID:  share.ClusterID,
+		TokenType:  share.TokenType,
+		ShareIndex: share.ShareIndex,
+		Email:      share.Email,
+	}
+}
+
+// AuditLog keeps AuditRecords. The controller doesn't use a token unless its
+// record was kept.
+type AuditLog interface {
+	Record(rec AuditRecord) error
+}
+
+// NewAuditLog returns an AuditLog which logs records to logger, and appends
+// them to the file at path, if path isn't empty.
+func NewAuditLog(path string, logger log.Logger) (AuditLog, error) {
+	audit := &auditLog{logger: log.NewContext(logger).With("audit", "auto-unseal")}
+	if path == "" {
+		return audit, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	audit.file = f
+
+	return audit, nil
+}
+
+type auditLog struct {
+	mu     sync.Mutex
+	file   *os.File
+	logger log.Logger
+}
+
+// Record logs rec, and appends it as a JSON line to the file, which is
+// synced before Record returns.
+func (a *auditLog) Record(rec AuditRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.logger.Log("node", rec.Node, "cluster_id", rec.ClusterID, "token_type", rec.TokenType, "share_index", rec.ShareIndex, "email", rec.Email)
+	if a.file == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.file.Write(append(b, '\n'))
+	if err != nil {
+		return err
+	}
+	return a.file.Sync()
+}
+
+// Alerter notifies people of the controller's attempts to unseal Vault.
+type Alerter interface {
+	Alert(subject, body string) error
+}
+
+// NewAlerter returns an Alerter emailing the auto_unseal_alert_emails
+// addresses from delivery_from, by delivery_method. It returns nil if no
+// addresses are set.
+func NewAlerter(cfg config.Provider) (Alerter, error) {
+	to := cfg.GetStringSlice("auto_unseal_alert_emails")
+	if len(to) == 0 {
+		return nil, nil
+	}
+
+	mailer, err := delivery.NewMailer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if mailer == nil {
+		return nil, ErrAlertMethodUnset
+	}
+
+	from := cfg.GetString("delivery_from")
+	if from == "" {
+		return nil, delivery.ErrDeliveryFromUnset
+	}
+
+	return &mailAlerter{mailer: mailer, from: from, to: to}, nil
+}
+
+type mailAlerter struct {
+	mailer delivery.Mailer
+	from   string
+	to     []string
+}
+
+// Alert sends a message to every address, and returns the last error.
+func (m *mailAlerter) Alert(subject, body string) error {
+	var err error
+	for _, to := range m.to {
+		sendErr := m.mailer.Send(&delivery.Message{
+			From:    m.from,
+			To:      strings.TrimSpace(to),
+			Subject: subject,
+			Body:    body,
+		})
+		if sendErr != nil {
+			err = sendErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,316 @@
+// Package autounseal unseals Vault nodes with the unseal tokens escrowed in
+// Armor's token holder store, so nobody has to gather the token holders after
+// every node restart. It is opt-in (see Armor's auto_unseal configuration),
+// and only tokens which aren't PGP encrypted can be used.
+package autounseal
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/cdwlabs/armor/pkg/proxy/service"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	vaultapi "github.com/hashicorp/vault/api"
+	"golang.org/x/net/context"
+)
+
+// auto-unseal errors
+var (
+	ErrRateLimited      = errors.New("auto-unseal attempts exceeded for the window")
+	ErrNotEnoughShares  = errors.New("not enough usable unseal tokens in the token holder store")
+	ErrStillSealed      = errors.New("vault is still sealed after submitting unseal tokens")
+	ErrAlertMethodUnset = errors.New("auto-unseal alert emails set, but no delivery method")
+	ErrMaxAttemptsUnset = errors.New("auto-unseal max attempts must be positive")
+)
+
+// Sealer is the part of Vault's sys API used by the controller, i.e.
+// (*vaultapi.Client).Sys().
+type Sealer interface {
+	SealStatus() (*vaultapi.SealStatusResponse, error)
+	Unseal(shard string) (*vaultapi.SealStatusResponse, error)
+}
+
+// Node is a Vault server watched by the controller.
+type Node struct {
+	Address   string
+	ClusterID string // identifies the node's unseal tokens in the token holder store
+	Sys       Sealer
+}
+
+// Controller watches the seal status of Vault nodes, and unseals sealed
+// nodes with the threshold number of unseal tokens from the token holder
+// store. Every token used is recorded in the audit log before it is
+// submitted, and every attempt is alerted.
+type Controller struct {
+	Nodes       []Node
+	Store       dbackend.TokenHolderStore
+	Audit       AuditLog
+	Alerter     Alerter         // may be nil
+	Attempts    metrics.Counter // counts attempts by node and result; may be nil
+	Logger      log.Logger
+	Interval    time.Duration // between seal status polls
+	MaxAttempts int           // allowed per node within Window
+	Window      time.Duration
+
+	mu       sync.Mutex
+	attempts map[string][]time.Time // recent attempts by node address
+	limited  map[string]bool        // whether the node's rate limiting was alerted
+	now      func() time.Time
+}
+
+// New creates the Controller set by Armor's auto_unseal_* configuration,
+// watching every node of every Vault cluster Armor manages.
+func New(logger log.Logger, attempts metrics.Counter) (*Controller, error) {
+	cfg := config.Config()
+
+	interval, maxAttempts, window, err := configLimits(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := service.Clusters()
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []Node
+	for _, cluster := range clusters {
+		clients, err := service.NewClusterNodeClients(cluster.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, client := range clients {
+			nodes = append(nodes, Node{
+				Address:   service.VaultAddress(client),
+				ClusterID: cluster.ClusterID,
+				Sys:       client.Sys(),
+			})
+		}
+	}
+
+	store, err := dbackend.TokenHolders()
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := NewAuditLog(cfg.GetString("auto_unseal_audit_file"), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	alerter, err := NewAlerter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Controller{
+		Nodes:       nodes,
+		Store:       store,
+		Audit:       audit,
+		Alerter:     alerter,
+		Attempts:    attempts,
+		Logger:      logger,
+		Interval:    interval,
+		MaxAttempts: maxAttempts,
+		Window:      window,
+	}, nil
+}
+
+// configLimits returns the interval, max attempts and window of Armor's
+// auto_unseal_* configuration. An interval or window which isn't positive
+// falls back to its default, while max attempts which aren't positive are
+// refused, since they would block every attempt.
+func configLimits(cfg config.Provider) (time.Duration, int, time.Duration, error) {
+	interval := cfg.GetDuration("auto_unseal_interval")
+	if interval <= 0 {
+		interval = config.AutoUnsealIntervalDefault
+	}
+
+	window := cfg.GetDuration("auto_unseal_window")
+	if window <= 0 {
+		window = config.AutoUnsealWindowDefault
+	}
+
+	maxAttempts := cfg.GetInt("auto_unseal_max_attempts")
+	if maxAttempts <= 0 {
+		return 0, 0, 0, ErrMaxAttemptsUnset
+	}
+
+	return interval, maxAttempts, window, nil
+}
+
+// Run checks the nodes every Interval, until ctx is done.
+func (c *Controller) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.Check()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check unseals every sealed node once.
+func (c *Controller) Check() {
+	for _, node := range c.Nodes {
+		logger := log.NewContext(c.Logger).With("node", node.Address)
+
+		unsealed, err := c.check(node)
+		switch {
+		case err == ErrRateLimited:
+			logger.Log("msg", "auto-unseal skipped", "err", err)
+			c.count(node, "rate_limited")
+		case err != nil:
+			logger.Log("msg", "auto-unseal failed", "err", err)
+			c.count(node, "failure")
+			c.alert(fmt.Sprintf("Armor failed to unseal Vault node %s", node.Address),
+				fmt.Sprintf("Armor's auto-unseal controller failed to unseal the Vault node %s of %s: %s\n", node.Address, node.ClusterID, err))
+		case unsealed:
+			logger.Log("msg", "auto-unseal succeeded")
+			c.count(node, "success")
+			c.alert(fmt.Sprintf("Armor unsealed Vault node %s", node.Address),
+				fmt.Sprintf("Armor's auto-unseal controller unsealed the Vault node %s of %s with escrowed unseal tokens. See the audit log for the tokens used.\n", node.Address, node.ClusterID))
+		}
+	}
+}
+
+// check unseals node if it is sealed, and reports whether it did.
+func (c *Controller) check(node Node) (bool, error) {
+	status, err := node.Sys.SealStatus()
+	if err != nil {
+		return false, err
+	}
+	if !status.Sealed {
+		c.resetLimit(node)
+		return false, nil
+	}
+
+	allowed, first := c.allow(node)
+	if !allowed {
+		if first {
+			c.alert(fmt.Sprintf("Armor stopped unsealing Vault node %s", node.Address),
+				fmt.Sprintf("Armor's auto-unseal controller made %d attempts to unseal the Vault node %s of %s within %s, and won't try again until the window passes. The node is still sealed.\n", c.MaxAttempts, node.Address, node.ClusterID, c.Window))
+		}
+		return false, ErrRateLimited
+	}
+
+	shares, err := c.shares(node, status.T)
+	if err != nil {
+		return false, err
+	}
+
+	for _, share := range shares {
+		// the token is only used once its use is on record
+		err = c.Audit.Record(newAuditRecord(node, share, c.clock()))
+		if err != nil {
+			return false, err
+		}
+
+		status, err = node.Sys.Unseal(share.Token)
+		if err != nil {
+			return false, err
+		}
+		if !status.Sealed {
+			return true, nil
+		}
+	}
+
+	return false, ErrStillSealed
+}
+
+// shares returns threshold unseal tokens of the node's cluster, which aren't
+// revoked or PGP encrypted.
+func (c *Controller) shares(node Node, threshold int) ([]*dbackend.TokenHolder, error) {
+	holders, err := c.Store.ListByCluster(node.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var shares []*dbackend.TokenHolder
+	for _, tokenHolder := range holders {
+		if tokenHolder.TokenType != dbackend.UnsealTokenType || tokenHolder.DateRevoked != "" {
+			continue
+		}
+		if tokenHolder.PGPFingerprint != "" || tokenHolder.Token == "" {
+			continue
+		}
+		shares = append(shares, tokenHolder)
+	}
+
+	if threshold < 1 || len(shares) < threshold {
+		return nil, fmt.Errorf("%s: %d of %d", ErrNotEnoughShares.Error(), len(shares), threshold)
+	}
+	return shares[:threshold], nil
+}
+
+// allow records an attempt on node, unless MaxAttempts were made within
+// Window. It also reports whether a refused attempt is the first in a row.
+func (c *Controller) allow(node Node) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.attempts == nil {
+		c.attempts = make(map[string][]time.Time)
+		c.limited = make(map[string]bool)
+	}
+
+	now := c.clock()
+	recent := c.attempts[node.Address][:0]
+	for _, at := range c.attempts[node.Address] {
+		if now.Sub(at) < c.Window {
+			recent = append(recent, at)
+		}
+	}
+	c.attempts[node.Address] = recent
+
+	if len(recent) >= c.MaxAttempts {
+		first := !c.limited[node.Address]
+		c.limited[node.Address] = true
+		return false, first
+	}
+
+	c.attempts[node.Address] = append(recent, now)
+	return true, false
+}
+
+// resetLimit re-arms the rate limiting alert of an unsealed node.
+func (c *Controller) resetLimit(node Node) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.limited, node.Address)
+}
+
+func (c *Controller) alert(subject, body string) {
+	if c.Alerter == nil {
+		return
+	}
+
+	err := c.Alerter.Alert(subject, body)
+	if err != nil {
+		c.Logger.Log("msg", "auto-unseal alert failed", "err", err)
+	}
+}
+
+func (c *Controller) count(node Node, result string) {
+	if c.Attempts != nil {
+		c.Attempts.With("node", node.Address, "result", result).Add(1)
+	}
+}
+
+func (c *Controller) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
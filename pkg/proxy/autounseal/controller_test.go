@@ -0,0 +1,194 @@
+package autounseal
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/go-kit/kit/log"
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// testSealer is a sealed Vault node, unsealed by threshold of its keys.
+type testSealer struct {
+	keys      map[string]bool
+	threshold int
+	submitted []string
+	sealed    bool
+}
+
+func newTestSealer(threshold int, keys ...string) *testSealer {
+	s := &testSealer{keys: make(map[string]bool), threshold: threshold, sealed: true}
+	for _, key := range keys {
+		s.keys[key] = true
+	}
+	return s
+}
+
+func (s *testSealer) status() *vaultapi.SealStatusResponse {
+	return &vaultapi.SealStatusResponse{Sealed: s.sealed, T: s.threshold, N: len(s.keys), Progress: len(s.submitted)}
+}
+
+func (s *testSealer) SealStatus() (*vaultapi.SealStatusResponse, error) {
+	return s.status(), nil
+}
+
+func (s *testSealer) Unseal(shard string) (*vaultapi.SealStatusResponse, error) {
+	if s.keys[shard] {
+		s.submitted = append(s.submitted, shard)
+	}
+	if len(s.submitted) >= s.threshold {
+		s.sealed = false
+	}
+	return s.status(), nil
+}
+
+type testAudit struct{ records []AuditRecord }
+
+func (a *testAudit) Record(rec AuditRecord) error {
+	a.records = append(a.records, rec)
+	return nil
+}
+
+type testAlerter struct{ subjects []string }
+
+func (a *testAlerter) Alert(subject, body string) error {
+	a.subjects = append(a.subjects, subject)
+	return nil
+}
+
+func putUnsealToken(t *testing.T, store dbackend.TokenHolderStore, index int, token string, mutate func(*dbackend.TokenHolder)) {
+	tokenHolder := dbackend.NewTokenHolder()
+	tokenHolder.ClusterID = "packers"
+	tokenHolder.Email = "aaron.rodgers@packers.com"
+	tokenHolder.TokenType = dbackend.UnsealTokenType
+	tokenHolder.ShareIndex = index
+	tokenHolder.Token = token
+	if mutate != nil {
+		mutate(tokenHolder)
+	}
+	err := store.Put(tokenHolder)
+	assert.NoError(t, err, "not expecting an error when putting token holder")
+}
+
+func newTestController(store dbackend.TokenHolderStore, sealer Sealer) (*Controller, *testAudit, *testAlerter) {
+	audit := &testAudit{}
+	alerter := &testAlerter{}
+	c := &Controller{
+		Nodes:       []Node{{Address: "https://vault-1:8200", ClusterID: "packers", Sys: sealer}},
+		Store:       store,
+		Audit:       audit,
+		Alerter:     alerter,
+		Logger:      log.NewNopLogger(),
+		MaxAttempts: 2,
+		Window:      time.Hour,
+	}
+	return c, audit, alerter
+}
+
+func TestController_Check(t *testing.T) {
+	store := dbackend.NewMemoryStore()
+	putUnsealToken(t, store, 1, "revoked", func(h *dbackend.TokenHolder) { h.DateRevoked = time.Now().Format(time.RFC3339) })
+	putUnsealToken(t, store, 2, "encrypted", func(h *dbackend.TokenHolder) { h.PGPFingerprint = "abcdef" })
+	putUnsealToken(t, store, 3, "key-3", nil)
+	putUnsealToken(t, store, 4, "key-4", nil)
+	putUnsealToken(t, store, 5, "key-5", nil)
+
+	sealer := newTestSealer(2, "key-3", "key-4", "key-5")
+	c, audit, alerter := newTestController(store, sealer)
+
+	c.Check()
+	assert.False(t, sealer.sealed, "expecting the node to be unsealed")
+	assert.Equal(t, []string{"key-3", "key-4"}, sealer.submitted, "expecting the threshold of usable tokens to be submitted")
+	if assert.Len(t, audit.records, 2, "expecting every token used to be audited") {
+		assert.Equal(t, 3, audit.records[0].ShareIndex, "expecting the audit record to identify the share")
+		assert.Equal(t, "https://vault-1:8200", audit.records[0].Node, "expecting the audit record to identify the node")
+	}
+	assert.Equal(t, []string{"Armor unsealed Vault node https://vault-1:8200"}, alerter.subjects, "expecting the unseal to be alerted")
+
+	c.Check()
+	assert.Len(t, audit.records, 2, "not expecting an unsealed node to be unsealed again")
+}
+
+func TestController_Check_NotEnoughShares(t *testing.T) {
+	store := dbackend.NewMemoryStore()
+	putUnsealToken(t, store, 1, "key-1", nil)
+	putUnsealToken(t, store, 2, "encrypted", func(h *dbackend.TokenHolder) { h.PGPFingerprint = "abcdef" })
+
+	sealer := newTestSealer(2, "key-1", "key-2")
+	c, audit, alerter := newTestController(store, sealer)
+
+	c.Check()
+	assert.True(t, sealer.sealed, "expecting the node to stay sealed")
+	assert.Empty(t, sealer.submitted, "not expecting tokens to be submitted short of the threshold")
+	assert.Empty(t, audit.records, "not expecting audit records when no token was used")
+	assert.Equal(t, []string{"Armor failed to unseal Vault node https://vault-1:8200"}, alerter.subjects, "expecting the failure to be alerted")
+}
+
+func TestController_Check_RateLimited(t *testing.T) {
+	store := dbackend.NewMemoryStore()
+	putUnsealToken(t, store, 1, "stale-1", nil)
+	putUnsealToken(t, store, 2, "stale-2", nil)
+
+	// the stored tokens were rekeyed, so the node is never unsealed
+	sealer := newTestSealer(2, "key-1", "key-2")
+	c, audit, alerter := newTestController(store, sealer)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 4; i++ {
+		c.Check()
+	}
+	assert.Len(t, audit.records, 4, "expecting only the allowed attempts to use tokens")
+	assert.Equal(t, []string{
+		"Armor failed to unseal Vault node https://vault-1:8200",
+		"Armor failed to unseal Vault node https://vault-1:8200",
+		"Armor stopped unsealing Vault node https://vault-1:8200",
+	}, alerter.subjects, "expecting the rate limiting to be alerted once")
+
+	now = now.Add(time.Hour)
+	c.Check()
+	assert.Len(t, audit.records, 6, "expecting attempts once the window passed")
+}
+
+func TestController_ConfigLimits(t *testing.T) {
+	defer os.Unsetenv(config.AutoUnsealIntervalEnvVar)
+	defer os.Unsetenv(config.AutoUnsealWindowEnvVar)
+	defer os.Unsetenv(config.AutoUnsealMaxAttemptsEnvVar)
+
+	cases := []struct {
+		interval, window, maxAttempts string
+		wantInterval, wantWindow      time.Duration
+		wantMaxAttempts               int
+		wantErr                       error
+	}{
+		{interval: "10s", window: "30m", maxAttempts: "5", wantInterval: 10 * time.Second, wantWindow: 30 * time.Minute, wantMaxAttempts: 5},
+		{interval: "0s", window: "0s", maxAttempts: "5", wantInterval: config.AutoUnsealIntervalDefault, wantWindow: config.AutoUnsealWindowDefault, wantMaxAttempts: 5},
+		{interval: "-10s", window: "-30m", maxAttempts: "5", wantInterval: config.AutoUnsealIntervalDefault, wantWindow: config.AutoUnsealWindowDefault, wantMaxAttempts: 5},
+		{interval: "10s", window: "30m", maxAttempts: "0", wantErr: ErrMaxAttemptsUnset},
+		{interval: "10s", window: "30m", maxAttempts: "-1", wantErr: ErrMaxAttemptsUnset},
+	}
+
+	for _, c := range cases {
+		os.Setenv(config.AutoUnsealIntervalEnvVar, c.interval)
+		os.Setenv(config.AutoUnsealWindowEnvVar, c.window)
+		os.Setenv(config.AutoUnsealMaxAttemptsEnvVar, c.maxAttempts)
+
+		interval, maxAttempts, window, err := configLimits(config.Config())
+		if c.wantErr != nil {
+			assert.Equal(t, c.wantErr, err, "expecting an error for max attempts %s", c.maxAttempts)
+			continue
+		}
+		if assert.NoError(t, err, "not expecting an error for max attempts %s", c.maxAttempts) {
+			assert.Equal(t, c.wantInterval, interval, "expecting a usable interval for %s", c.interval)
+			assert.Equal(t, c.wantWindow, window, "expecting a usable window for %s", c.window)
+			assert.Equal(t, c.wantMaxAttempts, maxAttempts, "expecting the max attempts of %s", c.maxAttempts)
+		}
+	}
+
+	_, err := New(log.NewNopLogger(), nil)
+	assert.Equal(t, ErrMaxAttemptsUnset, err, "expecting the controller to be refused for non-positive max attempts")
+}
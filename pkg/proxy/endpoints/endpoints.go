@@ -6,6 +6,8 @@ package endpoints
 // serialization formats. It also includes endpoint middlewares.
 
 import (
+	"encoding/json"
+	"github.com/cdwlabs/armor/pkg/proxy/auth"
 	"github.com/cdwlabs/armor/pkg/proxy/service"
 	"github.com/go-kit/kit/circuitbreaker"
 	"github.com/go-kit/kit/endpoint"
@@ -20,14 +22,16 @@ import (
 )
 
 // New returns an Endpoints that wraps the provided server, and wires in all of
-// the expected endpoint middlewares via the various parameters.
-func New(svc service.Service, logger log.Logger, duration metrics.Histogram, trace stdopentracing.Tracer) Endpoints {
+// the expected endpoint middlewares via the various parameters. Callers are
+// authorized by authorizer, unless nil.
+func New(svc service.Service, logger log.Logger, duration metrics.Histogram, trace stdopentracing.Tracer, authorizer *auth.Authorizer) Endpoints {
 	var initStatusEndpoint endpoint.Endpoint
 	{
 		initStatusEndpoint = MakeInitStatusEndpoint(svc)
 		initStatusEndpoint = opentracing.TraceServer(trace, "InitStatus")(initStatusEndpoint)
 		initStatusEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(initStatusEndpoint)
 		initStatusEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(initStatusEndpoint)
+		initStatusEndpoint = AuthMiddleware(authorizer, "InitStatus")(initStatusEndpoint)
 		initStatusEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "InitStatus"))(initStatusEndpoint)
 		initStatusEndpoint = InstrumentingMiddleware(duration.With("method", "InitStatus"))(initStatusEndpoint)
 	}
@@ -37,6 +41,7 @@ func New(svc service.Service, logger log.Logger, duration metrics.Histogram, tra
 		initEndpoint = opentracing.TraceServer(trace, "Init")(initEndpoint)
 		initEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(initEndpoint)
 		initEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(initEndpoint)
+		initEndpoint = AuthMiddleware(authorizer, "Init")(initEndpoint)
 		initEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "Init"))(initEndpoint)
 		initEndpoint = InstrumentingMiddleware(duration.With("method", "Init"))(initEndpoint)
 	}
@@ -46,15 +51,27 @@ func New(svc service.Service, logger log.Logger, duration metrics.Histogram, tra
 		sealStatusEndpoint = opentracing.TraceServer(trace, "SealStatus")(sealStatusEndpoint)
 		sealStatusEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(sealStatusEndpoint)
 		sealStatusEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(sealStatusEndpoint)
+		sealStatusEndpoint = AuthMiddleware(authorizer, "SealStatus")(sealStatusEndpoint)
 		sealStatusEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "SealStatus"))(sealStatusEndpoint)
 		sealStatusEndpoint = InstrumentingMiddleware(duration.With("method", "SealStatus"))(sealStatusEndpoint)
 	}
+	var watchSealStatusEndpoint endpoint.Endpoint
+	{
+		watchSealStatusEndpoint = MakeWatchSealStatusEndpoint(svc)
+		watchSealStatusEndpoint = opentracing.TraceServer(trace, "WatchSealStatus")(watchSealStatusEndpoint)
+		watchSealStatusEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(watchSealStatusEndpoint)
+		watchSealStatusEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(watchSealStatusEndpoint)
+		watchSealStatusEndpoint = AuthMiddleware(authorizer, "WatchSealStatus")(watchSealStatusEndpoint)
+		watchSealStatusEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "WatchSealStatus"))(watchSealStatusEndpoint)
+		watchSealStatusEndpoint = InstrumentingMiddleware(duration.With("method", "WatchSealStatus"))(watchSealStatusEndpoint)
+	}
 	var unsealEndpoint endpoint.Endpoint
 	{
 		unsealEndpoint = MakeUnsealEndpoint(svc)
 		unsealEndpoint = opentracing.TraceServer(trace, "Unseal")(unsealEndpoint)
 		unsealEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(unsealEndpoint)
 		unsealEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(unsealEndpoint)
+		unsealEndpoint = AuthMiddleware(authorizer, "Unseal")(unsealEndpoint)
 		unsealEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "Unseal"))(unsealEndpoint)
 		unsealEndpoint = InstrumentingMiddleware(duration.With("method", "Unseal"))(unsealEndpoint)
 	}
@@ -64,16 +81,128 @@ func New(svc service.Service, logger log.Logger, duration metrics.Histogram, tra
 		configureEndpoint = opentracing.TraceServer(trace, "Configure")(configureEndpoint)
 		configureEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(configureEndpoint)
 		configureEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(configureEndpoint)
+		configureEndpoint = AuthMiddleware(authorizer, "Configure")(configureEndpoint)
 		configureEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "Configure"))(configureEndpoint)
 		configureEndpoint = InstrumentingMiddleware(duration.With("method", "Configure"))(configureEndpoint)
 	}
+	var configureStreamEndpoint endpoint.Endpoint
+	{
+		configureStreamEndpoint = MakeConfigureStreamEndpoint(svc)
+		configureStreamEndpoint = opentracing.TraceServer(trace, "ConfigureStream")(configureStreamEndpoint)
+		configureStreamEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(configureStreamEndpoint)
+		configureStreamEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(configureStreamEndpoint)
+		configureStreamEndpoint = AuthMiddleware(authorizer, "ConfigureStream")(configureStreamEndpoint)
+		configureStreamEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "ConfigureStream"))(configureStreamEndpoint)
+		configureStreamEndpoint = InstrumentingMiddleware(duration.With("method", "ConfigureStream"))(configureStreamEndpoint)
+	}
+	var listTokenHoldersEndpoint endpoint.Endpoint
+	{
+		listTokenHoldersEndpoint = MakeListTokenHoldersEndpoint(svc)
+		listTokenHoldersEndpoint = opentracing.TraceServer(trace, "ListTokenHolders")(listTokenHoldersEndpoint)
+		listTokenHoldersEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(listTokenHoldersEndpoint)
+		listTokenHoldersEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(listTokenHoldersEndpoint)
+		listTokenHoldersEndpoint = AuthMiddleware(authorizer, "ListTokenHolders")(listTokenHoldersEndpoint)
+		listTokenHoldersEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "ListTokenHolders"))(listTokenHoldersEndpoint)
+		listTokenHoldersEndpoint = InstrumentingMiddleware(duration.With("method", "ListTokenHolders"))(listTokenHoldersEndpoint)
+	}
+	var getTokenHoldersEndpoint endpoint.Endpoint
+	{
+		getTokenHoldersEndpoint = MakeGetTokenHoldersEndpoint(svc)
+		getTokenHoldersEndpoint = opentracing.TraceServer(trace, "GetTokenHolders")(getTokenHoldersEndpoint)
+		getTokenHoldersEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(getTokenHoldersEndpoint)
+		getTokenHoldersEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(getTokenHoldersEndpoint)
+		getTokenHoldersEndpoint = AuthMiddleware(authorizer, "GetTokenHolders")(getTokenHoldersEndpoint)
+		getTokenHoldersEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "GetTokenHolders"))(getTokenHoldersEndpoint)
+		getTokenHoldersEndpoint = InstrumentingMiddleware(duration.With("method", "GetTokenHolders"))(getTokenHoldersEndpoint)
+	}
+	var reassignTokenHolderEndpoint endpoint.Endpoint
+	{
+		reassignTokenHolderEndpoint = MakeReassignTokenHolderEndpoint(svc)
+		reassignTokenHolderEndpoint = opentracing.TraceServer(trace, "ReassignTokenHolder")(reassignTokenHolderEndpoint)
+		reassignTokenHolderEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(reassignTokenHolderEndpoint)
+		reassignTokenHolderEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(reassignTokenHolderEndpoint)
+		reassignTokenHolderEndpoint = AuthMiddleware(authorizer, "ReassignTokenHolder")(reassignTokenHolderEndpoint)
+		reassignTokenHolderEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "ReassignTokenHolder"))(reassignTokenHolderEndpoint)
+		reassignTokenHolderEndpoint = InstrumentingMiddleware(duration.With("method", "ReassignTokenHolder"))(reassignTokenHolderEndpoint)
+	}
+	var revokeTokenHolderEndpoint endpoint.Endpoint
+	{
+		revokeTokenHolderEndpoint = MakeRevokeTokenHolderEndpoint(svc)
+		revokeTokenHolderEndpoint = opentracing.TraceServer(trace, "RevokeTokenHolder")(revokeTokenHolderEndpoint)
+		revokeTokenHolderEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(revokeTokenHolderEndpoint)
+		revokeTokenHolderEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(revokeTokenHolderEndpoint)
+		revokeTokenHolderEndpoint = AuthMiddleware(authorizer, "RevokeTokenHolder")(revokeTokenHolderEndpoint)
+		revokeTokenHolderEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "RevokeTokenHolder"))(revokeTokenHolderEndpoint)
+		revokeTokenHolderEndpoint = InstrumentingMiddleware(duration.With("method", "RevokeTokenHolder"))(revokeTokenHolderEndpoint)
+	}
+	var startUnsealCeremonyEndpoint endpoint.Endpoint
+	{
+		startUnsealCeremonyEndpoint = MakeStartUnsealCeremonyEndpoint(svc)
+		startUnsealCeremonyEndpoint = opentracing.TraceServer(trace, "StartUnsealCeremony")(startUnsealCeremonyEndpoint)
+		startUnsealCeremonyEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(startUnsealCeremonyEndpoint)
+		startUnsealCeremonyEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(startUnsealCeremonyEndpoint)
+		startUnsealCeremonyEndpoint = AuthMiddleware(authorizer, "StartUnsealCeremony")(startUnsealCeremonyEndpoint)
+		startUnsealCeremonyEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "StartUnsealCeremony"))(startUnsealCeremonyEndpoint)
+		startUnsealCeremonyEndpoint = InstrumentingMiddleware(duration.With("method", "StartUnsealCeremony"))(startUnsealCeremonyEndpoint)
+	}
+	var getUnsealCeremonyEndpoint endpoint.Endpoint
+	{
+		getUnsealCeremonyEndpoint = MakeGetUnsealCeremonyEndpoint(svc)
+		getUnsealCeremonyEndpoint = opentracing.TraceServer(trace, "GetUnsealCeremony")(getUnsealCeremonyEndpoint)
+		getUnsealCeremonyEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(getUnsealCeremonyEndpoint)
+		getUnsealCeremonyEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(getUnsealCeremonyEndpoint)
+		getUnsealCeremonyEndpoint = AuthMiddleware(authorizer, "GetUnsealCeremony")(getUnsealCeremonyEndpoint)
+		getUnsealCeremonyEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "GetUnsealCeremony"))(getUnsealCeremonyEndpoint)
+		getUnsealCeremonyEndpoint = InstrumentingMiddleware(duration.With("method", "GetUnsealCeremony"))(getUnsealCeremonyEndpoint)
+	}
+	var submitUnsealShareEndpoint endpoint.Endpoint
+	{
+		submitUnsealShareEndpoint = MakeSubmitUnsealShareEndpoint(svc)
+		submitUnsealShareEndpoint = opentracing.TraceServer(trace, "SubmitUnsealShare")(submitUnsealShareEndpoint)
+		submitUnsealShareEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(submitUnsealShareEndpoint)
+		submitUnsealShareEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(submitUnsealShareEndpoint)
+		submitUnsealShareEndpoint = AuthMiddleware(authorizer, "SubmitUnsealShare")(submitUnsealShareEndpoint)
+		submitUnsealShareEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "SubmitUnsealShare"))(submitUnsealShareEndpoint)
+		submitUnsealShareEndpoint = InstrumentingMiddleware(duration.With("method", "SubmitUnsealShare"))(submitUnsealShareEndpoint)
+	}
+	var abortUnsealCeremonyEndpoint endpoint.Endpoint
+	{
+		abortUnsealCeremonyEndpoint = MakeAbortUnsealCeremonyEndpoint(svc)
+		abortUnsealCeremonyEndpoint = opentracing.TraceServer(trace, "AbortUnsealCeremony")(abortUnsealCeremonyEndpoint)
+		abortUnsealCeremonyEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(abortUnsealCeremonyEndpoint)
+		abortUnsealCeremonyEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(abortUnsealCeremonyEndpoint)
+		abortUnsealCeremonyEndpoint = AuthMiddleware(authorizer, "AbortUnsealCeremony")(abortUnsealCeremonyEndpoint)
+		abortUnsealCeremonyEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "AbortUnsealCeremony"))(abortUnsealCeremonyEndpoint)
+		abortUnsealCeremonyEndpoint = InstrumentingMiddleware(duration.With("method", "AbortUnsealCeremony"))(abortUnsealCeremonyEndpoint)
+	}
+	var listClustersEndpoint endpoint.Endpoint
+	{
+		listClustersEndpoint = MakeListClustersEndpoint(svc)
+		listClustersEndpoint = opentracing.TraceServer(trace, "ListClusters")(listClustersEndpoint)
+		listClustersEndpoint = ratelimit.NewTokenBucketLimiter(rl.NewBucketWithRate(100, 100))(listClustersEndpoint)
+		listClustersEndpoint = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(gobreaker.Settings{}))(listClustersEndpoint)
+		listClustersEndpoint = AuthMiddleware(authorizer, "ListClusters")(listClustersEndpoint)
+		listClustersEndpoint = LoggingMiddleware(log.NewContext(logger).With("method", "ListClusters"))(listClustersEndpoint)
+		listClustersEndpoint = InstrumentingMiddleware(duration.With("method", "ListClusters"))(listClustersEndpoint)
+	}
 
 	return Endpoints{
-		InitStatusEndpoint: initStatusEndpoint,
-		InitEndpoint:       initEndpoint,
-		SealStatusEndpoint: sealStatusEndpoint,
-		UnsealEndpoint:     unsealEndpoint,
-		ConfigureEndpoint:  configureEndpoint,
+		InitStatusEndpoint:          initStatusEndpoint,
+		InitEndpoint:                initEndpoint,
+		SealStatusEndpoint:          sealStatusEndpoint,
+		WatchSealStatusEndpoint:     watchSealStatusEndpoint,
+		UnsealEndpoint:              unsealEndpoint,
+		ConfigureEndpoint:           configureEndpoint,
+		ConfigureStreamEndpoint:     configureStreamEndpoint,
+		ListTokenHoldersEndpoint:    listTokenHoldersEndpoint,
+		GetTokenHoldersEndpoint:     getTokenHoldersEndpoint,
+		ReassignTokenHolderEndpoint: reassignTokenHolderEndpoint,
+		RevokeTokenHolderEndpoint:   revokeTokenHolderEndpoint,
+		StartUnsealCeremonyEndpoint: startUnsealCeremonyEndpoint,
+		GetUnsealCeremonyEndpoint:   getUnsealCeremonyEndpoint,
+		SubmitUnsealShareEndpoint:   submitUnsealShareEndpoint,
+		AbortUnsealCeremonyEndpoint: abortUnsealCeremonyEndpoint,
+		ListClustersEndpoint:        listClustersEndpoint,
 	}
 }
 
@@ -92,16 +221,27 @@ func New(svc service.Service, logger log.Logger, duration metrics.Histogram, tra
 // might construct individual endpoints using transport/http.NewClient, combine
 // them into an Endpoints, and return it to the caller as a Service.
 type Endpoints struct {
-	InitStatusEndpoint endpoint.Endpoint
-	InitEndpoint       endpoint.Endpoint
-	SealStatusEndpoint endpoint.Endpoint
-	UnsealEndpoint     endpoint.Endpoint
-	ConfigureEndpoint  endpoint.Endpoint
+	InitStatusEndpoint          endpoint.Endpoint
+	InitEndpoint                endpoint.Endpoint
+	SealStatusEndpoint          endpoint.Endpoint
+	WatchSealStatusEndpoint     endpoint.Endpoint
+	UnsealEndpoint              endpoint.Endpoint
+	ConfigureEndpoint           endpoint.Endpoint
+	ConfigureStreamEndpoint     endpoint.Endpoint
+	ListTokenHoldersEndpoint    endpoint.Endpoint
+	GetTokenHoldersEndpoint     endpoint.Endpoint
+	ReassignTokenHolderEndpoint endpoint.Endpoint
+	RevokeTokenHolderEndpoint   endpoint.Endpoint
+	StartUnsealCeremonyEndpoint endpoint.Endpoint
+	GetUnsealCeremonyEndpoint   endpoint.Endpoint
+	SubmitUnsealShareEndpoint   endpoint.Endpoint
+	AbortUnsealCeremonyEndpoint endpoint.Endpoint
+	ListClustersEndpoint        endpoint.Endpoint
 }
 
 // InitStatus implements Service. Primarily useful in a client
-func (e Endpoints) InitStatus(ctx context.Context) (bool, error) {
-	request := InitStatusRequest{}
+func (e Endpoints) InitStatus(ctx context.Context, cluster string) (bool, error) {
+	request := InitStatusRequest{Cluster: cluster}
 	response, err := e.InitStatusEndpoint(ctx, request)
 	if err != nil {
 		return false, err
@@ -113,7 +253,8 @@ func (e Endpoints) InitStatus(ctx context.Context) (bool, error) {
 // service.  Primarily useful in a server.
 func MakeInitStatusEndpoint(s service.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
-		initialized, err := s.InitStatus(ctx)
+		var req = *request.(*InitStatusRequest)
+		initialized, err := s.InitStatus(ctx, req.Cluster)
 		return InitStatusResponse{
 			Initialized: initialized,
 			Err:         err,
@@ -145,8 +286,8 @@ func MakeInitEndpoint(s service.Service) endpoint.Endpoint {
 }
 
 // SealStatus implements Service. Primarily useful in a client
-func (e Endpoints) SealStatus(ctx context.Context) (service.SealState, error) {
-	request := SealStatusRequest{}
+func (e Endpoints) SealStatus(ctx context.Context, cluster string) (service.SealState, error) {
+	request := SealStatusRequest{Cluster: cluster}
 	response, err := e.SealStatusEndpoint(ctx, request)
 	if err != nil {
 		return service.SealState{}, err
@@ -159,15 +300,27 @@ func (e Endpoints) SealStatus(ctx context.Context) (service.SealState, error) {
 		Progress:    response.(SealStatusResponse).Progress,
 		ClusterName: response.(SealStatusResponse).ClusterName,
 		ClusterID:   response.(SealStatusResponse).ClusterID,
+		Nodes:       response.(SealStatusResponse).Nodes,
 	}
 	return state, response.(SealStatusResponse).Err
 }
 
+// WatchSealStatus implements Service. Primarily useful in a client
+func (e Endpoints) WatchSealStatus(ctx context.Context, cluster string, events service.SealEventFunc) error {
+	request := WatchSealStatusRequest{Cluster: cluster, Events: events}
+	response, err := e.WatchSealStatusEndpoint(ctx, request)
+	if err != nil {
+		return err
+	}
+	return response.(WatchSealStatusResponse).Err
+}
+
 // MakeSealStatusEndpoint returns an endpoint that invokes SealStatus on the
 // service.  Primarily useful in a server.
 func MakeSealStatusEndpoint(s service.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
-		state, err := s.SealStatus(ctx)
+		var req = *request.(*SealStatusRequest)
+		state, err := s.SealStatus(ctx, req.Cluster)
 		return SealStatusResponse{
 			Sealed:      state.Sealed,
 			T:           state.T,
@@ -176,14 +329,39 @@ func MakeSealStatusEndpoint(s service.Service) endpoint.Endpoint {
 			Version:     state.Version,
 			ClusterName: state.ClusterName,
 			ClusterID:   state.ClusterID,
+			Nodes:       state.Nodes,
 			Err:         err,
 		}, nil
 	}
 }
 
+// MakeWatchSealStatusEndpoint returns an endpoint that invokes
+// WatchSealStatus on the service, reporting its events to the request's
+// Events until the request's Done is closed. Primarily useful in a server.
+func MakeWatchSealStatusEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req = *request.(*WatchSealStatusRequest)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		if req.Done != nil {
+			go func() {
+				select {
+				case <-req.Done:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+		}
+
+		err = s.WatchSealStatus(ctx, req.Cluster, req.Events)
+		return WatchSealStatusResponse{Err: err}, nil
+	}
+}
+
 // Unseal implements Service. Primarily useful in a client
 func (e Endpoints) Unseal(ctx context.Context, opts service.UnsealOptions) (service.SealState, error) {
-	request := UnsealRequest{Key: opts.Key, Reset: opts.Reset}
+	request := UnsealRequest{Key: opts.Key, Reset: opts.Reset, Cluster: opts.Cluster, AllNodes: opts.AllNodes}
 	response, err := e.UnsealEndpoint(ctx, request)
 	if err != nil {
 		return service.SealState{}, err
@@ -196,6 +374,7 @@ func (e Endpoints) Unseal(ctx context.Context, opts service.UnsealOptions) (serv
 		Progress:    response.(UnsealResponse).Progress,
 		ClusterName: response.(UnsealResponse).ClusterName,
 		ClusterID:   response.(UnsealResponse).ClusterID,
+		Nodes:       response.(UnsealResponse).Nodes,
 	}
 	return state, response.(UnsealResponse).Err
 }
@@ -206,8 +385,10 @@ func MakeUnsealEndpoint(s service.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		var req = *request.(*UnsealRequest)
 		opts := service.UnsealOptions{
-			Key:   req.Key,
-			Reset: req.Reset,
+			Key:      req.Key,
+			Reset:    req.Reset,
+			Cluster:  req.Cluster,
+			AllNodes: req.AllNodes,
 		}
 
 		state, err := s.Unseal(ctx, opts)
@@ -219,6 +400,7 @@ func MakeUnsealEndpoint(s service.Service) endpoint.Endpoint {
 			Version:     state.Version,
 			ClusterName: state.ClusterName,
 			ClusterID:   state.ClusterID,
+			Nodes:       state.Nodes,
 			Err:         err,
 		}, nil
 	}
@@ -226,17 +408,122 @@ func MakeUnsealEndpoint(s service.Service) endpoint.Endpoint {
 
 // Configure implements Service. Primarily useful in a client
 func (e Endpoints) Configure(ctx context.Context, opts service.ConfigOptions) (service.ConfigState, error) {
-	request := ConfigureRequest{URL: opts.URL, Token: opts.Token}
+	request := ConfigureRequest{URL: opts.URL, Token: opts.Token, Cluster: opts.Cluster}
 	response, err := e.ConfigureEndpoint(ctx, request)
 	if err != nil {
 		return service.ConfigState{}, err
 	}
+	return response.(ConfigureResponse).ConfigState(), response.(ConfigureResponse).Err
+}
+
+// ConfigureStream implements Service. Primarily useful in a client
+func (e Endpoints) ConfigureStream(ctx context.Context, opts service.ConfigOptions, events service.ConfigEventFunc) (service.ConfigState, error) {
+	request := ConfigureStreamRequest{URL: opts.URL, Token: opts.Token, Cluster: opts.Cluster, Events: events}
+	response, err := e.ConfigureStreamEndpoint(ctx, request)
+	if err != nil {
+		return service.ConfigState{}, err
+	}
+	return response.(ConfigureResponse).ConfigState(), response.(ConfigureResponse).Err
+}
+
+// MakeConfigureEndpoint returns an endpoint that invokes Configure on the
+// service.  Primarily useful in a server.
+func MakeConfigureEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req = *request.(*ConfigureRequest)
+		opts := service.ConfigOptions{
+			URL:     req.URL,
+			Token:   req.Token,
+			Cluster: req.Cluster,
+		}
+
+		state, err := s.Configure(ctx, opts)
+		return NewConfigureResponse(state, err), nil
+	}
+}
 
+// MakeConfigureStreamEndpoint returns an endpoint that invokes
+// ConfigureStream on the service, reporting its events to the request's
+// Events. Primarily useful in a server.
+func MakeConfigureStreamEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req = *request.(*ConfigureStreamRequest)
+		opts := service.ConfigOptions{
+			URL:     req.URL,
+			Token:   req.Token,
+			Cluster: req.Cluster,
+		}
+
+		state, err := s.ConfigureStream(ctx, opts, req.Events)
+		return NewConfigureResponse(state, err), nil
+	}
+}
+
+// NewConfigureResponse converts the result of Configure to a response.
+func NewConfigureResponse(state service.ConfigState, err error) ConfigureResponse {
+	// mounts
+	var mounts map[string]MountOutput
+	if (state.Mounts != nil) && (len(state.Mounts) > 0) {
+		mounts = make(map[string]MountOutput)
+		for k, v := range state.Mounts {
+			mountCfgOut := MountConfigOutput{
+				DefaultLeaseTTL: v.Config.DefaultLeaseTTL,
+				MaxLeaseTTL:     v.Config.MaxLeaseTTL,
+			}
+
+			mountOut := MountOutput{
+				Type:        v.Type,
+				Description: v.Description,
+				Config:      mountCfgOut,
+			}
+
+			mounts[k] = mountOut
+		}
+	}
+
+	// auths
+	var auths map[string]AuthMountOutput
+	if (state.Auths != nil) && (len(state.Auths) > 0) {
+		auths = make(map[string]AuthMountOutput)
+		for k, v := range state.Auths {
+			cfgOut := AuthConfigOutput{
+				DefaultLeaseTTL: v.Config.DefaultLeaseTTL,
+				MaxLeaseTTL:     v.Config.MaxLeaseTTL,
+			}
+
+			authMountOut := AuthMountOutput{
+				Type:        v.Type,
+				Description: v.Description,
+				Config:      cfgOut,
+			}
+
+			auths[k] = authMountOut
+		}
+	}
+
+	// policies
+	var policies []string
+	if (state.Policies != nil) && (len(state.Policies) > 0) {
+		policies = state.Policies
+	}
+
+	return ConfigureResponse{
+		ConfigID:   state.ConfigID,
+		Mounts:     mounts,
+		Auths:      auths,
+		Policies:   policies,
+		Categories: state.Categories,
+		Err:        err,
+	}
+}
+
+// ConfigState converts a response to the result of Configure.
+func (r ConfigureResponse) ConfigState() service.ConfigState {
 	// mounts
 	var mounts map[string]service.MountOutput
-	if (response.(ConfigureResponse).Mounts != nil) && (len(response.(ConfigureResponse).Mounts) > 0) {
+	if (r.Mounts != nil) && (len(r.Mounts) > 0) {
 		mounts = make(map[string]service.MountOutput)
-		for k, v := range response.(ConfigureResponse).Mounts {
+		for k, v := range r.Mounts {
 			mountCfgOut := service.MountConfigOutput{
 				DefaultLeaseTTL: v.Config.DefaultLeaseTTL,
 				MaxLeaseTTL:     v.Config.MaxLeaseTTL,
@@ -254,9 +541,9 @@ func (e Endpoints) Configure(ctx context.Context, opts service.ConfigOptions) (s
 
 	// auths
 	var auths map[string]service.AuthMountOutput
-	if (response.(ConfigureResponse).Auths != nil) && (len(response.(ConfigureResponse).Auths) > 0) {
+	if (r.Auths != nil) && (len(r.Auths) > 0) {
 		auths = make(map[string]service.AuthMountOutput)
-		for k, v := range response.(ConfigureResponse).Auths {
+		for k, v := range r.Auths {
 			cfgOut := service.AuthConfigOutput{
 				DefaultLeaseTTL: v.Config.DefaultLeaseTTL,
 				MaxLeaseTTL:     v.Config.MaxLeaseTTL,
@@ -274,82 +561,221 @@ func (e Endpoints) Configure(ctx context.Context, opts service.ConfigOptions) (s
 
 	// policies
 	var policies []string
-	if (response.(ConfigureResponse).Policies != nil) && (len(response.(ConfigureResponse).Policies) > 0) {
-		policies = response.(ConfigureResponse).Policies
+	if (r.Policies != nil) && (len(r.Policies) > 0) {
+		policies = r.Policies
 	}
 
 	state := service.ConfigState{
-		ConfigID: response.(ConfigureResponse).ConfigID,
-		Mounts:   mounts,
-		Auths:    auths,
-		Policies: policies,
+		ConfigID:   r.ConfigID,
+		Mounts:     mounts,
+		Auths:      auths,
+		Policies:   policies,
+		Categories: r.Categories,
 	}
-	return state, response.(ConfigureResponse).Err
+	return state
 }
 
-// MakeConfigureEndpoint returns an endpoint that invokes Configure on the
-// service.  Primarily useful in a server.
-func MakeConfigureEndpoint(s service.Service) endpoint.Endpoint {
+// ListTokenHolders implements Service. Primarily useful in a client
+func (e Endpoints) ListTokenHolders(ctx context.Context, clusterID string) ([]service.TokenHolderOutput, error) {
+	request := ListTokenHoldersRequest{ClusterID: clusterID}
+	response, err := e.ListTokenHoldersEndpoint(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return response.(TokenHoldersResponse).TokenHolders, response.(TokenHoldersResponse).Err
+}
+
+// MakeListTokenHoldersEndpoint returns an endpoint that invokes
+// ListTokenHolders on the service.  Primarily useful in a server.
+func MakeListTokenHoldersEndpoint(s service.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
-		var req = *request.(*ConfigureRequest)
-		opts := service.ConfigOptions{
-			URL:   req.URL,
-			Token: req.Token,
-		}
+		var req = *request.(*ListTokenHoldersRequest)
+		holders, err := s.ListTokenHolders(ctx, req.ClusterID)
+		return TokenHoldersResponse{
+			TokenHolders: holders,
+			Err:          err,
+		}, nil
+	}
+}
 
-		state, err := s.Configure(ctx, opts)
+// GetTokenHolders implements Service. Primarily useful in a client
+func (e Endpoints) GetTokenHolders(ctx context.Context, email string) ([]service.TokenHolderOutput, error) {
+	request := GetTokenHoldersRequest{Email: email}
+	response, err := e.GetTokenHoldersEndpoint(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return response.(TokenHoldersResponse).TokenHolders, response.(TokenHoldersResponse).Err
+}
 
-		// mounts
-		var mounts map[string]MountOutput
-		if (state.Mounts != nil) && (len(state.Mounts) > 0) {
-			mounts = make(map[string]MountOutput)
-			for k, v := range state.Mounts {
-				mountCfgOut := MountConfigOutput{
-					DefaultLeaseTTL: v.Config.DefaultLeaseTTL,
-					MaxLeaseTTL:     v.Config.MaxLeaseTTL,
-				}
+// MakeGetTokenHoldersEndpoint returns an endpoint that invokes
+// GetTokenHolders on the service.  Primarily useful in a server.
+func MakeGetTokenHoldersEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req = *request.(*GetTokenHoldersRequest)
+		holders, err := s.GetTokenHolders(ctx, req.Email)
+		return TokenHoldersResponse{
+			TokenHolders: holders,
+			Err:          err,
+		}, nil
+	}
+}
 
-				mountOut := MountOutput{
-					Type:        v.Type,
-					Description: v.Description,
-					Config:      mountCfgOut,
-				}
+// ReassignTokenHolder implements Service. Primarily useful in a client
+func (e Endpoints) ReassignTokenHolder(ctx context.Context, opts service.ReassignOptions) (service.TokenHolderOutput, error) {
+	request := ReassignTokenHolderRequest{Opts: opts}
+	response, err := e.ReassignTokenHolderEndpoint(ctx, request)
+	if err != nil {
+		return service.TokenHolderOutput{}, err
+	}
+	return response.(TokenHolderResponse).TokenHolder, response.(TokenHolderResponse).Err
+}
 
-				mounts[k] = mountOut
-			}
-		}
+// MakeReassignTokenHolderEndpoint returns an endpoint that invokes
+// ReassignTokenHolder on the service.  Primarily useful in a server.
+func MakeReassignTokenHolderEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req = *request.(*ReassignTokenHolderRequest)
+		holder, err := s.ReassignTokenHolder(ctx, req.Opts)
+		return TokenHolderResponse{
+			TokenHolder: holder,
+			Err:         err,
+		}, nil
+	}
+}
 
-		// auths
-		var auths map[string]AuthMountOutput
-		if (state.Auths != nil) && (len(state.Auths) > 0) {
-			auths = make(map[string]AuthMountOutput)
-			for k, v := range state.Auths {
-				cfgOut := AuthConfigOutput{
-					DefaultLeaseTTL: v.Config.DefaultLeaseTTL,
-					MaxLeaseTTL:     v.Config.MaxLeaseTTL,
-				}
+// RevokeTokenHolder implements Service. Primarily useful in a client
+func (e Endpoints) RevokeTokenHolder(ctx context.Context, opts service.RevokeOptions) (service.TokenHolderOutput, error) {
+	request := RevokeTokenHolderRequest{Opts: opts}
+	response, err := e.RevokeTokenHolderEndpoint(ctx, request)
+	if err != nil {
+		return service.TokenHolderOutput{}, err
+	}
+	return response.(TokenHolderResponse).TokenHolder, response.(TokenHolderResponse).Err
+}
 
-				authMountOut := AuthMountOutput{
-					Type:        v.Type,
-					Description: v.Description,
-					Config:      cfgOut,
-				}
+// MakeRevokeTokenHolderEndpoint returns an endpoint that invokes
+// RevokeTokenHolder on the service.  Primarily useful in a server.
+func MakeRevokeTokenHolderEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req = *request.(*RevokeTokenHolderRequest)
+		holder, err := s.RevokeTokenHolder(ctx, req.Opts)
+		return TokenHolderResponse{
+			TokenHolder: holder,
+			Err:         err,
+		}, nil
+	}
+}
 
-				auths[k] = authMountOut
-			}
-		}
+// StartUnsealCeremony implements Service. Primarily useful in a client
+func (e Endpoints) StartUnsealCeremony(ctx context.Context, cluster string) (service.CeremonyOutput, error) {
+	request := StartUnsealCeremonyRequest{Cluster: cluster}
+	response, err := e.StartUnsealCeremonyEndpoint(ctx, request)
+	if err != nil {
+		return service.CeremonyOutput{}, err
+	}
+	return response.(CeremonyResponse).Ceremony, response.(CeremonyResponse).Err
+}
 
-		// policies
-		var policies []string
-		if (state.Policies != nil) && (len(state.Policies) > 0) {
-			policies = state.Policies
-		}
+// MakeStartUnsealCeremonyEndpoint returns an endpoint that invokes
+// StartUnsealCeremony on the service.  Primarily useful in a server.
+func MakeStartUnsealCeremonyEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req = *request.(*StartUnsealCeremonyRequest)
+		ceremony, err := s.StartUnsealCeremony(ctx, req.Cluster)
+		return CeremonyResponse{
+			Ceremony: ceremony,
+			Err:      err,
+		}, nil
+	}
+}
+
+// GetUnsealCeremony implements Service. Primarily useful in a client
+func (e Endpoints) GetUnsealCeremony(ctx context.Context, cluster, id string) (service.CeremonyOutput, error) {
+	request := GetUnsealCeremonyRequest{Cluster: cluster, ID: id}
+	response, err := e.GetUnsealCeremonyEndpoint(ctx, request)
+	if err != nil {
+		return service.CeremonyOutput{}, err
+	}
+	return response.(CeremonyResponse).Ceremony, response.(CeremonyResponse).Err
+}
+
+// MakeGetUnsealCeremonyEndpoint returns an endpoint that invokes
+// GetUnsealCeremony on the service.  Primarily useful in a server.
+func MakeGetUnsealCeremonyEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req = *request.(*GetUnsealCeremonyRequest)
+		ceremony, err := s.GetUnsealCeremony(ctx, req.Cluster, req.ID)
+		return CeremonyResponse{
+			Ceremony: ceremony,
+			Err:      err,
+		}, nil
+	}
+}
 
-		return ConfigureResponse{
-			ConfigID: state.ConfigID,
-			Mounts:   mounts,
-			Auths:    auths,
-			Policies: policies,
+// SubmitUnsealShare implements Service. Primarily useful in a client
+func (e Endpoints) SubmitUnsealShare(ctx context.Context, opts service.ShareOptions) (service.CeremonyOutput, error) {
+	request := SubmitUnsealShareRequest{Opts: opts}
+	response, err := e.SubmitUnsealShareEndpoint(ctx, request)
+	if err != nil {
+		return service.CeremonyOutput{}, err
+	}
+	return response.(CeremonyResponse).Ceremony, response.(CeremonyResponse).Err
+}
+
+// MakeSubmitUnsealShareEndpoint returns an endpoint that invokes
+// SubmitUnsealShare on the service.  Primarily useful in a server.
+func MakeSubmitUnsealShareEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req = *request.(*SubmitUnsealShareRequest)
+		ceremony, err := s.SubmitUnsealShare(ctx, req.Opts)
+		return CeremonyResponse{
+			Ceremony: ceremony,
+			Err:      err,
+		}, nil
+	}
+}
+
+// AbortUnsealCeremony implements Service. Primarily useful in a client
+func (e Endpoints) AbortUnsealCeremony(ctx context.Context, cluster, id string) (service.CeremonyOutput, error) {
+	request := AbortUnsealCeremonyRequest{Cluster: cluster, ID: id}
+	response, err := e.AbortUnsealCeremonyEndpoint(ctx, request)
+	if err != nil {
+		return service.CeremonyOutput{}, err
+	}
+	return response.(CeremonyResponse).Ceremony, response.(CeremonyResponse).Err
+}
+
+// MakeAbortUnsealCeremonyEndpoint returns an endpoint that invokes
+// AbortUnsealCeremony on the service.  Primarily useful in a server.
+func MakeAbortUnsealCeremonyEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req = *request.(*AbortUnsealCeremonyRequest)
+		ceremony, err := s.AbortUnsealCeremony(ctx, req.Cluster, req.ID)
+		return CeremonyResponse{
+			Ceremony: ceremony,
+			Err:      err,
+		}, nil
+	}
+}
+
+// ListClusters implements Service. Primarily useful in a client
+func (e Endpoints) ListClusters(ctx context.Context) ([]service.ClusterOutput, error) {
+	request := ListClustersRequest{}
+	response, err := e.ListClustersEndpoint(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return response.(ClustersResponse).Clusters, response.(ClustersResponse).Err
+}
+
+// MakeListClustersEndpoint returns an endpoint that invokes ListClusters on
+// the service.  Primarily useful in a server.
+func MakeListClustersEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		clusters, err := s.ListClusters(ctx)
+		return ClustersResponse{
+			Clusters: clusters,
 			Err:      err,
 		}, nil
 	}
@@ -365,7 +791,9 @@ type Failer interface {
 
 // InitStatusRequest collects the request parameters (if any) for the
 // InitStatus method.
-type InitStatusRequest struct{}
+type InitStatusRequest struct {
+	Cluster string
+}
 
 // InitStatusResponse collects the response values for the InitStatus method.
 type InitStatusResponse struct {
@@ -393,18 +821,40 @@ func (r InitResponse) Failed() error { return r.Err }
 
 // SealStatusRequest collects the request parameters (if any) for the
 // SealStatus method.
-type SealStatusRequest struct{}
+type SealStatusRequest struct {
+	Cluster string
+}
+
+// WatchSealStatusRequest collects the request parameters for the
+// WatchSealStatus method. Events receives the seal status as it changes.
+// Done is closed when the caller goes away, since the transports' servers
+// run endpoints in their own context.
+type WatchSealStatusRequest struct {
+	Cluster string
+	Events  service.SealEventFunc `json:"-"`
+	Done    <-chan struct{}       `json:"-"`
+}
+
+// WatchSealStatusResponse collects the response values for the
+// WatchSealStatus method, which only ends in error once watching began.
+type WatchSealStatusResponse struct {
+	Err error `json:"-"` // should be intercepted by Failed/errorEncoder
+}
+
+// Failed implements Failer.
+func (r WatchSealStatusResponse) Failed() error { return r.Err }
 
 // SealStatusResponse collects the response values for the SealStatus method.
 type SealStatusResponse struct {
-	Sealed      bool   `json:"sealed"`
-	T           int    `json:"t"`
-	N           int    `json:"n"`
-	Progress    int    `json:"progress"`
-	Version     string `json:"version"`
-	ClusterName string `json:"cluster_name,omitempty"`
-	ClusterID   string `json:"cluster_id,omitempty"`
-	Err         error  `json:"-"` // should be intercepted by Failed/errorEncoder
+	Sealed      bool                    `json:"sealed"`
+	T           int                     `json:"t"`
+	N           int                     `json:"n"`
+	Progress    int                     `json:"progress"`
+	Version     string                  `json:"version"`
+	ClusterName string                  `json:"cluster_name,omitempty"`
+	ClusterID   string                  `json:"cluster_id,omitempty"`
+	Nodes       []service.NodeSealState `json:"nodes,omitempty"`
+	Err         error                   `json:"-"` // should be intercepted by Failed/errorEncoder
 }
 
 // Failed implements Failer.
@@ -413,20 +863,23 @@ func (r SealStatusResponse) Failed() error { return r.Err }
 // UnsealRequest collects the request parameters (if any) for the
 // Unseal method.
 type UnsealRequest struct {
-	Key   string
-	Reset bool
+	Key      string
+	Reset    bool
+	Cluster  string
+	AllNodes bool
 }
 
 // UnsealResponse collects the response values for the Unseal method.
 type UnsealResponse struct {
-	Sealed      bool   `json:"sealed"`
-	T           int    `json:"t"`
-	N           int    `json:"n"`
-	Progress    int    `json:"progress"`
-	Version     string `json:"version"`
-	ClusterName string `json:"cluster_name,omitempty"`
-	ClusterID   string `json:"cluster_id,omitempty"`
-	Err         error  `json:"-"` // should be intercepted by Failed/errorEncoder
+	Sealed      bool                    `json:"sealed"`
+	T           int                     `json:"t"`
+	N           int                     `json:"n"`
+	Progress    int                     `json:"progress"`
+	Version     string                  `json:"version"`
+	ClusterName string                  `json:"cluster_name,omitempty"`
+	ClusterID   string                  `json:"cluster_id,omitempty"`
+	Nodes       []service.NodeSealState `json:"nodes,omitempty"`
+	Err         error                   `json:"-"` // should be intercepted by Failed/errorEncoder
 }
 
 // Failed implements Failer.
@@ -435,22 +888,127 @@ func (r UnsealResponse) Failed() error { return r.Err }
 // ConfigureRequest collects the request parameters (if any) for the Configure
 // method.
 type ConfigureRequest struct {
-	URL   string
-	Token string
+	URL     string
+	Token   string
+	Cluster string
+}
+
+// ConfigureStreamRequest collects the request parameters for the
+// ConfigureStream method. Events receives the progress of the run; its
+// response is a ConfigureResponse.
+type ConfigureStreamRequest struct {
+	URL     string
+	Token   string
+	Cluster string
+	Events  service.ConfigEventFunc `json:"-"`
 }
 
 // ConfigureResponse collects the response values for the Configure method.
 type ConfigureResponse struct {
-	ConfigID string                     `json:"config_id,omitempty"`
-	Mounts   map[string]MountOutput     `json:"mounts,omitempty"`
-	Auths    map[string]AuthMountOutput `json:"auths,omitempty"`
-	Policies []string                   `json:"policies,omitempty"`
-	Err      error                      `json:"-"` // should be intercepted by Failed/errorEncoder
+	ConfigID   string                     `json:"config_id,omitempty"`
+	Mounts     map[string]MountOutput     `json:"mounts,omitempty"`
+	Auths      map[string]AuthMountOutput `json:"auths,omitempty"`
+	Policies   []string                   `json:"policies,omitempty"`
+	Categories map[string]json.RawMessage `json:"categories,omitempty"`
+	Err        error                      `json:"-"` // should be intercepted by Failed/errorEncoder
 }
 
 // Failed implements Failer.
 func (r ConfigureResponse) Failed() error { return r.Err }
 
+// ListTokenHoldersRequest collects the request parameters (if any) for the
+// ListTokenHolders method.
+type ListTokenHoldersRequest struct {
+	ClusterID string
+}
+
+// GetTokenHoldersRequest collects the request parameters (if any) for the
+// GetTokenHolders method.
+type GetTokenHoldersRequest struct {
+	Email string
+}
+
+// TokenHoldersResponse collects the response values for the ListTokenHolders
+// and GetTokenHolders methods.
+type TokenHoldersResponse struct {
+	TokenHolders []service.TokenHolderOutput `json:"token_holders"`
+	Err          error                       `json:"-"` // should be intercepted by Failed/errorEncoder
+}
+
+// Failed implements Failer.
+func (r TokenHoldersResponse) Failed() error { return r.Err }
+
+// ReassignTokenHolderRequest collects the request parameters (if any) for the
+// ReassignTokenHolder method.
+type ReassignTokenHolderRequest struct {
+	Opts service.ReassignOptions
+}
+
+// RevokeTokenHolderRequest collects the request parameters (if any) for the
+// RevokeTokenHolder method.
+type RevokeTokenHolderRequest struct {
+	Opts service.RevokeOptions
+}
+
+// TokenHolderResponse collects the response values for the
+// ReassignTokenHolder and RevokeTokenHolder methods.
+type TokenHolderResponse struct {
+	TokenHolder service.TokenHolderOutput `json:"token_holder"`
+	Err         error                     `json:"-"` // should be intercepted by Failed/errorEncoder
+}
+
+// Failed implements Failer.
+func (r TokenHolderResponse) Failed() error { return r.Err }
+
+// StartUnsealCeremonyRequest collects the request parameters (if any) for
+// the StartUnsealCeremony method.
+type StartUnsealCeremonyRequest struct {
+	Cluster string
+}
+
+// GetUnsealCeremonyRequest collects the request parameters (if any) for the
+// GetUnsealCeremony method.
+type GetUnsealCeremonyRequest struct {
+	Cluster string
+	ID      string
+}
+
+// SubmitUnsealShareRequest collects the request parameters (if any) for the
+// SubmitUnsealShare method.
+type SubmitUnsealShareRequest struct {
+	Opts service.ShareOptions
+}
+
+// AbortUnsealCeremonyRequest collects the request parameters (if any) for
+// the AbortUnsealCeremony method.
+type AbortUnsealCeremonyRequest struct {
+	Cluster string
+	ID      string
+}
+
+// CeremonyResponse collects the response values for the unseal ceremony
+// methods.
+type CeremonyResponse struct {
+	Ceremony service.CeremonyOutput `json:"ceremony"`
+	Err      error                  `json:"-"` // should be intercepted by Failed/errorEncoder
+}
+
+// Failed implements Failer.
+func (r CeremonyResponse) Failed() error { return r.Err }
+
+// ListClustersRequest collects the request parameters (if any) for the
+// ListClusters method.
+type ListClustersRequest struct{}
+
+// ClustersResponse collects the response values for the ListClusters method.
+type ClustersResponse struct {
+	Clusters []service.ClusterOutput `json:"clusters"`
+	Err      error                   `json:"-"` // should be intercepted by Failed/errorEncoder
+}
+
+// Failed implements Failer.
+func (r ClustersResponse) Failed() error { return r.Err }
+
 // MountOutput maps directly to Vault's own MountOutput. Used by ConfigState to
 // describe the mounts currently defined in a Vault instance.
 type MountOutput struct {
@@ -6,6 +6,7 @@ import (
 
 	"golang.org/x/net/context"
 
+	"github.com/cdwlabs/armor/pkg/proxy/auth"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics"
@@ -42,3 +43,25 @@ func LoggingMiddleware(logger log.Logger) endpoint.Middleware {
 		}
 	}
 }
+
+// AuthMiddleware returns an endpoint middleware that authenticates the caller
+// by the credentials of the request context, and refuses the call unless the
+// authorizer's policy allows the caller to call method. Every call is allowed
+// if the authorizer is nil.
+func AuthMiddleware(authorizer *auth.Authorizer, method string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		if authorizer == nil {
+			return next
+		}
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			id, err := authorizer.Authenticate(auth.FromContext(ctx))
+			if err != nil {
+				return nil, err
+			}
+			if err := authorizer.Authorize(id, method); err != nil {
+				return nil, err
+			}
+			return next(auth.NewIdentityContext(ctx, id), request)
+		}
+	}
+}
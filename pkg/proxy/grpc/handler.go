@@ -4,7 +4,12 @@ package grpc
 // the transport/grpc.Server.
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/cdwlabs/armor/pb"
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/cdwlabs/armor/pkg/proxy/auth"
 	"github.com/cdwlabs/armor/pkg/proxy/endpoints"
 	"github.com/cdwlabs/armor/pkg/proxy/service"
 	"github.com/go-kit/kit/log"
@@ -12,14 +17,27 @@ import (
 	grpctransport "github.com/go-kit/kit/transport/grpc"
 	stdopentracing "github.com/opentracing/opentracing-go"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 type grpcServer struct {
-	initstatus grpctransport.Handler
-	init       grpctransport.Handler
-	sealstatus grpctransport.Handler
-	unseal     grpctransport.Handler
-	configure  grpctransport.Handler
+	initstatus          grpctransport.Handler
+	init                grpctransport.Handler
+	sealstatus          grpctransport.Handler
+	watchsealstatus     grpctransport.Handler
+	unseal              grpctransport.Handler
+	configure           grpctransport.Handler
+	configurestream     grpctransport.Handler
+	listtokenholders    grpctransport.Handler
+	gettokenholders     grpctransport.Handler
+	reassigntokenholder grpctransport.Handler
+	revoketokenholder   grpctransport.Handler
+	startunsealceremony grpctransport.Handler
+	getunsealceremony   grpctransport.Handler
+	submitunsealshare   grpctransport.Handler
+	abortunsealceremony grpctransport.Handler
+	listclusters        grpctransport.Handler
 }
 
 // NewHandler makes a set of endpoints available as a gRPC Server.
@@ -37,84 +55,277 @@ func NewHandler(ctx context.Context, endpoints endpoints.Endpoints, tracer stdop
 			endpoints.InitStatusEndpoint,
 			DecodeInitStatusRequest,
 			EncodeInitStatusResponse,
-			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "InitStatus", logger)))...,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "InitStatus", logger), auth.GRPCToContext()))...,
 		),
 		init: grpctransport.NewServer(
 			ctx,
 			endpoints.InitEndpoint,
 			DecodeInitRequest,
 			EncodeInitResponse,
-			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "Init", logger)))...,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "Init", logger), auth.GRPCToContext()))...,
 		),
 		sealstatus: grpctransport.NewServer(
 			ctx,
 			endpoints.SealStatusEndpoint,
 			DecodeSealStatusRequest,
 			EncodeSealStatusResponse,
-			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "SealStatus", logger)))...,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "SealStatus", logger), auth.GRPCToContext()))...,
+		),
+		watchsealstatus: grpctransport.NewServer(
+			ctx,
+			endpoints.WatchSealStatusEndpoint,
+			DecodeWatchSealStatusRequest,
+			EncodeWatchSealStatusResponse,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "WatchSealStatus", logger), auth.GRPCToContext()))...,
 		),
 		unseal: grpctransport.NewServer(
 			ctx,
 			endpoints.UnsealEndpoint,
 			DecodeUnsealRequest,
 			EncodeUnsealResponse,
-			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "Unseal", logger)))...,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "Unseal", logger), auth.GRPCToContext()))...,
 		),
 		configure: grpctransport.NewServer(
 			ctx,
 			endpoints.ConfigureEndpoint,
 			DecodeConfigureRequest,
 			EncodeConfigureResponse,
-			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "Configure", logger)))...,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "Configure", logger), auth.GRPCToContext()))...,
+		),
+		configurestream: grpctransport.NewServer(
+			ctx,
+			endpoints.ConfigureStreamEndpoint,
+			DecodeConfigureStreamRequest,
+			EncodeConfigureStreamResponse,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "ConfigureStream", logger), auth.GRPCToContext()))...,
+		),
+		listtokenholders: grpctransport.NewServer(
+			ctx,
+			endpoints.ListTokenHoldersEndpoint,
+			DecodeListTokenHoldersRequest,
+			EncodeTokenHoldersResponse,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "ListTokenHolders", logger), auth.GRPCToContext()))...,
+		),
+		gettokenholders: grpctransport.NewServer(
+			ctx,
+			endpoints.GetTokenHoldersEndpoint,
+			DecodeGetTokenHoldersRequest,
+			EncodeTokenHoldersResponse,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "GetTokenHolders", logger), auth.GRPCToContext()))...,
+		),
+		reassigntokenholder: grpctransport.NewServer(
+			ctx,
+			endpoints.ReassignTokenHolderEndpoint,
+			DecodeReassignTokenHolderRequest,
+			EncodeTokenHolderResponse,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "ReassignTokenHolder", logger), auth.GRPCToContext()))...,
+		),
+		revoketokenholder: grpctransport.NewServer(
+			ctx,
+			endpoints.RevokeTokenHolderEndpoint,
+			DecodeRevokeTokenHolderRequest,
+			EncodeTokenHolderResponse,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "RevokeTokenHolder", logger), auth.GRPCToContext()))...,
+		),
+		startunsealceremony: grpctransport.NewServer(
+			ctx,
+			endpoints.StartUnsealCeremonyEndpoint,
+			DecodeStartUnsealCeremonyRequest,
+			EncodeCeremonyResponse,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "StartUnsealCeremony", logger), auth.GRPCToContext()))...,
+		),
+		getunsealceremony: grpctransport.NewServer(
+			ctx,
+			endpoints.GetUnsealCeremonyEndpoint,
+			DecodeGetUnsealCeremonyRequest,
+			EncodeCeremonyResponse,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "GetUnsealCeremony", logger), auth.GRPCToContext()))...,
+		),
+		submitunsealshare: grpctransport.NewServer(
+			ctx,
+			endpoints.SubmitUnsealShareEndpoint,
+			DecodeSubmitUnsealShareRequest,
+			EncodeCeremonyResponse,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "SubmitUnsealShare", logger), auth.GRPCToContext()))...,
+		),
+		abortunsealceremony: grpctransport.NewServer(
+			ctx,
+			endpoints.AbortUnsealCeremonyEndpoint,
+			DecodeAbortUnsealCeremonyRequest,
+			EncodeCeremonyResponse,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "AbortUnsealCeremony", logger), auth.GRPCToContext()))...,
+		),
+		listclusters: grpctransport.NewServer(
+			ctx,
+			endpoints.ListClustersEndpoint,
+			DecodeListClustersRequest,
+			EncodeClustersResponse,
+			append(options, grpctransport.ServerBefore(opentracing.FromGRPCRequest(tracer, "ListClusters", logger), auth.GRPCToContext()))...,
 		),
 	}
 }
 
 func (s *grpcServer) InitStatus(ctx context.Context, req *pb.InitStatusRequest) (*pb.InitStatusResponse, error) {
-	_, rep, err := s.initstatus.ServeGRPC(ctx, req)
+	_, rep, err := s.initstatus.ServeGRPC(auth.PeerToMetadata(ctx), req)
 	if err != nil {
-		return nil, err
+		return nil, grpcError(err)
 	}
 	return rep.(*pb.InitStatusResponse), nil
 }
 
 func (s *grpcServer) Init(ctx context.Context, req *pb.InitRequest) (*pb.InitResponse, error) {
-	_, rep, err := s.init.ServeGRPC(ctx, req)
+	_, rep, err := s.init.ServeGRPC(auth.PeerToMetadata(ctx), req)
 	if err != nil {
-		return nil, err
+		return nil, grpcError(err)
 	}
 	return rep.(*pb.InitResponse), nil
 }
 
 func (s *grpcServer) SealStatus(ctx context.Context, req *pb.SealStatusRequest) (*pb.SealStatusResponse, error) {
-	_, rep, err := s.sealstatus.ServeGRPC(ctx, req)
+	_, rep, err := s.sealstatus.ServeGRPC(auth.PeerToMetadata(ctx), req)
 	if err != nil {
-		return nil, err
+		return nil, grpcError(err)
 	}
 	return rep.(*pb.SealStatusResponse), nil
 }
 
+// watchSealStatus is the request of the WatchSealStatus handler: the gRPC
+// request, and the stream its events are sent on.
+type watchSealStatus struct {
+	req    *pb.SealStatusRequest
+	stream pb.Vault_WatchSealStatusServer
+}
+
+func (s *grpcServer) WatchSealStatus(req *pb.SealStatusRequest, stream pb.Vault_WatchSealStatusServer) error {
+	_, _, err := s.watchsealstatus.ServeGRPC(auth.PeerToMetadata(stream.Context()), &watchSealStatus{req: req, stream: stream})
+	return grpcError(err)
+}
+
 func (s *grpcServer) Unseal(ctx context.Context, req *pb.UnsealRequest) (*pb.UnsealResponse, error) {
-	_, rep, err := s.unseal.ServeGRPC(ctx, req)
+	_, rep, err := s.unseal.ServeGRPC(auth.PeerToMetadata(ctx), req)
 	if err != nil {
-		return nil, err
+		return nil, grpcError(err)
 	}
 	return rep.(*pb.UnsealResponse), nil
 }
 
 func (s *grpcServer) Configure(ctx context.Context, req *pb.ConfigureRequest) (*pb.ConfigureResponse, error) {
-	_, rep, err := s.configure.ServeGRPC(ctx, req)
+	_, rep, err := s.configure.ServeGRPC(auth.PeerToMetadata(ctx), req)
 	if err != nil {
-		return nil, err
+		return nil, grpcError(err)
 	}
 	return rep.(*pb.ConfigureResponse), nil
 }
 
+// configureStream is the request of the ConfigureStream handler: the gRPC
+// request, and the stream its events are sent on.
+type configureStream struct {
+	req    *pb.ConfigureRequest
+	stream pb.Vault_ConfigureStreamServer
+	err    error // the first error sending an event
+}
+
+func (s *grpcServer) ConfigureStream(req *pb.ConfigureRequest, stream pb.Vault_ConfigureStreamServer) error {
+	cs := &configureStream{req: req, stream: stream}
+	_, _, err := s.configurestream.ServeGRPC(auth.PeerToMetadata(stream.Context()), cs)
+	if err != nil {
+		return grpcError(err)
+	}
+	return cs.err
+}
+
+func (s *grpcServer) ListTokenHolders(ctx context.Context, req *pb.ListTokenHoldersRequest) (*pb.TokenHoldersResponse, error) {
+	_, rep, err := s.listtokenholders.ServeGRPC(auth.PeerToMetadata(ctx), req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return rep.(*pb.TokenHoldersResponse), nil
+}
+
+func (s *grpcServer) GetTokenHolders(ctx context.Context, req *pb.GetTokenHoldersRequest) (*pb.TokenHoldersResponse, error) {
+	_, rep, err := s.gettokenholders.ServeGRPC(auth.PeerToMetadata(ctx), req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return rep.(*pb.TokenHoldersResponse), nil
+}
+
+func (s *grpcServer) ReassignTokenHolder(ctx context.Context, req *pb.ReassignTokenHolderRequest) (*pb.TokenHolderResponse, error) {
+	_, rep, err := s.reassigntokenholder.ServeGRPC(auth.PeerToMetadata(ctx), req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return rep.(*pb.TokenHolderResponse), nil
+}
+
+func (s *grpcServer) RevokeTokenHolder(ctx context.Context, req *pb.RevokeTokenHolderRequest) (*pb.TokenHolderResponse, error) {
+	_, rep, err := s.revoketokenholder.ServeGRPC(auth.PeerToMetadata(ctx), req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return rep.(*pb.TokenHolderResponse), nil
+}
+
+func (s *grpcServer) StartUnsealCeremony(ctx context.Context, req *pb.StartUnsealCeremonyRequest) (*pb.CeremonyResponse, error) {
+	_, rep, err := s.startunsealceremony.ServeGRPC(auth.PeerToMetadata(ctx), req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return rep.(*pb.CeremonyResponse), nil
+}
+
+func (s *grpcServer) GetUnsealCeremony(ctx context.Context, req *pb.GetUnsealCeremonyRequest) (*pb.CeremonyResponse, error) {
+	_, rep, err := s.getunsealceremony.ServeGRPC(auth.PeerToMetadata(ctx), req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return rep.(*pb.CeremonyResponse), nil
+}
+
+func (s *grpcServer) SubmitUnsealShare(ctx context.Context, req *pb.SubmitUnsealShareRequest) (*pb.CeremonyResponse, error) {
+	_, rep, err := s.submitunsealshare.ServeGRPC(auth.PeerToMetadata(ctx), req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return rep.(*pb.CeremonyResponse), nil
+}
+
+func (s *grpcServer) AbortUnsealCeremony(ctx context.Context, req *pb.AbortUnsealCeremonyRequest) (*pb.CeremonyResponse, error) {
+	_, rep, err := s.abortunsealceremony.ServeGRPC(auth.PeerToMetadata(ctx), req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return rep.(*pb.CeremonyResponse), nil
+}
+
+func (s *grpcServer) ListClusters(ctx context.Context, req *pb.ListClustersRequest) (*pb.ClustersResponse, error) {
+	_, rep, err := s.listclusters.ServeGRPC(auth.PeerToMetadata(ctx), req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return rep.(*pb.ClustersResponse), nil
+}
+
+// grpcError converts the errors of authorization into gRPC status errors, so
+// callers can tell them from the errors of the service.
+func grpcError(err error) error {
+	switch err {
+	case auth.ErrUnauthenticated:
+		return grpc.Errorf(codes.Unauthenticated, "%v", err)
+	case auth.ErrPermissionDenied:
+		return grpc.Errorf(codes.PermissionDenied, "%v", err)
+	case service.ErrSealWatcherClosed:
+		return grpc.Errorf(codes.Unavailable, "%v", err)
+	}
+	return err
+}
+
 // DecodeInitStatusRequest is a transport/grpc.DecodeRequestFunc that
 // converts a gRPC initstatus request to a user-domain initstatus request. Primarily useful
 // in a server.
 func DecodeInitStatusRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
-	return endpoints.InitStatusRequest{}, nil
+	req := grpcReq.(*pb.InitStatusRequest)
+	return &endpoints.InitStatusRequest{Cluster: req.Cluster}, nil
 }
 
 // DecodeInitStatusResponse is a transport/grpc.DecodeResponseFunc that
@@ -138,7 +349,8 @@ func EncodeInitStatusResponse(_ context.Context, response interface{}) (interfac
 // converts a user-domain initstatus request to a gRPC initstatus request. Primarily useful
 // in a client.
 func EncodeInitStatusRequest(_ context.Context, request interface{}) (interface{}, error) {
-	return &pb.InitStatusRequest{}, nil
+	req := request.(endpoints.InitStatusRequest)
+	return &pb.InitStatusRequest{Cluster: req.Cluster}, nil
 }
 
 // DecodeInitRequest is a transport/grpc.DecodeRequestFunc that
@@ -147,16 +359,18 @@ func EncodeInitStatusRequest(_ context.Context, request interface{}) (interface{
 func DecodeInitRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*pb.InitRequest)
 	opts := service.InitOptions{
-		SecretShares:          int(req.SecretShares),
-		SecretThreshold:       int(req.SecretThreshold),
-		StoredShares:          int(req.StoredShares),
-		PGPKeys:               req.PgpKeys,
-		RecoveryShares:        int(req.RecoveryShares),
-		RecoveryThreshold:     int(req.RecoveryThreshold),
-		RecoveryPGPKeys:       req.RecoveryPgpKeys,
-		RootTokenPGPKey:       req.RootTokenPgpKey,
-		RootTokenHolderEmail:  req.RootTokenHolderEmail,
-		SecretKeyHolderEmails: req.SecretKeyHolderEmails,
+		SecretShares:            int(req.SecretShares),
+		SecretThreshold:         int(req.SecretThreshold),
+		StoredShares:            int(req.StoredShares),
+		PGPKeys:                 req.PgpKeys,
+		RecoveryShares:          int(req.RecoveryShares),
+		RecoveryThreshold:       int(req.RecoveryThreshold),
+		RecoveryPGPKeys:         req.RecoveryPgpKeys,
+		RootTokenPGPKey:         req.RootTokenPgpKey,
+		RootTokenHolderEmail:    req.RootTokenHolderEmail,
+		SecretKeyHolderEmails:   req.SecretKeyHolderEmails,
+		RecoveryKeyHolderEmails: req.RecoveryKeyHolderEmails,
+		Cluster:                 req.Cluster,
 	}
 	return &endpoints.InitRequest{Opts: opts}, nil
 }
@@ -173,7 +387,7 @@ func DecodeInitResponse(_ context.Context, grpcReply interface{}) (interface{},
 		RecoveryKeysB64: reply.RecoveryKeysBase64,
 		RootToken:       reply.RootToken,
 	}
-	return endpoints.InitResponse{Init: init, Err: service.String2Error(reply.Err)}, nil
+	return endpoints.InitResponse{Init: init, Err: decodeError(reply.Err, reply.Errors)}, nil
 }
 
 // EncodeInitResponse is a transport/grpc.EncodeResponseFunc that
@@ -189,6 +403,7 @@ func EncodeInitResponse(_ context.Context, response interface{}) (interface{}, e
 		RecoveryKeysBase64: resp.Init.RecoveryKeysB64,
 		RootToken:          resp.Init.RootToken,
 		Err:                service.Error2String(resp.Err),
+		Errors:             encodeValidationErrors(resp.Err),
 	}, nil
 }
 
@@ -198,16 +413,18 @@ func EncodeInitResponse(_ context.Context, response interface{}) (interface{}, e
 func EncodeInitRequest(_ context.Context, request interface{}) (interface{}, error) {
 	req := request.(endpoints.InitRequest)
 	return &pb.InitRequest{
-		SecretShares:          uint32(req.Opts.SecretShares),
-		SecretThreshold:       uint32(req.Opts.SecretThreshold),
-		StoredShares:          uint32(req.Opts.StoredShares),
-		PgpKeys:               req.Opts.PGPKeys,
-		RecoveryShares:        uint32(req.Opts.RecoveryShares),
-		RecoveryThreshold:     uint32(req.Opts.RecoveryThreshold),
-		RecoveryPgpKeys:       req.Opts.RecoveryPGPKeys,
-		RootTokenPgpKey:       req.Opts.RootTokenPGPKey,
-		RootTokenHolderEmail:  req.Opts.RootTokenHolderEmail,
-		SecretKeyHolderEmails: req.Opts.SecretKeyHolderEmails,
+		SecretShares:            uint32(req.Opts.SecretShares),
+		SecretThreshold:         uint32(req.Opts.SecretThreshold),
+		StoredShares:            uint32(req.Opts.StoredShares),
+		PgpKeys:                 req.Opts.PGPKeys,
+		RecoveryShares:          uint32(req.Opts.RecoveryShares),
+		RecoveryThreshold:       uint32(req.Opts.RecoveryThreshold),
+		RecoveryPgpKeys:         req.Opts.RecoveryPGPKeys,
+		RootTokenPgpKey:         req.Opts.RootTokenPGPKey,
+		RootTokenHolderEmail:    req.Opts.RootTokenHolderEmail,
+		SecretKeyHolderEmails:   req.Opts.SecretKeyHolderEmails,
+		RecoveryKeyHolderEmails: req.Opts.RecoveryKeyHolderEmails,
+		Cluster:                 req.Opts.Cluster,
 	}, nil
 }
 
@@ -215,7 +432,8 @@ func EncodeInitRequest(_ context.Context, request interface{}) (interface{}, err
 // converts a gRPC sealstatus request to a user-domain sealstatus request. Primarily useful
 // in a server.
 func DecodeSealStatusRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
-	return endpoints.SealStatusRequest{}, nil
+	req := grpcReq.(*pb.SealStatusRequest)
+	return &endpoints.SealStatusRequest{Cluster: req.Cluster}, nil
 }
 
 // DecodeSealStatusResponse is a transport/grpc.DecodeResponseFunc that
@@ -231,6 +449,7 @@ func DecodeSealStatusResponse(_ context.Context, grpcReply interface{}) (interfa
 		Version:     reply.SealStatus.Version,
 		ClusterName: reply.SealStatus.ClusterName,
 		ClusterID:   reply.SealStatus.ClusterId,
+		Nodes:       decodeNodeSealStates(reply.SealStatus.Nodes),
 		Err:         service.String2Error(reply.Err),
 	}
 
@@ -251,6 +470,7 @@ func EncodeSealStatusResponse(_ context.Context, response interface{}) (interfac
 		Version:     resp.Version,
 		ClusterName: resp.ClusterName,
 		ClusterId:   resp.ClusterID,
+		Nodes:       encodeNodeSealStates(resp.Nodes),
 	}
 	return &pb.SealStatusResponse{
 		SealStatus: status,
@@ -262,7 +482,80 @@ func EncodeSealStatusResponse(_ context.Context, response interface{}) (interfac
 // converts a user-domain sealstatus request to a gRPC sealstatus request. Primarily useful
 // in a client.
 func EncodeSealStatusRequest(_ context.Context, request interface{}) (interface{}, error) {
-	return &pb.SealStatusRequest{}, nil
+	req := request.(endpoints.SealStatusRequest)
+	return &pb.SealStatusRequest{Cluster: req.Cluster}, nil
+}
+
+// DecodeWatchSealStatusRequest is a transport/grpc.DecodeRequestFunc that
+// converts a gRPC sealstatus request to a user-domain watchsealstatus
+// request, whose events are sent on the request's stream until its client
+// goes away. Primarily useful in a server.
+func DecodeWatchSealStatusRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	w := grpcReq.(*watchSealStatus)
+	ctx, cancel := context.WithCancel(w.stream.Context())
+	return &endpoints.WatchSealStatusRequest{
+		Cluster: w.req.Cluster,
+		Events: func(e service.SealEvent) {
+			if err := w.stream.Send(EncodeSealEvent(e)); err != nil {
+				cancel()
+			}
+		},
+		Done: ctx.Done(),
+	}, nil
+}
+
+// EncodeWatchSealStatusResponse is a transport/grpc.EncodeResponseFunc for
+// watchsealstatus responses. The watch ends without a reply, or with its
+// error. Primarily useful in a server.
+func EncodeWatchSealStatusResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(endpoints.WatchSealStatusResponse)
+	return nil, resp.Err
+}
+
+// EncodeSealEvent converts a user-domain seal event to a gRPC seal status
+// event.
+func EncodeSealEvent(e service.SealEvent) *pb.SealStatusEvent {
+	resp, _ := EncodeSealStatusResponse(nil, endpoints.SealStatusResponse{
+		Sealed:      e.State.Sealed,
+		T:           e.State.T,
+		N:           e.State.N,
+		Progress:    e.State.Progress,
+		Version:     e.State.Version,
+		ClusterName: e.State.ClusterName,
+		ClusterID:   e.State.ClusterID,
+		Nodes:       e.State.Nodes,
+	})
+	return &pb.SealStatusEvent{
+		SealStatus: resp.(*pb.SealStatusResponse).SealStatus,
+		Leader:     e.Leader,
+		Heartbeat:  e.Heartbeat,
+		Time:       e.Time.Format(time.RFC3339Nano),
+	}
+}
+
+// DecodeSealEvent converts a gRPC seal status event to a user-domain seal
+// event.
+func DecodeSealEvent(event *pb.SealStatusEvent) service.SealEvent {
+	e := service.SealEvent{
+		Leader:    event.Leader,
+		Heartbeat: event.Heartbeat,
+	}
+	e.Time, _ = time.Parse(time.RFC3339Nano, event.Time)
+	if event.SealStatus != nil {
+		resp, _ := DecodeSealStatusResponse(nil, &pb.SealStatusResponse{SealStatus: event.SealStatus})
+		status := resp.(endpoints.SealStatusResponse)
+		e.State = service.SealState{
+			Sealed:      status.Sealed,
+			T:           status.T,
+			N:           status.N,
+			Progress:    status.Progress,
+			Version:     status.Version,
+			ClusterName: status.ClusterName,
+			ClusterID:   status.ClusterID,
+			Nodes:       status.Nodes,
+		}
+	}
+	return e
 }
 
 // DecodeUnsealRequest is a transport/grpc.DecodeRequestFunc that
@@ -270,7 +563,7 @@ func EncodeSealStatusRequest(_ context.Context, request interface{}) (interface{
 // in a server.
 func DecodeUnsealRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*pb.UnsealRequest)
-	return &endpoints.UnsealRequest{Key: req.Key, Reset: req.Reset_}, nil
+	return &endpoints.UnsealRequest{Key: req.Key, Reset: req.Reset_, Cluster: req.Cluster, AllNodes: req.AllNodes}, nil
 }
 
 // DecodeUnsealResponse is a transport/grpc.DecodeResponseFunc that
@@ -286,6 +579,7 @@ func DecodeUnsealResponse(_ context.Context, grpcReply interface{}) (interface{}
 		Version:     reply.SealStatus.Version,
 		ClusterName: reply.SealStatus.ClusterName,
 		ClusterID:   reply.SealStatus.ClusterId,
+		Nodes:       decodeNodeSealStates(reply.SealStatus.Nodes),
 		Err:         service.String2Error(reply.Err),
 	}
 
@@ -306,6 +600,7 @@ func EncodeUnsealResponse(_ context.Context, response interface{}) (interface{},
 		Version:     resp.Version,
 		ClusterName: resp.ClusterName,
 		ClusterId:   resp.ClusterID,
+		Nodes:       encodeNodeSealStates(resp.Nodes),
 	}
 	return &pb.UnsealResponse{
 		SealStatus: status,
@@ -319,8 +614,10 @@ func EncodeUnsealResponse(_ context.Context, response interface{}) (interface{},
 func EncodeUnsealRequest(_ context.Context, request interface{}) (interface{}, error) {
 	req := request.(endpoints.UnsealRequest)
 	return &pb.UnsealRequest{
-		Key:    req.Key,
-		Reset_: req.Reset,
+		Key:      req.Key,
+		Reset_:   req.Reset,
+		Cluster:  req.Cluster,
+		AllNodes: req.AllNodes,
 	}, nil
 }
 
@@ -329,7 +626,7 @@ func EncodeUnsealRequest(_ context.Context, request interface{}) (interface{}, e
 // in a server.
 func DecodeConfigureRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*pb.ConfigureRequest)
-	return &endpoints.ConfigureRequest{URL: req.Url, Token: req.Token}, nil
+	return &endpoints.ConfigureRequest{URL: req.Url, Token: req.Token, Cluster: req.Cluster}, nil
 }
 
 // DecodeConfigureResponse is a transport/grpc.DecodeResponseFunc that
@@ -389,12 +686,22 @@ func DecodeConfigureResponse(_ context.Context, grpcReply interface{}) (interfac
 		policies = reply.ConfigStatus.Policies
 	}
 
+	// categories
+	var categories map[string]json.RawMessage
+	if (reply.ConfigStatus.Categories != nil) && (len(reply.ConfigStatus.Categories) > 0) {
+		categories = make(map[string]json.RawMessage)
+		for k, v := range reply.ConfigStatus.Categories {
+			categories[k] = json.RawMessage(v)
+		}
+	}
+
 	status := endpoints.ConfigureResponse{
-		ConfigID: reply.ConfigStatus.ConfigId,
-		Mounts:   mounts,
-		Auths:    auths,
-		Policies: policies,
-		Err:      service.String2Error(reply.Err),
+		ConfigID:   reply.ConfigStatus.ConfigId,
+		Mounts:     mounts,
+		Auths:      auths,
+		Policies:   policies,
+		Categories: categories,
+		Err:        decodeError(reply.Err, reply.Errors),
 	}
 
 	return status, nil
@@ -452,25 +759,563 @@ func EncodeConfigureResponse(_ context.Context, response interface{}) (interface
 		policies = resp.Policies
 	}
 
+	// categories
+	var categories map[string][]byte
+	if (resp.Categories != nil) && (len(resp.Categories) > 0) {
+		categories = make(map[string][]byte)
+		for k, v := range resp.Categories {
+			categories[k] = []byte(v)
+		}
+	}
+
 	status := &pb.ConfigStatus{
-		ConfigId: resp.ConfigID,
-		Mounts:   mounts,
-		Auths:    auths,
-		Policies: policies,
+		ConfigId:   resp.ConfigID,
+		Mounts:     mounts,
+		Auths:      auths,
+		Policies:   policies,
+		Categories: categories,
 	}
 	return &pb.ConfigureResponse{
 		ConfigStatus: status,
 		Err:          service.Error2String(resp.Err),
+		Errors:       encodeValidationErrors(resp.Err),
+	}, nil
+}
+
+// DecodeConfigureStreamRequest is a transport/grpc.DecodeRequestFunc that
+// converts a gRPC configure request to a user-domain configurestream request,
+// whose events are sent on the request's stream. Primarily useful in a server.
+func DecodeConfigureStreamRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	cs := grpcReq.(*configureStream)
+	return &endpoints.ConfigureStreamRequest{
+		URL:     cs.req.Url,
+		Token:   cs.req.Token,
+		Cluster: cs.req.Cluster,
+		Events: func(e service.ConfigEvent) {
+			if cs.err == nil {
+				cs.err = cs.stream.Send(EncodeConfigEvent(e))
+			}
+		},
 	}, nil
 }
 
+// EncodeConfigureStreamResponse is a transport/grpc.EncodeResponseFunc for
+// configurestream responses. The result was already sent as the done event,
+// so there is nothing left to encode. Primarily useful in a server.
+func EncodeConfigureStreamResponse(_ context.Context, response interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+// EncodeConfigEvent converts a user-domain configure event to a gRPC
+// configure event.
+func EncodeConfigEvent(e service.ConfigEvent) *pb.ConfigureEvent {
+	event := &pb.ConfigureEvent{
+		ConfigId: e.ConfigID,
+		Phase:    string(e.Phase),
+		Category: e.Category,
+		Action:   e.Action,
+		Paths:    e.Paths,
+		Failed:   e.Failed,
+		Err:      e.Err,
+		Errors:   encodeValidationErrors(e.Errors),
+	}
+	if e.State != nil {
+		resp, _ := EncodeConfigureResponse(nil, endpoints.NewConfigureResponse(*e.State, nil))
+		event.ConfigStatus = resp.(*pb.ConfigureResponse).ConfigStatus
+	}
+	return event
+}
+
+// DecodeConfigEvent converts a gRPC configure event to a user-domain
+// configure event.
+func DecodeConfigEvent(event *pb.ConfigureEvent) service.ConfigEvent {
+	e := service.ConfigEvent{
+		ConfigID: event.ConfigId,
+		Phase:    service.ConfigPhase(event.Phase),
+		Category: event.Category,
+		Action:   event.Action,
+		Paths:    event.Paths,
+		Failed:   event.Failed,
+		Err:      event.Err,
+	}
+	if errs, ok := decodeError(event.Err, event.Errors).(config.ValidationErrors); ok {
+		e.Errors = errs
+	}
+	if event.ConfigStatus != nil {
+		resp, _ := DecodeConfigureResponse(nil, &pb.ConfigureResponse{ConfigStatus: event.ConfigStatus})
+		state := resp.(endpoints.ConfigureResponse).ConfigState()
+		e.State = &state
+	}
+	return e
+}
+
 // EncodeConfigureRequest is a transport/grpc.EncodeRequestFunc that
 // converts a user-domain configure request to a gRPC configure request. Primarily useful
 // in a client.
 func EncodeConfigureRequest(_ context.Context, request interface{}) (interface{}, error) {
 	req := request.(endpoints.ConfigureRequest)
 	return &pb.ConfigureRequest{
-		Url:   req.URL,
-		Token: req.Token,
+		Url:     req.URL,
+		Token:   req.Token,
+		Cluster: req.Cluster,
 	}, nil
 }
+
+// DecodeListTokenHoldersRequest is a transport/grpc.DecodeRequestFunc that
+// converts a gRPC listtokenholders request to a user-domain listtokenholders
+// request. Primarily useful in a server.
+func DecodeListTokenHoldersRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.ListTokenHoldersRequest)
+	return &endpoints.ListTokenHoldersRequest{ClusterID: req.ClusterId}, nil
+}
+
+// EncodeListTokenHoldersRequest is a transport/grpc.EncodeRequestFunc that
+// converts a user-domain listtokenholders request to a gRPC listtokenholders
+// request. Primarily useful in a client.
+func EncodeListTokenHoldersRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(endpoints.ListTokenHoldersRequest)
+	return &pb.ListTokenHoldersRequest{ClusterId: req.ClusterID}, nil
+}
+
+// DecodeGetTokenHoldersRequest is a transport/grpc.DecodeRequestFunc that
+// converts a gRPC gettokenholders request to a user-domain gettokenholders
+// request. Primarily useful in a server.
+func DecodeGetTokenHoldersRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetTokenHoldersRequest)
+	return &endpoints.GetTokenHoldersRequest{Email: req.Email}, nil
+}
+
+// EncodeGetTokenHoldersRequest is a transport/grpc.EncodeRequestFunc that
+// converts a user-domain gettokenholders request to a gRPC gettokenholders
+// request. Primarily useful in a client.
+func EncodeGetTokenHoldersRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(endpoints.GetTokenHoldersRequest)
+	return &pb.GetTokenHoldersRequest{Email: req.Email}, nil
+}
+
+// DecodeTokenHoldersResponse is a transport/grpc.DecodeResponseFunc that
+// converts a gRPC tokenholders reply to a user-domain tokenholders response.
+// Primarily useful in a client.
+func DecodeTokenHoldersResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	reply := grpcReply.(*pb.TokenHoldersResponse)
+	holders := make([]service.TokenHolderOutput, 0, len(reply.TokenHolders))
+	for _, v := range reply.TokenHolders {
+		holders = append(holders, decodeTokenHolder(v))
+	}
+	return endpoints.TokenHoldersResponse{TokenHolders: holders, Err: decodeError(reply.Err, reply.Errors)}, nil
+}
+
+// EncodeTokenHoldersResponse is a transport/grpc.EncodeResponseFunc that
+// converts a user-domain tokenholders response to a gRPC tokenholders reply.
+// Primarily useful in a server.
+func EncodeTokenHoldersResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(endpoints.TokenHoldersResponse)
+	holders := make([]*pb.TokenHolder, 0, len(resp.TokenHolders))
+	for _, v := range resp.TokenHolders {
+		holders = append(holders, encodeTokenHolder(v))
+	}
+	return &pb.TokenHoldersResponse{
+		TokenHolders: holders,
+		Err:          service.Error2String(resp.Err),
+		Errors:       encodeValidationErrors(resp.Err),
+	}, nil
+}
+
+// DecodeReassignTokenHolderRequest is a transport/grpc.DecodeRequestFunc that
+// converts a gRPC reassigntokenholder request to a user-domain
+// reassigntokenholder request. Primarily useful in a server.
+func DecodeReassignTokenHolderRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.ReassignTokenHolderRequest)
+	key := req.GetKey()
+	if key == nil {
+		key = &pb.TokenHolderKey{}
+	}
+	opts := service.ReassignOptions{
+		ClusterID:  key.ClusterId,
+		TokenType:  key.TokenType,
+		ShareIndex: int(key.ShareIndex),
+		Email:      req.Email,
+	}
+	return &endpoints.ReassignTokenHolderRequest{Opts: opts}, nil
+}
+
+// EncodeReassignTokenHolderRequest is a transport/grpc.EncodeRequestFunc that
+// converts a user-domain reassigntokenholder request to a gRPC
+// reassigntokenholder request. Primarily useful in a client.
+func EncodeReassignTokenHolderRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(endpoints.ReassignTokenHolderRequest)
+	return &pb.ReassignTokenHolderRequest{
+		Key: &pb.TokenHolderKey{
+			ClusterId:  req.Opts.ClusterID,
+			TokenType:  req.Opts.TokenType,
+			ShareIndex: uint32(req.Opts.ShareIndex),
+		},
+		Email: req.Opts.Email,
+	}, nil
+}
+
+// DecodeRevokeTokenHolderRequest is a transport/grpc.DecodeRequestFunc that
+// converts a gRPC revoketokenholder request to a user-domain
+// revoketokenholder request. Primarily useful in a server.
+func DecodeRevokeTokenHolderRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.RevokeTokenHolderRequest)
+	key := req.GetKey()
+	if key == nil {
+		key = &pb.TokenHolderKey{}
+	}
+	opts := service.RevokeOptions{
+		ClusterID:  key.ClusterId,
+		TokenType:  key.TokenType,
+		ShareIndex: int(key.ShareIndex),
+	}
+	return &endpoints.RevokeTokenHolderRequest{Opts: opts}, nil
+}
+
+// EncodeRevokeTokenHolderRequest is a transport/grpc.EncodeRequestFunc that
+// converts a user-domain revoketokenholder request to a gRPC
+// revoketokenholder request. Primarily useful in a client.
+func EncodeRevokeTokenHolderRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(endpoints.RevokeTokenHolderRequest)
+	return &pb.RevokeTokenHolderRequest{
+		Key: &pb.TokenHolderKey{
+			ClusterId:  req.Opts.ClusterID,
+			TokenType:  req.Opts.TokenType,
+			ShareIndex: uint32(req.Opts.ShareIndex),
+		},
+	}, nil
+}
+
+// DecodeTokenHolderResponse is a transport/grpc.DecodeResponseFunc that
+// converts a gRPC tokenholder reply to a user-domain tokenholder response.
+// Primarily useful in a client.
+func DecodeTokenHolderResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	reply := grpcReply.(*pb.TokenHolderResponse)
+	var holder service.TokenHolderOutput
+	if reply.TokenHolder != nil {
+		holder = decodeTokenHolder(reply.TokenHolder)
+	}
+	return endpoints.TokenHolderResponse{TokenHolder: holder, Err: decodeError(reply.Err, reply.Errors)}, nil
+}
+
+// EncodeTokenHolderResponse is a transport/grpc.EncodeResponseFunc that
+// converts a user-domain tokenholder response to a gRPC tokenholder reply.
+// Primarily useful in a server.
+func EncodeTokenHolderResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(endpoints.TokenHolderResponse)
+	return &pb.TokenHolderResponse{
+		TokenHolder: encodeTokenHolder(resp.TokenHolder),
+		Err:         service.Error2String(resp.Err),
+		Errors:      encodeValidationErrors(resp.Err),
+	}, nil
+}
+
+// DecodeStartUnsealCeremonyRequest is a transport/grpc.DecodeRequestFunc
+// that converts a gRPC startunsealceremony request to a user-domain
+// startunsealceremony request. Primarily useful in a server.
+func DecodeStartUnsealCeremonyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.StartUnsealCeremonyRequest)
+	return &endpoints.StartUnsealCeremonyRequest{Cluster: req.Cluster}, nil
+}
+
+// EncodeStartUnsealCeremonyRequest is a transport/grpc.EncodeRequestFunc
+// that converts a user-domain startunsealceremony request to a gRPC
+// startunsealceremony request. Primarily useful in a client.
+func EncodeStartUnsealCeremonyRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(endpoints.StartUnsealCeremonyRequest)
+	return &pb.StartUnsealCeremonyRequest{Cluster: req.Cluster}, nil
+}
+
+// DecodeGetUnsealCeremonyRequest is a transport/grpc.DecodeRequestFunc that
+// converts a gRPC getunsealceremony request to a user-domain
+// getunsealceremony request. Primarily useful in a server.
+func DecodeGetUnsealCeremonyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetUnsealCeremonyRequest)
+	return &endpoints.GetUnsealCeremonyRequest{Cluster: req.Cluster, ID: req.Id}, nil
+}
+
+// EncodeGetUnsealCeremonyRequest is a transport/grpc.EncodeRequestFunc that
+// converts a user-domain getunsealceremony request to a gRPC
+// getunsealceremony request. Primarily useful in a client.
+func EncodeGetUnsealCeremonyRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(endpoints.GetUnsealCeremonyRequest)
+	return &pb.GetUnsealCeremonyRequest{Id: req.ID, Cluster: req.Cluster}, nil
+}
+
+// DecodeSubmitUnsealShareRequest is a transport/grpc.DecodeRequestFunc that
+// converts a gRPC submitunsealshare request to a user-domain
+// submitunsealshare request. Primarily useful in a server.
+func DecodeSubmitUnsealShareRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.SubmitUnsealShareRequest)
+	opts := service.ShareOptions{
+		CeremonyID: req.CeremonyId,
+		Email:      req.Email,
+		Key:        req.Key,
+		Cluster:    req.Cluster,
+	}
+	return &endpoints.SubmitUnsealShareRequest{Opts: opts}, nil
+}
+
+// EncodeSubmitUnsealShareRequest is a transport/grpc.EncodeRequestFunc that
+// converts a user-domain submitunsealshare request to a gRPC
+// submitunsealshare request. Primarily useful in a client.
+func EncodeSubmitUnsealShareRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(endpoints.SubmitUnsealShareRequest)
+	return &pb.SubmitUnsealShareRequest{
+		CeremonyId: req.Opts.CeremonyID,
+		Email:      req.Opts.Email,
+		Key:        req.Opts.Key,
+		Cluster:    req.Opts.Cluster,
+	}, nil
+}
+
+// DecodeAbortUnsealCeremonyRequest is a transport/grpc.DecodeRequestFunc
+// that converts a gRPC abortunsealceremony request to a user-domain
+// abortunsealceremony request. Primarily useful in a server.
+func DecodeAbortUnsealCeremonyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.AbortUnsealCeremonyRequest)
+	return &endpoints.AbortUnsealCeremonyRequest{Cluster: req.Cluster, ID: req.Id}, nil
+}
+
+// EncodeAbortUnsealCeremonyRequest is a transport/grpc.EncodeRequestFunc
+// that converts a user-domain abortunsealceremony request to a gRPC
+// abortunsealceremony request. Primarily useful in a client.
+func EncodeAbortUnsealCeremonyRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(endpoints.AbortUnsealCeremonyRequest)
+	return &pb.AbortUnsealCeremonyRequest{Id: req.ID, Cluster: req.Cluster}, nil
+}
+
+// DecodeCeremonyResponse is a transport/grpc.DecodeResponseFunc that
+// converts a gRPC ceremony reply to a user-domain ceremony response.
+// Primarily useful in a client.
+func DecodeCeremonyResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	reply := grpcReply.(*pb.CeremonyResponse)
+	var ceremony service.CeremonyOutput
+	if reply.Ceremony != nil {
+		ceremony = decodeCeremony(reply.Ceremony)
+	}
+	return endpoints.CeremonyResponse{Ceremony: ceremony, Err: decodeError(reply.Err, reply.Errors)}, nil
+}
+
+// EncodeCeremonyResponse is a transport/grpc.EncodeResponseFunc that
+// converts a user-domain ceremony response to a gRPC ceremony reply.
+// Primarily useful in a server.
+func EncodeCeremonyResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(endpoints.CeremonyResponse)
+	return &pb.CeremonyResponse{
+		Ceremony: encodeCeremony(resp.Ceremony),
+		Err:      service.Error2String(resp.Err),
+		Errors:   encodeValidationErrors(resp.Err),
+	}, nil
+}
+
+func encodeCeremony(v service.CeremonyOutput) *pb.Ceremony {
+	holders := make([]*pb.CeremonyHolder, 0, len(v.Holders))
+	for _, h := range v.Holders {
+		holders = append(holders, &pb.CeremonyHolder{
+			Email:         h.Email,
+			Shares:        uint32(h.Shares),
+			Submitted:     uint32(h.Submitted),
+			Verified:      h.Verified,
+			DateSubmitted: h.DateSubmitted,
+		})
+	}
+	return &pb.Ceremony{
+		ClusterId:   v.ClusterID,
+		Id:          v.ID,
+		State:       v.State,
+		Threshold:   uint32(v.Threshold),
+		Progress:    uint32(v.Progress),
+		Sealed:      v.Sealed,
+		DateStarted: v.DateStarted,
+		DateExpires: v.DateExpires,
+		DateEnded:   v.DateEnded,
+		Holders:     holders,
+	}
+}
+
+func decodeCeremony(v *pb.Ceremony) service.CeremonyOutput {
+	holders := make([]service.CeremonyHolderStatus, 0, len(v.Holders))
+	for _, h := range v.Holders {
+		holders = append(holders, service.CeremonyHolderStatus{
+			Email:         h.Email,
+			Shares:        int(h.Shares),
+			Submitted:     int(h.Submitted),
+			Verified:      h.Verified,
+			DateSubmitted: h.DateSubmitted,
+		})
+	}
+	return service.CeremonyOutput{
+		ClusterID:   v.ClusterId,
+		ID:          v.Id,
+		State:       v.State,
+		Threshold:   int(v.Threshold),
+		Progress:    int(v.Progress),
+		Sealed:      v.Sealed,
+		DateStarted: v.DateStarted,
+		DateExpires: v.DateExpires,
+		DateEnded:   v.DateEnded,
+		Holders:     holders,
+	}
+}
+
+func encodeTokenHolder(v service.TokenHolderOutput) *pb.TokenHolder {
+	return &pb.TokenHolder{
+		Key: &pb.TokenHolderKey{
+			ClusterId:  v.ClusterID,
+			TokenType:  v.TokenType,
+			ShareIndex: uint32(v.ShareIndex),
+		},
+		Email:           v.Email,
+		DateCreated:     v.DateCreated,
+		DateInitialized: v.DateInitialized,
+		DateDelivered:   v.DateDelivered,
+		DateRevoked:     v.DateRevoked,
+		PgpFingerprint:  v.PGPFingerprint,
+		KeyId:           v.KeyID,
+	}
+}
+
+func decodeTokenHolder(v *pb.TokenHolder) service.TokenHolderOutput {
+	key := v.GetKey()
+	if key == nil {
+		key = &pb.TokenHolderKey{}
+	}
+	return service.TokenHolderOutput{
+		ClusterID:       key.ClusterId,
+		TokenType:       key.TokenType,
+		ShareIndex:      int(key.ShareIndex),
+		Email:           v.Email,
+		DateCreated:     v.DateCreated,
+		DateInitialized: v.DateInitialized,
+		DateDelivered:   v.DateDelivered,
+		DateRevoked:     v.DateRevoked,
+		PGPFingerprint:  v.PgpFingerprint,
+		KeyID:           v.KeyId,
+	}
+}
+
+// DecodeListClustersRequest is a transport/grpc.DecodeRequestFunc that
+// converts a gRPC listclusters request to a user-domain listclusters
+// request. Primarily useful in a server.
+func DecodeListClustersRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	return &endpoints.ListClustersRequest{}, nil
+}
+
+// EncodeListClustersRequest is a transport/grpc.EncodeRequestFunc that
+// converts a user-domain listclusters request to a gRPC listclusters
+// request. Primarily useful in a client.
+func EncodeListClustersRequest(_ context.Context, request interface{}) (interface{}, error) {
+	return &pb.ListClustersRequest{}, nil
+}
+
+// DecodeClustersResponse is a transport/grpc.DecodeResponseFunc that
+// converts a gRPC clusters reply to a user-domain clusters response.
+// Primarily useful in a client.
+func DecodeClustersResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	reply := grpcReply.(*pb.ClustersResponse)
+	clusters := make([]service.ClusterOutput, 0, len(reply.Clusters))
+	for _, v := range reply.Clusters {
+		clusters = append(clusters, service.ClusterOutput{
+			Name:      v.Name,
+			Address:   v.Address,
+			ClusterID: v.ClusterId,
+			Default:   v.Default,
+		})
+	}
+	return endpoints.ClustersResponse{Clusters: clusters, Err: service.String2Error(reply.Err)}, nil
+}
+
+// EncodeClustersResponse is a transport/grpc.EncodeResponseFunc that
+// converts a user-domain clusters response to a gRPC clusters reply.
+// Primarily useful in a server.
+func EncodeClustersResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(endpoints.ClustersResponse)
+	clusters := make([]*pb.Cluster, 0, len(resp.Clusters))
+	for _, v := range resp.Clusters {
+		clusters = append(clusters, &pb.Cluster{
+			Name:      v.Name,
+			Address:   v.Address,
+			ClusterId: v.ClusterID,
+			Default:   v.Default,
+		})
+	}
+	return &pb.ClustersResponse{
+		Clusters: clusters,
+		Err:      service.Error2String(resp.Err),
+	}, nil
+}
+
+func encodeNodeSealStates(v []service.NodeSealState) []*pb.NodeSealStatus {
+	nodes := make([]*pb.NodeSealStatus, 0, len(v))
+	for _, node := range v {
+		nodes = append(nodes, &pb.NodeSealStatus{
+			Address:  node.Address,
+			Sealed:   node.Sealed,
+			T:        uint32(node.T),
+			N:        uint32(node.N),
+			Progress: uint32(node.Progress),
+			Version:  node.Version,
+			Err:      node.Err,
+		})
+	}
+	return nodes
+}
+
+func decodeNodeSealStates(v []*pb.NodeSealStatus) []service.NodeSealState {
+	if len(v) == 0 {
+		return nil
+	}
+
+	nodes := make([]service.NodeSealState, 0, len(v))
+	for _, node := range v {
+		nodes = append(nodes, service.NodeSealState{
+			Address:  node.Address,
+			Sealed:   node.Sealed,
+			T:        int(node.T),
+			N:        int(node.N),
+			Progress: int(node.Progress),
+			Version:  node.Version,
+			Err:      node.Err,
+		})
+	}
+	return nodes
+}
+
+// encodeValidationErrors converts every problem found while validating
+// a request into gRPC error details. Other errors have no details.
+func encodeValidationErrors(err error) []*pb.ValidationError {
+	errs, ok := err.(config.ValidationErrors)
+	if !ok {
+		return nil
+	}
+
+	details := make([]*pb.ValidationError, 0, len(errs))
+	for _, v := range errs {
+		details = append(details, &pb.ValidationError{
+			Field:   v.Field,
+			File:    v.File,
+			Offset:  v.Offset,
+			Message: v.Message,
+		})
+	}
+	return details
+}
+
+// decodeError translates a gRPC error string, and any error details, back to
+// a Go error. When details are present, config.ValidationErrors is returned.
+func decodeError(s string, details []*pb.ValidationError) error {
+	if len(details) == 0 {
+		return service.String2Error(s)
+	}
+
+	errs := make(config.ValidationErrors, 0, len(details))
+	for _, v := range details {
+		errs = append(errs, config.ValidationError{
+			Field:   v.Field,
+			File:    v.File,
+			Offset:  v.Offset,
+			Message: v.Message,
+		})
+	}
+	return errs
+}
@@ -1,54 +1,51 @@
 package health
 
 import (
-	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
 	awsdyno "github.com/cdwlabs/armor/pkg/backend/data"
 	"github.com/cdwlabs/armor/pkg/config"
 	"github.com/go-kit/kit/log"
 )
 
+// awsHealth checks the AWS credentials of the backend, see
+// awsCredentialsHealth.
+var awsHealth = awsCredentialsHealth
+
 // This function is a wrapper to all the other backend health/readiness checks.
 func backendDataHealth(logger log.Logger) error {
-	err := awsCredentialsHealth(logger)
-	if err != nil {
-		return err
+	// AWS is only needed when token holders are kept in DynamoDB
+	store := config.Config().GetString("token_holder_store")
+	if store == "" || store == awsdyno.DynamoDBTokenHolderStore {
+		err := awsHealth(logger)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = tokenHolderTableHealth(logger)
+	err := tokenHolderStoreHealth(logger)
 	return err
 }
 
+// awsCredentialsHealth checks that Armor's backend has valid AWS
+// credentials, from any provider of config.NewAWSCredentials. Unlike
+// iam:GetUser, sts:GetCallerIdentity works for roles, and needs no IAM
+// permission.
 func awsCredentialsHealth(logger log.Logger) error {
-	cfg := config.Config()
-
-	if !cfg.IsSet("aws_access_key_id") || cfg.GetString("aws_access_key_id") == "" {
-		return errors.New("AWS access key id is not set")
-	}
-
-	if !cfg.IsSet("aws_secret_access_key") || cfg.GetString("aws_secret_access_key") == "" {
-		return errors.New("AWS secret access key is not set")
-	}
-
-	svc := iam.New(config.AWSSession())
+	svc := sts.New(config.AWSSession())
 
-	params := &iam.GetUserInput{}
+	params := &sts.GetCallerIdentityInput{}
 
-	_, err := svc.GetUser(params)
+	_, err := svc.GetCallerIdentity(params)
 
 	if err != nil {
+		logger.Log("msg", fmt.Sprintf("error checking AWS credentials: %s", err.Error()))
 		if awsErr, ok := err.(awserr.Error); ok {
 			switch awsErr.Code() {
-			case "NoSuchEntity":
-				// The request was rejected because it referenced an entity that does not
-				// exist.
-				return err
-			case "ServiceFailure":
-				// The request processing has failed because of an unknown error,
-				// exception or failure.
-				return err
+			case "NoCredentialProviders":
+				// None of the credential chain's providers found credentials.
+				return fmt.Errorf("AWS credentials are not set: %s", awsErr.Error())
 			default:
 				return err
 			}
@@ -59,17 +56,18 @@ func awsCredentialsHealth(logger log.Logger) error {
 	return nil
 }
 
-func tokenHolderTableHealth(logger log.Logger) error {
-
-	// For now, just verify that the dynamodb table exists
-	exists, err := awsdyno.TokenHolderTableExists()
+func tokenHolderStoreHealth(logger log.Logger) error {
+	store, err := awsdyno.TokenHolders()
 	if err != nil {
-		logger.Log("msg", fmt.Sprintf("error checking token holder table: %s", err.Error()))
-	} else if !exists {
-		logger.Log("msg", fmt.Sprintf("creating missing token holder table on aws dynamodb: %s", awsdyno.TokenHolderTableName()))
-		err = awsdyno.CreateTokenHolderTable()
+		logger.Log("msg", fmt.Sprintf("error opening token holder store: %s", err.Error()))
 		return err
 	}
 
-	return nil
+	// creates the store's schema (e.g. dynamodb table) when missing
+	err = store.EnsureSchema()
+	if err != nil {
+		logger.Log("msg", fmt.Sprintf("error ensuring token holder store schema: %s", err.Error()))
+	}
+
+	return err
 }
@@ -0,0 +1,46 @@
+package health
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	awsdyno "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackendDataHealth_AWSCredentials(t *testing.T) {
+	errChecked := errors.New("aws credentials checked")
+	checked := 0
+	awsHealth = func(log.Logger) error {
+		checked++
+		return errChecked
+	}
+	defer func() { awsHealth = awsCredentialsHealth }()
+	defer os.Unsetenv(config.TokenHolderStoreEnvVar)
+
+	cases := []struct {
+		store   string
+		checked bool
+	}{
+		{store: "", checked: true},
+		{store: awsdyno.DynamoDBTokenHolderStore, checked: true},
+		{store: awsdyno.MemoryTokenHolderStore, checked: false},
+	}
+
+	for _, c := range cases {
+		os.Setenv(config.TokenHolderStoreEnvVar, c.store)
+		checked = 0
+
+		err := backendDataHealth(log.NewNopLogger())
+		if c.checked {
+			assert.Equal(t, errChecked, err, "expecting the aws credentials check to fail readiness for store %q", c.store)
+			assert.Equal(t, 1, checked, "expecting aws credentials to be checked for store %q", c.store)
+		} else {
+			assert.NoError(t, err, "not expecting an error for store %q", c.store)
+			assert.Equal(t, 0, checked, "not expecting aws credentials to be checked for store %q", c.store)
+		}
+	}
+}
@@ -10,10 +10,14 @@ import (
 	"github.com/gorilla/mux"
 	"io/ioutil"
 	"net/http"
+	"path"
 
 	stdopentracing "github.com/opentracing/opentracing-go"
 	"golang.org/x/net/context"
 
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/cdwlabs/armor/pkg/proxy/auth"
 	"github.com/cdwlabs/armor/pkg/proxy/endpoints"
 	"github.com/cdwlabs/armor/pkg/proxy/service"
 	"github.com/go-kit/kit/log"
@@ -36,35 +40,112 @@ func NewHandler(ctx context.Context, endpoints endpoints.Endpoints, tracer stdop
 		endpoints.InitStatusEndpoint,
 		DecodeInitStatusRequest,
 		EncodeGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "InitStatus", logger)))...,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "InitStatus", logger), auth.HTTPToContext()))...,
 	))
 	r.Methods("PUT").Path("/init").Handler(httptransport.NewServer(
 		ctx,
 		endpoints.InitEndpoint,
 		DecodeInitRequest,
 		EncodeGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "Init", logger)))...,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "Init", logger), auth.HTTPToContext()))...,
 	))
 	r.Methods("GET").Path("/seal/status").Handler(httptransport.NewServer(
 		ctx,
 		endpoints.SealStatusEndpoint,
 		DecodeSealStatusRequest,
 		EncodeGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "SealStatus", logger)))...,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "SealStatus", logger), auth.HTTPToContext()))...,
 	))
+	r.Methods("GET").Path("/seal/status/watch").Handler(streamHandler(httptransport.NewServer(
+		ctx,
+		endpoints.WatchSealStatusEndpoint,
+		DecodeWatchSealStatusRequest,
+		EncodeWatchSealStatusResponse,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "WatchSealStatus", logger), auth.HTTPToContext(), eventStreamToContext()))...,
+	)))
 	r.Methods("PUT").Path("/unseal").Handler(httptransport.NewServer(
 		ctx,
 		endpoints.UnsealEndpoint,
 		DecodeUnsealRequest,
 		EncodeGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "Unseal", logger)))...,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "Unseal", logger), auth.HTTPToContext()))...,
 	))
 	r.Methods("POST").Path("/configure").Handler(httptransport.NewServer(
 		ctx,
 		endpoints.ConfigureEndpoint,
 		DecodeConfigureRequest,
 		EncodeGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "Configure", logger)))...,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "Configure", logger), auth.HTTPToContext()))...,
+	))
+	r.Methods("POST").Path("/configure/stream").Handler(streamHandler(httptransport.NewServer(
+		ctx,
+		endpoints.ConfigureStreamEndpoint,
+		DecodeConfigureStreamRequest,
+		EncodeConfigureStreamResponse,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "ConfigureStream", logger), auth.HTTPToContext(), eventStreamToContext()))...,
+	)))
+	r.Methods("GET").Path("/token-holders").Handler(httptransport.NewServer(
+		ctx,
+		endpoints.ListTokenHoldersEndpoint,
+		DecodeListTokenHoldersRequest,
+		EncodeGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "ListTokenHolders", logger), auth.HTTPToContext()))...,
+	))
+	r.Methods("GET").Path("/token-holders/{email}").Handler(httptransport.NewServer(
+		ctx,
+		endpoints.GetTokenHoldersEndpoint,
+		DecodeGetTokenHoldersRequest,
+		EncodeGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "GetTokenHolders", logger), auth.HTTPToContext()))...,
+	))
+	r.Methods("POST").Path("/token-holders/reassign").Handler(httptransport.NewServer(
+		ctx,
+		endpoints.ReassignTokenHolderEndpoint,
+		DecodeReassignTokenHolderRequest,
+		EncodeGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "ReassignTokenHolder", logger), auth.HTTPToContext()))...,
+	))
+	r.Methods("POST").Path("/token-holders/revoke").Handler(httptransport.NewServer(
+		ctx,
+		endpoints.RevokeTokenHolderEndpoint,
+		DecodeRevokeTokenHolderRequest,
+		EncodeGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "RevokeTokenHolder", logger), auth.HTTPToContext()))...,
+	))
+	r.Methods("POST").Path("/unseal/ceremonies").Handler(httptransport.NewServer(
+		ctx,
+		endpoints.StartUnsealCeremonyEndpoint,
+		DecodeStartUnsealCeremonyRequest,
+		EncodeGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "StartUnsealCeremony", logger), auth.HTTPToContext()))...,
+	))
+	r.Methods("GET").Path("/unseal/ceremonies/{id}").Handler(httptransport.NewServer(
+		ctx,
+		endpoints.GetUnsealCeremonyEndpoint,
+		DecodeGetUnsealCeremonyRequest,
+		EncodeGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "GetUnsealCeremony", logger), auth.HTTPToContext()))...,
+	))
+	r.Methods("POST").Path("/unseal/ceremonies/{id}/shares").Handler(httptransport.NewServer(
+		ctx,
+		endpoints.SubmitUnsealShareEndpoint,
+		DecodeSubmitUnsealShareRequest,
+		EncodeGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "SubmitUnsealShare", logger), auth.HTTPToContext()))...,
+	))
+	r.Methods("DELETE").Path("/unseal/ceremonies/{id}").Handler(httptransport.NewServer(
+		ctx,
+		endpoints.AbortUnsealCeremonyEndpoint,
+		DecodeAbortUnsealCeremonyRequest,
+		EncodeGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "AbortUnsealCeremony", logger), auth.HTTPToContext()))...,
+	))
+	r.Methods("GET").Path("/clusters").Handler(httptransport.NewServer(
+		ctx,
+		endpoints.ListClustersEndpoint,
+		DecodeListClustersRequest,
+		EncodeGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.FromHTTPRequest(tracer, "ListClusters", logger), auth.HTTPToContext()))...,
 	))
 	r.Methods("GET").Path("/metrics").Handler(promhttp.Handler())
 
@@ -72,16 +153,44 @@ func NewHandler(ctx context.Context, endpoints endpoints.Endpoints, tracer stdop
 }
 
 func errorEncoder(_ context.Context, err error, w http.ResponseWriter) {
-	w.WriteHeader(err2code(err))
-	json.NewEncoder(w).Encode(errorWrapper{Error: err.Error()})
+	code := err2code(err)
+	if code == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	w.WriteHeader(code)
+	wrapper := errorWrapper{Error: err.Error()}
+	if errs, ok := err.(config.ValidationErrors); ok {
+		wrapper.Errors = errs
+	}
+	json.NewEncoder(w).Encode(wrapper)
 }
 
 func err2code(err error) int {
 	switch err {
-	case service.ErrExample:
+	case service.ErrExample, dbackend.ErrTokenHolderEmailUnset:
 		return http.StatusBadRequest
+	case dbackend.ErrTokenHolderNotFound, dbackend.ErrCeremonyNotFound:
+		return http.StatusNotFound
+	case service.ErrTokenHolderRevoked, service.ErrInitInProgress, service.ErrVaultInitialized:
+		return http.StatusConflict
+	case service.ErrCeremonyInProgress, service.ErrCeremonyClosed, service.ErrVaultUnsealed, service.ErrShareAlreadySubmitted, dbackend.ErrCeremonyConflict:
+		return http.StatusConflict
+	case service.ErrNotUnsealTokenHolder, auth.ErrPermissionDenied:
+		return http.StatusForbidden
+	case auth.ErrUnauthenticated:
+		return http.StatusUnauthorized
+	case service.ErrShareMismatch:
+		return http.StatusUnprocessableEntity
+	case service.ErrSealWatcherClosed:
+		return http.StatusServiceUnavailable
 	}
 	switch e := err.(type) {
+	case config.ValidationErrors:
+		return http.StatusBadRequest
+	case service.UnknownClusterError:
+		return http.StatusNotFound
+	case service.IncompleteInitError:
+		return http.StatusConflict
 	case httptransport.Error:
 		switch e.Domain {
 		case httptransport.DomainDecode:
@@ -98,19 +207,41 @@ func errorDecoder(r *http.Response) error {
 	if err := json.NewDecoder(r.Body).Decode(&w); err != nil {
 		return err
 	}
+	if len(w.Errors) > 0 {
+		return w.Errors
+	}
 	return errors.New(w.Error)
 }
 
+// errorWrapper is the body of every failed response. Validation failures
+// additionally list every problem found in Errors.
 type errorWrapper struct {
-	Error string `json:"error"`
+	Error  string                  `json:"error"`
+	Errors config.ValidationErrors `json:"errors,omitempty"`
 }
 
-// DecodeInitStatusRequest is a transport/http.DecodeRequestFunc that is
-// basically a noop.  Normally, this method's default behavior is to decode
-// a JSON-encoded request from the HTTP request body. Primarily useful in
-// a server.
+// setCluster sets the name of the Vault cluster a request targets, if any,
+// as the cluster query parameter.
+func setCluster(r *http.Request, cluster string) {
+	if cluster != "" {
+		q := r.URL.Query()
+		q.Set("cluster", cluster)
+		r.URL.RawQuery = q.Encode()
+	}
+}
+
+// EncodeInitStatusRequest is a transport/http.EncodeRequestFunc that sets
+// the cluster, if any, as a query parameter. Primarily useful in a client.
+func EncodeInitStatusRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.InitStatusRequest)
+	setCluster(r, req.Cluster)
+	return nil
+}
+
+// DecodeInitStatusRequest is a transport/http.DecodeRequestFunc that reads
+// the optional cluster query parameter. Primarily useful in a server.
 func DecodeInitStatusRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	var req = &endpoints.InitStatusRequest{}
+	var req = &endpoints.InitStatusRequest{Cluster: r.URL.Query().Get("cluster")}
 	return req, nil
 }
 
@@ -166,12 +297,18 @@ func DecodeInitResponse(_ context.Context, r *http.Response) (interface{}, error
 	return resp, err
 }
 
-// DecodeSealStatusRequest is a transport/http.DecodeRequestFunc that is
-// basically a noop.  Normally, this method's default behavior is to decode
-// a JSON-encoded request from the HTTP request body. Primarily useful in
-// a server.
+// EncodeSealStatusRequest is a transport/http.EncodeRequestFunc that sets
+// the cluster, if any, as a query parameter. Primarily useful in a client.
+func EncodeSealStatusRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.SealStatusRequest)
+	setCluster(r, req.Cluster)
+	return nil
+}
+
+// DecodeSealStatusRequest is a transport/http.DecodeRequestFunc that reads
+// the optional cluster query parameter. Primarily useful in a server.
 func DecodeSealStatusRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	var req = &endpoints.SealStatusRequest{}
+	var req = &endpoints.SealStatusRequest{Cluster: r.URL.Query().Get("cluster")}
 	return req, nil
 }
 
@@ -189,6 +326,20 @@ func DecodeSealStatusResponse(_ context.Context, r *http.Response) (interface{},
 	return resp, err
 }
 
+// EncodeUnsealRequest is a transport/http.EncodeRequestFunc that
+// JSON-encodes the unseal request to the request body. Primarily useful in
+// a client.
+func EncodeUnsealRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.UnsealRequest)
+	opts := service.UnsealOptions{
+		Key:      req.Key,
+		Reset:    req.Reset,
+		Cluster:  req.Cluster,
+		AllNodes: req.AllNodes,
+	}
+	return EncodeGenericRequest(ctx, r, opts)
+}
+
 // DecodeUnsealRequest is a transport/http.DecodeRequestFunc that decodes
 // a JSON-encoded unseal request from the HTTP request body. Primarily useful in
 // a server.
@@ -199,7 +350,7 @@ func DecodeUnsealRequest(_ context.Context, r *http.Request) (interface{}, error
 		return &endpoints.UnsealRequest{}, err
 	}
 
-	return &endpoints.UnsealRequest{Key: opts.Key, Reset: opts.Reset}, nil
+	return &endpoints.UnsealRequest{Key: opts.Key, Reset: opts.Reset, Cluster: opts.Cluster, AllNodes: opts.AllNodes}, nil
 }
 
 // DecodeUnsealResponse is a transport/http.DecodeResponseFunc that
@@ -226,7 +377,7 @@ func DecodeConfigureRequest(_ context.Context, r *http.Request) (interface{}, er
 		return &endpoints.ConfigureRequest{}, err
 	}
 
-	return &endpoints.ConfigureRequest{URL: opts.URL, Token: opts.Token}, nil
+	return &endpoints.ConfigureRequest{URL: opts.URL, Token: opts.Token, Cluster: opts.Cluster}, nil
 }
 
 // DecodeConfigureResponse is a transport/http.DecodeResponseFunc that
@@ -243,6 +394,216 @@ func DecodeConfigureResponse(_ context.Context, r *http.Response) (interface{},
 	return resp, err
 }
 
+// EncodeListTokenHoldersRequest is a transport/http.EncodeRequestFunc that
+// sets the cluster id, if any, as a query parameter. Primarily useful in
+// a client.
+func EncodeListTokenHoldersRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.ListTokenHoldersRequest)
+	if req.ClusterID != "" {
+		q := r.URL.Query()
+		q.Set("cluster_id", req.ClusterID)
+		r.URL.RawQuery = q.Encode()
+	}
+	return nil
+}
+
+// DecodeListTokenHoldersRequest is a transport/http.DecodeRequestFunc that
+// reads the optional cluster_id query parameter. Primarily useful in
+// a server.
+func DecodeListTokenHoldersRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return &endpoints.ListTokenHoldersRequest{ClusterID: r.URL.Query().Get("cluster_id")}, nil
+}
+
+// EncodeGetTokenHoldersRequest is a transport/http.EncodeRequestFunc that
+// appends the email address to the request path. Primarily useful in
+// a client.
+func EncodeGetTokenHoldersRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.GetTokenHoldersRequest)
+	r.URL.Path = path.Join(r.URL.Path, req.Email)
+	return nil
+}
+
+// DecodeGetTokenHoldersRequest is a transport/http.DecodeRequestFunc that
+// reads the email address from the request path. Primarily useful in
+// a server.
+func DecodeGetTokenHoldersRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return &endpoints.GetTokenHoldersRequest{Email: mux.Vars(r)["email"]}, nil
+}
+
+// DecodeTokenHoldersResponse is a transport/http.DecodeResponseFunc that
+// decodes a JSON-encoded token holders response from the HTTP response body.
+// If the response has a non-200 status code, we will interpret that as an
+// error and attempt to decode the specific error message from the response
+// body. Primarily useful in a client.
+func DecodeTokenHoldersResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	var resp endpoints.TokenHoldersResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// EncodeReassignTokenHolderRequest is a transport/http.EncodeRequestFunc that
+// JSON-encodes the reassign request to the request body. Primarily useful in
+// a client.
+func EncodeReassignTokenHolderRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.ReassignTokenHolderRequest)
+	return EncodeGenericRequest(ctx, r, req.Opts)
+}
+
+// DecodeReassignTokenHolderRequest is a transport/http.DecodeRequestFunc that
+// decodes a JSON-encoded reassign request from the HTTP request body.
+// Primarily useful in a server.
+func DecodeReassignTokenHolderRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var opts = service.ReassignOptions{}
+	err := json.NewDecoder(r.Body).Decode(&opts)
+	if err != nil {
+		return &endpoints.ReassignTokenHolderRequest{}, err
+	}
+	return &endpoints.ReassignTokenHolderRequest{Opts: opts}, nil
+}
+
+// EncodeRevokeTokenHolderRequest is a transport/http.EncodeRequestFunc that
+// JSON-encodes the revoke request to the request body. Primarily useful in
+// a client.
+func EncodeRevokeTokenHolderRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.RevokeTokenHolderRequest)
+	return EncodeGenericRequest(ctx, r, req.Opts)
+}
+
+// DecodeRevokeTokenHolderRequest is a transport/http.DecodeRequestFunc that
+// decodes a JSON-encoded revoke request from the HTTP request body.
+// Primarily useful in a server.
+func DecodeRevokeTokenHolderRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var opts = service.RevokeOptions{}
+	err := json.NewDecoder(r.Body).Decode(&opts)
+	if err != nil {
+		return &endpoints.RevokeTokenHolderRequest{}, err
+	}
+	return &endpoints.RevokeTokenHolderRequest{Opts: opts}, nil
+}
+
+// DecodeTokenHolderResponse is a transport/http.DecodeResponseFunc that
+// decodes a JSON-encoded token holder response from the HTTP response body.
+// If the response has a non-200 status code, we will interpret that as an
+// error and attempt to decode the specific error message from the response
+// body. Primarily useful in a client.
+func DecodeTokenHolderResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	var resp endpoints.TokenHolderResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// EncodeStartUnsealCeremonyRequest is a transport/http.EncodeRequestFunc
+// that sets the cluster, if any, as a query parameter. Primarily useful in
+// a client.
+func EncodeStartUnsealCeremonyRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.StartUnsealCeremonyRequest)
+	setCluster(r, req.Cluster)
+	return nil
+}
+
+// DecodeStartUnsealCeremonyRequest is a transport/http.DecodeRequestFunc
+// that reads the optional cluster query parameter. Primarily useful in
+// a server.
+func DecodeStartUnsealCeremonyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return &endpoints.StartUnsealCeremonyRequest{Cluster: r.URL.Query().Get("cluster")}, nil
+}
+
+// EncodeGetUnsealCeremonyRequest is a transport/http.EncodeRequestFunc that
+// appends the ceremony id to the request path, and sets the cluster, if any,
+// as a query parameter. Primarily useful in a client.
+func EncodeGetUnsealCeremonyRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.GetUnsealCeremonyRequest)
+	r.URL.Path = path.Join(r.URL.Path, req.ID)
+	setCluster(r, req.Cluster)
+	return nil
+}
+
+// DecodeGetUnsealCeremonyRequest is a transport/http.DecodeRequestFunc that
+// reads the ceremony id from the request path, and the optional cluster
+// query parameter. Primarily useful in a server.
+func DecodeGetUnsealCeremonyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return &endpoints.GetUnsealCeremonyRequest{Cluster: r.URL.Query().Get("cluster"), ID: mux.Vars(r)["id"]}, nil
+}
+
+// EncodeSubmitUnsealShareRequest is a transport/http.EncodeRequestFunc that
+// appends the ceremony id to the request path, and JSON-encodes the share to
+// the request body. Primarily useful in a client.
+func EncodeSubmitUnsealShareRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.SubmitUnsealShareRequest)
+	r.URL.Path = path.Join(r.URL.Path, req.Opts.CeremonyID, "shares")
+	return EncodeGenericRequest(ctx, r, req.Opts)
+}
+
+// DecodeSubmitUnsealShareRequest is a transport/http.DecodeRequestFunc that
+// decodes a JSON-encoded share from the HTTP request body, for the ceremony
+// in the request path. Primarily useful in a server.
+func DecodeSubmitUnsealShareRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var opts = service.ShareOptions{}
+	err := json.NewDecoder(r.Body).Decode(&opts)
+	if err != nil {
+		return &endpoints.SubmitUnsealShareRequest{}, err
+	}
+	opts.CeremonyID = mux.Vars(r)["id"]
+	return &endpoints.SubmitUnsealShareRequest{Opts: opts}, nil
+}
+
+// EncodeAbortUnsealCeremonyRequest is a transport/http.EncodeRequestFunc that
+// appends the ceremony id to the request path, and sets the cluster, if any,
+// as a query parameter. Primarily useful in a client.
+func EncodeAbortUnsealCeremonyRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.AbortUnsealCeremonyRequest)
+	r.URL.Path = path.Join(r.URL.Path, req.ID)
+	setCluster(r, req.Cluster)
+	return nil
+}
+
+// DecodeAbortUnsealCeremonyRequest is a transport/http.DecodeRequestFunc that
+// reads the ceremony id from the request path, and the optional cluster
+// query parameter. Primarily useful in a server.
+func DecodeAbortUnsealCeremonyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return &endpoints.AbortUnsealCeremonyRequest{Cluster: r.URL.Query().Get("cluster"), ID: mux.Vars(r)["id"]}, nil
+}
+
+// DecodeCeremonyResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded unseal ceremony response from the HTTP response body. If the
+// response has a non-200 status code, we will interpret that as an error and
+// attempt to decode the specific error message from the response body.
+// Primarily useful in a client.
+func DecodeCeremonyResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	var resp endpoints.CeremonyResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// DecodeListClustersRequest is a transport/http.DecodeRequestFunc that is
+// basically a noop. Primarily useful in a server.
+func DecodeListClustersRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return &endpoints.ListClustersRequest{}, nil
+}
+
+// DecodeClustersResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded clusters response from the HTTP response body. If the
+// response has a non-200 status code, we will interpret that as an error and
+// attempt to decode the specific error message from the response body.
+// Primarily useful in a client.
+func DecodeClustersResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	var resp endpoints.ClustersResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
 // EncodeGenericRequest is a transport/http.EncodeRequestFunc that
 // JSON-encodes any request to the request body. Primarily useful in a client.
 func EncodeGenericRequest(_ context.Context, r *http.Request, request interface{}) error {
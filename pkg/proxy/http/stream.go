@@ -0,0 +1,310 @@
+package http
+
+// this file provides the bindings of the streaming methods, whose responses
+// are written as events while the method runs: newline delimited JSON for
+// ConfigureStream, and server-sent events for WatchSealStatus.
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/cdwlabs/armor/pkg/proxy/endpoints"
+	"github.com/cdwlabs/armor/pkg/proxy/service"
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+const (
+	// NDJSONContentType is the content type of ConfigureStream responses,
+	// one JSON event per line.
+	NDJSONContentType = "application/x-ndjson"
+
+	// SSEContentType is the content type of WatchSealStatus responses,
+	// server-sent events whose data is JSON.
+	SSEContentType = "text/event-stream"
+)
+
+// Server-sent events of WatchSealStatus responses.
+const (
+	SealStatusEvent = "seal-status" // data is a service.SealEvent
+	HeartbeatEvent  = "heartbeat"   // data is a service.SealEvent repeating the last seal status
+	ErrorEvent      = "error"       // data is the error which ended the watch
+)
+
+type contextKey int
+
+const (
+	eventStreamKey contextKey = iota
+	configEventsKey
+	sealEventsKey
+)
+
+// eventStream writes the events of a streamed response, flushing each one to
+// the client as soon as it is written.
+type eventStream struct {
+	w       http.ResponseWriter
+	started bool
+	err     error // the first error writing an event
+}
+
+// send writes v as a line of JSON.
+func (s *eventStream) send(v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		s.err = err
+		return
+	}
+	s.write(NDJSONContentType, append(b, '\n'))
+}
+
+// sendEvent writes v as the JSON data of a server-sent event.
+func (s *eventStream) sendEvent(event string, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		s.err = err
+		return
+	}
+	s.write(SSEContentType, []byte(fmt.Sprintf("event: %s\ndata: %s\n\n", event, b)))
+}
+
+func (s *eventStream) write(contentType string, b []byte) {
+	if s.err != nil {
+		return
+	}
+	if !s.started {
+		s.started = true
+		s.w.Header().Set("Content-Type", contentType)
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.WriteHeader(http.StatusOK)
+	}
+	if _, s.err = s.w.Write(b); s.err != nil {
+		return
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// streamHandler returns a handler giving the decoder and encoder of h access
+// to the response writer, through the eventStream set by
+// eventStreamToContext.
+func streamHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := &eventStream{w: w}
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), eventStreamKey, s)))
+	})
+}
+
+// eventStreamToContext returns a RequestFunc moving the eventStream of a
+// request served by streamHandler to the endpoint context.
+func eventStreamToContext() httptransport.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		return context.WithValue(ctx, eventStreamKey, r.Context().Value(eventStreamKey))
+	}
+}
+
+func eventStreamFromContext(ctx context.Context) *eventStream {
+	s, _ := ctx.Value(eventStreamKey).(*eventStream)
+	return s
+}
+
+// DecodeConfigureStreamRequest is a transport/http.DecodeRequestFunc that
+// decodes a JSON-encoded configure request from the HTTP request body, whose
+// events are streamed to the response. Primarily useful in a server.
+func DecodeConfigureStreamRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var opts = service.ConfigOptions{}
+	err := json.NewDecoder(r.Body).Decode(&opts)
+	if err != nil {
+		return &endpoints.ConfigureStreamRequest{}, err
+	}
+
+	req := &endpoints.ConfigureStreamRequest{URL: opts.URL, Token: opts.Token, Cluster: opts.Cluster}
+	if s := eventStreamFromContext(ctx); s != nil {
+		req.Events = func(e service.ConfigEvent) { s.send(e) }
+	}
+	return req, nil
+}
+
+// EncodeConfigureStreamResponse is a transport/http.EncodeResponseFunc for
+// configurestream responses. Once events were streamed, the response is
+// already written as the done event; otherwise it is encoded like any other.
+// Primarily useful in a server.
+func EncodeConfigureStreamResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	if s := eventStreamFromContext(ctx); s != nil && s.started {
+		return s.err
+	}
+	return EncodeGenericResponse(ctx, w, response)
+}
+
+// ConfigEventsToContext is a client endpoint.Middleware handing the Events of
+// a configurestream request to DecodeConfigureStreamResponse, which only sees
+// the request context.
+func ConfigEventsToContext(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		if req, ok := request.(endpoints.ConfigureStreamRequest); ok && req.Events != nil {
+			ctx = context.WithValue(ctx, configEventsKey, req.Events)
+		}
+		return next(ctx, request)
+	}
+}
+
+// DecodeConfigureStreamResponse is a transport/http.DecodeResponseFunc that
+// reads the events of a configurestream response as they arrive, handing each
+// to the Events set by ConfigEventsToContext. The response is the outcome of
+// the done event. If the response has a non-200 status code, we will
+// interpret that as an error and attempt to decode the specific error message
+// from the response body. Primarily useful in a client.
+func DecodeConfigureStreamResponse(ctx context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	if r.Header.Get("Content-Type") != NDJSONContentType {
+		return DecodeConfigureResponse(ctx, r)
+	}
+
+	events, _ := ctx.Value(configEventsKey).(service.ConfigEventFunc)
+	dec := json.NewDecoder(r.Body)
+	for {
+		var e service.ConfigEvent
+		if err := dec.Decode(&e); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if events != nil {
+			events(e)
+		}
+		if e.Phase == service.ConfigPhaseDone {
+			state := service.ConfigState{ConfigID: e.ConfigID}
+			if e.State != nil {
+				state = *e.State
+			}
+			return endpoints.NewConfigureResponse(state, e.Failure()), nil
+		}
+	}
+}
+
+// DecodeWatchSealStatusRequest is a transport/http.DecodeRequestFunc that
+// reads the cluster, if any, from the query parameters of a watchsealstatus
+// request, whose events are streamed to the response until its client goes
+// away. Primarily useful in a server.
+func DecodeWatchSealStatusRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	done, cancel := context.WithCancel(r.Context())
+	req := &endpoints.WatchSealStatusRequest{Cluster: r.URL.Query().Get("cluster"), Done: done.Done()}
+	if s := eventStreamFromContext(ctx); s != nil {
+		req.Events = func(e service.SealEvent) {
+			name := SealStatusEvent
+			if e.Heartbeat {
+				name = HeartbeatEvent
+			}
+			if s.sendEvent(name, e); s.err != nil {
+				cancel()
+			}
+		}
+	}
+	return req, nil
+}
+
+// EncodeWatchSealStatusResponse is a transport/http.EncodeResponseFunc for
+// watchsealstatus responses. Once events were streamed, the error which ended
+// the watch, if any, is sent as an error event; otherwise it is encoded like
+// any other. Primarily useful in a server.
+func EncodeWatchSealStatusResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(endpoints.WatchSealStatusResponse)
+	if s := eventStreamFromContext(ctx); s != nil && s.started {
+		if resp.Err != nil {
+			s.sendEvent(ErrorEvent, errorWrapper{Error: resp.Err.Error()})
+		}
+		return nil
+	}
+	if resp.Err != nil {
+		errorEncoder(ctx, resp.Err, w)
+	}
+	return nil
+}
+
+// EncodeWatchSealStatusRequest is a transport/http.EncodeRequestFunc that
+// sets the cluster, if any, as a query parameter. Primarily useful in a
+// client.
+func EncodeWatchSealStatusRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.WatchSealStatusRequest)
+	setCluster(r, req.Cluster)
+	r.Header.Set("Accept", SSEContentType)
+	return nil
+}
+
+// SealEventsToContext is a client endpoint.Middleware handing the Events of
+// a watchsealstatus request to DecodeWatchSealStatusResponse, which only sees
+// the request context.
+func SealEventsToContext(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		if req, ok := request.(endpoints.WatchSealStatusRequest); ok && req.Events != nil {
+			ctx = context.WithValue(ctx, sealEventsKey, req.Events)
+		}
+		return next(ctx, request)
+	}
+}
+
+// DecodeWatchSealStatusResponse is a transport/http.DecodeResponseFunc that
+// reads the server-sent events of a watchsealstatus response as they arrive,
+// handing each seal status to the Events set by SealEventsToContext. The
+// watch ends without error once ctx is done. If the response has a non-200
+// status code, we will interpret that as an error and attempt to decode the
+// specific error message from the response body. Primarily useful in a
+// client.
+func DecodeWatchSealStatusResponse(ctx context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+
+	events, _ := ctx.Value(sealEventsKey).(service.SealEventFunc)
+	var (
+		name string
+		data bytes.Buffer
+	)
+	reader := bufio.NewReader(r.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return endpoints.WatchSealStatusResponse{}, nil
+			}
+			return nil, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(line[len("event:"):])
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimSpace(line[len("data:"):]))
+		case line == "" && data.Len() > 0:
+			switch name {
+			case ErrorEvent:
+				var w errorWrapper
+				if err := json.Unmarshal(data.Bytes(), &w); err != nil {
+					return nil, err
+				}
+				return endpoints.WatchSealStatusResponse{Err: service.String2Error(w.Error)}, nil
+			case SealStatusEvent, HeartbeatEvent:
+				var e service.SealEvent
+				if err := json.Unmarshal(data.Bytes(), &e); err != nil {
+					return nil, err
+				}
+				if events != nil {
+					events(e)
+				}
+			}
+			name = ""
+			data.Reset()
+		}
+	}
+}
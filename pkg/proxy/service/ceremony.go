@@ -0,0 +1,460 @@
+package service
+
+// This file contains the unseal ceremony methods of the service.
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/config"
+	vaultapi "github.com/hashicorp/vault/api"
+	"golang.org/x/net/context"
+)
+
+// ceremonyPutAttempts bounds the retries of recording a participant when the
+// ceremony is written concurrently.
+const ceremonyPutAttempts = 5
+
+var (
+	// ErrCeremonyInProgress is returned when starting a ceremony while
+	// another is open.
+	ErrCeremonyInProgress = errors.New("an unseal ceremony is already in progress")
+
+	// ErrCeremonyClosed is returned when submitting an unseal key to a
+	// ceremony which is no longer open.
+	ErrCeremonyClosed = errors.New("unseal ceremony is no longer open")
+
+	// ErrVaultUnsealed is returned when starting a ceremony while Vault is
+	// unsealed.
+	ErrVaultUnsealed = errors.New("vault is already unsealed")
+
+	// ErrNotUnsealTokenHolder is returned when the email address holds no
+	// live unseal token of the cluster.
+	ErrNotUnsealTokenHolder = errors.New("email address holds no unseal token of the cluster")
+
+	// ErrShareMismatch is returned when an unseal key doesn't match any
+	// share the token holder keeps.
+	ErrShareMismatch = errors.New("unseal key doesn't match the token holder's shares")
+
+	// ErrShareAlreadySubmitted is returned when a token holder submits a
+	// share already submitted, or more keys than they hold shares.
+	ErrShareAlreadySubmitted = errors.New("token holder already submitted their unseal key")
+)
+
+// ShareOptions is an unseal key submitted by a token holder during a
+// ceremony.
+type ShareOptions struct {
+	CeremonyID string `json:"ceremony_id" validate:"required"`
+	Email      string `json:"email" validate:"required,email"`
+	Key        string `json:"key" validate:"required"`
+	Cluster    string `json:"cluster,omitempty"`
+}
+
+// CeremonyHolderStatus tells whether one of the cluster's unseal token
+// holders took part in a ceremony.
+type CeremonyHolderStatus struct {
+	Email         string `json:"email"`
+	Shares        int    `json:"shares"`    // live unseal shares held
+	Submitted     int    `json:"submitted"` // keys submitted during the ceremony
+	Verified      bool   `json:"verified"`  // every key submitted matched a stored share hash
+	DateSubmitted string `json:"date_submitted,omitempty"`
+}
+
+// CeremonyOutput is the status of an unseal ceremony. Sealed and Progress are
+// read from Vault.
+type CeremonyOutput struct {
+	ClusterID   string                 `json:"cluster_id"`
+	ID          string                 `json:"id"`
+	State       string                 `json:"state"`
+	Threshold   int                    `json:"threshold"`
+	Progress    int                    `json:"progress"`
+	Sealed      bool                   `json:"sealed"`
+	DateStarted string                 `json:"date_started"`
+	DateExpires string                 `json:"date_expires"`
+	DateEnded   string                 `json:"date_ended,omitempty"`
+	Holders     []CeremonyHolderStatus `json:"holders"`
+}
+
+// StartUnsealCeremony implements Service. Vault's unseal progress is reset,
+// so only keys submitted during the ceremony count.
+func (s proxyService) StartUnsealCeremony(_ context.Context, cluster string) (CeremonyOutput, error) {
+	c, err := newCeremonies(cluster)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	err = c.store.EnsureSchema()
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	return c.start()
+}
+
+// GetUnsealCeremony implements Service.
+func (s proxyService) GetUnsealCeremony(_ context.Context, cluster, id string) (CeremonyOutput, error) {
+	c, err := newCeremonies(cluster)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	return c.get(id)
+}
+
+// SubmitUnsealShare implements Service. The key is verified against the
+// token holder's stored share hashes before it is submitted to Vault. Keys
+// of shares Armor keeps no hash for (i.e. PGP encrypted shares) are
+// submitted unverified.
+func (s proxyService) SubmitUnsealShare(_ context.Context, opts ShareOptions) (CeremonyOutput, error) {
+	err := config.Validator().Struct(opts)
+	if err != nil {
+		return CeremonyOutput{}, config.NewValidationErrors(err)
+	}
+
+	c, err := newCeremonies(opts.Cluster)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	return c.submit(opts)
+}
+
+// AbortUnsealCeremony implements Service. Vault's unseal progress is reset.
+func (s proxyService) AbortUnsealCeremony(_ context.Context, cluster, id string) (CeremonyOutput, error) {
+	c, err := newCeremonies(cluster)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	return c.abort(id)
+}
+
+// unsealSys is the part of Vault's sys API used by ceremonies, i.e.
+// (*vaultapi.Client).Sys().
+type unsealSys interface {
+	SealStatus() (*vaultapi.SealStatusResponse, error)
+	Unseal(shard string) (*vaultapi.SealStatusResponse, error)
+	ResetUnsealProcess() (*vaultapi.SealStatusResponse, error)
+}
+
+// ceremonies runs the unseal ceremonies of a Vault cluster.
+type ceremonies struct {
+	store     dbackend.TokenHolderStore
+	sys       unsealSys
+	clusterID string
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+// newCeremonies returns the ceremonies of the named Vault cluster.
+func newCeremonies(cluster string) (*ceremonies, error) {
+	store, err := dbackend.TokenHolders()
+	if err != nil {
+		return nil, err
+	}
+
+	client, clusterID, err := NewClusterClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ceremonies{
+		store:     store,
+		sys:       client.Sys(),
+		clusterID: clusterID,
+		ttl:       config.Config().GetDuration("unseal_ceremony_ttl"),
+		now:       time.Now,
+	}, nil
+}
+
+func (c *ceremonies) start() (CeremonyOutput, error) {
+	status, err := c.sys.SealStatus()
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+	if !status.Sealed {
+		return CeremonyOutput{}, ErrVaultUnsealed
+	}
+
+	existing, err := c.store.ListCeremonies(c.clusterID)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+	for _, cer := range existing {
+		if cer.State != dbackend.CeremonyOpen {
+			continue
+		}
+		if !cer.Expired(c.now()) {
+			return CeremonyOutput{}, ErrCeremonyInProgress
+		}
+		_, err = c.end(cer, dbackend.CeremonyExpired)
+		if err != nil {
+			return CeremonyOutput{}, err
+		}
+	}
+
+	status, err = c.sys.ResetUnsealProcess()
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	id, err := newCeremonyID()
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	now := c.now()
+	cer := &dbackend.Ceremony{
+		ClusterID:   c.clusterID,
+		ID:          id,
+		State:       dbackend.CeremonyOpen,
+		Threshold:   status.T,
+		DateStarted: now.Format(time.RFC3339),
+		DateExpires: now.Add(c.ttl).Format(time.RFC3339),
+	}
+	err = c.store.PutCeremony(cer, nil)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	return c.output(cer, status)
+}
+
+func (c *ceremonies) get(id string) (CeremonyOutput, error) {
+	cer, err := c.current(id)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	status, err := c.sys.SealStatus()
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	return c.output(cer, status)
+}
+
+func (c *ceremonies) submit(opts ShareOptions) (CeremonyOutput, error) {
+	cer, err := c.current(opts.CeremonyID)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+	if cer.State != dbackend.CeremonyOpen {
+		return CeremonyOutput{}, ErrCeremonyClosed
+	}
+
+	holders, err := c.store.ListByCluster(c.clusterID)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	participant, err := verifyShare(cer, unsealShares(holders, opts.Email), opts.Key)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	status, err := c.sys.Unseal(opts.Key)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	// the key was used, so it's recorded even if the ceremony was written
+	// concurrently
+	participant.DateSubmitted = c.now().Format(time.RFC3339)
+	for i := 0; ; i++ {
+		next := *cer
+		next.Participants = append(append([]dbackend.CeremonyParticipant(nil), cer.Participants...), participant)
+		if !status.Sealed {
+			next.State = dbackend.CeremonyUnsealed
+			next.DateEnded = participant.DateSubmitted
+		}
+
+		err = c.store.PutCeremony(&next, cer)
+		if err == nil {
+			return c.output(&next, status)
+		}
+		if err != dbackend.ErrCeremonyConflict || i+1 == ceremonyPutAttempts {
+			return CeremonyOutput{}, err
+		}
+
+		cer, err = c.store.GetCeremony(c.clusterID, opts.CeremonyID)
+		if err != nil {
+			return CeremonyOutput{}, err
+		}
+	}
+}
+
+func (c *ceremonies) abort(id string) (CeremonyOutput, error) {
+	cer, err := c.current(id)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+	if cer.State != dbackend.CeremonyOpen {
+		return CeremonyOutput{}, ErrCeremonyClosed
+	}
+
+	cer, err = c.end(cer, dbackend.CeremonyAborted)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	status, err := c.sys.SealStatus()
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	return c.output(cer, status)
+}
+
+// current returns the ceremony, after marking it expired if it is open past
+// its expiry.
+func (c *ceremonies) current(id string) (*dbackend.Ceremony, error) {
+	cer, err := c.store.GetCeremony(c.clusterID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if cer.State == dbackend.CeremonyOpen && cer.Expired(c.now()) {
+		return c.end(cer, dbackend.CeremonyExpired)
+	}
+	return cer, nil
+}
+
+// end closes an open ceremony, and resets Vault's unseal progress so keys
+// submitted during the ceremony don't linger.
+func (c *ceremonies) end(cer *dbackend.Ceremony, state string) (*dbackend.Ceremony, error) {
+	next := *cer
+	next.State = state
+	next.DateEnded = c.now().Format(time.RFC3339)
+
+	err := c.store.PutCeremony(&next, cer)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.sys.ResetUnsealProcess()
+	if err != nil {
+		return nil, err
+	}
+
+	return &next, nil
+}
+
+// output describes cer, with the status of every live unseal token holder of
+// the cluster, and of participants who no longer hold a share.
+func (c *ceremonies) output(cer *dbackend.Ceremony, status *vaultapi.SealStatusResponse) (CeremonyOutput, error) {
+	holders, err := c.store.ListByCluster(c.clusterID)
+	if err != nil {
+		return CeremonyOutput{}, err
+	}
+
+	var statuses []CeremonyHolderStatus
+	index := make(map[string]int)
+	holderStatus := func(email string) *CeremonyHolderStatus {
+		i, ok := index[email]
+		if !ok {
+			i = len(statuses)
+			index[email] = i
+			statuses = append(statuses, CeremonyHolderStatus{Email: email, Verified: true})
+		}
+		return &statuses[i]
+	}
+
+	for _, tokenHolder := range holders {
+		if tokenHolder.TokenType == dbackend.UnsealTokenType && tokenHolder.DateRevoked == "" {
+			holderStatus(tokenHolder.Email).Shares++
+		}
+	}
+	for _, p := range cer.Participants {
+		hs := holderStatus(p.Email)
+		hs.Submitted++
+		hs.Verified = hs.Verified && p.Verified
+		hs.DateSubmitted = p.DateSubmitted
+	}
+	for i := range statuses {
+		if statuses[i].Submitted == 0 {
+			statuses[i].Verified = false
+		}
+	}
+
+	return CeremonyOutput{
+		ClusterID:   cer.ClusterID,
+		ID:          cer.ID,
+		State:       cer.State,
+		Threshold:   cer.Threshold,
+		Progress:    status.Progress,
+		Sealed:      status.Sealed,
+		DateStarted: cer.DateStarted,
+		DateExpires: cer.DateExpires,
+		DateEnded:   cer.DateEnded,
+		Holders:     statuses,
+	}, nil
+}
+
+// unsealShares returns the live unseal shares held by the email address.
+func unsealShares(holders []*dbackend.TokenHolder, email string) []*dbackend.TokenHolder {
+	var shares []*dbackend.TokenHolder
+	for _, tokenHolder := range holders {
+		if tokenHolder.Email == email && tokenHolder.TokenType == dbackend.UnsealTokenType && tokenHolder.DateRevoked == "" {
+			shares = append(shares, tokenHolder)
+		}
+	}
+	return shares
+}
+
+// verifyShare matches key against the holder's shares. A key matching no
+// stored hash is only accepted, unverified, if the holder has a share Armor
+// keeps no hash for.
+func verifyShare(cer *dbackend.Ceremony, shares []*dbackend.TokenHolder, key string) (dbackend.CeremonyParticipant, error) {
+	if len(shares) == 0 {
+		return dbackend.CeremonyParticipant{}, ErrNotUnsealTokenHolder
+	}
+	email := shares[0].Email
+
+	submitted := 0
+	for _, p := range cer.Participants {
+		if p.Email == email {
+			submitted++
+		}
+	}
+	if submitted >= len(shares) {
+		return dbackend.CeremonyParticipant{}, ErrShareAlreadySubmitted
+	}
+
+	hash := dbackend.ShareHash(key)
+	unhashed := false
+	for _, share := range shares {
+		if share.TokenHash == "" {
+			unhashed = true
+			continue
+		}
+		if share.TokenHash != hash {
+			continue
+		}
+
+		for _, p := range cer.Participants {
+			if p.Verified && p.ShareIndex == share.ShareIndex {
+				return dbackend.CeremonyParticipant{}, ErrShareAlreadySubmitted
+			}
+		}
+		return dbackend.CeremonyParticipant{Email: email, ShareIndex: share.ShareIndex, Verified: true}, nil
+	}
+
+	if !unhashed {
+		return dbackend.CeremonyParticipant{}, ErrShareMismatch
+	}
+	return dbackend.CeremonyParticipant{Email: email}, nil
+}
+
+// newCeremonyID returns a random ceremony id.
+func newCeremonyID() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// testUnsealSys is a sealed Vault, unsealed by threshold of its keys.
+type testUnsealSys struct {
+	keys      map[string]bool
+	threshold int
+	progress  int
+	sealed    bool
+}
+
+func (s *testUnsealSys) status() *vaultapi.SealStatusResponse {
+	return &vaultapi.SealStatusResponse{Sealed: s.sealed, T: s.threshold, N: len(s.keys), Progress: s.progress}
+}
+
+func (s *testUnsealSys) SealStatus() (*vaultapi.SealStatusResponse, error) {
+	return s.status(), nil
+}
+
+func (s *testUnsealSys) Unseal(shard string) (*vaultapi.SealStatusResponse, error) {
+	if s.keys[shard] {
+		s.progress++
+	}
+	if s.progress >= s.threshold {
+		s.sealed = false
+		s.progress = 0
+	}
+	return s.status(), nil
+}
+
+func (s *testUnsealSys) ResetUnsealProcess() (*vaultapi.SealStatusResponse, error) {
+	s.progress = 0
+	return s.status(), nil
+}
+
+func TestCeremonies(t *testing.T) {
+	store := dbackend.NewMemoryStore()
+	keys := []string{"5f4dcc3b5aa765d61d8327deb882cf99", "6cb75f652a9b52798eb6cf2201057c73", "819b0643d6b89dc9b579fdfc9094f28e"}
+	emails := []string{"aaron.rodgers@packers.com", "david.bakhtiari@packers.com", "bryan.bulaga@packers.com"}
+	for i, key := range keys {
+		tokenHolder := dbackend.NewTokenHolder()
+		tokenHolder.ClusterID = "packers"
+		tokenHolder.ShareIndex = i
+		tokenHolder.Email = emails[i]
+		tokenHolder.Token = key
+		tokenHolder.TokenType = dbackend.UnsealTokenType
+		if i == 2 {
+			// PGP encrypted, so there is no hash to verify against
+			tokenHolder.PGPFingerprint = "abcdef"
+		} else {
+			tokenHolder.TokenHash = dbackend.ShareHash(key)
+		}
+		err := store.Put(tokenHolder)
+		assert.NoError(t, err, "not expecting an error when putting token holder")
+	}
+
+	sys := &testUnsealSys{keys: map[string]bool{keys[0]: true, keys[1]: true, keys[2]: true}, threshold: 2, sealed: true, progress: 1}
+	now := time.Now()
+	c := &ceremonies{store: store, sys: sys, clusterID: "packers", ttl: time.Hour, now: func() time.Time { return now }}
+
+	cer, err := c.start()
+	assert.NoError(t, err, "not expecting an error when starting a ceremony")
+	assert.Equal(t, dbackend.CeremonyOpen, cer.State, "expecting an open ceremony")
+	assert.Equal(t, 0, cer.Progress, "expecting earlier unseal progress to be reset")
+	assert.Len(t, cer.Holders, 3, "expecting the status of every unseal token holder")
+
+	_, err = c.start()
+	assert.Equal(t, ErrCeremonyInProgress, err, "expecting a single open ceremony")
+
+	_, err = c.submit(ShareOptions{CeremonyID: cer.ID, Email: "clay.matthews@packers.com", Key: keys[0]})
+	assert.Equal(t, ErrNotUnsealTokenHolder, err, "expecting an error for an email address without unseal token")
+
+	_, err = c.submit(ShareOptions{CeremonyID: cer.ID, Email: emails[0], Key: keys[1]})
+	assert.Equal(t, ErrShareMismatch, err, "expecting an error for another holder's key")
+	assert.Equal(t, 0, sys.progress, "not expecting a mismatched key to be submitted")
+
+	cer, err = c.submit(ShareOptions{CeremonyID: cer.ID, Email: emails[0], Key: keys[0]})
+	assert.NoError(t, err, "not expecting an error when submitting a share")
+	assert.Equal(t, 1, cer.Progress, "expecting the share to be submitted")
+	assert.Equal(t, CeremonyHolderStatus{Email: emails[0], Shares: 1, Submitted: 1, Verified: true, DateSubmitted: now.Format(time.RFC3339)}, cer.Holders[0], "expecting the holder to be verified")
+	assert.Equal(t, 0, cer.Holders[1].Submitted, "expecting the other holders to be pending")
+
+	_, err = c.submit(ShareOptions{CeremonyID: cer.ID, Email: emails[0], Key: keys[0]})
+	assert.Equal(t, ErrShareAlreadySubmitted, err, "expecting an error for a repeated share")
+
+	cer, err = c.submit(ShareOptions{CeremonyID: cer.ID, Email: emails[2], Key: keys[2]})
+	assert.NoError(t, err, "not expecting an error when submitting an unhashed share")
+	assert.Equal(t, dbackend.CeremonyUnsealed, cer.State, "expecting the ceremony to end once Vault is unsealed")
+	assert.False(t, cer.Sealed, "expecting Vault to be unsealed")
+	assert.False(t, cer.Holders[2].Verified, "expecting an unhashed share to be unverified")
+
+	_, err = c.submit(ShareOptions{CeremonyID: cer.ID, Email: emails[1], Key: keys[1]})
+	assert.Equal(t, ErrCeremonyClosed, err, "expecting an error for a finished ceremony")
+
+	stored, err := store.GetCeremony("packers", cer.ID)
+	assert.NoError(t, err, "not expecting an error when getting the ceremony")
+	assert.Len(t, stored.Participants, 2, "expecting the participants to be recorded")
+}
+
+func TestCeremonies_Expiry(t *testing.T) {
+	store := dbackend.NewMemoryStore()
+	sys := &testUnsealSys{keys: map[string]bool{}, threshold: 2, sealed: true}
+	now := time.Now()
+	c := &ceremonies{store: store, sys: sys, clusterID: "packers", ttl: time.Hour, now: func() time.Time { return now }}
+
+	cer, err := c.start()
+	assert.NoError(t, err, "not expecting an error when starting a ceremony")
+	sys.progress = 1
+
+	now = now.Add(time.Hour)
+	expired, err := c.get(cer.ID)
+	assert.NoError(t, err, "not expecting an error when getting an expired ceremony")
+	assert.Equal(t, dbackend.CeremonyExpired, expired.State, "expecting a stale ceremony to expire")
+	assert.Equal(t, 0, sys.progress, "expecting unseal progress to be reset on expiry")
+
+	next, err := c.start()
+	assert.NoError(t, err, "not expecting an error when starting a ceremony after expiry")
+
+	aborted, err := c.abort(next.ID)
+	assert.NoError(t, err, "not expecting an error when aborting a ceremony")
+	assert.Equal(t, dbackend.CeremonyAborted, aborted.State, "expecting an aborted ceremony")
+
+	_, err = c.abort(next.ID)
+	assert.Equal(t, ErrCeremonyClosed, err, "expecting an error when aborting a closed ceremony")
+
+	_, err = c.get("unknown")
+	assert.Equal(t, dbackend.ErrCeremonyNotFound, err, "expecting not found for an unknown ceremony")
+}
@@ -0,0 +1,154 @@
+package service
+
+// This file contains the resolution of the Vault cluster a request targets.
+
+import (
+	"fmt"
+
+	"github.com/cdwlabs/armor/pkg/config"
+	vaultapi "github.com/hashicorp/vault/api"
+	"golang.org/x/net/context"
+)
+
+// UnknownClusterError is returned for a cluster that isn't in the config
+// file.
+type UnknownClusterError struct {
+	Name string
+}
+
+func (e UnknownClusterError) Error() string {
+	return fmt.Sprintf("unknown vault cluster: %q", e.Name)
+}
+
+// ClusterOutput describes a Vault cluster Armor manages.
+type ClusterOutput struct {
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	ClusterID string `json:"cluster_id"` // token holders of the cluster are kept under
+	Default   bool   `json:"default"`    // whether requests which don't name a cluster target it
+}
+
+// ClusterName returns the name of the cluster a request targets: cluster, or
+// Armor's default_cluster if it is empty.
+func ClusterName(cluster string) string {
+	if cluster != "" {
+		return cluster
+	}
+	if name := config.Config().GetString("default_cluster"); name != "" {
+		return name
+	}
+	return config.DefaultClusterName
+}
+
+// NewClusterClient returns a client of the named Vault cluster (see
+// ClusterName), and the id its token holders are kept under.
+func NewClusterClient(cluster string) (*vaultapi.Client, string, error) {
+	name := ClusterName(cluster)
+
+	clusters, err := config.Clusters()
+	if err != nil {
+		return nil, "", err
+	}
+
+	c, ok := clusters[name]
+	if !ok {
+		if name != config.DefaultClusterName {
+			return nil, "", UnknownClusterError{Name: name}
+		}
+
+		client, err := NewVaultClient()
+		if err != nil {
+			return nil, "", err
+		}
+		return client, VaultClusterID(client), nil
+	}
+
+	return clusterClient(c)
+}
+
+// clusterClient returns a client of a cluster of the config file, and the id
+// its token holders are kept under.
+func clusterClient(c config.Cluster) (*vaultapi.Client, string, error) {
+	vaultcfg := vaultapi.DefaultConfig()
+	vaultcfg.Address = c.Address
+	err := vaultcfg.ConfigureTLS(&vaultapi.TLSConfig{
+		CACert:        c.CACert,
+		CAPath:        c.CAPath,
+		ClientCert:    c.ClientCert,
+		ClientKey:     c.ClientKey,
+		TLSServerName: c.TLSServerName,
+		Insecure:      c.SkipVerify,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	client, err := vaultapi.NewClient(vaultcfg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	clusterID := c.ClusterID
+	if clusterID == "" {
+		clusterID = VaultAddress(client)
+	}
+	return client, clusterID, nil
+}
+
+// clusterNodes returns the node addresses of the named Vault cluster (see
+// ClusterName), which are empty if it isn't an HA cluster, and a function
+// returning a client of one of its nodes.
+func clusterNodes(cluster string) ([]string, nodeClientFunc, error) {
+	name := ClusterName(cluster)
+
+	clusters, err := config.Clusters()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, ok := clusters[name]
+	if !ok {
+		if name != config.DefaultClusterName {
+			return nil, nil, UnknownClusterError{Name: name}
+		}
+		return config.Config().GetStringSlice("vault_nodes"), NewVaultNodeClient, nil
+	}
+
+	newClient := func(address string) (*vaultapi.Client, error) {
+		node := c
+		node.Address = address
+		client, _, err := clusterClient(node)
+		return client, err
+	}
+	return c.Nodes, newClient, nil
+}
+
+// Clusters describes every Vault cluster Armor manages, by name.
+func Clusters() ([]ClusterOutput, error) {
+	names, err := config.ClusterNames()
+	if err != nil {
+		return nil, err
+	}
+
+	defaultName := ClusterName("")
+	clusters := make([]ClusterOutput, 0, len(names))
+	for _, name := range names {
+		client, clusterID, err := NewClusterClient(name)
+		if err != nil {
+			return nil, err
+		}
+
+		clusters = append(clusters, ClusterOutput{
+			Name:      name,
+			Address:   VaultAddress(client),
+			ClusterID: clusterID,
+			Default:   name == defaultName,
+		})
+	}
+	return clusters, nil
+}
+
+// ListClusters implements Service.
+func (s proxyService) ListClusters(_ context.Context) ([]ClusterOutput, error) {
+	return Clusters()
+}
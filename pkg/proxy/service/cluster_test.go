@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterName(t *testing.T) {
+	assert.Equal(t, config.DefaultClusterName, ClusterName(""), "expecting the default cluster when none is named")
+	assert.Equal(t, "prod", ClusterName("prod"), "expecting the named cluster")
+}
+
+func TestNewClusterClient(t *testing.T) {
+	_, _, err := NewClusterClient("lions")
+	assert.Equal(t, UnknownClusterError{Name: "lions"}, err, "expecting an error for an unknown cluster")
+
+	client, clusterID, err := clusterClient(config.Cluster{Name: "prod", Address: "https://vault.prod.packers.com:8200", ClusterID: "lambeau"})
+	assert.NoError(t, err, "not expecting an error for a configured cluster")
+	assert.Equal(t, "https://vault.prod.packers.com:8200", VaultAddress(client), "expecting the cluster's address")
+	assert.Equal(t, "lambeau", clusterID, "expecting the cluster's configured id")
+
+	_, clusterID, err = clusterClient(config.Cluster{Name: "staging", Address: "https://vault.staging.packers.com:8200"})
+	assert.NoError(t, err, "not expecting an error for a configured cluster")
+	assert.Equal(t, "https://vault.staging.packers.com:8200", clusterID, "expecting the address as id when none is configured")
+
+	_, _, err = clusterClient(config.Cluster{Name: "prod", Address: "https://vault.prod.packers.com:8200", CACert: "test-fixtures/missing-ca.pem"})
+	assert.Error(t, err, "expecting an error for a missing ca cert")
+}
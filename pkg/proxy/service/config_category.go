@@ -0,0 +1,106 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	vaultapi "github.com/hashicorp/vault/api"
+	"strings"
+)
+
+// ConfigCategory is implemented by every Vault endpoint that Configure can
+// manage from the source directory (e.g. /sys/mounts/). New endpoints are
+// supported by registering a ConfigCategory with RegisterConfigCategory,
+// without any changes to Configure itself.
+type ConfigCategory interface {
+	// Prefix is the Vault endpoint managed by the category, as found in the
+	// source directory (e.g. "/sys/mounts/").
+	Prefix() string
+
+	// Actions lists the actions supported by the category. Configuration
+	// files for any other action are ignored.
+	Actions() []ConfigActionType
+
+	// Decode unmarshals and validates the configuration file of an add or
+	// tune request. Schema violations are returned as
+	// config.ValidationErrors.
+	Decode(meta ConfigPathMeta) (interface{}, error)
+
+	// Plan is called for every category before any are applied, so that
+	// requests conflicting with the current state of Vault are rejected
+	// before Vault is changed.
+	Plan(client *vaultapi.Client, reqs ConfigRequests) error
+
+	// Apply performs the categorized requests against Vault.
+	Apply(client *vaultapi.Client, reqs ConfigRequests) error
+
+	// Report records the state of the category, after it has been applied,
+	// in ConfigState.
+	Report(client *vaultapi.Client, state *ConfigState) error
+}
+
+// ConfigRequests holds the categorized configuration files of a single
+// category, keyed by action and then by Vault path (e.g. "postgresql").
+type ConfigRequests map[ConfigActionType]map[string]ConfigPathMeta
+
+// configCategories are the Vault endpoints which may be configured from the
+// source directory. Categories are planned, applied and reported in this
+// order.
+var configCategories = []ConfigCategory{
+	sysPluginsCategory{},
+	sysMountsCategory{},
+	sysAuthCategory{},
+	sysPolicyCategory{},
+	identityEntityCategory{},
+	identityGroupCategory{},
+	identityAliasCategory{kind: "entity"},
+	identityAliasCategory{kind: "group"},
+}
+
+// RegisterConfigCategory adds a Vault endpoint to those Configure manages.
+// It is meant to be called from an init func, before any Configure request is
+// served. Registered categories are applied after the built-in ones.
+func RegisterConfigCategory(c ConfigCategory) error {
+	for _, v := range configCategories {
+		if strings.HasPrefix(c.Prefix(), v.Prefix()) || strings.HasPrefix(v.Prefix(), c.Prefix()) {
+			return fmt.Errorf("config category %q overlaps with registered category %q", c.Prefix(), v.Prefix())
+		}
+	}
+
+	configCategories = append(configCategories, c)
+	return nil
+}
+
+// Find the category managing a path of the source directory (e.g.
+// /sys/mounts/pki/).
+func configCategoryFor(path string) ConfigCategory {
+	for _, c := range configCategories {
+		if strings.HasPrefix(path, c.Prefix()) {
+			return c
+		}
+	}
+	return nil
+}
+
+func supportsAction(c ConfigCategory, action ConfigActionType) bool {
+	for _, v := range c.Actions() {
+		if v == action {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCategory records the state of a category which has no field of its own
+// in ConfigState. The state is stored as json, keyed by the category prefix.
+func (s *ConfigState) SetCategory(prefix string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if s.Categories == nil {
+		s.Categories = make(map[string]json.RawMessage)
+	}
+	s.Categories[prefix] = raw
+	return nil
+}
@@ -16,4 +16,5 @@ type ConfigPathMeta struct {
 	ConfigPath    string           `json:"config_path"`
 	Action        ConfigActionType `json:"action"`
 	File          string           `json:"file"`
+	Input         interface{}      `json:"input,omitempty"`
 }
@@ -1,16 +1,18 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/cdwlabs/armor/pkg/config"
 	getter "github.com/hashicorp/go-getter"
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/nats-io/nuid"
-	"gopkg.in/go-playground/validator.v9"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 )
 
 // validation errors
@@ -23,6 +25,7 @@ var (
 	ErrSrcStatFail               = errors.New("policy source failed being stat'd")
 	ErrSrcMalformed              = errors.New("policy source does not follow prescribed layout")
 	ErrSrcMultiJSON              = errors.New("policy source subdirectory contains more than one json file")
+	ErrSrcUnknownCategory        = errors.New("policy source file is not under a known vault configuration category")
 	ErrSrcNoValidation           = errors.New("internal state error encountered. no actions determined")
 	ErrStateSysMountAddReqEmpty  = errors.New("no valid vault configuration requests submitted for adding /sys/mounts/")
 	ErrStateSysMountUpdReqEmpty  = errors.New("no valid vault configuration requests submitted for tuning /sys/mounts/")
@@ -35,54 +38,84 @@ var (
 )
 
 // ConfigActionType describes the different types of configuration or policies
-// we apply to an unsealed instance of Vault
+// we apply to an unsealed instance of Vault. The action of a configuration
+// file follows from its directory: <category><path>/ is an add, while
+// <category><path>/tune/ and <category><path>/disable/ are a tune and a delete
+// respectively.
 type ConfigActionType int
 
 const (
-	sysMountAdd     ConfigActionType = iota // 0
-	sysMountUpdate                          // 1
-	sysAuthAdd                              // 2
-	sysAuthDelete                           // 3
-	sysPolicyAdd                            // 4
-	sysPolicyDelete                         // 5
+	ConfigAdd    ConfigActionType = iota // 0
+	ConfigTune                           // 1
+	ConfigDelete                         // 2
 )
 
+func (a ConfigActionType) String() string {
+	switch a {
+	case ConfigAdd:
+		return "add"
+	case ConfigTune:
+		return "tune"
+	case ConfigDelete:
+		return "delete"
+	}
+	return fmt.Sprintf("ConfigActionType(%d)", int(a))
+}
+
 // ConfigOptions are used to configure an unsealed Vault instance with system
 // mounts, auths and policies. Generally, configuration takes the form of
 // a URL.  Initially, this URL will support a local directory. But it is
 // designed to support Git/Mercurial repositories, AWS S3, and HTTP endpoints.
 type ConfigOptions struct {
-	URL   string `json:"url" validate:"required"`
-	Token string `json:"token" validate:"required"`
+	URL     string `json:"url" validate:"required"`
+	Token   string `json:"token" validate:"required"`
+	Cluster string `json:"cluster,omitempty"`
 }
 
 // configOptsExp contains the necessary payload for performing the actual
 // configuration updates to Vault.  It is strictly internal use only!
 type configOptsExp struct {
-	ConfigID        string                    `json:"config_id"`
-	Token           string                    `json:"token"`
-	SourceDir       string                    `json:"source_dir"`
-	Actions         []ConfigActionType        `json:"actions"`
-	SysMountAddReq  map[string]ConfigPathMeta `json:"sys_mount_add_req"`
-	SysMountUpdReq  map[string]ConfigPathMeta `json:"sys_mount_upd_req"`
-	SysAuthAddReq   map[string]ConfigPathMeta `json:"sys_auth_add_req"`
-	SysAuthDelReq   map[string]ConfigPathMeta `json:"sys_auth_del_req"`
-	SysPolicyAddReq map[string]ConfigPathMeta `json:"sys_policy_add_req"`
-	SysPolicyDelReq map[string]ConfigPathMeta `json:"sys_policy_del_req"`
+	ConfigID  string                    `json:"config_id"`
+	Token     string                    `json:"token"`
+	SourceDir string                    `json:"source_dir"`
+	Requests  map[string]ConfigRequests `json:"requests"`
 }
 
 // ConfigState represents the current state of Vault after performing
 // a config operation.
 type ConfigState struct {
-	ConfigID string                     `json:"config_id"`
-	Mounts   map[string]MountOutput     `json:"mounts"`
-	Auths    map[string]AuthMountOutput `json:"auths"`
-	Policies []string                   `json:"policies"`
+	ConfigID   string                     `json:"config_id"`
+	Mounts     map[string]MountOutput     `json:"mounts"`
+	Auths      map[string]AuthMountOutput `json:"auths"`
+	Policies   []string                   `json:"policies"`
+	Categories map[string]json.RawMessage `json:"categories,omitempty"`
 }
 
 // ensures that the Configure request payload is valid and for valid
-// payloads then retrieves the requested URL resource.
-func (opts *ConfigOptions) validate() (configOptsExp, error) {
+// payloads then retrieves the requested URL resource. The fetch and
+// categorize phases are reported to events.
+func (opts *ConfigOptions) validate(events ConfigEventFunc) (configOptsExp, error) {
+	cfgState, err := opts.fetch()
+	events.emit(ConfigEvent{ConfigID: cfgState.ConfigID, Phase: ConfigPhaseFetch}.withError(err))
+	if err != nil {
+		return cfgState, err
+	}
+
+	// categorize individual configuration files
+	err = cfgState.categorize()
+
+	// no valid requests found, that's a problem.
+	if err == nil && !cfgState.hasRequests() {
+		err = ErrSrcReqEmpty
+	}
+
+	events.emit(ConfigEvent{ConfigID: cfgState.ConfigID, Phase: ConfigPhaseCategorize}.withError(err))
+	return cfgState, err
+}
+
+// Retrieve the requested URL resource into the policy config dir, and create
+// our internal, expanded configuration options from it.
+func (opts *ConfigOptions) fetch() (configOptsExp, error) {
 	cfg := config.Config()
 	var policyConfigDir string
 	if cfg.IsSet("policy_config_dir") && cfg.GetString("policy_config_dir") != "" {
@@ -99,184 +132,311 @@ func (opts *ConfigOptions) validate() (configOptsExp, error) {
 
 	err := config.Validator().Struct(opts)
 	if err != nil {
-		validationerr := ""
-		for _, err := range err.(validator.ValidationErrors) {
-			//fmt.Printf("Error: ActualTag: %s", err.ActualTag())
-			//fmt.Printf("Error: Field: %s", err.Field())
-			//fmt.Printf("Error: Kind: %s", err.Kind())
-			//fmt.Printf("Error: Namespace: %s", err.Namespace())
-			//fmt.Printf("Error: Param: %s", err.Param())
-			//fmt.Printf("Error: StructNamespace: %s", err.StructNamespace())
-			//fmt.Printf("Error: StructField: %s", err.StructField())
-			//fmt.Printf("Error: Tag: %s", err.Tag())
-			validationerr = fmt.Sprintf("%s validation failed on '%s' check", err.Namespace(), err.Tag())
-			break
-		}
-
-		if validationerr != "" {
-			return configOptsExp{}, fmt.Errorf(validationerr)
-		}
-		return configOptsExp{}, fmt.Errorf("Invalid configuration option(s)")
+		return configOptsExp{}, config.NewValidationErrors(err)
 	}
 
 	requestid := nuid.Next()
 	srcdest := policyConfigDir + "/" + requestid
 	err = getter.Get(srcdest, opts.URL)
 	if err != nil {
-		return configOptsExp{}, err
+		return configOptsExp{ConfigID: requestid}, err
 	}
 
 	// validate source directory for correctness
 	srcdata := srcdest + "/data"
 	_, err = os.Stat(srcdata)
 	if os.IsNotExist(err) {
-		return configOptsExp{}, ErrSrcMalformed
+		return configOptsExp{ConfigID: requestid}, ErrSrcMalformed
 	} else if err != nil {
-		return configOptsExp{}, err
+		return configOptsExp{ConfigID: requestid}, err
 	}
 
 	// To continue with validation, create our internal, expanded configuration
 	// options. It's here that we determine what kind of updates are being
 	// requested.  And if no valid updates are requested, we treat that as an
 	// error condition as well.
-	cfgState := configOptsExp{
+	return configOptsExp{
 		ConfigID:  requestid,
 		Token:     opts.Token,
 		SourceDir: srcdata,
-		Actions:   make([]ConfigActionType, 0, 25),
-	}
+	}, nil
+}
 
-	// categorize individual configuration files
-	err = cfgState.categorize()
+// Categorize individual configuration files. Every problem found with the
+// source (e.g. malformed directories or json) is collected and returned as
+// config.ValidationErrors.
+func (opts *configOptsExp) categorize() error {
+	metaset, err := filesByExt(opts.SourceDir, ".json")
 	if err != nil {
-		return cfgState, err
+		return err
 	}
 
-	// no valid requests found, that's a problem.
-	if len(cfgState.Actions) == 0 {
-		return cfgState, ErrSrcReqEmpty
+	errs := checkLayout(metaset)
+
+	opts.Requests = make(map[string]ConfigRequests)
+	for _, meta := range metaset {
+		opts.addRequest(meta)
+	}
+
+	errs = append(errs, opts.decodeRequests()...)
+	if len(errs) > 0 {
+		sort.Stable(byFile(errs))
+		return errs
 	}
 
-	return cfgState, nil
+	return nil
 }
 
-// Perform any configuration updates to Vault.
-func (opts *configOptsExp) categorize() error {
-	metaset, err := filesByExt(opts.SourceDir, ".json")
-	if err != nil {
-		return err
+// Determine the category, action and Vault path of a configuration file and
+// record it as a request. Files which follow no supported action of their
+// category are skipped (layout problems are reported by checkLayout).
+func (opts *configOptsExp) addRequest(meta ConfigPathMeta) {
+	// literals describing type of action
+	tunelit := "/tune/"
+	disablelit := "/disable/"
+	addlit := "/"
+
+	// substring one - split file from full path
+	subsone, _ := filepath.Split(meta.FullPath)
+
+	// substring two - strip base from beginning of string
+	substwo := subsone[len(meta.BasePath):]
+
+	category := configCategoryFor(substwo)
+	if category == nil {
+		return
 	}
 
-	// find /sys/mounts/
-	err = opts.findSysMounts(metaset)
-	if err != nil {
-		return err
+	// substring three - strip category from beginning of string. And
+	// substring four - the vault path, once the type of update (e.g. tune or
+	// disable) is removed
+	substhree := substwo[len(category.Prefix()):]
+	subsfour := ""
+	meta.VaultEndPoint = category.Prefix()
+
+	if strings.HasSuffix(substhree, tunelit) {
+		subsfour = substhree[:len(substhree)-len(tunelit)]
+		meta.Action = ConfigTune
+
+	} else if strings.HasSuffix(substhree, disablelit) {
+		subsfour = substhree[:len(substhree)-len(disablelit)]
+		meta.Action = ConfigDelete
+
+	} else if strings.HasSuffix(substhree, addlit) {
+		subsfour = substhree[:len(substhree)-len(addlit)]
+		meta.Action = ConfigAdd
+
+	} else {
+		// a file directly under the category
+		return
 	}
 
-	// find /sys/auth/
-	err = opts.findSysAuths(metaset)
-	if err != nil {
-		return err
+	if !supportsAction(category, meta.Action) {
+		return
 	}
+	meta.ConfigPath = subsfour
 
-	// find /sys/policy/
-	err = opts.findSysPolicies(metaset)
-	return err
+	reqs, ok := opts.Requests[category.Prefix()]
+	if !ok {
+		reqs = make(ConfigRequests)
+		opts.Requests[category.Prefix()] = reqs
+	}
+	if reqs[meta.Action] == nil {
+		reqs[meta.Action] = make(map[string]ConfigPathMeta)
+	}
+	reqs[meta.Action][subsfour] = meta
 }
 
-// Perform any configuration updates to Vault.
-func (opts *configOptsExp) handleRequests(client *vaultapi.Client) (ConfigState, error) {
+// Return the requests of a category for a single action.
+func (opts *configOptsExp) requests(prefix string, action ConfigActionType) map[string]ConfigPathMeta {
+	return opts.Requests[prefix][action]
+}
 
-	state := ConfigState{
-		ConfigID: opts.ConfigID,
+func (opts *configOptsExp) hasRequests() bool {
+	for _, reqs := range opts.Requests {
+		for _, paths := range reqs {
+			if len(paths) > 0 {
+				return true
+			}
+		}
 	}
+	return false
+}
 
-	if opts.hasSysMountRequests() {
-		mounts, err := opts.handleSysMounts(client)
-		if err != nil {
-			return state, err
+// Verify each configuration file is found where categorize can make sense of
+// it: under a known category, in a subdirectory naming the Vault path, and
+// alone in that subdirectory.
+func checkLayout(metaset []ConfigPathMeta) config.ValidationErrors {
+	var errs config.ValidationErrors
+	dirs := make(map[string][]string)
+
+	for _, meta := range metaset {
+		subsone, _ := filepath.Split(meta.FullPath)
+		substwo := subsone[len(meta.BasePath):]
+		dirs[substwo] = append(dirs[substwo], meta.File)
+
+		category := configCategoryFor(substwo)
+
+		if category == nil {
+			errs = append(errs, config.ValidationError{
+				File:    substwo + meta.File,
+				Message: ErrSrcUnknownCategory.Error(),
+			})
+		} else if substwo == category.Prefix() {
+			errs = append(errs, config.ValidationError{
+				File:    substwo + meta.File,
+				Message: ErrSrcMalformed.Error(),
+			})
 		}
-		state.Mounts = mounts
 	}
 
-	if opts.hasSysAuthRequests() {
-		auths, err := opts.handleSysAuths(client)
-		if err != nil {
-			return state, err
+	for dir, files := range dirs {
+		if len(files) > 1 {
+			errs = append(errs, config.ValidationError{
+				File:    dir,
+				Message: fmt.Sprintf("%s: %s", ErrSrcMultiJSON.Error(), strings.Join(files, ", ")),
+			})
 		}
-		state.Auths = auths
 	}
 
-	if opts.hasSysPolicyRequests() {
-		policies, err := opts.handleSysPolicies(client)
-		if err != nil {
-			return state, err
+	return errs
+}
+
+// Decode every categorized configuration file, so that all malformed json is
+// reported before any change is made to Vault. Deletes are made by path name
+// only, so their files are not decoded.
+func (opts *configOptsExp) decodeRequests() config.ValidationErrors {
+	var errs config.ValidationErrors
+
+	for _, category := range configCategories {
+		reqs := opts.Requests[category.Prefix()]
+
+		for _, action := range []ConfigActionType{ConfigAdd, ConfigTune} {
+			for path, meta := range reqs[action] {
+				input, err := category.Decode(meta)
+				if verrs, ok := err.(config.ValidationErrors); ok {
+					errs = append(errs, schemaValidationErrors(meta, verrs)...)
+					continue
+				} else if err != nil {
+					errs = append(errs, decodeValidationError(meta, err))
+					continue
+				}
+
+				meta.Input = input
+				reqs[action][path] = meta
+			}
 		}
-		state.Policies = policies
 	}
 
-	return state, nil
+	return errs
 }
 
-func (opts *configOptsExp) dumpMeta() error {
+// Attribute schema violations to the file they were found in.
+func schemaValidationErrors(meta ConfigPathMeta, errs config.ValidationErrors) config.ValidationErrors {
+	for i := range errs {
+		errs[i].File = meta.FullPath[len(meta.BasePath):]
+	}
+	return errs
+}
 
-	fmt.Println("")
-	fmt.Println("/sys/mounts/ adds:")
-	if len(opts.SysMountAddReq) == 0 {
-		fmt.Println("no requests found")
-	} else {
-		for path, meta := range opts.SysMountAddReq {
-			fmt.Printf("path: %q\n\tfullpath: %q\n\tbase: %q\n\tvault endpoint: %q\n\taction: %d\n\tpath: %q\n\tfile: %q\n", path, meta.FullPath, meta.BasePath, meta.VaultEndPoint, meta.Action, meta.ConfigPath, meta.File)
-		}
+// Describe a json decoding error, including the offending byte offset when
+// encoding/json reports one.
+func decodeValidationError(meta ConfigPathMeta, err error) config.ValidationError {
+	verr := config.ValidationError{
+		File:    meta.FullPath[len(meta.BasePath):],
+		Message: err.Error(),
 	}
 
-	fmt.Println("")
-	fmt.Println("/sys/mounts/ tune:")
-	if len(opts.SysMountUpdReq) == 0 {
-		fmt.Println("no requests found")
-	} else {
-		for path, meta := range opts.SysMountUpdReq {
-			fmt.Printf("path: %q\n\tfullpath: %q\n\tbase: %q\n\tvault endpoint: %q\n\taction: %d\n\tpath: %q\n\tfile: %q\n", path, meta.FullPath, meta.BasePath, meta.VaultEndPoint, meta.Action, meta.ConfigPath, meta.File)
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		verr.Offset = e.Offset
+	case *json.UnmarshalTypeError:
+		verr.Offset = e.Offset
+	}
+
+	return verr
+}
+
+// byFile sorts validation errors by file, so they are reported in
+// a predictable order.
+type byFile config.ValidationErrors
+
+func (a byFile) Len() int           { return len(a) }
+func (a byFile) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byFile) Less(i, j int) bool { return a[i].File < a[j].File }
+
+// Perform any configuration updates to Vault. Each category is planned, and
+// then applied one action at a time, in the order of its Actions; every plan
+// and action is reported to events.
+func (opts *configOptsExp) handleRequests(client *vaultapi.Client, events ConfigEventFunc) (ConfigState, error) {
+
+	state := ConfigState{
+		ConfigID: opts.ConfigID,
+	}
+
+	// categories with requests, in the order they are applied
+	var categories []ConfigCategory
+	for _, category := range configCategories {
+		if len(opts.Requests[category.Prefix()]) > 0 {
+			categories = append(categories, category)
 		}
 	}
 
-	fmt.Println("")
-	fmt.Println("/sys/auth/ adds:")
-	if len(opts.SysAuthAddReq) == 0 {
-		fmt.Println("no requests found")
-	} else {
-		for path, meta := range opts.SysAuthAddReq {
-			fmt.Printf("path: %q\n\tfullpath: %q\n\tbase: %q\n\tvault endpoint: %q\n\taction: %d\n\tpath: %q\n\tfile: %q\n", path, meta.FullPath, meta.BasePath, meta.VaultEndPoint, meta.Action, meta.ConfigPath, meta.File)
+	for _, category := range categories {
+		err := category.Plan(client, opts.Requests[category.Prefix()])
+		events.emit(ConfigEvent{ConfigID: opts.ConfigID, Phase: ConfigPhasePlan, Category: category.Prefix()}.withError(err))
+		if err != nil {
+			return state, err
 		}
 	}
 
-	fmt.Println("")
-	fmt.Println("/sys/auth/ disable:")
-	if len(opts.SysAuthDelReq) == 0 {
-		fmt.Println("no requests found")
-	} else {
-		for path, meta := range opts.SysAuthDelReq {
-			fmt.Printf("path: %q\n\tfullpath: %q\n\tbase: %q\n\tvault endpoint: %q\n\taction: %d\n\tpath: %q\n\tfile: %q\n", path, meta.FullPath, meta.BasePath, meta.VaultEndPoint, meta.Action, meta.ConfigPath, meta.File)
+	for _, category := range categories {
+		for _, action := range category.Actions() {
+			reqs := opts.requests(category.Prefix(), action)
+			if len(reqs) == 0 {
+				continue
+			}
+
+			err := category.Apply(client, ConfigRequests{action: reqs})
+			events.emit(ConfigEvent{
+				ConfigID: opts.ConfigID,
+				Phase:    ConfigPhaseApply,
+				Category: category.Prefix(),
+				Action:   action.String(),
+				Paths:    configPaths(reqs),
+			}.withError(err))
+			if err != nil {
+				return state, err
+			}
 		}
 	}
 
-	fmt.Println("")
-	fmt.Println("/sys/policy/ adds:")
-	if len(opts.SysPolicyAddReq) == 0 {
-		fmt.Println("no requests found")
-	} else {
-		for path, meta := range opts.SysPolicyAddReq {
-			fmt.Printf("path: %q\n\tfullpath: %q\n\tbase: %q\n\tvault endpoint: %q\n\taction: %d\n\tpath: %q\n\tfile: %q\n", path, meta.FullPath, meta.BasePath, meta.VaultEndPoint, meta.Action, meta.ConfigPath, meta.File)
+	for _, category := range categories {
+		err := category.Report(client, &state)
+		if err != nil {
+			return state, err
 		}
 	}
 
-	fmt.Println("")
-	fmt.Println("/sys/policy/ disable:")
-	if len(opts.SysPolicyDelReq) == 0 {
-		fmt.Println("no requests found")
-	} else {
-		for path, meta := range opts.SysPolicyDelReq {
-			fmt.Printf("path: %q\n\tfullpath: %q\n\tbase: %q\n\tvault endpoint: %q\n\taction: %d\n\tpath: %q\n\tfile: %q\n", path, meta.FullPath, meta.BasePath, meta.VaultEndPoint, meta.Action, meta.ConfigPath, meta.File)
+	return state, nil
+}
+
+func (opts *configOptsExp) dumpMeta() error {
+
+	for _, category := range configCategories {
+		for _, action := range category.Actions() {
+			fmt.Println("")
+			fmt.Printf("%s %s:\n", category.Prefix(), action)
+
+			reqs := opts.requests(category.Prefix(), action)
+			if len(reqs) == 0 {
+				fmt.Println("no requests found")
+				continue
+			}
+
+			for path, meta := range reqs {
+				fmt.Printf("path: %q\n\tfullpath: %q\n\tbase: %q\n\tvault endpoint: %q\n\taction: %s\n\tpath: %q\n\tfile: %q\n", path, meta.FullPath, meta.BasePath, meta.VaultEndPoint, meta.Action, meta.ConfigPath, meta.File)
+			}
 		}
 	}
 
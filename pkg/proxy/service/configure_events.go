@@ -0,0 +1,85 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/cdwlabs/armor/pkg/config"
+)
+
+// ConfigPhase names a phase of a Configure run.
+type ConfigPhase string
+
+const (
+	// ConfigPhaseFetch retrieves the configuration files from their URL.
+	ConfigPhaseFetch ConfigPhase = "fetch"
+
+	// ConfigPhaseCategorize sorts and decodes the configuration files.
+	ConfigPhaseCategorize ConfigPhase = "categorize"
+
+	// ConfigPhasePlan checks the requests of a category against Vault.
+	ConfigPhasePlan ConfigPhase = "plan"
+
+	// ConfigPhaseApply performs the requests of a category for one action.
+	ConfigPhaseApply ConfigPhase = "apply"
+
+	// ConfigPhaseDone ends every run, with the resulting ConfigState or the
+	// error which stopped it.
+	ConfigPhaseDone ConfigPhase = "done"
+)
+
+// ConfigEvent reports the progress of a Configure run: the outcome of each
+// phase, and of each action applied to a category.
+type ConfigEvent struct {
+	ConfigID string                  `json:"config_id,omitempty"`
+	Phase    ConfigPhase             `json:"phase"`
+	Category string                  `json:"category,omitempty"` // plan and apply only
+	Action   string                  `json:"action,omitempty"`   // apply only
+	Paths    []string                `json:"paths,omitempty"`    // apply only, the Vault paths of the action
+	Failed   bool                    `json:"failed"`
+	Err      string                  `json:"error,omitempty"`
+	Errors   config.ValidationErrors `json:"errors,omitempty"`
+	State    *ConfigState            `json:"state,omitempty"` // done only, unless failed
+}
+
+// ConfigEventFunc receives the events of a Configure run as they happen.
+type ConfigEventFunc func(ConfigEvent)
+
+func (f ConfigEventFunc) emit(e ConfigEvent) {
+	if f != nil {
+		f(e)
+	}
+}
+
+// withError marks the event failed by err, if set.
+func (e ConfigEvent) withError(err error) ConfigEvent {
+	if err == nil {
+		return e
+	}
+	e.Failed = true
+	e.Err = err.Error()
+	if errs, ok := err.(config.ValidationErrors); ok {
+		e.Errors = errs
+	}
+	return e
+}
+
+// Failure returns the error which failed the event, if any.
+func (e ConfigEvent) Failure() error {
+	if !e.Failed {
+		return nil
+	}
+	if len(e.Errors) > 0 {
+		return e.Errors
+	}
+	return String2Error(e.Err)
+}
+
+// configPaths returns the Vault paths of the requests of an action, sorted.
+func configPaths(reqs map[string]ConfigPathMeta) []string {
+	paths := make([]string, 0, len(reqs))
+	for path := range reqs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -1,10 +1,13 @@
 package service
 
 import (
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
 	"github.com/cdwlabs/armor/pkg/config"
+	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
+	"time"
 )
 
 func setUp(t *testing.T) {
@@ -31,7 +34,7 @@ func TestConfigOptions_Validate_DestDir(t *testing.T) {
 		Token: "nbkd193dnakd1ueadf3",
 	}
 
-	_, err := opts.validate()
+	_, err := opts.validate(nil)
 	if assert.Error(t, err, "expecting an error when policy config dir does not exist") {
 		assert.Contains(t, err.Error(), "policy download dest does not exist", "expecting error type of 'policy config dir does not exist'")
 	}
@@ -44,7 +47,7 @@ func TestConfigOptions_Validate_SourceDir(t *testing.T) {
 
 	// missing source url
 	opts := &ConfigOptions{}
-	_, err := opts.validate()
+	_, err := opts.validate(nil)
 	if assert.Error(t, err, "expecting an error when policy config src url is not set") {
 		assert.Contains(t, err.Error(), "ConfigOptions.URL validation failed on 'required' check", "expecting URL validation error for missing URL")
 	}
@@ -53,7 +56,7 @@ func TestConfigOptions_Validate_SourceDir(t *testing.T) {
 	opts = &ConfigOptions{
 		URL: "",
 	}
-	_, err = opts.validate()
+	_, err = opts.validate(nil)
 	if assert.Error(t, err, "expecting an error when policy config src url is empty") {
 		assert.Contains(t, err.Error(), "ConfigOptions.URL validation failed on 'required' check", "expecting URL validation error for empty URL")
 	}
@@ -64,14 +67,14 @@ func TestConfigOptions_Validate_SourceDir(t *testing.T) {
 		URL: cwd + missingdir,
 	}
 
-	_, err = opts.validate()
+	_, err = opts.validate(nil)
 	if assert.Error(t, err, "expecting an error when policy config src token is empty") {
 		assert.Contains(t, err.Error(), "ConfigOptions.Token validation failed on 'required' check", "expecting Token validation error for missing token")
 	}
 
 	// non-existent source directory
 	opts.Token = "nbkd193dnakd1ueadf3"
-	_, err = opts.validate()
+	_, err = opts.validate(nil)
 	if assert.Error(t, err, "expecting an error when policy config src does not exist") {
 		assert.Contains(t, err.Error(), missingdir+": no such file or directory", "expecting error type of 'no such file or directory'")
 	}
@@ -95,27 +98,343 @@ func TestConfigOptions_Validate_SourceDir(t *testing.T) {
 		URL:   cwd + initialmntsdir,
 		Token: "nbkd193dnakd1ueadf3",
 	}
-	state, err := opts.validate()
+	state, err := opts.validate(nil)
 	assert.NoError(t, err, "not expecting an error when policy config src directory is well formed initial mounts")
 	state.dumpMeta()
 
-	assert.True(t, state.hasSysMountRequests(), "expecting mount requests")
-	postgresql, ok := state.SysMountAddReq["postgresql"]
+	assert.True(t, state.hasRequests(), "expecting mount requests")
+	postgresql, ok := state.requests("/sys/mounts/", ConfigAdd)["postgresql"]
 	assert.True(t, ok, "expecting to find request")
 	assert.Contains(t, postgresql.FullPath, "data/sys/mounts/postgresql/postgresql.json", "expecting to find fullpath")
-	assert.Equal(t, sysMountAdd, postgresql.Action, "expecting a match on action")
+	assert.Equal(t, ConfigAdd, postgresql.Action, "expecting a match on action")
 	assert.Equal(t, "/sys/mounts/", postgresql.VaultEndPoint, "expecting match for vault endpoint")
 	assert.Equal(t, "postgresql", postgresql.ConfigPath, "expecting match on config path")
 	_, err = deserializeMountInput(postgresql.FullPath)
 	assert.NoError(t, err, "not expecting an error when deserializing json config")
 
-	aws, ok := state.SysMountAddReq["aws"]
+	aws, ok := state.requests("/sys/mounts/", ConfigAdd)["aws"]
 	assert.True(t, ok, "expecting to find request")
 	assert.Contains(t, aws.FullPath, "data/sys/mounts/aws/aws.json", "expecting to find fullpath")
-	assert.Equal(t, sysMountAdd, aws.Action, "expecting a match on action")
+	assert.Equal(t, ConfigAdd, aws.Action, "expecting a match on action")
 	assert.Equal(t, "/sys/mounts/", aws.VaultEndPoint, "expecting match for vault endpoint")
 	assert.Equal(t, "aws", aws.ConfigPath, "expecting match on config path")
 	_, err = deserializeMountInput(aws.FullPath)
 	assert.NoError(t, err, "not expecting an error when deserializing json config")
 
 }
+
+func TestConfigOptions_Validate_Events(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+	cwd, _ := os.Getwd()
+
+	var events []ConfigEvent
+	record := func(e ConfigEvent) { events = append(events, e) }
+
+	// failed fetch ends the run
+	opts := &ConfigOptions{URL: cwd + "/test-fixtures/configure/initialmounts"}
+	_, err := opts.validate(record)
+	assert.Error(t, err, "expecting an error when policy config src token is empty")
+	if assert.Len(t, events, 1, "expecting a fetch event only") {
+		assert.Equal(t, ConfigPhaseFetch, events[0].Phase, "expecting a fetch event")
+		assert.True(t, events[0].Failed, "expecting the fetch to fail")
+		assert.Equal(t, err.Error(), events[0].Err, "expecting the error of the fetch")
+	}
+
+	events = nil
+	opts.Token = "nbkd193dnakd1ueadf3"
+	state, err := opts.validate(record)
+	assert.NoError(t, err, "not expecting an error when policy config src directory is well formed initial mounts")
+	if assert.Len(t, events, 2, "expecting fetch and categorize events") {
+		assert.Equal(t, ConfigPhaseFetch, events[0].Phase, "expecting a fetch event")
+		assert.Equal(t, ConfigPhaseCategorize, events[1].Phase, "expecting a categorize event")
+		for _, e := range events {
+			assert.False(t, e.Failed, "not expecting a failed event")
+			assert.Equal(t, state.ConfigID, e.ConfigID, "expecting events of the run's config id")
+			assert.NoError(t, e.Failure(), "not expecting a failure")
+		}
+	}
+}
+
+func TestConfigOptions_Categorize_CollectsErrors(t *testing.T) {
+	cwd, _ := os.Getwd()
+	opts := &configOptsExp{
+		SourceDir: cwd + "/test-fixtures/configure/invalid/data",
+	}
+
+	err := opts.categorize()
+	if assert.Error(t, err, "expecting an error when categorizing an invalid source directory") {
+		errs, ok := err.(config.ValidationErrors)
+		if assert.True(t, ok, "expecting config.ValidationErrors") {
+			assert.Len(t, errs, 5, "expecting every problem to be reported")
+
+			assert.Equal(t, "/sys/auth/github/github.json", errs[0].File, "expecting json type error for github auth")
+			assert.True(t, errs[0].Offset > 0, "expecting an offset for json type error")
+
+			assert.Equal(t, "/sys/mounts/aws/aws.json", errs[1].File, "expecting json syntax error for aws mount")
+			assert.True(t, errs[1].Offset > 0, "expecting an offset for json syntax error")
+
+			assert.Equal(t, "/sys/mounts/pki/", errs[2].File, "expecting multiple json files error for pki mount")
+			assert.Contains(t, errs[2].Message, ErrSrcMultiJSON.Error(), "expecting multiple json files error")
+
+			assert.Equal(t, "/sys/mounts/stray.json", errs[3].File, "expecting malformed layout error")
+			assert.Equal(t, ErrSrcMalformed.Error(), errs[3].Message, "expecting malformed layout error")
+
+			assert.Equal(t, "/sys/unknown/thing/thing.json", errs[4].File, "expecting unknown category error")
+			assert.Equal(t, ErrSrcUnknownCategory.Error(), errs[4].Message, "expecting unknown category error")
+		}
+	}
+}
+
+func TestInitOptions_Validate_CollectsErrors(t *testing.T) {
+	opts := &InitOptions{
+		SecretShares:          2,
+		RootTokenHolderEmail:  "not-an-email",
+		SecretKeyHolderEmails: []string{"one@example.com"},
+	}
+
+	err := opts.validate()
+	if assert.Error(t, err, "expecting an error when init options are invalid") {
+		errs, ok := err.(config.ValidationErrors)
+		if assert.True(t, ok, "expecting config.ValidationErrors") {
+			assert.Len(t, errs, 2, "expecting every problem to be reported")
+		}
+		assert.Contains(t, err.Error(), "InitOptions.RootTokenHolderEmail validation failed on 'email' check", "expecting email validation error")
+		assert.Contains(t, err.Error(), "InitOptions.SecretKeyHolderEmails validation failed on 'secretholders' check", "expecting secret holders validation error")
+	}
+}
+
+func TestInitOptions_Validate_RecoveryKeyHolders(t *testing.T) {
+	opts := &InitOptions{
+		SecretShares:            1,
+		RecoveryShares:          2,
+		RootTokenHolderEmail:    "aaron.rodgers@packers.com",
+		SecretKeyHolderEmails:   []string{"david.bakhtiari@packers.com"},
+		RecoveryKeyHolderEmails: []string{"bryan.bulaga@packers.com"},
+	}
+
+	err := opts.validate()
+	if assert.Error(t, err, "expecting an error when recovery key holders don't match recovery shares") {
+		assert.Contains(t, err.Error(), "InitOptions.RecoveryKeyHolderEmails validation failed on 'recoveryholders' check", "expecting recovery holders validation error")
+	}
+
+	opts.RecoveryKeyHolderEmails = append(opts.RecoveryKeyHolderEmails, "jordy.nelson@packers.com")
+	err = opts.validate()
+	assert.NoError(t, err, "not expecting an error when every recovery share has a holder")
+
+	opts.RecoveryShares = 0
+	err = opts.validate()
+	assert.Error(t, err, "expecting an error for recovery key holders without recovery shares")
+}
+
+// holderKeys is a HolderKeyRegistry of fake keys
+type holderKeys map[string]string
+
+func (r holderKeys) Lookup(email string) (*dbackend.HolderKey, error) {
+	key, ok := r[email]
+	if !ok {
+		return nil, dbackend.ErrHolderKeyNotFound
+	}
+	return &dbackend.HolderKey{Email: email, Vault: key}, nil
+}
+
+func TestInitOptions_ApplyHolderKeys(t *testing.T) {
+	registry := holderKeys{
+		"aaron.rodgers@packers.com":   "rodgers-key",
+		"david.bakhtiari@packers.com": "bakhtiari-key",
+		"bryan.bulaga@packers.com":    "bulaga-key",
+	}
+	newOpts := func(holders ...string) *InitOptions {
+		return &InitOptions{
+			SecretShares:          len(holders),
+			RootTokenHolderEmail:  "aaron.rodgers@packers.com",
+			SecretKeyHolderEmails: holders,
+		}
+	}
+
+	opts := newOpts("david.bakhtiari@packers.com", "bryan.bulaga@packers.com")
+	err := opts.applyHolderKeys(registry, true)
+	assert.NoError(t, err, "not expecting an error when every holder has a key")
+	assert.Equal(t, []string{"bakhtiari-key", "bulaga-key"}, opts.PGPKeys, "expecting pgp_keys in holder order")
+	assert.Equal(t, "rodgers-key", opts.RootTokenPGPKey, "expecting root_token_pgp_key")
+
+	opts = newOpts("david.bakhtiari@packers.com")
+	opts.PGPKeys = []string{"given-key"}
+	err = opts.applyHolderKeys(registry, false)
+	assert.NoError(t, err, "not expecting an error when pgp_keys are given")
+	assert.Equal(t, []string{"given-key"}, opts.PGPKeys, "expecting given pgp_keys to be kept")
+
+	opts = newOpts("david.bakhtiari@packers.com", "jordy.nelson@packers.com")
+	err = opts.applyHolderKeys(registry, false)
+	assert.NoError(t, err, "not expecting an error for a missing key unless enforced")
+	assert.Len(t, opts.PGPKeys, 0, "expecting no pgp_keys unless every holder has a key")
+
+	opts = newOpts("david.bakhtiari@packers.com", "jordy.nelson@packers.com")
+	err = opts.applyHolderKeys(registry, true)
+	if assert.Error(t, err, "expecting an error for a missing key when enforced") {
+		assert.Contains(t, err.Error(), "none registered for jordy.nelson@packers.com", "expecting the holder without key")
+	}
+
+	opts = newOpts("david.bakhtiari@packers.com")
+	opts.RecoveryShares = 2
+	opts.RecoveryKeyHolderEmails = []string{"bryan.bulaga@packers.com", "aaron.rodgers@packers.com"}
+	err = opts.applyHolderKeys(registry, true)
+	assert.NoError(t, err, "not expecting an error when every recovery key holder has a key")
+	assert.Equal(t, []string{"bulaga-key", "rodgers-key"}, opts.RecoveryPGPKeys, "expecting recovery_pgp_keys in holder order")
+
+	opts = newOpts("david.bakhtiari@packers.com")
+	opts.RecoveryShares = 1
+	opts.RecoveryKeyHolderEmails = []string{"jordy.nelson@packers.com"}
+	err = opts.applyHolderKeys(registry, true)
+	if assert.Error(t, err, "expecting an error for a recovery key holder without key when enforced") {
+		assert.Contains(t, err.Error(), "recovery key holder; none registered for jordy.nelson@packers.com", "expecting the recovery key holder without key")
+	}
+
+	opts = newOpts("david.bakhtiari@packers.com")
+	err = opts.applyHolderKeys(nil, true)
+	if assert.Error(t, err, "expecting an error without registry when enforced") {
+		errs, ok := err.(config.ValidationErrors)
+		if assert.True(t, ok, "expecting config.ValidationErrors") {
+			assert.Len(t, errs, 2, "expecting both pgp_keys and root_token_pgp_key to be reported")
+		}
+	}
+}
+
+func TestConfigOptions_Categorize_Schema(t *testing.T) {
+	cwd, _ := os.Getwd()
+	opts := &configOptsExp{
+		SourceDir: cwd + "/test-fixtures/configure/schema/data",
+	}
+
+	err := opts.categorize()
+	if assert.Error(t, err, "expecting an error when categorizing documents that violate the schema") {
+		errs, ok := err.(config.ValidationErrors)
+		if assert.True(t, ok, "expecting config.ValidationErrors") {
+			assert.Len(t, errs, 5, "expecting every schema violation to be reported")
+
+			assert.Equal(t, "/sys/auth/ldap/ldap.json", errs[0].File, "expecting missing type for ldap auth")
+			assert.Equal(t, "type", errs[0].Field, "expecting missing type for ldap auth")
+			assert.Equal(t, "/sys/auth/ldap/ldap.json", errs[1].File, "expecting bad ttl for ldap auth")
+			assert.Equal(t, "config.max_lease_ttl", errs[1].Field, "expecting bad ttl for ldap auth")
+
+			assert.Equal(t, "/sys/mounts/pki/tune/pki.json", errs[2].File, "expecting ttl ordering error for pki tune")
+			assert.Equal(t, "max_lease_ttl", errs[2].Field, "expecting ttl ordering error for pki tune")
+
+			assert.Equal(t, "/sys/mounts/secret2/generic.json", errs[3].File, "expecting unknown field error for secret2 mount")
+			assert.Contains(t, errs[3].Message, "descripton", "expecting unknown field to be named")
+
+			assert.Equal(t, "/sys/mounts/transit/transit.json", errs[4].File, "expecting unknown type for transit mount")
+			assert.Equal(t, "type", errs[4].Field, "expecting unknown type for transit mount")
+		}
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	d, err := parseTTL("21")
+	assert.NoError(t, err, "expecting whole seconds to parse")
+	assert.Equal(t, 21*time.Second, d, "expecting a match on ttl")
+
+	d, err = parseTTL("1h30m")
+	assert.NoError(t, err, "expecting a duration to parse")
+	assert.Equal(t, 90*time.Minute, d, "expecting a match on ttl")
+
+	_, err = parseTTL("-5")
+	assert.Error(t, err, "expecting an error for a negative ttl")
+}
+
+type testCategory struct{}
+
+func (testCategory) Prefix() string              { return "/sys/audit/" }
+func (testCategory) Actions() []ConfigActionType { return []ConfigActionType{ConfigAdd} }
+func (testCategory) Decode(meta ConfigPathMeta) (interface{}, error) {
+	return meta.File, nil
+}
+func (testCategory) Plan(client *vaultapi.Client, reqs ConfigRequests) error  { return nil }
+func (testCategory) Apply(client *vaultapi.Client, reqs ConfigRequests) error { return nil }
+func (testCategory) Report(client *vaultapi.Client, state *ConfigState) error {
+	return state.SetCategory("/sys/audit/", []string{"file"})
+}
+
+func TestRegisterConfigCategory(t *testing.T) {
+	builtin := configCategories
+	defer func() { configCategories = builtin }()
+
+	err := RegisterConfigCategory(sysMountsCategory{})
+	assert.Error(t, err, "expecting an error when registering a category twice")
+
+	err = RegisterConfigCategory(testCategory{})
+	assert.NoError(t, err, "not expecting an error when registering a new category")
+
+	cwd, _ := os.Getwd()
+	opts := &configOptsExp{SourceDir: cwd + "/test-fixtures/configure/category/data"}
+	err = opts.categorize()
+	assert.NoError(t, err, "not expecting an error when categorizing a registered category")
+
+	file, ok := opts.requests("/sys/audit/", ConfigAdd)["file"]
+	assert.True(t, ok, "expecting to find request for registered category")
+	assert.Equal(t, "file.json", file.Input, "expecting request to be decoded by registered category")
+	assert.Empty(t, opts.requests("/sys/audit/", ConfigTune), "expecting unsupported actions to be ignored")
+
+	state := ConfigState{}
+	err = testCategory{}.Report(nil, &state)
+	assert.NoError(t, err, "not expecting an error when reporting category state")
+	assert.Equal(t, `["file"]`, string(state.Categories["/sys/audit/"]), "expecting category state as json")
+}
+
+func TestConfigOptions_Categorize_Identity(t *testing.T) {
+	cwd, _ := os.Getwd()
+	opts := &configOptsExp{
+		SourceDir: cwd + "/test-fixtures/configure/identity/data",
+	}
+
+	err := opts.categorize()
+	assert.NoError(t, err, "not expecting an error when categorizing identity source directory")
+
+	jdoe, ok := opts.requests("/identity/entity/", ConfigAdd)["jdoe"]
+	if assert.True(t, ok, "expecting to find entity request") {
+		assert.Equal(t, []string{"default"}, jdoe.Input.(IdentityEntityInput).Policies, "expecting a match on entity policies")
+	}
+
+	engineering, ok := opts.requests("/identity/group/", ConfigAdd)["engineering"]
+	if assert.True(t, ok, "expecting to find group request") {
+		groupIn := engineering.Input.(IdentityGroupInput)
+		assert.Equal(t, []string{"jdoe"}, groupIn.MemberEntityNames, "expecting member entities by name")
+		assert.Equal(t, []string{"admins"}, groupIn.MemberGroupNames, "expecting member groups by name")
+	}
+
+	admins, ok := opts.requests("/identity/group-alias/", ConfigAdd)["ldap/admins"]
+	if assert.True(t, ok, "expecting to find group alias request") {
+		assert.Equal(t, "admins", admins.Input.(IdentityAliasInput).CanonicalName, "expecting a match on canonical name")
+		mountPath, name := splitConfigPath(admins.ConfigPath)
+		assert.Equal(t, "ldap", mountPath, "expecting alias auth backend path")
+		assert.Equal(t, "admins", name, "expecting alias name")
+	}
+
+	_, ok = opts.requests("/identity/entity-alias/", ConfigAdd)["github/jdoe"]
+	assert.True(t, ok, "expecting to find entity alias request")
+}
+
+func TestConfigOptions_Categorize_Plugins(t *testing.T) {
+	cwd, _ := os.Getwd()
+	os.Setenv(config.VaultPluginDirEnvVar, cwd+"/test-fixtures/configure/plugins/bin")
+	defer os.Unsetenv(config.VaultPluginDirEnvVar)
+
+	opts := &configOptsExp{SourceDir: cwd + "/test-fixtures/configure/plugins/data"}
+	err := opts.categorize()
+	assert.NoError(t, err, "not expecting an error when plugin sha256 matches its binary")
+
+	myplugin, ok := opts.requests("/sys/plugins/catalog/", ConfigAdd)["database/myplugin"]
+	if assert.True(t, ok, "expecting to find plugin request") {
+		assert.Equal(t, "myplugin", myplugin.Input.(PluginInput).Command, "expecting command to default to plugin name")
+	}
+	assert.Equal(t, "/sys/plugins/catalog/", configCategories[0].Prefix(), "expecting plugins to be registered before mounts and auths")
+
+	opts = &configOptsExp{SourceDir: cwd + "/test-fixtures/configure/plugins/mismatch"}
+	err = opts.categorize()
+	if assert.Error(t, err, "expecting an error when plugin sha256 does not match its binary") {
+		errs, ok := err.(config.ValidationErrors)
+		if assert.True(t, ok, "expecting config.ValidationErrors") && assert.Len(t, errs, 1, "expecting a single error") {
+			assert.Equal(t, "sha256", errs[0].Field, "expecting sha256 mismatch")
+		}
+	}
+}
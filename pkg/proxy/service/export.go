@@ -0,0 +1,266 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cdwlabs/armor/pkg/config"
+	vaultapi "github.com/hashicorp/vault/api"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// export errors
+var (
+	ErrExportDestExists    = errors.New("export destination already contains a data directory")
+	ErrExportAmbiguousPath = errors.New("vault path cannot be exported without being mistaken for an action (e.g. tune or disable)")
+)
+
+// These mounts, auth backends and policies come with every Vault instance.
+// They are excluded from an export unless explicitly requested.
+var (
+	builtinMounts   = []string{"cubbyhole", "secret"}
+	builtinAuths    = []string{"token"}
+	builtinPolicies = []string{"default"}
+)
+
+// The root policy can neither be read as rules nor written, and the sys
+// mount can't be mounted, so neither is ever exported.
+const (
+	rootPolicy = "root"
+	sysMount   = "sys"
+)
+
+// ExportOptions are used to export the configuration of an unsealed Vault
+// instance into the same source-tree layout that Configure consumes.
+type ExportOptions struct {
+	Dest           string `json:"dest" validate:"required"`
+	Token          string `json:"token" validate:"required"`
+	IncludeBuiltin bool   `json:"include_builtin"`
+	Cluster        string `json:"cluster"`
+}
+
+// ExportState describes what was written during an export. Each entry is
+// the Vault path (or policy name) of an exported item.
+type ExportState struct {
+	SourceDir string   `json:"source_dir"`
+	Mounts    []string `json:"mounts"`
+	Tunes     []string `json:"tunes"`
+	Auths     []string `json:"auths"`
+	Policies  []string `json:"policies"`
+}
+
+// Export reads mounts, mount tunings, auth backends and policies from Vault
+// and writes them to opts.Dest in the layout understood by Configure (i.e.
+// data/sys/mounts, data/sys/auth and data/sys/policy). Configuring Vault with
+// the exported tree is a no-op.
+func Export(opts ExportOptions) (ExportState, error) {
+	err := opts.validate()
+	if err != nil {
+		return ExportState{}, err
+	}
+
+	client, _, err := NewClusterClient(opts.Cluster)
+	if err != nil {
+		return ExportState{}, err
+	}
+	client.SetToken(opts.Token)
+
+	mounts, err := client.Sys().ListMounts()
+	if err != nil {
+		return ExportState{}, err
+	}
+
+	auths, err := client.Sys().ListAuth()
+	if err != nil {
+		return ExportState{}, err
+	}
+
+	names, err := client.Sys().ListPolicies()
+	if err != nil {
+		return ExportState{}, err
+	}
+
+	policies := make(map[string]string)
+	for _, name := range names {
+		if name == rootPolicy {
+			continue
+		}
+
+		rules, err := client.Sys().GetPolicy(name)
+		if err != nil {
+			return ExportState{}, err
+		}
+		policies[name] = rules
+	}
+
+	return exportTree(opts.Dest, mounts, auths, policies, opts.IncludeBuiltin)
+}
+
+func (opts *ExportOptions) validate() error {
+	err := config.Validator().Struct(opts)
+	if err != nil {
+		return config.NewValidationErrors(err)
+	}
+
+	_, err = os.Stat(filepath.Join(opts.Dest, "data"))
+	if err == nil {
+		return ErrExportDestExists
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Write the Vault configuration into dest. Split out from Export so that it
+// can be tested without a running Vault.
+func exportTree(dest string, mounts map[string]*vaultapi.MountOutput, auths map[string]*vaultapi.AuthMount, policies map[string]string, includeBuiltin bool) (ExportState, error) {
+	srcdata := filepath.Join(dest, "data")
+	state := ExportState{
+		SourceDir: srcdata,
+		Mounts:    []string{},
+		Tunes:     []string{},
+		Auths:     []string{},
+		Policies:  []string{},
+	}
+
+	// /sys/mounts/
+	for k, v := range mounts {
+		path := strings.TrimSuffix(k, "/")
+		if path == sysMount || (!includeBuiltin && inStrings(builtinMounts, path)) {
+			continue
+		}
+
+		err := exportPathAmbiguous(path)
+		if err != nil {
+			return state, err
+		}
+
+		mountIn := MountInput{
+			Type:        v.Type,
+			Description: v.Description,
+		}
+
+		dir := filepath.Join(srcdata, "sys", "mounts", path)
+		err = exportFile(dir, v.Type, mountIn)
+		if err != nil {
+			return state, err
+		}
+		state.Mounts = append(state.Mounts, path)
+
+		// only tune mounts that deviate from the system defaults
+		if v.Config.DefaultLeaseTTL == 0 && v.Config.MaxLeaseTTL == 0 {
+			continue
+		}
+
+		tuneIn := MountConfigInput{
+			DefaultLeaseTTL: exportTTL(v.Config.DefaultLeaseTTL),
+			MaxLeaseTTL:     exportTTL(v.Config.MaxLeaseTTL),
+		}
+
+		err = exportFile(filepath.Join(dir, "tune"), v.Type, tuneIn)
+		if err != nil {
+			return state, err
+		}
+		state.Tunes = append(state.Tunes, path)
+	}
+
+	// /sys/auth/
+	for k, v := range auths {
+		path := strings.TrimSuffix(k, "/")
+		if !includeBuiltin && inStrings(builtinAuths, path) {
+			continue
+		}
+
+		err := exportPathAmbiguous(path)
+		if err != nil {
+			return state, err
+		}
+
+		authIn := AuthInput{
+			Type:        v.Type,
+			Description: v.Description,
+		}
+
+		err = exportFile(filepath.Join(srcdata, "sys", "auth", path), v.Type, authIn)
+		if err != nil {
+			return state, err
+		}
+		state.Auths = append(state.Auths, path)
+	}
+
+	// /sys/policy/
+	for name, rules := range policies {
+		if name == rootPolicy || (!includeBuiltin && inStrings(builtinPolicies, name)) {
+			continue
+		}
+
+		err := exportPathAmbiguous(name)
+		if err != nil {
+			return state, err
+		}
+
+		policyIn := PolicyInput{
+			Rules: rules,
+		}
+
+		err = exportFile(filepath.Join(srcdata, "sys", "policy", name), filepath.Base(name), policyIn)
+		if err != nil {
+			return state, err
+		}
+		state.Policies = append(state.Policies, name)
+	}
+
+	sort.Strings(state.Mounts)
+	sort.Strings(state.Tunes)
+	sort.Strings(state.Auths)
+	sort.Strings(state.Policies)
+
+	return state, nil
+}
+
+// Marshal v as json into dir/name.json.
+func exportFile(dir, name string, v interface{}) error {
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, name+".json"), append(raw, '\n'), 0644)
+}
+
+// A Vault path ending in tune or disable would be categorized as an action
+// rather than an add when the export is re-applied.
+func exportPathAmbiguous(path string) error {
+	base := filepath.Base(path)
+	if base == "tune" || base == "disable" {
+		return fmt.Errorf("%s: %s", ErrExportAmbiguousPath.Error(), path)
+	}
+	return nil
+}
+
+// Vault reports lease TTLs in seconds, where zero means the system default.
+func exportTTL(secs int) string {
+	if secs == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%ds", secs)
+}
+
+func inStrings(arr []string, el string) bool {
+	for _, v := range arr {
+		if v == el {
+			return true
+		}
+	}
+	return false
+}
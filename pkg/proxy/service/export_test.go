@@ -0,0 +1,115 @@
+package service
+
+import (
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestExport_Tree_Categorize(t *testing.T) {
+	dest, err := ioutil.TempDir("", "armor-export")
+	assert.NoError(t, err, "not expecting an error when creating export dest")
+	defer os.RemoveAll(dest)
+
+	mounts := map[string]*vaultapi.MountOutput{
+		"sys/":       {Type: "system"},
+		"cubbyhole/": {Type: "cubbyhole"},
+		"secret/":    {Type: "generic"},
+		"postgresql/": {
+			Type:   "postgresql",
+			Config: vaultapi.MountConfigOutput{DefaultLeaseTTL: 3600, MaxLeaseTTL: 86400},
+		},
+		"cdw/mans/xyzinc/app1/prod/db/": {Type: "postgresql", Description: "app1 database"},
+	}
+	auths := map[string]*vaultapi.AuthMount{
+		"token/":   {Type: "token"},
+		"approle/": {Type: "approle"},
+	}
+	policies := map[string]string{
+		"root":                "",
+		"default":             "path \"auth/token/lookup-self\" {\n  capabilities = [\"read\"]\n}",
+		"postgresql/readonly": "path \"postgresql/creds/readonly\" {\n  capabilities = [\"read\"]\n}",
+	}
+
+	state, err := exportTree(dest, mounts, auths, policies, false)
+	assert.NoError(t, err, "not expecting an error when exporting tree")
+	assert.Equal(t, []string{"cdw/mans/xyzinc/app1/prod/db", "postgresql"}, state.Mounts, "expecting built-in mounts to be excluded")
+	assert.Equal(t, []string{"postgresql"}, state.Tunes, "expecting only non-default mounts to be tuned")
+	assert.Equal(t, []string{"approle"}, state.Auths, "expecting built-in auths to be excluded")
+	assert.Equal(t, []string{"postgresql/readonly"}, state.Policies, "expecting built-in policies to be excluded")
+
+	// the exported tree must be understood by categorize
+	opts := &configOptsExp{
+		SourceDir: state.SourceDir,
+	}
+	err = opts.categorize()
+	assert.NoError(t, err, "not expecting an error when categorizing exported tree")
+
+	postgresql, ok := opts.requests("/sys/mounts/", ConfigAdd)["postgresql"]
+	assert.True(t, ok, "expecting to find postgresql mount request")
+	mountIn, err := deserializeMountInput(postgresql.FullPath)
+	assert.NoError(t, err, "not expecting an error when deserializing exported mount")
+	assert.Equal(t, "postgresql", mountIn.Type, "expecting a match on mount type")
+
+	tune, ok := opts.requests("/sys/mounts/", ConfigTune)["postgresql"]
+	assert.True(t, ok, "expecting to find postgresql tune request")
+	tuneIn, err := deserializeMountConfigInput(tune.FullPath)
+	assert.NoError(t, err, "not expecting an error when deserializing exported tune")
+	assert.Equal(t, "3600s", tuneIn.DefaultLeaseTTL, "expecting a match on default lease ttl")
+	assert.Equal(t, "86400s", tuneIn.MaxLeaseTTL, "expecting a match on max lease ttl")
+
+	_, ok = opts.requests("/sys/mounts/", ConfigAdd)["cdw/mans/xyzinc/app1/prod/db"]
+	assert.True(t, ok, "expecting to find nested mount request")
+	_, ok = opts.requests("/sys/auth/", ConfigAdd)["approle"]
+	assert.True(t, ok, "expecting to find approle auth request")
+
+	readonly, ok := opts.requests("/sys/policy/", ConfigAdd)["postgresql/readonly"]
+	assert.True(t, ok, "expecting to find policy request")
+	policyIn, err := deserializePolicyInput(readonly.FullPath)
+	assert.NoError(t, err, "not expecting an error when deserializing exported policy")
+	assert.Equal(t, policies["postgresql/readonly"], policyIn.Rules, "expecting a match on policy rules")
+
+	// exporting over an existing tree is refused
+	exportOpts := &ExportOptions{Dest: dest, Token: "nbkd193dnakd1ueadf3"}
+	assert.Equal(t, ErrExportDestExists, exportOpts.validate(), "expecting an error when export dest already has data")
+}
+
+func TestExport_Tree_Builtin(t *testing.T) {
+	dest, err := ioutil.TempDir("", "armor-export")
+	assert.NoError(t, err, "not expecting an error when creating export dest")
+	defer os.RemoveAll(dest)
+
+	mounts := map[string]*vaultapi.MountOutput{
+		"sys/":    {Type: "system"},
+		"secret/": {Type: "generic"},
+	}
+	auths := map[string]*vaultapi.AuthMount{
+		"token/": {Type: "token"},
+	}
+	policies := map[string]string{
+		"root":    "",
+		"default": "",
+	}
+
+	state, err := exportTree(dest, mounts, auths, policies, true)
+	assert.NoError(t, err, "not expecting an error when exporting tree")
+	assert.Equal(t, []string{"secret"}, state.Mounts, "expecting built-in mounts, but never sys, when requested")
+	assert.Equal(t, []string{"token"}, state.Auths, "expecting built-in auths when requested")
+	assert.Equal(t, []string{"default"}, state.Policies, "expecting root policy to never be exported")
+
+	// the exported built-ins must be understood by categorize
+	opts := &configOptsExp{
+		SourceDir: state.SourceDir,
+	}
+	err = opts.categorize()
+	assert.NoError(t, err, "not expecting an error when categorizing exported built-ins")
+	_, ok := opts.requests("/sys/mounts/", ConfigAdd)["sys"]
+	assert.False(t, ok, "not expecting a sys mount request")
+	_, ok = opts.requests("/sys/mounts/", ConfigAdd)["secret"]
+	assert.True(t, ok, "expecting to find secret mount request")
+
+	_, err = exportTree(dest, map[string]*vaultapi.MountOutput{"pki/tune/": {Type: "pki"}}, nil, nil, false)
+	assert.Error(t, err, "expecting an error when a mount path would be mistaken for a tune")
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/config"
+)
+
+// applyHolderKeys fills PGPKeys, RecoveryPGPKeys and RootTokenPGPKey, when
+// not given, with the token holders' keys from the registry. PGPKeys and
+// RecoveryPGPKeys are only filled if every holder of the respective shares
+// has a registered key, since Vault requires a key per share. If enforce is
+// set, opts are rejected unless every token will be PGP encrypted, so no
+// plaintext token is ever persisted.
+func (opts *InitOptions) applyHolderKeys(registry dbackend.HolderKeyRegistry, enforce bool) error {
+	var missing, missingRecovery []string
+
+	if registry != nil && len(opts.PGPKeys) == 0 {
+		var keys []string
+		keys, missing = lookupHolderKeys(registry, opts.SecretKeyHolderEmails)
+		if len(missing) == 0 {
+			opts.PGPKeys = keys
+		}
+	}
+
+	if registry != nil && len(opts.RecoveryPGPKeys) == 0 && len(opts.RecoveryKeyHolderEmails) > 0 {
+		var keys []string
+		keys, missingRecovery = lookupHolderKeys(registry, opts.RecoveryKeyHolderEmails)
+		if len(missingRecovery) == 0 {
+			opts.RecoveryPGPKeys = keys
+		}
+	}
+
+	if registry != nil && opts.RootTokenPGPKey == "" {
+		key, err := registry.Lookup(opts.RootTokenHolderEmail)
+		if err == nil {
+			opts.RootTokenPGPKey = key.Vault
+		}
+	}
+
+	if !enforce {
+		return nil
+	}
+
+	errs := config.ValidationErrors{}
+	if len(opts.PGPKeys) != opts.SecretShares {
+		msg := "enforce_pgp requires a PGP key for every secret key holder"
+		if len(missing) > 0 {
+			msg = fmt.Sprintf("%s; none registered for %s", msg, strings.Join(missing, ", "))
+		}
+		errs = append(errs, config.ValidationError{Field: "PGPKeys", Message: msg})
+	}
+	if len(opts.RecoveryPGPKeys) != opts.RecoveryShares {
+		msg := "enforce_pgp requires a PGP key for every recovery key holder"
+		if len(missingRecovery) > 0 {
+			msg = fmt.Sprintf("%s; none registered for %s", msg, strings.Join(missingRecovery, ", "))
+		}
+		errs = append(errs, config.ValidationError{Field: "RecoveryPGPKeys", Message: msg})
+	}
+	if opts.RootTokenPGPKey == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "RootTokenPGPKey",
+			Message: fmt.Sprintf("enforce_pgp requires a PGP key for the root token holder; none registered for %s", opts.RootTokenHolderEmail),
+		})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// lookupHolderKeys returns the registered key of each email address, in
+// order, and the email addresses without a key.
+func lookupHolderKeys(registry dbackend.HolderKeyRegistry, emails []string) ([]string, []string) {
+	var missing []string
+
+	keys := make([]string, 0, len(emails))
+	for _, email := range emails {
+		key, err := registry.Lookup(email)
+		if err != nil {
+			missing = append(missing, email)
+			continue
+		}
+		keys = append(keys, key.Vault)
+	}
+	return keys, missing
+}
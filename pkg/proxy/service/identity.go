@@ -0,0 +1,513 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/cdwlabs/armor/pkg/config"
+	vaultapi "github.com/hashicorp/vault/api"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// IdentityEntityInput describes the request details for an identity entity.
+// Entities are keyed by name, taken from the source directory (e.g.
+// /identity/entity/jdoe/).
+type IdentityEntityInput struct {
+	Policies []string          `json:"policies,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Disabled bool              `json:"disabled,omitempty"`
+}
+
+// IdentityGroupInput describes the request details for an identity group.
+// Groups are keyed by name, taken from the source directory (e.g.
+// /identity/group/engineering/). Member entities and groups are referenced by
+// name and resolved to their IDs when the group is written.
+type IdentityGroupInput struct {
+	Type              string            `json:"type,omitempty"`
+	Policies          []string          `json:"policies,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	MemberEntityNames []string          `json:"member_entity_names,omitempty"`
+	MemberGroupNames  []string          `json:"member_group_names,omitempty"`
+}
+
+// IdentityAliasInput describes the request details for an entity or group
+// alias. Aliases are keyed by the path of their auth backend and their name,
+// taken from the source directory (e.g. /identity/group-alias/ldap/admins/
+// for the LDAP group admins). CanonicalName is the name of the entity or
+// group the alias belongs to.
+type IdentityAliasInput struct {
+	CanonicalName string `json:"canonical_name"`
+}
+
+// IdentityEntityOutput describes an identity entity defined in a Vault
+// instance.
+type IdentityEntityOutput struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Policies []string          `json:"policies,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Disabled bool              `json:"disabled,omitempty"`
+}
+
+// IdentityGroupOutput describes an identity group defined in a Vault
+// instance.
+type IdentityGroupOutput struct {
+	ID              string            `json:"id"`
+	Name            string            `json:"name"`
+	Type            string            `json:"type"`
+	Policies        []string          `json:"policies,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	MemberEntityIDs []string          `json:"member_entity_ids,omitempty"`
+	MemberGroupIDs  []string          `json:"member_group_ids,omitempty"`
+}
+
+// IdentityAliasOutput describes an entity or group alias defined in a Vault
+// instance.
+type IdentityAliasOutput struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	MountAccessor string `json:"mount_accessor"`
+	CanonicalID   string `json:"canonical_id"`
+}
+
+// identityEntityCategory configures identity entities through
+// /identity/entity/.
+type identityEntityCategory struct{}
+
+func (identityEntityCategory) Prefix() string { return "/identity/entity/" }
+
+func (identityEntityCategory) Actions() []ConfigActionType {
+	return []ConfigActionType{ConfigAdd, ConfigDelete}
+}
+
+func (identityEntityCategory) Decode(meta ConfigPathMeta) (interface{}, error) {
+	var entityIn IdentityEntityInput
+	err := deserializeIdentityInput(meta.FullPath, &entityIn)
+	if err != nil {
+		return nil, err
+	}
+	return entityIn, nil
+}
+
+func (identityEntityCategory) Plan(client *vaultapi.Client, reqs ConfigRequests) error {
+	return nil
+}
+
+// Entities are written by name, which creates or updates them.
+func (identityEntityCategory) Apply(client *vaultapi.Client, reqs ConfigRequests) error {
+	for name, meta := range reqs[ConfigAdd] {
+		entityIn := meta.Input.(IdentityEntityInput)
+
+		_, err := client.Logical().Write("identity/entity/name/"+name, map[string]interface{}{
+			"policies": entityIn.Policies,
+			"metadata": entityIn.Metadata,
+			"disabled": entityIn.Disabled,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for name := range reqs[ConfigDelete] {
+		_, err := client.Logical().Delete("identity/entity/name/" + name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c identityEntityCategory) Report(client *vaultapi.Client, state *ConfigState) error {
+	names, err := listIdentityNames(client, "identity/entity/name")
+	if err != nil {
+		return err
+	}
+
+	entities := make(map[string]IdentityEntityOutput)
+	for _, name := range names {
+		var entityOut IdentityEntityOutput
+		_, err := readIdentity(client, "identity/entity/name/"+name, &entityOut)
+		if err != nil {
+			return err
+		}
+		entities[name] = entityOut
+	}
+
+	return state.SetCategory(c.Prefix(), entities)
+}
+
+// identityGroupCategory configures identity groups through /identity/group/.
+type identityGroupCategory struct{}
+
+func (identityGroupCategory) Prefix() string { return "/identity/group/" }
+
+func (identityGroupCategory) Actions() []ConfigActionType {
+	return []ConfigActionType{ConfigAdd, ConfigDelete}
+}
+
+func (identityGroupCategory) Decode(meta ConfigPathMeta) (interface{}, error) {
+	var groupIn IdentityGroupInput
+	err := deserializeIdentityInput(meta.FullPath, &groupIn)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs config.ValidationErrors
+	switch groupIn.Type {
+	case "", "internal":
+	case "external":
+		// members of external groups are managed through group aliases
+		if len(groupIn.MemberEntityNames) > 0 || len(groupIn.MemberGroupNames) > 0 {
+			errs = append(errs, config.ValidationError{
+				Field:   "member_entity_names",
+				Message: "external groups can't have members; use a group alias instead",
+			})
+		}
+	default:
+		errs = append(errs, config.ValidationError{
+			Field:   "type",
+			Message: fmt.Sprintf("unknown group type %q (expecting internal or external)", groupIn.Type),
+		})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return groupIn, nil
+}
+
+func (identityGroupCategory) Plan(client *vaultapi.Client, reqs ConfigRequests) error {
+	return nil
+}
+
+// Groups are written by name, which creates or updates them. Member groups
+// may be defined in the same source, so all groups are written before their
+// member groups are resolved.
+func (identityGroupCategory) Apply(client *vaultapi.Client, reqs ConfigRequests) error {
+	for name, meta := range reqs[ConfigAdd] {
+		groupIn := meta.Input.(IdentityGroupInput)
+
+		data := map[string]interface{}{
+			"policies": groupIn.Policies,
+			"metadata": groupIn.Metadata,
+		}
+		if groupIn.Type != "" {
+			data["type"] = groupIn.Type
+		}
+		if len(groupIn.MemberEntityNames) > 0 {
+			ids, err := identityIDs(client, "identity/entity/name/", groupIn.MemberEntityNames)
+			if err != nil {
+				return err
+			}
+			data["member_entity_ids"] = ids
+		}
+
+		_, err := client.Logical().Write("identity/group/name/"+name, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	for name, meta := range reqs[ConfigAdd] {
+		groupIn := meta.Input.(IdentityGroupInput)
+		if len(groupIn.MemberGroupNames) == 0 {
+			continue
+		}
+
+		ids, err := identityIDs(client, "identity/group/name/", groupIn.MemberGroupNames)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.Logical().Write("identity/group/name/"+name, map[string]interface{}{
+			"member_group_ids": ids,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for name := range reqs[ConfigDelete] {
+		_, err := client.Logical().Delete("identity/group/name/" + name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c identityGroupCategory) Report(client *vaultapi.Client, state *ConfigState) error {
+	names, err := listIdentityNames(client, "identity/group/name")
+	if err != nil {
+		return err
+	}
+
+	groups := make(map[string]IdentityGroupOutput)
+	for _, name := range names {
+		var groupOut IdentityGroupOutput
+		_, err := readIdentity(client, "identity/group/name/"+name, &groupOut)
+		if err != nil {
+			return err
+		}
+		groups[name] = groupOut
+	}
+
+	return state.SetCategory(c.Prefix(), groups)
+}
+
+// identityAliasCategory configures entity aliases through
+// /identity/entity-alias/ and group aliases through /identity/group-alias/.
+type identityAliasCategory struct {
+	// kind is either entity or group
+	kind string
+}
+
+func (c identityAliasCategory) Prefix() string { return "/identity/" + c.kind + "-alias/" }
+
+func (identityAliasCategory) Actions() []ConfigActionType {
+	return []ConfigActionType{ConfigAdd, ConfigDelete}
+}
+
+func (identityAliasCategory) Decode(meta ConfigPathMeta) (interface{}, error) {
+	var aliasIn IdentityAliasInput
+	err := deserializeIdentityInput(meta.FullPath, &aliasIn)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs config.ValidationErrors
+	if !strings.Contains(meta.ConfigPath, "/") {
+		errs = append(errs, config.ValidationError{
+			Message: "alias must be found under its auth backend path (e.g. ldap/admins)",
+		})
+	}
+	if aliasIn.CanonicalName == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "canonical_name",
+			Message: "canonical_name is required",
+		})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return aliasIn, nil
+}
+
+func (identityAliasCategory) Plan(client *vaultapi.Client, reqs ConfigRequests) error {
+	return nil
+}
+
+// Aliases have no name endpoint, so existing aliases are found by their name
+// and auth backend, and updated in place.
+func (c identityAliasCategory) Apply(client *vaultapi.Client, reqs ConfigRequests) error {
+	accessors, err := authAccessors(client)
+	if err != nil {
+		return err
+	}
+
+	existing, err := c.list(client)
+	if err != nil {
+		return err
+	}
+
+	for path, meta := range reqs[ConfigAdd] {
+		aliasIn := meta.Input.(IdentityAliasInput)
+		mountPath, name := splitConfigPath(path)
+
+		accessor, ok := accessors[mountPath+"/"]
+		if !ok {
+			return fmt.Errorf("auth backend %q not found for %s alias %q", mountPath, c.kind, name)
+		}
+
+		ids, err := identityIDs(client, "identity/"+c.kind+"/name/", []string{aliasIn.CanonicalName})
+		if err != nil {
+			return err
+		}
+
+		data := map[string]interface{}{
+			"name":           name,
+			"mount_accessor": accessor,
+			"canonical_id":   ids[0],
+		}
+
+		endpoint := "identity/" + c.kind + "-alias"
+		if alias, ok := findAlias(existing, name, accessor); ok {
+			if alias.CanonicalID == ids[0] {
+				continue
+			}
+			endpoint += "/id/" + alias.ID
+		}
+
+		_, err = client.Logical().Write(endpoint, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	for path := range reqs[ConfigDelete] {
+		mountPath, name := splitConfigPath(path)
+
+		alias, ok := findAlias(existing, name, accessors[mountPath+"/"])
+		if !ok {
+			continue
+		}
+
+		_, err := client.Logical().Delete("identity/" + c.kind + "-alias/id/" + alias.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c identityAliasCategory) Report(client *vaultapi.Client, state *ConfigState) error {
+	aliases, err := c.list(client)
+	if err != nil {
+		return err
+	}
+
+	return state.SetCategory(c.Prefix(), aliases)
+}
+
+// Get all the current aliases of this kind in Vault, keyed by ID.
+func (c identityAliasCategory) list(client *vaultapi.Client) (map[string]IdentityAliasOutput, error) {
+	aliases := make(map[string]IdentityAliasOutput)
+
+	secret, err := client.Logical().List("identity/" + c.kind + "-alias/id")
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data["key_info"] == nil {
+		return aliases, nil
+	}
+
+	var keyInfo map[string]IdentityAliasOutput
+	err = decodeSecretData(secret.Data["key_info"], &keyInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, alias := range keyInfo {
+		alias.ID = id
+		aliases[id] = alias
+	}
+
+	return aliases, nil
+}
+
+func findAlias(aliases map[string]IdentityAliasOutput, name, accessor string) (IdentityAliasOutput, bool) {
+	for _, alias := range aliases {
+		if alias.Name == name && alias.MountAccessor == accessor {
+			return alias, true
+		}
+	}
+	return IdentityAliasOutput{}, false
+}
+
+// The last element of a config path is a name, everything before it is its
+// parent (e.g. the auth backend of the alias ldap/admins, or the type of the
+// plugin database/mysql-legacy).
+func splitConfigPath(path string) (mountPath, name string) {
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return "", path
+	}
+	return path[:i], path[i+1:]
+}
+
+// Get the accessor of every auth backend in Vault, keyed by path.
+func authAccessors(client *vaultapi.Client) (map[string]string, error) {
+	secret, err := client.Logical().Read("sys/auth")
+	if err != nil {
+		return nil, err
+	}
+
+	accessors := make(map[string]string)
+	if secret == nil {
+		return accessors, nil
+	}
+
+	for path, v := range secret.Data {
+		if auth, ok := v.(map[string]interface{}); ok {
+			if accessor, ok := auth["accessor"].(string); ok {
+				accessors[path] = accessor
+			}
+		}
+	}
+
+	return accessors, nil
+}
+
+// Resolve the names of entities or groups to their IDs.
+func identityIDs(client *vaultapi.Client, endpoint string, names []string) ([]string, error) {
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		var out struct {
+			ID string `json:"id"`
+		}
+
+		found, err := readIdentity(client, endpoint+name, &out)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("identity %s %q not found", strings.Split(endpoint, "/")[1], name)
+		}
+		ids = append(ids, out.ID)
+	}
+	return ids, nil
+}
+
+func listIdentityNames(client *vaultapi.Client, endpoint string) ([]string, error) {
+	secret, err := client.Logical().List(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data["keys"] == nil {
+		return nil, nil
+	}
+
+	var names []string
+	err = decodeSecretData(secret.Data["keys"], &names)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func readIdentity(client *vaultapi.Client, endpoint string, v interface{}) (bool, error) {
+	secret, err := client.Logical().Read(endpoint)
+	if err != nil {
+		return false, err
+	}
+	if secret == nil {
+		return false, nil
+	}
+
+	return true, decodeSecretData(secret.Data, v)
+}
+
+// Secret data is generic json, so round trip it into the given struct.
+func decodeSecretData(data interface{}, v interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Unmarshal a json file into an identity input.
+func deserializeIdentityInput(path string, v interface{}) error {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return strictUnmarshal(raw, v)
+}
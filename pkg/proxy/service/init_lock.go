@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/config"
+)
+
+var (
+	// ErrInitInProgress is returned while another Armor replica, or an
+	// earlier call, holds the lease on initializing the Vault cluster.
+	ErrInitInProgress = errors.New("vault init is already in progress")
+
+	// ErrVaultInitialized is returned when Init is called, e.g. retried,
+	// after the Vault cluster was initialized, through Armor or not.
+	ErrVaultInitialized = errors.New("vault is already initialized")
+)
+
+// IncompleteInitError reports a half-finished init: Vault was initialized,
+// but Armor stopped before every token holder was persisted. The keys Vault
+// returned may be lost, and can only be recovered from whoever received the
+// Init response.
+type IncompleteInitError struct {
+	Record    dbackend.InitRecord
+	Persisted []string // keys of the token holders that were persisted
+}
+
+func (e IncompleteInitError) Error() string {
+	persisted := "none"
+	if len(e.Persisted) > 0 {
+		persisted = strings.Join(e.Persisted, ", ")
+	}
+	return fmt.Sprintf("vault was initialized by %s at %s, but the init never finished; token holders persisted: %s",
+		e.Record.Owner, e.Record.DateStarted, persisted)
+}
+
+// acquireInit takes the lease on initializing the cluster, by writing
+// a pending init record. initialized reports whether Vault is initialized:
+// without a record, it tells a Vault initialized outside Armor, which gets
+// no record; with one, it tells a stale record from a finished or
+// half-finished init.
+func acquireInit(store dbackend.TokenHolderStore, clusterID string, initialized func() (bool, error), now time.Time) (*dbackend.InitRecord, error) {
+	prev, err := store.GetInitRecord(clusterID)
+	if err == dbackend.ErrInitRecordNotFound {
+		prev = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if prev == nil {
+		inited, err := initialized()
+		if err != nil {
+			return nil, err
+		}
+		if inited {
+			return nil, ErrVaultInitialized
+		}
+	} else {
+		if prev.State == dbackend.InitPending && !prev.LeaseExpired(now) {
+			return nil, ErrInitInProgress
+		}
+
+		inited, err := initialized()
+		if err != nil {
+			return nil, err
+		}
+		if inited && prev.State == dbackend.InitPending {
+			return nil, incompleteInit(store, prev)
+		}
+		if inited {
+			return nil, ErrVaultInitialized
+		}
+		// Vault isn't initialized, so the record is stale: its owner stopped
+		// before initializing Vault, or Vault's storage was reset
+	}
+
+	rec := &dbackend.InitRecord{
+		ClusterID:    clusterID,
+		State:        dbackend.InitPending,
+		Owner:        initOwner(),
+		DateStarted:  now.Format(time.RFC3339),
+		LeaseExpires: now.Add(config.Config().GetDuration("init_lock_ttl")).Format(time.RFC3339),
+	}
+	err = store.PutInitRecord(rec, prev)
+	if err == dbackend.ErrInitRecordConflict {
+		return nil, ErrInitInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+// finishInit records that every token holder of the init was persisted.
+func finishInit(store dbackend.TokenHolderStore, rec *dbackend.InitRecord, now time.Time) error {
+	next := *rec
+	next.State = dbackend.InitFinished
+	next.DateFinished = now.Format(time.RFC3339)
+	next.LeaseExpires = ""
+	return store.PutInitRecord(&next, rec)
+}
+
+// incompleteInit describes the half-finished init of rec.
+func incompleteInit(store dbackend.TokenHolderStore, rec *dbackend.InitRecord) error {
+	holders, err := store.ListByCluster(rec.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	persisted := make([]string, 0, len(holders))
+	for _, tokenHolder := range holders {
+		if tokenHolder.DateInitialized >= rec.DateStarted {
+			persisted = append(persisted, tokenHolder.Key().String())
+		}
+	}
+	return IncompleteInitError{Record: *rec, Persisted: persisted}
+}
+
+// initOwner identifies this Armor process in init records.
+func initOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
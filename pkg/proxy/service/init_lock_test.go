@@ -0,0 +1,62 @@
+package service
+
+import (
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAcquireInit(t *testing.T) {
+	store := dbackend.NewMemoryStore()
+	now := time.Now()
+	vaultInitialized := false
+	initialized := func() (bool, error) { return vaultInitialized, nil }
+
+	// Vault was initialized outside Armor, or before init records were kept
+	vaultInitialized = true
+	_, err := acquireInit(store, "packers", initialized, now)
+	assert.Equal(t, ErrVaultInitialized, err, "expecting an init of an initialized Vault to be refused")
+	_, err = store.GetInitRecord("packers")
+	assert.Equal(t, dbackend.ErrInitRecordNotFound, err, "not expecting an init record for an initialized Vault")
+	vaultInitialized = false
+
+	lease, err := acquireInit(store, "packers", initialized, now)
+	assert.NoError(t, err, "not expecting an error when no init is pending")
+	assert.Equal(t, dbackend.InitPending, lease.State, "expecting a pending init record")
+
+	_, err = acquireInit(store, "packers", initialized, now)
+	assert.Equal(t, ErrInitInProgress, err, "expecting a second init to wait for the lease")
+
+	// the owner stopped before Vault was initialized; once the lease lapses,
+	// the stale record is taken over
+	later := now.Add(time.Hour)
+	lease, err = acquireInit(store, "packers", initialized, later)
+	assert.NoError(t, err, "not expecting an error when taking over a stale init record")
+
+	// the owner stopped after Vault was initialized, having persisted a single
+	// token holder
+	vaultInitialized = true
+	tokenHolder := dbackend.NewTokenHolder()
+	tokenHolder.ClusterID = "packers"
+	tokenHolder.Email = "aaron.rodgers@packers.com"
+	tokenHolder.TokenType = dbackend.RootTokenType
+	tokenHolder.DateInitialized = later.Format(time.RFC3339)
+	err = store.Put(tokenHolder)
+	assert.NoError(t, err, "not expecting an error when putting token holder")
+
+	_, err = acquireInit(store, "packers", initialized, later.Add(time.Hour))
+	if assert.IsType(t, IncompleteInitError{}, err, "expecting a half-finished init to be reported") {
+		assert.Equal(t, []string{"root#000"}, err.(IncompleteInitError).Persisted, "expecting the persisted token holders")
+	}
+
+	err = finishInit(store, lease, later)
+	assert.NoError(t, err, "not expecting an error when finishing the init")
+
+	_, err = acquireInit(store, "packers", initialized, later)
+	assert.Equal(t, ErrVaultInitialized, err, "expecting a retried init to be refused")
+
+	vaultInitialized = false
+	_, err = acquireInit(store, "bears", initialized, later)
+	assert.NoError(t, err, "not expecting other clusters to be affected")
+}
@@ -31,21 +31,23 @@ type loggingMiddleware struct {
 	next   Service
 }
 
-func (mw loggingMiddleware) InitStatus(ctx context.Context) (initialized bool, err error) {
+func (mw loggingMiddleware) InitStatus(ctx context.Context, cluster string) (initialized bool, err error) {
 	defer func() {
 		mw.logger.Log(
 			"method", "InitStatus",
+			"cluster", ClusterName(cluster),
 			"result", initialized,
 			"error", err,
 		)
 	}()
-	return mw.next.InitStatus(ctx)
+	return mw.next.InitStatus(ctx, cluster)
 }
 
 func (mw loggingMiddleware) Init(ctx context.Context, opts InitOptions) (resp InitKeys, err error) {
 	defer func() {
 		mw.logger.Log(
 			"method", "Init",
+			"cluster", ClusterName(opts.Cluster),
 			"result", InitKeys{},
 			"error", err,
 		)
@@ -53,21 +55,34 @@ func (mw loggingMiddleware) Init(ctx context.Context, opts InitOptions) (resp In
 	return mw.next.Init(ctx, opts)
 }
 
-func (mw loggingMiddleware) SealStatus(ctx context.Context) (resp SealState, err error) {
+func (mw loggingMiddleware) SealStatus(ctx context.Context, cluster string) (resp SealState, err error) {
 	defer func() {
 		mw.logger.Log(
 			"method", "SealStatus",
+			"cluster", ClusterName(cluster),
 			"result", SealState{},
 			"error", err,
 		)
 	}()
-	return mw.next.SealStatus(ctx)
+	return mw.next.SealStatus(ctx, cluster)
+}
+
+func (mw loggingMiddleware) WatchSealStatus(ctx context.Context, cluster string, events SealEventFunc) (err error) {
+	defer func() {
+		mw.logger.Log(
+			"method", "WatchSealStatus",
+			"cluster", ClusterName(cluster),
+			"error", err,
+		)
+	}()
+	return mw.next.WatchSealStatus(ctx, cluster, events)
 }
 
 func (mw loggingMiddleware) Unseal(ctx context.Context, opts UnsealOptions) (resp SealState, err error) {
 	defer func() {
 		mw.logger.Log(
 			"method", "Unseal",
+			"cluster", ClusterName(opts.Cluster),
 			"result", SealState{},
 			"error", err,
 		)
@@ -79,6 +94,7 @@ func (mw loggingMiddleware) Configure(ctx context.Context, opts ConfigOptions) (
 	defer func() {
 		mw.logger.Log(
 			"method", "Configure",
+			"cluster", ClusterName(opts.Cluster),
 			"result", ConfigState{},
 			"error", err,
 		)
@@ -86,6 +102,121 @@ func (mw loggingMiddleware) Configure(ctx context.Context, opts ConfigOptions) (
 	return mw.next.Configure(ctx, opts)
 }
 
+func (mw loggingMiddleware) ConfigureStream(ctx context.Context, opts ConfigOptions, events ConfigEventFunc) (resp ConfigState, err error) {
+	defer func() {
+		mw.logger.Log(
+			"method", "ConfigureStream",
+			"cluster", ClusterName(opts.Cluster),
+			"result", resp.ConfigID,
+			"error", err,
+		)
+	}()
+	return mw.next.ConfigureStream(ctx, opts, events)
+}
+
+func (mw loggingMiddleware) ListTokenHolders(ctx context.Context, clusterID string) (resp []TokenHolderOutput, err error) {
+	defer func() {
+		mw.logger.Log(
+			"method", "ListTokenHolders",
+			"result", len(resp),
+			"error", err,
+		)
+	}()
+	return mw.next.ListTokenHolders(ctx, clusterID)
+}
+
+func (mw loggingMiddleware) GetTokenHolders(ctx context.Context, email string) (resp []TokenHolderOutput, err error) {
+	defer func() {
+		mw.logger.Log(
+			"method", "GetTokenHolders",
+			"result", len(resp),
+			"error", err,
+		)
+	}()
+	return mw.next.GetTokenHolders(ctx, email)
+}
+
+func (mw loggingMiddleware) ReassignTokenHolder(ctx context.Context, opts ReassignOptions) (resp TokenHolderOutput, err error) {
+	defer func() {
+		mw.logger.Log(
+			"method", "ReassignTokenHolder",
+			"result", TokenHolderOutput{},
+			"error", err,
+		)
+	}()
+	return mw.next.ReassignTokenHolder(ctx, opts)
+}
+
+func (mw loggingMiddleware) RevokeTokenHolder(ctx context.Context, opts RevokeOptions) (resp TokenHolderOutput, err error) {
+	defer func() {
+		mw.logger.Log(
+			"method", "RevokeTokenHolder",
+			"result", TokenHolderOutput{},
+			"error", err,
+		)
+	}()
+	return mw.next.RevokeTokenHolder(ctx, opts)
+}
+
+func (mw loggingMiddleware) StartUnsealCeremony(ctx context.Context, cluster string) (resp CeremonyOutput, err error) {
+	defer func() {
+		mw.logger.Log(
+			"method", "StartUnsealCeremony",
+			"cluster", ClusterName(cluster),
+			"result", resp.State,
+			"error", err,
+		)
+	}()
+	return mw.next.StartUnsealCeremony(ctx, cluster)
+}
+
+func (mw loggingMiddleware) GetUnsealCeremony(ctx context.Context, cluster, id string) (resp CeremonyOutput, err error) {
+	defer func() {
+		mw.logger.Log(
+			"method", "GetUnsealCeremony",
+			"cluster", ClusterName(cluster),
+			"result", resp.State,
+			"error", err,
+		)
+	}()
+	return mw.next.GetUnsealCeremony(ctx, cluster, id)
+}
+
+func (mw loggingMiddleware) SubmitUnsealShare(ctx context.Context, opts ShareOptions) (resp CeremonyOutput, err error) {
+	defer func() {
+		mw.logger.Log(
+			"method", "SubmitUnsealShare",
+			"cluster", ClusterName(opts.Cluster),
+			"result", resp.State,
+			"error", err,
+		)
+	}()
+	return mw.next.SubmitUnsealShare(ctx, opts)
+}
+
+func (mw loggingMiddleware) AbortUnsealCeremony(ctx context.Context, cluster, id string) (resp CeremonyOutput, err error) {
+	defer func() {
+		mw.logger.Log(
+			"method", "AbortUnsealCeremony",
+			"cluster", ClusterName(cluster),
+			"result", resp.State,
+			"error", err,
+		)
+	}()
+	return mw.next.AbortUnsealCeremony(ctx, cluster, id)
+}
+
+func (mw loggingMiddleware) ListClusters(ctx context.Context) (resp []ClusterOutput, err error) {
+	defer func() {
+		mw.logger.Log(
+			"method", "ListClusters",
+			"result", len(resp),
+			"error", err,
+		)
+	}()
+	return mw.next.ListClusters(ctx)
+}
+
 // InstrumentingMiddleware returns a service middleware that instruments
 // requests made over the lifetime of the service.
 func InstrumentingMiddleware(requestCount metrics.Counter, requestLatency metrics.Histogram) Middleware {
@@ -104,20 +235,20 @@ type instrumentingMiddleware struct {
 	next           Service
 }
 
-func (mw instrumentingMiddleware) InitStatus(ctx context.Context) (bool, error) {
+func (mw instrumentingMiddleware) InitStatus(ctx context.Context, cluster string) (bool, error) {
 	defer func(begin time.Time) {
-		lvs := []string{"method", "initstatus", "error", "false"}
+		lvs := []string{"method", "initstatus", "cluster", ClusterName(cluster), "error", "false"}
 		mw.requestCount.With(lvs...).Add(1)
 		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	initialized, err := mw.next.InitStatus(ctx)
+	initialized, err := mw.next.InitStatus(ctx, cluster)
 	return initialized, err
 }
 
 func (mw instrumentingMiddleware) Init(ctx context.Context, opts InitOptions) (resp InitKeys, err error) {
 	defer func(begin time.Time) {
-		lvs := []string{"method", "init", "error", "false"}
+		lvs := []string{"method", "init", "cluster", ClusterName(opts.Cluster), "error", "false"}
 		mw.requestCount.With(lvs...).Add(1)
 		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
@@ -126,20 +257,31 @@ func (mw instrumentingMiddleware) Init(ctx context.Context, opts InitOptions) (r
 	return resp, err
 }
 
-func (mw instrumentingMiddleware) SealStatus(ctx context.Context) (resp SealState, err error) {
+func (mw instrumentingMiddleware) SealStatus(ctx context.Context, cluster string) (resp SealState, err error) {
 	defer func(begin time.Time) {
-		lvs := []string{"method", "sealstatus", "error", "false"}
+		lvs := []string{"method", "sealstatus", "cluster", ClusterName(cluster), "error", "false"}
 		mw.requestCount.With(lvs...).Add(1)
 		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	resp, err = mw.next.SealStatus(ctx)
+	resp, err = mw.next.SealStatus(ctx, cluster)
 	return resp, err
 }
 
+func (mw instrumentingMiddleware) WatchSealStatus(ctx context.Context, cluster string, events SealEventFunc) (err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "watchsealstatus", "cluster", ClusterName(cluster), "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	err = mw.next.WatchSealStatus(ctx, cluster, events)
+	return err
+}
+
 func (mw instrumentingMiddleware) Unseal(ctx context.Context, opts UnsealOptions) (resp SealState, err error) {
 	defer func(begin time.Time) {
-		lvs := []string{"method", "unseal", "error", "false"}
+		lvs := []string{"method", "unseal", "cluster", ClusterName(opts.Cluster), "error", "false"}
 		mw.requestCount.With(lvs...).Add(1)
 		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
@@ -150,7 +292,7 @@ func (mw instrumentingMiddleware) Unseal(ctx context.Context, opts UnsealOptions
 
 func (mw instrumentingMiddleware) Configure(ctx context.Context, opts ConfigOptions) (resp ConfigState, err error) {
 	defer func(begin time.Time) {
-		lvs := []string{"method", "configure", "error", "false"}
+		lvs := []string{"method", "configure", "cluster", ClusterName(opts.Cluster), "error", "false"}
 		mw.requestCount.With(lvs...).Add(1)
 		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
@@ -158,3 +300,113 @@ func (mw instrumentingMiddleware) Configure(ctx context.Context, opts ConfigOpti
 	resp, err = mw.next.Configure(ctx, opts)
 	return resp, err
 }
+
+func (mw instrumentingMiddleware) ConfigureStream(ctx context.Context, opts ConfigOptions, events ConfigEventFunc) (resp ConfigState, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "configurestream", "cluster", ClusterName(opts.Cluster), "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = mw.next.ConfigureStream(ctx, opts, events)
+	return resp, err
+}
+
+func (mw instrumentingMiddleware) ListTokenHolders(ctx context.Context, clusterID string) (resp []TokenHolderOutput, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "listtokenholders", "cluster", "", "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = mw.next.ListTokenHolders(ctx, clusterID)
+	return resp, err
+}
+
+func (mw instrumentingMiddleware) GetTokenHolders(ctx context.Context, email string) (resp []TokenHolderOutput, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "gettokenholders", "cluster", "", "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = mw.next.GetTokenHolders(ctx, email)
+	return resp, err
+}
+
+func (mw instrumentingMiddleware) ReassignTokenHolder(ctx context.Context, opts ReassignOptions) (resp TokenHolderOutput, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "reassigntokenholder", "cluster", "", "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = mw.next.ReassignTokenHolder(ctx, opts)
+	return resp, err
+}
+
+func (mw instrumentingMiddleware) RevokeTokenHolder(ctx context.Context, opts RevokeOptions) (resp TokenHolderOutput, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "revoketokenholder", "cluster", "", "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = mw.next.RevokeTokenHolder(ctx, opts)
+	return resp, err
+}
+
+func (mw instrumentingMiddleware) StartUnsealCeremony(ctx context.Context, cluster string) (resp CeremonyOutput, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "startunsealceremony", "cluster", ClusterName(cluster), "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = mw.next.StartUnsealCeremony(ctx, cluster)
+	return resp, err
+}
+
+func (mw instrumentingMiddleware) GetUnsealCeremony(ctx context.Context, cluster, id string) (resp CeremonyOutput, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "getunsealceremony", "cluster", ClusterName(cluster), "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = mw.next.GetUnsealCeremony(ctx, cluster, id)
+	return resp, err
+}
+
+func (mw instrumentingMiddleware) SubmitUnsealShare(ctx context.Context, opts ShareOptions) (resp CeremonyOutput, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "submitunsealshare", "cluster", ClusterName(opts.Cluster), "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = mw.next.SubmitUnsealShare(ctx, opts)
+	return resp, err
+}
+
+func (mw instrumentingMiddleware) AbortUnsealCeremony(ctx context.Context, cluster, id string) (resp CeremonyOutput, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "abortunsealceremony", "cluster", ClusterName(cluster), "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = mw.next.AbortUnsealCeremony(ctx, cluster, id)
+	return resp, err
+}
+
+func (mw instrumentingMiddleware) ListClusters(ctx context.Context) (resp []ClusterOutput, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "listclusters", "cluster", "", "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = mw.next.ListClusters(ctx)
+	return resp, err
+}
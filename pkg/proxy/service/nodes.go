@@ -0,0 +1,175 @@
+package service
+
+// This file contains the handling of the nodes of an HA Vault cluster.
+
+import (
+	"errors"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// leaderAttempts bounds the lookups of a cluster's active node while a leader
+// is being elected.
+const leaderAttempts = 5
+
+// leaderRetryWait is the wait between lookups of a cluster's active node.
+var leaderRetryWait = 2 * time.Second
+
+// ErrNoVaultLeader is returned when no active node of an HA Vault cluster
+// could be found.
+var ErrNoVaultLeader = errors.New("vault cluster has no active node")
+
+// NodeSealState is the seal status of one node of a Vault cluster. Err is set
+// if the node couldn't be reached.
+type NodeSealState struct {
+	Address  string `json:"address"`
+	Sealed   bool   `json:"sealed"`
+	T        int    `json:"t"`
+	N        int    `json:"n"`
+	Progress int    `json:"progress"`
+	Version  string `json:"version"`
+	Err      string `json:"error,omitempty"`
+}
+
+// nodeClientFunc returns a client of the Vault node at address, configured
+// like the client of its cluster.
+type nodeClientFunc func(address string) (*vaultapi.Client, error)
+
+// NewClusterNodeClients returns a client of every node of the named Vault
+// cluster (see ClusterName). A cluster without node addresses is a single
+// node at its address.
+func NewClusterNodeClients(cluster string) ([]*vaultapi.Client, error) {
+	nodes, newClient, err := clusterNodes(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 {
+		client, _, err := NewClusterClient(cluster)
+		if err != nil {
+			return nil, err
+		}
+		return []*vaultapi.Client{client}, nil
+	}
+
+	clients := make([]*vaultapi.Client, 0, len(nodes))
+	for _, address := range nodes {
+		client, err := newClient(address)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+	return clients, nil
+}
+
+// NewClusterLeaderClient returns a client of the active node of the named
+// Vault cluster (see ClusterName), and the id its token holders are kept
+// under. Writes go through it, since standby nodes don't serve them.
+func NewClusterLeaderClient(cluster string) (*vaultapi.Client, string, error) {
+	client, clusterID, err := NewClusterClient(cluster)
+	if err != nil {
+		return nil, "", err
+	}
+
+	_, newClient, err := clusterNodes(cluster)
+	if err != nil {
+		return nil, "", err
+	}
+
+	leader, err := followLeader(client, newClient)
+	if err != nil {
+		return nil, "", err
+	}
+	return leader, clusterID, nil
+}
+
+// followLeader asks client's node for the active node of its cluster, and
+// returns a client of it. The lookup is retried while a leader is elected.
+// client itself is returned if its node is active, or HA isn't enabled.
+func followLeader(client *vaultapi.Client, newClient nodeClientFunc) (*vaultapi.Client, error) {
+	var err error
+	for attempt := 0; attempt < leaderAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(leaderRetryWait)
+		}
+
+		var leader *vaultapi.LeaderResponse
+		leader, err = client.Sys().Leader()
+		if err != nil {
+			continue
+		}
+		if !leader.HAEnabled || leader.IsSelf {
+			return client, nil
+		}
+		if leader.LeaderAddress == "" {
+			err = ErrNoVaultLeader
+			continue
+		}
+
+		leaderClient, err := newClient(leader.LeaderAddress)
+		if err != nil {
+			return nil, err
+		}
+		leaderClient.SetToken(client.Token())
+		return leaderClient, nil
+	}
+	return nil, err
+}
+
+// nodeSealStates returns the seal status of every node of the named Vault
+// cluster. Nodes which can't be reached are reported with their error.
+func nodeSealStates(cluster string) ([]NodeSealState, error) {
+	clients, err := NewClusterNodeClients(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]NodeSealState, 0, len(clients))
+	for _, client := range clients {
+		resp, err := client.Sys().SealStatus()
+		states = append(states, nodeSealState(client, resp, err))
+	}
+	return states, nil
+}
+
+// unsealNodes submits an unseal key to every sealed node of the named Vault
+// cluster, or resets the unseal progress of every node. Nodes which can't be
+// reached, or reject the key, are reported with their error.
+func unsealNodes(cluster string, opts UnsealOptions) ([]NodeSealState, error) {
+	clients, err := NewClusterNodeClients(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]NodeSealState, 0, len(clients))
+	for _, client := range clients {
+		var resp *vaultapi.SealStatusResponse
+		if opts.Reset {
+			resp, err = client.Sys().ResetUnsealProcess()
+		} else {
+			resp, err = client.Sys().SealStatus()
+			if err == nil && resp.Sealed {
+				resp, err = client.Sys().Unseal(opts.Key)
+			}
+		}
+		states = append(states, nodeSealState(client, resp, err))
+	}
+	return states, nil
+}
+
+func nodeSealState(client *vaultapi.Client, resp *vaultapi.SealStatusResponse, err error) NodeSealState {
+	state := NodeSealState{Address: VaultAddress(client)}
+	if err != nil {
+		state.Err = err.Error()
+		return state
+	}
+
+	state.Sealed = resp.Sealed
+	state.T = resp.T
+	state.N = resp.N
+	state.Progress = resp.Progress
+	state.Version = resp.Version
+	return state
+}
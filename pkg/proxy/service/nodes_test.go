@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// testLeaderServer answers sys/leader with each response in turn, repeating
+// the last.
+func testLeaderServer(responses ...string) *httptest.Server {
+	calls := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := responses[len(responses)-1]
+		if calls < len(responses) {
+			resp = responses[calls]
+		}
+		calls++
+		fmt.Fprint(w, resp)
+	}))
+}
+
+func TestFollowLeader(t *testing.T) {
+	defer func(wait time.Duration) { leaderRetryWait = wait }(leaderRetryWait)
+	leaderRetryWait = 0
+
+	active := testLeaderServer(`{"ha_enabled": true, "is_self": true}`)
+	defer active.Close()
+	standby := testLeaderServer(
+		`{"ha_enabled": true, "is_self": false, "leader_address": ""}`,
+		fmt.Sprintf(`{"ha_enabled": true, "is_self": false, "leader_address": %q}`, active.URL),
+	)
+	defer standby.Close()
+
+	client, err := NewVaultNodeClient(active.URL)
+	assert.NoError(t, err, "not expecting an error when creating a client")
+	leader, err := followLeader(client, NewVaultNodeClient)
+	assert.NoError(t, err, "not expecting an error for the active node")
+	assert.Equal(t, client, leader, "expecting the active node's own client")
+
+	client, err = NewVaultNodeClient(standby.URL)
+	assert.NoError(t, err, "not expecting an error when creating a client")
+	client.SetToken("packers")
+	leader, err = followLeader(client, NewVaultNodeClient)
+	assert.NoError(t, err, "not expecting an error once a leader is elected")
+	assert.Equal(t, active.URL, VaultAddress(leader), "expecting a client of the active node")
+	assert.Equal(t, "packers", leader.Token(), "expecting the token to follow the leader")
+
+	electing := testLeaderServer(`{"ha_enabled": true, "is_self": false, "leader_address": ""}`)
+	defer electing.Close()
+	client, err = NewVaultNodeClient(electing.URL)
+	assert.NoError(t, err, "not expecting an error when creating a client")
+	_, err = followLeader(client, NewVaultNodeClient)
+	assert.Equal(t, ErrNoVaultLeader, err, "expecting an error when no leader is elected")
+}
+
+func TestUnsealNodes(t *testing.T) {
+	sealed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/sys/seal-status":
+			fmt.Fprint(w, `{"sealed": true, "t": 2, "n": 3, "progress": 0}`)
+		case "/v1/sys/unseal":
+			fmt.Fprint(w, `{"sealed": true, "t": 2, "n": 3, "progress": 1}`)
+		}
+	}))
+	defer sealed.Close()
+	unsealed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/sys/unseal" {
+			t.Error("not expecting an unsealed node to be sent the key")
+		}
+		fmt.Fprint(w, `{"sealed": false, "t": 2, "n": 3, "progress": 0}`)
+	}))
+	defer unsealed.Close()
+
+	os.Setenv(config.VaultNodesEnvVar, sealed.URL+" "+unsealed.URL+" http://127.0.0.1:1")
+	defer os.Unsetenv(config.VaultNodesEnvVar)
+
+	states, err := unsealNodes("", UnsealOptions{Key: "5f4dcc3b5aa765d61d8327deb882cf99", AllNodes: true})
+	assert.NoError(t, err, "not expecting an error when unsealing nodes")
+	if assert.Len(t, states, 3, "expecting the outcome of every node") {
+		assert.Equal(t, NodeSealState{Address: sealed.URL, Sealed: true, T: 2, N: 3, Progress: 1}, states[0], "expecting the key to be submitted to a sealed node")
+		assert.Equal(t, NodeSealState{Address: unsealed.URL, T: 2, N: 3}, states[1], "expecting an unsealed node to be left alone")
+		assert.NotEmpty(t, states[2].Err, "expecting an unreachable node to be reported")
+	}
+}
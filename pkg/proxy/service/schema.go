@@ -0,0 +1,199 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/cdwlabs/armor/pkg/config"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The secret and auth backend types that Vault ships with. Additional types
+// (e.g. plugins) are accepted when listed in Armor's vault_mount_types and
+// vault_auth_types configuration.
+var (
+	builtinMountTypes = []string{
+		"aws", "cassandra", "consul", "cubbyhole", "database", "generic", "kv",
+		"mongodb", "mssql", "mysql", "pki", "plugin", "postgresql", "rabbitmq",
+		"ssh", "totp", "transit",
+	}
+	builtinAuthTypes = []string{
+		"app-id", "approle", "aws", "aws-ec2", "cert", "gcp", "github",
+		"kubernetes", "ldap", "okta", "plugin", "radius", "token", "userpass",
+	}
+)
+
+// knownMountTypes returns every secret backend type a mount may declare.
+func knownMountTypes() []string {
+	return append(append([]string{}, builtinMountTypes...), config.Config().GetStringSlice("vault_mount_types")...)
+}
+
+// knownAuthTypes returns every auth backend type an auth may declare.
+func knownAuthTypes() []string {
+	return append(append([]string{}, builtinAuthTypes...), config.Config().GetStringSlice("vault_auth_types")...)
+}
+
+// validate checks a /sys/mounts/ document against its schema.
+func (in MountInput) validate() config.ValidationErrors {
+	errs := validateType(in.Type, knownMountTypes())
+	errs = append(errs, validatePluginName(in.Type, in.PluginName)...)
+	return append(errs, validateTTLs("config.", in.Config.DefaultLeaseTTL, in.Config.MaxLeaseTTL)...)
+}
+
+// validate checks a /sys/mounts/.../tune/ document against its schema.
+func (in MountConfigInput) validate() config.ValidationErrors {
+	errs := validateTTLs("", in.DefaultLeaseTTL, in.MaxLeaseTTL)
+	if in.DefaultLeaseTTL == "" && in.MaxLeaseTTL == "" {
+		errs = append(errs, config.ValidationError{
+			Message: "tune must set at least one of default_lease_ttl or max_lease_ttl",
+		})
+	}
+	return errs
+}
+
+// validate checks a /sys/auth/ document against its schema.
+func (in AuthInput) validate() config.ValidationErrors {
+	errs := validateType(in.Type, knownAuthTypes())
+	errs = append(errs, validatePluginName(in.Type, in.PluginName)...)
+	return append(errs, validateTTLs("config.", in.Config.DefaultLeaseTTL, in.Config.MaxLeaseTTL)...)
+}
+
+func validateType(typ string, known []string) config.ValidationErrors {
+	if typ == "" {
+		return config.ValidationErrors{{Field: "type", Message: "type is required"}}
+	}
+
+	if !inStrings(known, typ) {
+		sort.Strings(known)
+		return config.ValidationErrors{{
+			Field:   "type",
+			Message: fmt.Sprintf("unknown backend type %q (expecting one of %s)", typ, strings.Join(known, ", ")),
+		}}
+	}
+
+	return nil
+}
+
+// Plugin backends name their plugin in the catalog, and only plugin backends
+// may do so.
+func validatePluginName(typ, pluginName string) config.ValidationErrors {
+	if typ == "plugin" && pluginName == "" {
+		return config.ValidationErrors{{Field: "plugin_name", Message: "plugin_name is required for plugin backends"}}
+	}
+	if typ != "plugin" && pluginName != "" {
+		return config.ValidationErrors{{Field: "plugin_name", Message: "plugin_name is only allowed for plugin backends"}}
+	}
+	return nil
+}
+
+// Both TTLs are optional, but when set they must parse and the max lease
+// must not be shorter than the default lease.
+func validateTTLs(prefix, defaultTTL, maxTTL string) config.ValidationErrors {
+	var errs config.ValidationErrors
+
+	def, err := parseTTL(defaultTTL)
+	if err != nil {
+		errs = append(errs, config.ValidationError{Field: prefix + "default_lease_ttl", Message: err.Error()})
+	}
+
+	max, err := parseTTL(maxTTL)
+	if err != nil {
+		errs = append(errs, config.ValidationError{Field: prefix + "max_lease_ttl", Message: err.Error()})
+	}
+
+	if len(errs) == 0 && def > 0 && max > 0 && max < def {
+		errs = append(errs, config.ValidationError{
+			Field:   prefix + "max_lease_ttl",
+			Message: fmt.Sprintf("max_lease_ttl (%s) must be greater than or equal to default_lease_ttl (%s)", maxTTL, defaultTTL),
+		})
+	}
+
+	return errs
+}
+
+// parseTTL accepts the same TTL strings as Vault: either a whole number of
+// seconds or a Go duration (e.g. "1h30m").
+func parseTTL(ttl string) (time.Duration, error) {
+	if ttl == "" {
+		return 0, nil
+	}
+
+	if secs, err := strconv.ParseInt(ttl, 10, 64); err == nil {
+		if secs < 0 {
+			return 0, fmt.Errorf("ttl %q must not be negative", ttl)
+		}
+		return time.Duration(secs) * time.Second, nil
+	}
+
+	d, err := time.ParseDuration(ttl)
+	if err != nil {
+		return 0, fmt.Errorf("ttl %q is not a number of seconds or a duration", ttl)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("ttl %q must not be negative", ttl)
+	}
+	return d, nil
+}
+
+// strictUnmarshal behaves like json.Unmarshal, but also rejects any field
+// that v (or its nested structs) does not declare. This catches typos such as
+// "typ" which would otherwise be silently ignored.
+func strictUnmarshal(raw []byte, v interface{}) error {
+	err := json.Unmarshal(raw, v)
+	if err != nil {
+		return err
+	}
+
+	return checkUnknownFields(raw, reflect.TypeOf(v), "")
+}
+
+func checkUnknownFields(raw []byte, t reflect.Type, prefix string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(raw, &fields)
+	if err != nil {
+		return err
+	}
+
+	declared := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		declared[name] = f.Type
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ft, ok := declared[name]
+		if !ok {
+			return fmt.Errorf("json: unknown field %q", prefix+name)
+		}
+
+		err = checkUnknownFields(fields[name], ft, prefix+name+".")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
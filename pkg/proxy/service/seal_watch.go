@@ -0,0 +1,369 @@
+package service
+
+// This file contains the watching of the seal status of Vault clusters, with
+// a single poller per Vault node shared by every watcher.
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/cdwlabs/armor/pkg/config"
+	vaultapi "github.com/hashicorp/vault/api"
+	"golang.org/x/net/context"
+)
+
+// ErrSealWatcherClosed is returned to the watchers of a seal status when
+// Armor shuts down.
+var ErrSealWatcherClosed = errors.New("seal status watcher is closed")
+
+// SealEvent reports the seal status of a watched Vault cluster. It is sent
+// once every node was polled, on every change, and as a heartbeat when
+// nothing changed for a while.
+type SealEvent struct {
+	State     SealState `json:"state"`
+	Leader    string    `json:"leader,omitempty"`    // address of the active node of an HA cluster
+	Heartbeat bool      `json:"heartbeat,omitempty"` // State is unchanged since the last event
+	Time      time.Time `json:"time"`
+}
+
+// SealEventFunc receives the events of a seal status watch as they happen.
+type SealEventFunc func(SealEvent)
+
+func (f SealEventFunc) emit(e SealEvent) {
+	if f != nil {
+		f(e)
+	}
+}
+
+// NodeSys is the part of Vault's sys API polled for the seal status of a
+// node, i.e. (*vaultapi.Client).Sys().
+type NodeSys interface {
+	SealStatus() (*vaultapi.SealStatusResponse, error)
+	Leader() (*vaultapi.LeaderResponse, error)
+}
+
+// WatchedNode is a Vault node polled by a SealWatcher.
+type WatchedNode struct {
+	Address string
+	Sys     NodeSys
+}
+
+// SealWatcher polls the seal status of the nodes of the Vault clusters being
+// watched, and hands every change to their watchers. Each node is polled by a
+// single poller, started by its first watcher and stopped with its last.
+type SealWatcher struct {
+	Interval  time.Duration // between seal status polls of a node
+	Heartbeat time.Duration // without events before a heartbeat is sent
+
+	// Nodes returns the nodes of the named Vault cluster.
+	Nodes func(cluster string) ([]WatchedNode, error)
+
+	mu      sync.Mutex
+	pollers map[string]*nodePoller // by node address
+	closed  bool
+	done    chan struct{}
+	wg      sync.WaitGroup // pollers and watches
+}
+
+// NewSealWatcher creates a SealWatcher of the clusters Armor manages. An
+// interval or heartbeat which isn't positive falls back to its default,
+// since tickers can't run on it.
+func NewSealWatcher(interval, heartbeat time.Duration) *SealWatcher {
+	if interval <= 0 {
+		interval = config.SealWatchIntervalDefault
+	}
+	if heartbeat <= 0 {
+		heartbeat = config.SealWatchHeartbeatDefault
+	}
+	return &SealWatcher{
+		Interval:  interval,
+		Heartbeat: heartbeat,
+		Nodes:     clusterWatchedNodes,
+		pollers:   make(map[string]*nodePoller),
+		done:      make(chan struct{}),
+	}
+}
+
+var (
+	defaultSealWatcher   *SealWatcher
+	defaultSealWatcherMu sync.Mutex
+)
+
+// SealWatchers returns the SealWatcher set by Armor's seal_watch_*
+// configuration. The watcher is created the first time SealWatchers is
+// called.
+func SealWatchers() *SealWatcher {
+	defaultSealWatcherMu.Lock()
+	defer defaultSealWatcherMu.Unlock()
+
+	if defaultSealWatcher == nil {
+		cfg := config.Config()
+		defaultSealWatcher = NewSealWatcher(cfg.GetDuration("seal_watch_interval"), cfg.GetDuration("seal_watch_heartbeat"))
+	}
+	return defaultSealWatcher
+}
+
+// clusterWatchedNodes returns every node of the named Vault cluster.
+func clusterWatchedNodes(cluster string) ([]WatchedNode, error) {
+	clients, err := NewClusterNodeClients(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]WatchedNode, 0, len(clients))
+	for _, client := range clients {
+		nodes = append(nodes, WatchedNode{Address: VaultAddress(client), Sys: client.Sys()})
+	}
+	return nodes, nil
+}
+
+// Watch hands the seal status of the named Vault cluster to events until ctx
+// is done, which isn't an error, or the watcher is closed.
+func (w *SealWatcher) Watch(ctx context.Context, cluster string, events SealEventFunc) error {
+	nodes, err := w.Nodes(cluster)
+	if err != nil {
+		return err
+	}
+
+	sub, err := w.subscribe(nodes)
+	if err != nil {
+		return err
+	}
+	defer w.unsubscribe(sub)
+
+	heartbeat := time.NewTicker(w.Heartbeat)
+	defer heartbeat.Stop()
+
+	var (
+		last SealEvent
+		sent bool // an event was sent since the last heartbeat
+	)
+	for {
+		var event SealEvent
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-w.done:
+			return ErrSealWatcherClosed
+		case <-sub.changed:
+			var ok bool
+			event, ok = sub.event()
+			if !ok || (!last.Time.IsZero() && event.Leader == last.Leader && reflect.DeepEqual(event.State, last.State)) {
+				continue
+			}
+		case <-heartbeat.C:
+			if sent || last.Time.IsZero() {
+				sent = false
+				continue
+			}
+			event = last
+			event.Heartbeat = true
+		}
+
+		event.Time = time.Now()
+		events.emit(event)
+		last, sent = event, true
+		last.Heartbeat = false
+	}
+}
+
+// Close stops every poller, and ends every watch with ErrSealWatcherClosed.
+// It returns once the watches handed over their last event.
+func (w *SealWatcher) Close() {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.done)
+	}
+	w.mu.Unlock()
+
+	w.wg.Wait()
+}
+
+// subscribe starts a watch of nodes, starting the pollers of the nodes which
+// aren't polled yet.
+func (w *SealWatcher) subscribe(nodes []WatchedNode) (*sealSubscription, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil, ErrSealWatcherClosed
+	}
+	w.wg.Add(1)
+
+	sub := &sealSubscription{
+		statuses: make(map[string]nodeStatus, len(nodes)),
+		changed:  make(chan struct{}, 1),
+	}
+	for _, node := range nodes {
+		p, ok := w.pollers[node.Address]
+		if !ok {
+			p = &nodePoller{node: node, subs: make(map[*sealSubscription]bool), stop: make(chan struct{})}
+			w.pollers[node.Address] = p
+			w.wg.Add(1)
+			go w.poll(p)
+		}
+		if p.subs[sub] {
+			continue
+		}
+		p.subs[sub] = true
+		sub.addresses = append(sub.addresses, node.Address)
+		if p.status != nil {
+			sub.update(node.Address, *p.status)
+		}
+	}
+	return sub, nil
+}
+
+// unsubscribe ends a watch, stopping the pollers no other watch needs.
+func (w *SealWatcher) unsubscribe(sub *sealSubscription) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, address := range sub.addresses {
+		p, ok := w.pollers[address]
+		if !ok {
+			continue
+		}
+		delete(p.subs, sub)
+		if len(p.subs) == 0 {
+			close(p.stop)
+			delete(w.pollers, address)
+		}
+	}
+	w.wg.Done()
+}
+
+// poll polls the node of p every Interval, until it is stopped or the watcher
+// closed, and hands every change to its watches.
+func (w *SealWatcher) poll(p *nodePoller) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		status := pollNode(p.node)
+
+		w.mu.Lock()
+		if p.status == nil || !reflect.DeepEqual(*p.status, status) {
+			p.status = &status
+			for sub := range p.subs {
+				sub.update(p.node.Address, status)
+			}
+		}
+		w.mu.Unlock()
+
+		select {
+		case <-p.stop:
+			return
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// nodePoller polls the seal status of a Vault node for its watches. It is
+// guarded by the watcher's mutex.
+type nodePoller struct {
+	node   WatchedNode
+	subs   map[*sealSubscription]bool
+	status *nodeStatus // nil until the first poll
+	stop   chan struct{}
+}
+
+// nodeStatus is the outcome of a poll of a Vault node.
+type nodeStatus struct {
+	NodeSealState
+	ClusterName string
+	ClusterID   string
+	Active      bool   // the node is the active node of an HA cluster
+	Leader      string // address of the active node, as known to the node
+}
+
+func pollNode(node WatchedNode) nodeStatus {
+	status := nodeStatus{NodeSealState: NodeSealState{Address: node.Address}}
+	resp, err := node.Sys.SealStatus()
+	if err != nil {
+		status.Err = err.Error()
+		return status
+	}
+
+	status.Sealed = resp.Sealed
+	status.T = resp.T
+	status.N = resp.N
+	status.Progress = resp.Progress
+	status.Version = resp.Version
+	status.ClusterName = resp.ClusterName
+	status.ClusterID = resp.ClusterID
+
+	// sealed and non HA nodes have no leader to report
+	if leader, err := node.Sys.Leader(); err == nil && leader.HAEnabled {
+		status.Active = leader.IsSelf
+		status.Leader = leader.LeaderAddress
+	}
+	return status
+}
+
+// sealSubscription is a watch of the nodes of a Vault cluster, holding the
+// last status of each.
+type sealSubscription struct {
+	addresses []string // of the watched nodes, in cluster order
+
+	mu       sync.Mutex
+	statuses map[string]nodeStatus // by node address
+	changed  chan struct{}         // signaled on every update
+}
+
+func (s *sealSubscription) update(address string, status nodeStatus) {
+	s.mu.Lock()
+	s.statuses[address] = status
+	s.mu.Unlock()
+
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// event returns the seal status of the watched cluster, once every node was
+// polled. The cluster's status is the status of its active node, or else of
+// its first reachable node.
+func (s *sealSubscription) event() (SealEvent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.statuses) < len(s.addresses) {
+		return SealEvent{}, false
+	}
+
+	var (
+		event   SealEvent
+		primary *nodeStatus
+	)
+	for _, address := range s.addresses {
+		status := s.statuses[address]
+		event.State.Nodes = append(event.State.Nodes, status.NodeSealState)
+
+		if status.Active {
+			event.Leader = address
+		} else if event.Leader == "" && status.Leader != "" {
+			event.Leader = status.Leader
+		}
+		if primary == nil || (status.Active && !primary.Active) || (primary.Err != "" && status.Err == "") {
+			primary = &status
+		}
+	}
+
+	event.State.Sealed = primary.Sealed
+	event.State.T = primary.T
+	event.State.N = primary.N
+	event.State.Progress = primary.Progress
+	event.State.Version = primary.Version
+	event.State.ClusterName = primary.ClusterName
+	event.State.ClusterID = primary.ClusterID
+	return event, true
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cdwlabs/armor/pkg/config"
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// testNodeSys is a Vault node whose seal status and leader are set by tests.
+type testNodeSys struct {
+	mu     sync.Mutex
+	seal   vaultapi.SealStatusResponse
+	leader vaultapi.LeaderResponse
+	polls  int
+}
+
+func (n *testNodeSys) SealStatus() (*vaultapi.SealStatusResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.polls++
+	seal := n.seal
+	return &seal, nil
+}
+
+func (n *testNodeSys) Leader() (*vaultapi.LeaderResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	leader := n.leader
+	return &leader, nil
+}
+
+func (n *testNodeSys) set(f func(n *testNodeSys)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	f(n)
+}
+
+func testSealWatcher(nodes ...WatchedNode) *SealWatcher {
+	w := NewSealWatcher(5*time.Millisecond, 100*time.Millisecond)
+	w.Nodes = func(cluster string) ([]WatchedNode, error) {
+		if cluster != "" {
+			return nil, UnknownClusterError{Name: cluster}
+		}
+		return nodes, nil
+	}
+	return w
+}
+
+// watch starts a watch of the default cluster, whose events and outcome are
+// sent on the returned channels.
+func watch(ctx context.Context, w *SealWatcher) (<-chan SealEvent, <-chan error) {
+	events := make(chan SealEvent, 16)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- w.Watch(ctx, "", func(e SealEvent) { events <- e })
+	}()
+	return events, errc
+}
+
+func nextSealEvent(t *testing.T, events <-chan SealEvent) SealEvent {
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(2 * time.Second):
+		t.Fatal("expecting a seal event")
+	}
+	return SealEvent{}
+}
+
+func TestSealWatcher_Events(t *testing.T) {
+	active := &testNodeSys{
+		seal:   vaultapi.SealStatusResponse{Sealed: false, T: 2, N: 3, ClusterID: "packers"},
+		leader: vaultapi.LeaderResponse{HAEnabled: true, IsSelf: true, LeaderAddress: "https://a:8200"},
+	}
+	standby := &testNodeSys{
+		seal:   vaultapi.SealStatusResponse{Sealed: true, T: 2, N: 3},
+		leader: vaultapi.LeaderResponse{HAEnabled: true, LeaderAddress: "https://a:8200"},
+	}
+	w := testSealWatcher(WatchedNode{"https://b:8200", standby}, WatchedNode{"https://a:8200", active})
+	defer w.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errc := watch(ctx, w)
+
+	e := nextSealEvent(t, events)
+	assert.False(t, e.Heartbeat, "not expecting a heartbeat first")
+	assert.False(t, e.State.Sealed, "expecting the status of the active node")
+	assert.Equal(t, "packers", e.State.ClusterID, "expecting the status of the active node")
+	assert.Equal(t, "https://a:8200", e.Leader, "expecting the active node to lead")
+	if assert.Len(t, e.State.Nodes, 2, "expecting every node") {
+		assert.Equal(t, "https://b:8200", e.State.Nodes[0].Address, "expecting the nodes in cluster order")
+		assert.True(t, e.State.Nodes[0].Sealed, "expecting the standby node sealed")
+	}
+
+	standby.set(func(n *testNodeSys) { n.seal.Progress = 1 })
+	e = nextSealEvent(t, events)
+	assert.False(t, e.Heartbeat, "expecting a change")
+	assert.Equal(t, 1, e.State.Nodes[0].Progress, "expecting the unseal progress of the standby node")
+
+	active.set(func(n *testNodeSys) {
+		n.leader = vaultapi.LeaderResponse{HAEnabled: true, LeaderAddress: "https://b:8200"}
+	})
+	standby.set(func(n *testNodeSys) {
+		n.seal = vaultapi.SealStatusResponse{Sealed: false, T: 2, N: 3, ClusterID: "packers"}
+		n.leader = vaultapi.LeaderResponse{HAEnabled: true, IsSelf: true, LeaderAddress: "https://b:8200"}
+	})
+	// the nodes are polled in turn, so the change may take a few events
+	var changed SealEvent
+	for e = nextSealEvent(t, events); !e.Heartbeat; e = nextSealEvent(t, events) {
+		changed = e
+	}
+	assert.Equal(t, "https://b:8200", changed.Leader, "expecting the leader change")
+	assert.False(t, changed.State.Nodes[0].Sealed, "expecting the new leader unsealed")
+	assert.Equal(t, changed.State, e.State, "expecting the heartbeat to repeat the last status")
+	assert.Equal(t, "https://b:8200", e.Leader, "expecting the heartbeat to repeat the last leader")
+
+	cancel()
+	assert.NoError(t, <-errc, "not expecting an error once the watch is canceled")
+}
+
+func TestSealWatcher_SharesPollers(t *testing.T) {
+	node := &testNodeSys{seal: vaultapi.SealStatusResponse{Sealed: true, T: 2, N: 3}}
+	w := testSealWatcher(WatchedNode{"https://a:8200", node})
+	defer w.Close()
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	events1, errc1 := watch(ctx1, w)
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	events2, errc2 := watch(ctx2, w)
+	nextSealEvent(t, events1)
+	nextSealEvent(t, events2)
+
+	w.mu.Lock()
+	assert.Len(t, w.pollers, 1, "expecting a single poller of the node")
+	w.mu.Unlock()
+
+	cancel1()
+	assert.NoError(t, <-errc1, "not expecting an error once the watch is canceled")
+	w.mu.Lock()
+	assert.Len(t, w.pollers, 1, "expecting the poller to keep polling for the other watch")
+	w.mu.Unlock()
+
+	cancel2()
+	assert.NoError(t, <-errc2, "not expecting an error once the watch is canceled")
+	w.mu.Lock()
+	assert.Len(t, w.pollers, 0, "expecting the poller to stop with its last watch")
+	w.mu.Unlock()
+
+	err := w.Watch(context.Background(), "dev", nil)
+	assert.Equal(t, UnknownClusterError{Name: "dev"}, err, "expecting an error for an unknown cluster")
+}
+
+func TestSealWatcher_Close(t *testing.T) {
+	node := &testNodeSys{seal: vaultapi.SealStatusResponse{Sealed: true, T: 2, N: 3}}
+	w := testSealWatcher(WatchedNode{"https://a:8200", node})
+
+	events, errc := watch(context.Background(), w)
+	nextSealEvent(t, events)
+
+	w.Close()
+	assert.Equal(t, ErrSealWatcherClosed, <-errc, "expecting the watch to end when the watcher is closed")
+
+	node.set(func(n *testNodeSys) { n.polls = 0 })
+	time.Sleep(20 * time.Millisecond)
+	node.set(func(n *testNodeSys) { assert.Equal(t, 0, n.polls, "not expecting polls once the watcher is closed") })
+
+	err := w.Watch(context.Background(), "", nil)
+	assert.Equal(t, ErrSealWatcherClosed, err, "expecting an error watching a closed watcher")
+}
+
+func TestNewSealWatcher_Durations(t *testing.T) {
+	cases := []struct {
+		interval, heartbeat time.Duration
+		wantInterval        time.Duration
+		wantHeartbeat       time.Duration
+	}{
+		{interval: time.Second, heartbeat: time.Minute, wantInterval: time.Second, wantHeartbeat: time.Minute},
+		{interval: 0, heartbeat: 0, wantInterval: config.SealWatchIntervalDefault, wantHeartbeat: config.SealWatchHeartbeatDefault},
+		{interval: -time.Second, heartbeat: -time.Minute, wantInterval: config.SealWatchIntervalDefault, wantHeartbeat: config.SealWatchHeartbeatDefault},
+	}
+
+	for _, c := range cases {
+		w := NewSealWatcher(c.interval, c.heartbeat)
+		assert.Equal(t, c.wantInterval, w.Interval, "expecting a usable interval for %s", c.interval)
+		assert.Equal(t, c.wantHeartbeat, w.Heartbeat, "expecting a usable heartbeat for %s", c.heartbeat)
+	}
+
+	// a watch must not panic on the durations it was given
+	node := &testNodeSys{seal: vaultapi.SealStatusResponse{Sealed: true, T: 2, N: 3}}
+	w := NewSealWatcher(0, -time.Second)
+	w.Nodes = func(string) ([]WatchedNode, error) { return []WatchedNode{{"https://a:8200", node}}, nil }
+	events, _ := watch(context.Background(), w)
+	nextSealEvent(t, events)
+	w.Close()
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/backend/delivery"
 	"github.com/cdwlabs/armor/pkg/config"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics"
@@ -21,25 +22,41 @@ import (
 
 // Service describes the service proxy to Vault.
 type Service interface {
-	InitStatus(ctx context.Context) (bool, error)
+	InitStatus(ctx context.Context, cluster string) (bool, error)
 	Init(ctx context.Context, opts InitOptions) (InitKeys, error)
-	SealStatus(ctx context.Context) (SealState, error)
+	SealStatus(ctx context.Context, cluster string) (SealState, error)
+	WatchSealStatus(ctx context.Context, cluster string, events SealEventFunc) error
 	Unseal(ctx context.Context, opts UnsealOptions) (SealState, error)
 	Configure(ctx context.Context, opts ConfigOptions) (ConfigState, error)
+	ConfigureStream(ctx context.Context, opts ConfigOptions, events ConfigEventFunc) (ConfigState, error)
+	ListTokenHolders(ctx context.Context, clusterID string) ([]TokenHolderOutput, error)
+	GetTokenHolders(ctx context.Context, email string) ([]TokenHolderOutput, error)
+	ReassignTokenHolder(ctx context.Context, opts ReassignOptions) (TokenHolderOutput, error)
+	RevokeTokenHolder(ctx context.Context, opts RevokeOptions) (TokenHolderOutput, error)
+	StartUnsealCeremony(ctx context.Context, cluster string) (CeremonyOutput, error)
+	GetUnsealCeremony(ctx context.Context, cluster, id string) (CeremonyOutput, error)
+	SubmitUnsealShare(ctx context.Context, opts ShareOptions) (CeremonyOutput, error)
+	AbortUnsealCeremony(ctx context.Context, cluster, id string) (CeremonyOutput, error)
+	ListClusters(ctx context.Context) ([]ClusterOutput, error)
 }
 
+// Requests target the Vault cluster named by their cluster field, or Armor's
+// default cluster if it is empty. See ClusterName.
+
 // InitOptions maps to InitRequest structs in Vault.
 type InitOptions struct {
-	SecretShares          int      `json:"secret_shares" validate:"required,gte=1,lte=10"`
-	SecretThreshold       int      `json:"secret_threshold"`
-	StoredShares          int      `json:"stored_shares"`
-	PGPKeys               []string `json:"pgp_keys"`
-	RecoveryShares        int      `json:"recovery_shares"`
-	RecoveryThreshold     int      `json:"recovery_threshold"`
-	RecoveryPGPKeys       []string `json:"recovery_pgp_keys"`
-	RootTokenPGPKey       string   `json:"root_token_pgp_key"`
-	RootTokenHolderEmail  string   `json:"root_token_holder_email" validate:"required,email"` // recipient of the root token
-	SecretKeyHolderEmails []string `json:"secret_key_holder_emails" validate:"required"`      // recipients of the secret keys used for unsealing
+	SecretShares            int      `json:"secret_shares" validate:"required,gte=1,lte=10"`
+	SecretThreshold         int      `json:"secret_threshold"`
+	StoredShares            int      `json:"stored_shares"`
+	PGPKeys                 []string `json:"pgp_keys"`
+	RecoveryShares          int      `json:"recovery_shares"`
+	RecoveryThreshold       int      `json:"recovery_threshold"`
+	RecoveryPGPKeys         []string `json:"recovery_pgp_keys"`
+	RootTokenPGPKey         string   `json:"root_token_pgp_key"`
+	RootTokenHolderEmail    string   `json:"root_token_holder_email" validate:"required,email"` // recipient of the root token
+	SecretKeyHolderEmails   []string `json:"secret_key_holder_emails" validate:"required"`      // recipients of the secret keys used for unsealing
+	RecoveryKeyHolderEmails []string `json:"recovery_key_holder_emails"`                        // recipients of the recovery keys of an auto-unsealed Vault
+	Cluster                 string   `json:"cluster,omitempty"`
 }
 
 // InitKeys is the result of successfully initializing a Vault instance.
@@ -55,26 +72,29 @@ type InitKeys struct {
 // SealState represents the current state of Vault during the process
 // of unsealing it with required number of keys.
 type SealState struct {
-	Sealed      bool   `json:"sealed"`
-	T           int    `json:"t"`
-	N           int    `json:"n"`
-	Progress    int    `json:"progress"`
-	Version     string `json:"version"`
-	ClusterName string `json:"cluster_name"`
-	ClusterID   string `json:"cluster_id"`
+	Sealed      bool            `json:"sealed"`
+	T           int             `json:"t"`
+	N           int             `json:"n"`
+	Progress    int             `json:"progress"`
+	Version     string          `json:"version"`
+	ClusterName string          `json:"cluster_name"`
+	ClusterID   string          `json:"cluster_id"`
+	Nodes       []NodeSealState `json:"nodes,omitempty"` // every node of the cluster
 }
 
 // UnsealOptions maps to UnsealRequest structs in Vault.
 type UnsealOptions struct {
-	Key   string `json:"key"`
-	Reset bool   `json:"reset"`
+	Key      string `json:"key"`
+	Reset    bool   `json:"reset"`
+	Cluster  string `json:"cluster,omitempty"`
+	AllNodes bool   `json:"all_nodes"` // submit the key to every sealed node of the cluster
 }
 
 // New creates a new Service instance
 func New(logger log.Logger, requestCount metrics.Counter, requestLatency metrics.Histogram) Service {
 	var svc Service
 	{
-		svc = NewProxyService()
+		svc = NewProxyService(logger)
 		svc = LoggingMiddleware(logger)(svc)
 		svc = InstrumentingMiddleware(requestCount, requestLatency)(svc)
 	}
@@ -85,6 +105,15 @@ var (
 	// ErrExample is an error to an arbitrary business rule for the "xxxxx"
 	// method.
 	ErrExample = errors.New("This is just a sample error.")
+
+	// ErrTokenHolderRevoked is returned when reassigning a revoked token.
+	ErrTokenHolderRevoked = errors.New("token holder was revoked")
+
+	// ErrRecoveryKeyHolderMissing is returned when Vault issues more recovery
+	// keys than recovery key holders were given. Vault is initialized all the
+	// same, and the extra recovery keys are held by the root token holder
+	// until they are reassigned.
+	ErrRecoveryKeyHolderMissing = errors.New("vault returned a recovery key without a recovery key holder")
 )
 
 // These annoying helper functions are required to translate Go error types to
@@ -108,15 +137,19 @@ func Error2String(err error) string {
 }
 
 // NewProxyService returns a naive, stateless implementation of Service.
-func NewProxyService() Service {
-	return proxyService{}
+// logger records what can't be returned to the caller, e.g. a failed token
+// delivery after Vault was initialized.
+func NewProxyService(logger log.Logger) Service {
+	return proxyService{logger: logger}
 }
 
-type proxyService struct{}
+type proxyService struct {
+	logger log.Logger
+}
 
 // InitStatus implements Service
-func (s proxyService) InitStatus(_ context.Context) (bool, error) {
-	client, err := NewVaultClient()
+func (s proxyService) InitStatus(_ context.Context, cluster string) (bool, error) {
+	client, _, err := NewClusterClient(cluster)
 	if err != nil {
 		return false, err
 	}
@@ -132,7 +165,42 @@ func (s proxyService) Init(_ context.Context, opts InitOptions) (InitKeys, error
 		return InitKeys{}, err
 	}
 
-	client, err := NewVaultClient()
+	holderKeys, err := dbackend.HolderKeys()
+	if err != nil {
+		return InitKeys{}, err
+	}
+
+	err = opts.applyHolderKeys(holderKeys, config.Config().GetBool("enforce_pgp"))
+	if err != nil {
+		return InitKeys{}, err
+	}
+
+	// the store must be usable before Vault is initialized, otherwise the
+	// root and unseal tokens would be lost
+	store, err := dbackend.TokenHolders()
+	if err != nil {
+		return InitKeys{}, err
+	}
+
+	err = store.EnsureSchema()
+	if err != nil {
+		return InitKeys{}, err
+	}
+
+	// a misconfigured delivery must surface before Vault is initialized
+	deliverer, err := delivery.New(store, config.Config())
+	if err != nil {
+		return InitKeys{}, err
+	}
+
+	client, clusterID, err := NewClusterClient(opts.Cluster)
+	if err != nil {
+		return InitKeys{}, err
+	}
+
+	// only one replica may initialize the cluster; the pending record also
+	// lets a crash between Vault init and persisting every token be detected
+	lease, err := acquireInit(store, clusterID, client.Sys().InitStatus, time.Now())
 	if err != nil {
 		return InitKeys{}, err
 	}
@@ -150,6 +218,11 @@ func (s proxyService) Init(_ context.Context, opts InitOptions) (InitKeys, error
 
 	resp, err := client.Sys().Init(initRequest)
 	if err != nil {
+		// release the lease for a retry, unless Vault was initialized after
+		// all (e.g. the response was lost)
+		if inited, statusErr := client.Sys().InitStatus(); statusErr == nil && !inited {
+			store.DeleteInitRecord(lease)
+		}
 		return InitKeys{}, err
 	}
 
@@ -167,36 +240,112 @@ func (s proxyService) Init(_ context.Context, opts InitOptions) (InitKeys, error
 
 	// persist the root token holder
 	tokenHolder := dbackend.NewTokenHolder()
+	tokenHolder.ClusterID = clusterID
 	tokenHolder.Email = opts.RootTokenHolderEmail
 	tokenHolder.Token = resp.RootToken
 	tokenHolder.TokenType = dbackend.RootTokenType
+	tokenHolder.PGPFingerprint = dbackend.PGPKeyFingerprint(opts.RootTokenPGPKey)
 	tokenHolder.DateCreated = rfc
 	tokenHolder.DateInitialized = rfc
-	err = tokenHolder.PutItem()
+	err = store.Put(tokenHolder)
 	if err != nil {
 		return initResp, err
 	}
+	holders := []*dbackend.TokenHolder{tokenHolder}
 
 	// persist each secret key
 	for i, v := range resp.Keys {
 		tokenHolder := dbackend.NewTokenHolder()
+		tokenHolder.ClusterID = clusterID
+		tokenHolder.ShareIndex = i
 		tokenHolder.Email = opts.SecretKeyHolderEmails[i]
 		tokenHolder.Token = v
 		tokenHolder.TokenType = dbackend.UnsealTokenType
+		if i < len(opts.PGPKeys) {
+			tokenHolder.PGPFingerprint = dbackend.PGPKeyFingerprint(opts.PGPKeys[i])
+		}
+		// a PGP encrypted key can't be hashed, Armor never sees it in plaintext
+		if tokenHolder.PGPFingerprint == "" {
+			tokenHolder.TokenHash = dbackend.ShareHash(v)
+		}
+		tokenHolder.DateCreated = rfc
+		tokenHolder.DateInitialized = rfc
+		err = store.Put(tokenHolder)
+		if err != nil {
+			return initResp, err
+		}
+		holders = append(holders, tokenHolder)
+	}
+
+	// persist each recovery key; Vault only returns these when it is sealed
+	// by an HSM or KMS. validate matches the recovery key holders with the
+	// recovery shares, but should Vault return more keys, they are kept by
+	// the root token holder until reassigned, so that none is lost
+	var holderMissing bool
+	for i, v := range resp.RecoveryKeys {
+		email := opts.RootTokenHolderEmail
+		if i < len(opts.RecoveryKeyHolderEmails) {
+			email = opts.RecoveryKeyHolderEmails[i]
+		} else {
+			holderMissing = true
+		}
+
+		tokenHolder := dbackend.NewTokenHolder()
+		tokenHolder.ClusterID = clusterID
+		tokenHolder.ShareIndex = i
+		tokenHolder.Email = email
+		tokenHolder.Token = v
+		tokenHolder.TokenType = dbackend.RecoveryTokenType
+		if i < len(opts.RecoveryPGPKeys) {
+			tokenHolder.PGPFingerprint = dbackend.PGPKeyFingerprint(opts.RecoveryPGPKeys[i])
+		}
 		tokenHolder.DateCreated = rfc
 		tokenHolder.DateInitialized = rfc
-		err = tokenHolder.PutItem()
+		err = store.Put(tokenHolder)
 		if err != nil {
 			return initResp, err
 		}
+		holders = append(holders, tokenHolder)
 	}
 
+	err = finishInit(store, lease, time.Now())
+	if err != nil {
+		return initResp, err
+	}
+	if holderMissing {
+		err = ErrRecoveryKeyHolderMissing
+	}
+
+	// deliver each PGP encrypted token to its holder; tokens are persisted,
+	// so a failed delivery is only logged and may be retried with
+	// `armor deliver`, while the keys are still returned to the caller
+	if deliverer == nil {
+		return initResp, err
+	}
+
+	encrypted := []*dbackend.TokenHolder{}
+	for _, tokenHolder := range holders {
+		if tokenHolder.PGPFingerprint != "" {
+			encrypted = append(encrypted, tokenHolder)
+		}
+	}
+
+	deliveryErr := deliverer.Deliver(encrypted)
+	if deliveryErr != nil {
+		s.logger.Log(
+			"method", "Init",
+			"cluster", clusterID,
+			"delivery", "failed",
+			"error", deliveryErr,
+		)
+	}
 	return initResp, err
 }
 
-// SealStatus implements Service
-func (s proxyService) SealStatus(_ context.Context) (SealState, error) {
-	client, err := NewVaultClient()
+// SealStatus implements Service. The status of every node of the cluster is
+// listed in Nodes.
+func (s proxyService) SealStatus(_ context.Context, cluster string) (SealState, error) {
+	client, _, err := NewClusterClient(cluster)
 	if err != nil {
 		return SealState{}, err
 	}
@@ -216,12 +365,22 @@ func (s proxyService) SealStatus(_ context.Context) (SealState, error) {
 		ClusterID:   resp.ClusterID,
 	}
 
+	stateResp.Nodes, err = nodeSealStates(cluster)
 	return stateResp, err
 }
 
-// Unseal implements Service
+// WatchSealStatus implements Service. The seal status of the cluster is
+// handed to events until ctx is done, from the pollers of Armor's
+// SealWatchers.
+func (s proxyService) WatchSealStatus(ctx context.Context, cluster string, events SealEventFunc) error {
+	return SealWatchers().Watch(ctx, cluster, events)
+}
+
+// Unseal implements Service. With AllNodes set, the key is submitted to (or
+// the unseal progress reset on) every sealed node of the cluster instead, and
+// the outcome of each is listed in Nodes.
 func (s proxyService) Unseal(_ context.Context, opts UnsealOptions) (SealState, error) {
-	client, err := NewVaultClient()
+	client, _, err := NewClusterClient(opts.Cluster)
 	if err != nil {
 		return SealState{}, err
 	}
@@ -230,6 +389,29 @@ func (s proxyService) Unseal(_ context.Context, opts UnsealOptions) (SealState,
 		return SealState{}, errors.New("'key' must specified, or 'reset' set to true")
 	}
 
+	if opts.AllNodes {
+		nodes, err := unsealNodes(opts.Cluster, opts)
+		if err != nil {
+			return SealState{}, err
+		}
+
+		resp, err := client.Sys().SealStatus()
+		if err != nil {
+			return SealState{}, err
+		}
+
+		return SealState{
+			Sealed:      resp.Sealed,
+			T:           resp.T,
+			N:           resp.N,
+			Progress:    resp.Progress,
+			Version:     resp.Version,
+			ClusterName: resp.ClusterName,
+			ClusterID:   resp.ClusterID,
+			Nodes:       nodes,
+		}, nil
+	}
+
 	var stateResp SealState
 	if opts.Reset {
 		resp, err := client.Sys().ResetUnsealProcess()
@@ -267,22 +449,38 @@ func (s proxyService) Unseal(_ context.Context, opts UnsealOptions) (SealState,
 	return stateResp, err
 }
 
-// Configure implements Service
-func (s proxyService) Configure(_ context.Context, opts ConfigOptions) (ConfigState, error) {
+// Configure implements Service. The configuration is written to the active
+// node of an HA cluster.
+func (s proxyService) Configure(ctx context.Context, opts ConfigOptions) (ConfigState, error) {
+	return s.ConfigureStream(ctx, opts, nil)
+}
+
+// ConfigureStream implements Service.
+func (s proxyService) ConfigureStream(_ context.Context, opts ConfigOptions, events ConfigEventFunc) (ConfigState, error) {
+	state, err := configure(opts, events)
+	done := ConfigEvent{ConfigID: state.ConfigID, Phase: ConfigPhaseDone}.withError(err)
+	if err == nil {
+		done.State = &state
+	}
+	events.emit(done)
+	return state, err
+}
+
+func configure(opts ConfigOptions, events ConfigEventFunc) (ConfigState, error) {
 
 	// validate incoming request
-	cfgexpanded, err := opts.validate()
+	cfgexpanded, err := opts.validate(events)
 	if err != nil {
-		return ConfigState{}, err
+		return ConfigState{ConfigID: cfgexpanded.ConfigID}, err
 	}
 
-	client, err := NewVaultClient()
+	client, _, err := NewClusterLeaderClient(opts.Cluster)
 	if err != nil {
-		return ConfigState{}, err
+		return ConfigState{ConfigID: cfgexpanded.ConfigID}, err
 	}
 	client.SetToken(cfgexpanded.Token)
 
-	state, err := cfgexpanded.handleRequests(client)
+	state, err := cfgexpanded.handleRequests(client, events)
 	return state, err
 }
 
@@ -290,26 +488,8 @@ func (opts *InitOptions) validate() error {
 	validate := config.Validator()
 	validate.RegisterStructValidation(initOptionsStructLevelValidation, InitOptions{})
 	err := validate.Struct(opts)
-
 	if err != nil {
-		validationerr := ""
-		for _, err := range err.(validator.ValidationErrors) {
-			//fmt.Printf("Error: ActualTag: %s", err.ActualTag())
-			//fmt.Printf("Error: Field: %s", err.Field())
-			//fmt.Printf("Error: Kind: %s", err.Kind())
-			//fmt.Printf("Error: Namespace: %s", err.Namespace())
-			//fmt.Printf("Error: Param: %s", err.Param())
-			//fmt.Printf("Error: StructNamespace: %s", err.StructNamespace())
-			//fmt.Printf("Error: StructField: %s", err.StructField())
-			//fmt.Printf("Error: Tag: %s", err.Tag())
-			validationerr = fmt.Sprintf("%s validation failed on '%s' check", err.Namespace(), err.Tag())
-			break
-		}
-
-		if validationerr != "" {
-			return fmt.Errorf(validationerr)
-		}
-		return fmt.Errorf("Invalid init option(s)")
+		return config.NewValidationErrors(err)
 	}
 	return nil
 }
@@ -321,6 +501,10 @@ func initOptionsStructLevelValidation(sl validator.StructLevel) {
 	if opts.SecretShares > 0 && len(opts.SecretKeyHolderEmails) != opts.SecretShares {
 		sl.ReportError(opts.SecretKeyHolderEmails, "SecretKeyHolderEmails", "secretholders", "secretholders", "")
 	}
+
+	if len(opts.RecoveryKeyHolderEmails) != opts.RecoveryShares {
+		sl.ReportError(opts.RecoveryKeyHolderEmails, "RecoveryKeyHolderEmails", "recoveryholders", "recoveryholders", "")
+	}
 }
 
 // NewVaultClient creates a Vault client by starting with Vault's DefaultConfig.
@@ -328,17 +512,29 @@ func initOptionsStructLevelValidation(sl validator.StructLevel) {
 // finally, checks for existence of same environment variables as the Vault
 // client CLI (e.g. VAULT_ADDR).
 func NewVaultClient() (*vaultapi.Client, error) {
+	cfg := config.Config()
+	address := ""
+	if cfg.IsSet("vault_address") && cfg.GetString("vault_address") != "" {
+		address = cfg.GetString("vault_address")
+	} else if v := os.Getenv("VAULT_ADDRESS"); v != "" {
+		address = v
+	}
+
+	return NewVaultNodeClient(address)
+}
+
+// NewVaultNodeClient returns a Vault client of the Vault server at address,
+// configured like NewVaultClient otherwise. Vault's default address is used
+// if address is empty.
+func NewVaultNodeClient(address string) (*vaultapi.Client, error) {
 	tlsConfig, err := DefaultTLSConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	cfg := config.Config()
 	vaultcfg := vaultapi.DefaultConfig()
-	if cfg.IsSet("vault_address") && cfg.GetString("vault_address") != "" {
-		vaultcfg.Address = cfg.GetString("vault_address")
-	} else if v := os.Getenv("VAULT_ADDRESS"); v != "" {
-		vaultcfg.Address = v
+	if address != "" {
+		vaultcfg.Address = address
 	}
 
 	vaultcfg.ConfigureTLS(tlsConfig)
@@ -350,6 +546,24 @@ func NewVaultClient() (*vaultapi.Client, error) {
 	return client, nil
 }
 
+// VaultClusterID returns the identifier under which the token holders of the
+// client's Vault server are kept. It is Armor's vault_cluster_id, if set, and
+// otherwise the Vault server address. Vault's own cluster id can't be used,
+// since it isn't assigned until the server is first unsealed.
+func VaultClusterID(client *vaultapi.Client) string {
+	if id := config.Config().GetString("vault_cluster_id"); id != "" {
+		return id
+	}
+
+	return VaultAddress(client)
+}
+
+// VaultAddress returns the address of the client's Vault server.
+func VaultAddress(client *vaultapi.Client) string {
+	u := client.NewRequest("GET", "/").URL
+	return u.Scheme + "://" + u.Host
+}
+
 // DefaultTLSConfig builds a Vault client-compatible TLS configuration. It
 // first checks if necessary flags were set in Armor and
 // secondarily checks for existence of same environment variables as the Vault
@@ -1,11 +1,9 @@
 package service
 
 import (
-	"encoding/json"
+	"fmt"
 	vaultapi "github.com/hashicorp/vault/api"
 	"io/ioutil"
-	"path/filepath"
-	"strings"
 )
 
 // AuthInput describes the request details for adding auth backends to a Vault
@@ -14,6 +12,7 @@ type AuthInput struct {
 	Type        string          `json:"type"`
 	Description string          `json:"description"`
 	Config      AuthConfigInput `json:"config,omitempty"`
+	PluginName  string          `json:"plugin_name,omitempty"`
 }
 
 // AuthConfigInput describes the lease details of requested mount.
@@ -37,116 +36,75 @@ type AuthConfigOutput struct {
 	MaxLeaseTTL     int `json:"max_lease_ttl,omitempty"`
 }
 
-func (opts *configOptsExp) hasSysAuthRequests() bool {
-	return (len(opts.SysAuthAddReq) > 0) || (len(opts.SysAuthDelReq) > 0)
-}
-
-// searches the source for /sys/auth/xxx
-func (opts *configOptsExp) findSysAuths(metaset []ConfigPathMeta) error {
-	if len(metaset) == 0 {
-		return nil
-	}
-
-	sysauthslit := "/sys/auth/"
-
-	// literals describing type of action
-	tunelit := "/tune/"
-	disablelit := "/disable/"
-	addlit := "/"
-
-	sysAuthAddReq := make(map[string]ConfigPathMeta)
-	sysAuthDelReq := make(map[string]ConfigPathMeta)
-
-	for _, meta := range metaset {
-		// substring one - split file from full path
-		subsone, _ := filepath.Split(meta.FullPath)
-
-		// substring two - strip base from beginning of string
-		substwo := subsone[len(meta.BasePath):]
-
-		// substring three - determine what type of vault config is being submitted
-		// (e.g. /sys/mounts/).  And substring - determine type of update (e.g.
-		// tune or disable)
-		substhree := ""
-		subsfour := ""
-
-		if strings.HasPrefix(substwo, sysauthslit) {
-			substhree = substwo[len(sysauthslit):]
-			meta.VaultEndPoint = sysauthslit
-
-			if strings.HasSuffix(substhree, tunelit) {
-				subsfour = substhree[:len(substhree)-len(tunelit)]
-				// tuning auths is currently unsupported
-				continue
+// sysAuthCategory configures auth backends through /sys/auth/.
+type sysAuthCategory struct{}
 
-			} else if strings.HasSuffix(substhree, disablelit) {
-				subsfour = substhree[:len(substhree)-len(disablelit)]
-				meta.ConfigPath = subsfour
-				meta.Action = sysAuthDelete
-				sysAuthDelReq[subsfour] = meta
+func (sysAuthCategory) Prefix() string { return "/sys/auth/" }
 
-			} else if strings.HasSuffix(substhree, addlit) {
-				subsfour = substhree[:len(substhree)-len(addlit)]
-				meta.ConfigPath = subsfour
-				meta.Action = sysAuthAdd
-				sysAuthAddReq[subsfour] = meta
+// tuning auths is currently unsupported
+func (sysAuthCategory) Actions() []ConfigActionType {
+	return []ConfigActionType{ConfigAdd, ConfigDelete}
+}
 
-			} else {
-				// not sure we will ever get here...just defensive programming
-				continue
-			}
+func (sysAuthCategory) Decode(meta ConfigPathMeta) (interface{}, error) {
+	authIn, err := deserializeAuthInput(meta.FullPath)
+	if err != nil {
+		return nil, err
+	}
+	if errs := authIn.validate(); len(errs) > 0 {
+		return nil, errs
+	}
+	return authIn, nil
+}
 
-		} else {
-			// string prefix is not /sys/auth/
-			continue
-		}
-	} // for loop
+// Auth backends that already exist with a different type can't be added.
+func (sysAuthCategory) Plan(client *vaultapi.Client, reqs ConfigRequests) error {
+	if len(reqs[ConfigAdd]) == 0 {
+		return nil
+	}
 
-	// Populate our configOptsExp with the categorization results
-	opts.SysAuthAddReq = sysAuthAddReq
-	if len(opts.SysAuthAddReq) > 0 {
-		opts.Actions = append(opts.Actions, sysAuthAdd)
+	existing, err := client.Sys().ListAuth()
+	if err != nil {
+		return err
 	}
 
-	opts.SysAuthDelReq = sysAuthDelReq
-	if len(opts.SysAuthDelReq) > 0 {
-		opts.Actions = append(opts.Actions, sysAuthDelete)
+	for path, meta := range reqs[ConfigAdd] {
+		authInput := meta.Input.(AuthInput)
+		if auth, ok := existing[path+"/"]; ok && auth.Type != authInput.Type {
+			return fmt.Errorf("auth backend %q already exists with type %q", path, auth.Type)
+		}
 	}
 
 	return nil
 }
 
 // Perform any updates to /sys/auth/ in Vault.
-func (opts *configOptsExp) handleSysAuths(client *vaultapi.Client) (map[string]AuthMountOutput, error) {
-
-	madechgs := false
-	var err error
-	if len(opts.SysAuthAddReq) > 0 {
-		err = opts.addSysAuths(client)
+func (sysAuthCategory) Apply(client *vaultapi.Client, reqs ConfigRequests) error {
+	if len(reqs[ConfigAdd]) > 0 {
+		err := addSysAuths(client, reqs[ConfigAdd])
 		if err != nil {
-			return nil, err
+			return err
 		}
-		madechgs = true
 	}
 
-	if len(opts.SysAuthDelReq) > 0 {
-		err = opts.deleteSysAuths(client)
+	if len(reqs[ConfigDelete]) > 0 {
+		err := deleteSysAuths(client, reqs[ConfigDelete])
 		if err != nil {
-			return nil, err
+			return err
 		}
-		madechgs = true
 	}
 
-	// if there were auth changes, then get list of auth backends from Vault
-	var auths map[string]AuthMountOutput
-	if madechgs {
-		auths, err = listAuths(client)
-		if err != nil {
-			return nil, err
-		}
+	return nil
+}
+
+func (sysAuthCategory) Report(client *vaultapi.Client, state *ConfigState) error {
+	auths, err := listAuths(client)
+	if err != nil {
+		return err
 	}
 
-	return auths, nil
+	state.Auths = auths
+	return nil
 }
 
 // Get all the current auth backends in Vault.
@@ -177,15 +135,37 @@ func listAuths(client *vaultapi.Client) (map[string]AuthMountOutput, error) {
 }
 
 // Add new auth backends to Vault
-func (opts *configOptsExp) addSysAuths(client *vaultapi.Client) error {
-	if len(opts.SysAuthAddReq) == 0 {
+func addSysAuths(client *vaultapi.Client, reqs map[string]ConfigPathMeta) error {
+	if len(reqs) == 0 {
 		return ErrStateSysAuthAddReqEmpty
 	}
 
-	for path, meta := range opts.SysAuthAddReq {
-		authInput, err := deserializeAuthInput(meta.FullPath)
-		if err != nil {
-			return err
+	// existing auth backends are skipped, so that re-applying the same source
+	// (e.g. the output of Export) is a no-op.
+	existing, err := client.Sys().ListAuth()
+	if err != nil {
+		return err
+	}
+
+	for path, meta := range reqs {
+		authInput := meta.Input.(AuthInput)
+
+		// existing paths of a different type were rejected by Plan
+		if _, ok := existing[path+"/"]; ok {
+			continue
+		}
+
+		// the vault client predates plugin backends
+		if authInput.PluginName != "" {
+			_, err = client.Logical().Write("sys/auth/"+path, map[string]interface{}{
+				"type":        authInput.Type,
+				"description": authInput.Description,
+				"plugin_name": authInput.PluginName,
+			})
+			if err != nil {
+				return err
+			}
+			continue
 		}
 
 		err = client.Sys().EnableAuth(path, authInput.Type, authInput.Description)
@@ -198,14 +178,14 @@ func (opts *configOptsExp) addSysAuths(client *vaultapi.Client) error {
 }
 
 // Disable existing auth backends in Vault
-func (opts *configOptsExp) deleteSysAuths(client *vaultapi.Client) error {
-	if len(opts.SysAuthDelReq) == 0 {
+func deleteSysAuths(client *vaultapi.Client, reqs map[string]ConfigPathMeta) error {
+	if len(reqs) == 0 {
 		return ErrStateSysAuthDelReqEmpty
 	}
 
 	// NOTE: disabling auth backends in Vault doesn't require any real json file.
 	// Disable by path name only.
-	for path := range opts.SysAuthDelReq {
+	for path := range reqs {
 		err := client.Sys().DisableAuth(path)
 		if err != nil {
 			return err
@@ -225,7 +205,7 @@ func deserializeAuthInput(path string) (AuthInput, error) {
 
 	// unmarshal json file into generic map
 	var authIn AuthInput
-	err = json.Unmarshal(raw, &authIn)
+	err = strictUnmarshal(raw, &authIn)
 	if err != nil {
 		return AuthInput{}, err
 	}
@@ -1,11 +1,9 @@
 package service
 
 import (
-	"encoding/json"
+	"fmt"
 	vaultapi "github.com/hashicorp/vault/api"
 	"io/ioutil"
-	"path/filepath"
-	"strings"
 )
 
 // MountInput maps directly to Vault's own MountInput.
@@ -13,6 +11,7 @@ type MountInput struct {
 	Type        string           `json:"type"`
 	Description string           `json:"description"`
 	Config      MountConfigInput `json:"config,omitempty"`
+	PluginName  string           `json:"plugin_name,omitempty"`
 }
 
 // MountConfigInput describes the lease details of requested mount.
@@ -36,117 +35,86 @@ type MountConfigOutput struct {
 	MaxLeaseTTL     int `json:"max_lease_ttl,omitempty"`
 }
 
-func (opts *configOptsExp) hasSysMountRequests() bool {
-	return (len(opts.SysMountAddReq) > 0) || (len(opts.SysMountUpdReq) > 0)
+// sysMountsCategory configures secret backends through /sys/mounts/.
+type sysMountsCategory struct{}
+
+func (sysMountsCategory) Prefix() string { return "/sys/mounts/" }
+
+// unmounting is currently unsupported
+func (sysMountsCategory) Actions() []ConfigActionType {
+	return []ConfigActionType{ConfigAdd, ConfigTune}
 }
 
-// searches the source for /sys/mounts/xxx
-func (opts *configOptsExp) findSysMounts(metaset []ConfigPathMeta) error {
-	if len(metaset) == 0 {
-		return nil
+func (sysMountsCategory) Decode(meta ConfigPathMeta) (interface{}, error) {
+	if meta.Action == ConfigTune {
+		mountCfgIn, err := deserializeMountConfigInput(meta.FullPath)
+		if err != nil {
+			return nil, err
+		}
+		if errs := mountCfgIn.validate(); len(errs) > 0 {
+			return nil, errs
+		}
+		return mountCfgIn, nil
 	}
 
-	sysmountslit := "/sys/mounts/"
-
-	// literals describing type of action
-	tunelit := "/tune/"
-	disablelit := "/disable/"
-	addlit := "/"
-
-	sysMountAddReq := make(map[string]ConfigPathMeta)
-	sysMountUpdReq := make(map[string]ConfigPathMeta)
-
-	for _, meta := range metaset {
-		// substring one - split file from full path
-		subsone, _ := filepath.Split(meta.FullPath)
-
-		// substring two - strip base from beginning of string
-		substwo := subsone[len(meta.BasePath):]
-
-		// substring three - determine what type of vault config is being submitted
-		// (e.g. /sys/mounts/).  And substring - determine type of update (e.g.
-		// tune or disable)
-		substhree := ""
-		subsfour := ""
-
-		if strings.HasPrefix(substwo, sysmountslit) {
-			substhree = substwo[len(sysmountslit):]
-			meta.VaultEndPoint = sysmountslit
-
-			if strings.HasSuffix(substhree, tunelit) {
-				subsfour = substhree[:len(substhree)-len(tunelit)]
-				meta.ConfigPath = subsfour
-				meta.Action = sysMountUpdate
-				sysMountUpdReq[subsfour] = meta
-
-			} else if strings.HasSuffix(substhree, disablelit) {
-				subsfour = substhree[:len(substhree)-len(disablelit)]
-				// unmounting is currently unsupported
-				continue
-
-			} else if strings.HasSuffix(substhree, addlit) {
-				subsfour = substhree[:len(substhree)-len(addlit)]
-				meta.ConfigPath = subsfour
-				meta.Action = sysMountAdd
-				sysMountAddReq[subsfour] = meta
-
-			} else {
-				// not sure we will ever get here...just defensive programming
-				continue
-			}
+	mountIn, err := deserializeMountInput(meta.FullPath)
+	if err != nil {
+		return nil, err
+	}
+	if errs := mountIn.validate(); len(errs) > 0 {
+		return nil, errs
+	}
+	return mountIn, nil
+}
 
-		} else {
-			// string prefix is not /sys/mounts/
-			continue
-		}
-	} // for loop
+// Mounts that already exist with a different type can't be added.
+func (sysMountsCategory) Plan(client *vaultapi.Client, reqs ConfigRequests) error {
+	if len(reqs[ConfigAdd]) == 0 {
+		return nil
+	}
 
-	// Populate our configOptsExp with the categorization results
-	opts.SysMountAddReq = sysMountAddReq
-	if len(opts.SysMountAddReq) > 0 {
-		opts.Actions = append(opts.Actions, sysMountAdd)
+	existing, err := client.Sys().ListMounts()
+	if err != nil {
+		return err
 	}
 
-	opts.SysMountUpdReq = sysMountUpdReq
-	if len(opts.SysMountUpdReq) > 0 {
-		opts.Actions = append(opts.Actions, sysMountUpdate)
+	for path, meta := range reqs[ConfigAdd] {
+		mountInput := meta.Input.(MountInput)
+		if mnt, ok := existing[path+"/"]; ok && mnt.Type != mountInput.Type {
+			return fmt.Errorf("mount %q already exists with type %q", path, mnt.Type)
+		}
 	}
 
 	return nil
 }
 
 // Perform any updates to /sys/mounts/ in Vault.
-func (opts *configOptsExp) handleSysMounts(client *vaultapi.Client) (map[string]MountOutput, error) {
-
-	madechgs := false
-	var err error
-	if len(opts.SysMountAddReq) > 0 {
-		err = opts.addSysMounts(client)
+func (sysMountsCategory) Apply(client *vaultapi.Client, reqs ConfigRequests) error {
+	if len(reqs[ConfigAdd]) > 0 {
+		err := addSysMounts(client, reqs[ConfigAdd])
 		if err != nil {
-			return nil, err
+			return err
 		}
-		madechgs = true
 	}
 
-	if len(opts.SysMountUpdReq) > 0 {
-		err = opts.tuneSysMounts(client)
+	if len(reqs[ConfigTune]) > 0 {
+		err := tuneSysMounts(client, reqs[ConfigTune])
 		if err != nil {
-			return nil, err
+			return err
 		}
-		madechgs = true
 	}
 
-	// get list of mounts from Vault
-	var mounts map[string]MountOutput
-	if madechgs {
-		// get list of mounts from Vault
-		mounts, err = listMounts(client)
-		if err != nil {
-			return nil, err
-		}
+	return nil
+}
+
+func (sysMountsCategory) Report(client *vaultapi.Client, state *ConfigState) error {
+	mounts, err := listMounts(client)
+	if err != nil {
+		return err
 	}
 
-	return mounts, nil
+	state.Mounts = mounts
+	return nil
 }
 
 // Get all the current mounts in Vault.
@@ -177,15 +145,38 @@ func listMounts(client *vaultapi.Client) (map[string]MountOutput, error) {
 }
 
 // Add new mounts to Vault
-func (opts *configOptsExp) addSysMounts(client *vaultapi.Client) error {
-	if len(opts.SysMountAddReq) == 0 {
+func addSysMounts(client *vaultapi.Client, reqs map[string]ConfigPathMeta) error {
+	if len(reqs) == 0 {
 		return ErrStateSysMountAddReqEmpty
 	}
 
-	for path, meta := range opts.SysMountAddReq {
-		mountInput, err := deserializeMountInput(meta.FullPath)
-		if err != nil {
-			return err
+	// existing mounts are skipped, so that re-applying the same source (e.g.
+	// the output of Export) is a no-op.
+	existing, err := client.Sys().ListMounts()
+	if err != nil {
+		return err
+	}
+
+	for path, meta := range reqs {
+		mountInput := meta.Input.(MountInput)
+
+		// existing paths of a different type were rejected by Plan
+		if _, ok := existing[path+"/"]; ok {
+			continue
+		}
+
+		// the vault client predates plugin backends
+		if mountInput.PluginName != "" {
+			_, err = client.Logical().Write("sys/mounts/"+path, map[string]interface{}{
+				"type":        mountInput.Type,
+				"description": mountInput.Description,
+				"config":      mountInput.Config,
+				"plugin_name": mountInput.PluginName,
+			})
+			if err != nil {
+				return err
+			}
+			continue
 		}
 
 		mountCfgIn := vaultapi.MountConfigInput{
@@ -209,23 +200,20 @@ func (opts *configOptsExp) addSysMounts(client *vaultapi.Client) error {
 }
 
 // Update existing mounts to Vault
-func (opts *configOptsExp) tuneSysMounts(client *vaultapi.Client) error {
-	if len(opts.SysMountUpdReq) == 0 {
+func tuneSysMounts(client *vaultapi.Client, reqs map[string]ConfigPathMeta) error {
+	if len(reqs) == 0 {
 		return ErrStateSysMountUpdReqEmpty
 	}
 
-	for path, meta := range opts.SysMountUpdReq {
-		mountInput, err := deserializeMountConfigInput(meta.FullPath)
-		if err != nil {
-			return err
-		}
+	for path, meta := range reqs {
+		mountInput := meta.Input.(MountConfigInput)
 
 		mountCfgIn := vaultapi.MountConfigInput{
 			DefaultLeaseTTL: mountInput.DefaultLeaseTTL,
 			MaxLeaseTTL:     mountInput.MaxLeaseTTL,
 		}
 
-		err = client.Sys().TuneMount(path, mountCfgIn)
+		err := client.Sys().TuneMount(path, mountCfgIn)
 		if err != nil {
 			return err
 		}
@@ -244,7 +232,7 @@ func deserializeMountInput(path string) (MountInput, error) {
 
 	// unmarshal json file into generic map
 	var mountIn MountInput
-	err = json.Unmarshal(raw, &mountIn)
+	err = strictUnmarshal(raw, &mountIn)
 	if err != nil {
 		return MountInput{}, err
 	}
@@ -262,7 +250,7 @@ func deserializeMountConfigInput(path string) (MountConfigInput, error) {
 
 	// unmarshal json file into generic map
 	var mountCfgIn MountConfigInput
-	err = json.Unmarshal(raw, &mountCfgIn)
+	err = strictUnmarshal(raw, &mountCfgIn)
 	if err != nil {
 		return MountConfigInput{}, err
 	}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/cdwlabs/armor/pkg/config"
+	vaultapi "github.com/hashicorp/vault/api"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// pluginTypes are the types of plugin Vault's catalog distinguishes.
+var pluginTypes = []string{"auth", "database", "secret"}
+
+// PluginInput describes the request details for registering a plugin in
+// Vault's plugin catalog. Plugins are keyed by type and name, taken from the
+// source directory (e.g. /sys/plugins/catalog/database/mysql-legacy/).
+type PluginInput struct {
+	SHA256  string   `json:"sha256"`
+	Command string   `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+	Env     []string `json:"env,omitempty"`
+}
+
+// sysPluginsCategory registers plugins through /sys/plugins/catalog/. It is
+// applied before any other category, so mounts and auths may use the plugins
+// it registers.
+type sysPluginsCategory struct{}
+
+func (sysPluginsCategory) Prefix() string { return "/sys/plugins/catalog/" }
+
+func (sysPluginsCategory) Actions() []ConfigActionType {
+	return []ConfigActionType{ConfigAdd, ConfigDelete}
+}
+
+// The declared sha256 is verified against the plugin binary found in
+// vault_plugin_dir, so that a plugin is never registered with a checksum Vault
+// will refuse to run.
+func (sysPluginsCategory) Decode(meta ConfigPathMeta) (interface{}, error) {
+	raw, err := ioutil.ReadFile(meta.FullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var pluginIn PluginInput
+	err = strictUnmarshal(raw, &pluginIn)
+	if err != nil {
+		return nil, err
+	}
+
+	pluginType, name := splitConfigPath(meta.ConfigPath)
+	if !inStrings(pluginTypes, pluginType) {
+		return nil, config.ValidationErrors{{
+			Message: fmt.Sprintf("plugin must be found under its type (expecting one of %s)", strings.Join(pluginTypes, ", ")),
+		}}
+	}
+
+	if pluginIn.Command == "" {
+		pluginIn.Command = name
+	}
+
+	errs := pluginIn.verify()
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return pluginIn, nil
+}
+
+func (sysPluginsCategory) Plan(client *vaultapi.Client, reqs ConfigRequests) error {
+	return nil
+}
+
+// Perform any updates to /sys/plugins/catalog/ in Vault.
+func (sysPluginsCategory) Apply(client *vaultapi.Client, reqs ConfigRequests) error {
+	for path, meta := range reqs[ConfigAdd] {
+		pluginIn := meta.Input.(PluginInput)
+
+		_, err := client.Logical().Write("sys/plugins/catalog/"+path, map[string]interface{}{
+			"sha256":  pluginIn.SHA256,
+			"command": pluginIn.Command,
+			"args":    pluginIn.Args,
+			"env":     pluginIn.Env,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for path := range reqs[ConfigDelete] {
+		_, err := client.Logical().Delete("sys/plugins/catalog/" + path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Plugins are reported by type, e.g. {"database": ["mysql-legacy"]}.
+func (c sysPluginsCategory) Report(client *vaultapi.Client, state *ConfigState) error {
+	plugins, err := listPlugins(client)
+	if err != nil {
+		return err
+	}
+
+	return state.SetCategory(c.Prefix(), plugins)
+}
+
+// Get all the plugins registered in Vault's catalog, keyed by type.
+func listPlugins(client *vaultapi.Client) (map[string][]string, error) {
+	plugins := make(map[string][]string)
+
+	secret, err := client.Logical().Read("sys/plugins/catalog")
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return plugins, nil
+	}
+
+	for _, pluginType := range pluginTypes {
+		if secret.Data[pluginType] == nil {
+			continue
+		}
+
+		var names []string
+		err = decodeSecretData(secret.Data[pluginType], &names)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(names)
+		plugins[pluginType] = names
+	}
+
+	return plugins, nil
+}
+
+// Compare the declared sha256 of a plugin with its binary.
+func (in PluginInput) verify() config.ValidationErrors {
+	if in.SHA256 == "" {
+		return config.ValidationErrors{{Field: "sha256", Message: "sha256 is required"}}
+	}
+
+	// like Vault, only run binaries found directly in the plugin directory
+	if filepath.Base(in.Command) != in.Command {
+		return config.ValidationErrors{{Field: "command", Message: "command must name a binary in the plugin directory"}}
+	}
+
+	dir := config.Config().GetString("vault_plugin_dir")
+	if dir == "" {
+		return config.ValidationErrors{{
+			Field:   "sha256",
+			Message: "vault_plugin_dir must be set to verify the plugin sha256",
+		}}
+	}
+
+	sum, err := fileSHA256(filepath.Join(dir, in.Command))
+	if err != nil {
+		return config.ValidationErrors{{Field: "command", Message: err.Error()}}
+	}
+
+	if !strings.EqualFold(sum, in.SHA256) {
+		return config.ValidationErrors{{
+			Field:   "sha256",
+			Message: fmt.Sprintf("sha256 %s does not match plugin binary %q (%s)", in.SHA256, in.Command, sum),
+		}}
+	}
+
+	return nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -1,11 +1,8 @@
 package service
 
 import (
-	"encoding/json"
 	vaultapi "github.com/hashicorp/vault/api"
 	"io/ioutil"
-	"path/filepath"
-	"strings"
 )
 
 // PolicyInput describes the request details for managing policies in a Vault
@@ -14,116 +11,52 @@ type PolicyInput struct {
 	Rules string `json:"rules"`
 }
 
-func (opts *configOptsExp) hasSysPolicyRequests() bool {
-	return (len(opts.SysPolicyAddReq) > 0) || (len(opts.SysPolicyDelReq) > 0)
-}
-
-// searches the source for /sys/policy/xxx
-func (opts *configOptsExp) findSysPolicies(metaset []ConfigPathMeta) error {
-	if len(metaset) == 0 {
-		return nil
-	}
+// sysPolicyCategory configures policies through /sys/policy/.
+type sysPolicyCategory struct{}
 
-	syspolicylit := "/sys/policy/"
-
-	// literals describing type of action
-	tunelit := "/tune/"
-	disablelit := "/disable/"
-	addlit := "/"
-
-	sysPolicyAddReq := make(map[string]ConfigPathMeta)
-	sysPolicyDelReq := make(map[string]ConfigPathMeta)
-
-	for _, meta := range metaset {
-		// substring one - split file from full path
-		subsone, _ := filepath.Split(meta.FullPath)
-
-		// substring two - strip base from beginning of string
-		substwo := subsone[len(meta.BasePath):]
-
-		// substring three - determine what type of vault config is being submitted
-		// (e.g. /sys/mounts/).  And substring - determine type of update (e.g.
-		// tune or disable)
-		substhree := ""
-		subsfour := ""
-
-		if strings.HasPrefix(substwo, syspolicylit) {
-			substhree = substwo[len(syspolicylit):]
-			meta.VaultEndPoint = syspolicylit
-
-			if strings.HasSuffix(substhree, tunelit) {
-				subsfour = substhree[:len(substhree)-len(tunelit)]
-				// tuning auths is currently unsupported
-				continue
-
-			} else if strings.HasSuffix(substhree, disablelit) {
-				subsfour = substhree[:len(substhree)-len(disablelit)]
-				meta.ConfigPath = subsfour
-				meta.Action = sysPolicyDelete
-				sysPolicyDelReq[subsfour] = meta
-
-			} else if strings.HasSuffix(substhree, addlit) {
-				subsfour = substhree[:len(substhree)-len(addlit)]
-				meta.ConfigPath = subsfour
-				meta.Action = sysPolicyAdd
-				sysPolicyAddReq[subsfour] = meta
-
-			} else {
-				// not sure we will ever get here...just defensive programming
-				continue
-			}
-
-		} else {
-			// string prefix is not /sys/policy/
-			continue
-		}
-	} // for loop
+func (sysPolicyCategory) Prefix() string { return "/sys/policy/" }
 
-	// Populate our configOptsExp with the categorization results
-	opts.SysPolicyAddReq = sysPolicyAddReq
-	if len(opts.SysPolicyAddReq) > 0 {
-		opts.Actions = append(opts.Actions, sysPolicyAdd)
-	}
+// tuning policies is unsupported
+func (sysPolicyCategory) Actions() []ConfigActionType {
+	return []ConfigActionType{ConfigAdd, ConfigDelete}
+}
 
-	opts.SysPolicyDelReq = sysPolicyDelReq
-	if len(opts.SysPolicyDelReq) > 0 {
-		opts.Actions = append(opts.Actions, sysPolicyDelete)
-	}
+func (sysPolicyCategory) Decode(meta ConfigPathMeta) (interface{}, error) {
+	return deserializePolicyInput(meta.FullPath)
+}
 
+// Policies are written as a whole, so there is nothing to conflict with.
+func (sysPolicyCategory) Plan(client *vaultapi.Client, reqs ConfigRequests) error {
 	return nil
 }
 
 // Perform any updates to /sys/policy/ in Vault.
-func (opts *configOptsExp) handleSysPolicies(client *vaultapi.Client) ([]string, error) {
-
-	madechgs := false
-	var err error
-	if len(opts.SysPolicyAddReq) > 0 {
-		err = opts.addSysPolicies(client)
+func (sysPolicyCategory) Apply(client *vaultapi.Client, reqs ConfigRequests) error {
+	if len(reqs[ConfigAdd]) > 0 {
+		err := addSysPolicies(client, reqs[ConfigAdd])
 		if err != nil {
-			return nil, err
+			return err
 		}
-		madechgs = true
 	}
 
-	if len(opts.SysPolicyDelReq) > 0 {
-		err = opts.deleteSysPolicies(client)
+	if len(reqs[ConfigDelete]) > 0 {
+		err := deleteSysPolicies(client, reqs[ConfigDelete])
 		if err != nil {
-			return nil, err
+			return err
 		}
-		madechgs = true
 	}
 
-	// if there were policy changes, then get list of policies from Vault
-	var policies []string
-	if madechgs {
-		policies, err = listPolicies(client)
-		if err != nil {
-			return nil, err
-		}
+	return nil
+}
+
+func (sysPolicyCategory) Report(client *vaultapi.Client, state *ConfigState) error {
+	policies, err := listPolicies(client)
+	if err != nil {
+		return err
 	}
 
-	return policies, nil
+	state.Policies = policies
+	return nil
 }
 
 // Get all the current policies in Vault.
@@ -138,18 +71,15 @@ func listPolicies(client *vaultapi.Client) ([]string, error) {
 }
 
 // Add new policies to Vault
-func (opts *configOptsExp) addSysPolicies(client *vaultapi.Client) error {
-	if len(opts.SysPolicyAddReq) == 0 {
+func addSysPolicies(client *vaultapi.Client, reqs map[string]ConfigPathMeta) error {
+	if len(reqs) == 0 {
 		return ErrStateSysPolicyAddReqEmpty
 	}
 
-	for path, meta := range opts.SysPolicyAddReq {
-		policyInput, err := deserializePolicyInput(meta.FullPath)
-		if err != nil {
-			return err
-		}
+	for path, meta := range reqs {
+		policyInput := meta.Input.(PolicyInput)
 
-		err = client.Sys().PutPolicy(path, policyInput.Rules)
+		err := client.Sys().PutPolicy(path, policyInput.Rules)
 		if err != nil {
 			return err
 		}
@@ -159,14 +89,14 @@ func (opts *configOptsExp) addSysPolicies(client *vaultapi.Client) error {
 }
 
 // Delete existing policies in Vault
-func (opts *configOptsExp) deleteSysPolicies(client *vaultapi.Client) error {
-	if len(opts.SysPolicyDelReq) == 0 {
+func deleteSysPolicies(client *vaultapi.Client, reqs map[string]ConfigPathMeta) error {
+	if len(reqs) == 0 {
 		return ErrStateSysPolicyDelReqEmpty
 	}
 
 	// NOTE: deleting policies in Vault doesn't require any real json file.
 	// Disable by path name only.
-	for path := range opts.SysPolicyDelReq {
+	for path := range reqs {
 		err := client.Sys().DeletePolicy(path)
 		if err != nil {
 			return err
@@ -186,7 +116,7 @@ func deserializePolicyInput(path string) (PolicyInput, error) {
 
 	// unmarshal json file into generic map
 	var policyIn PolicyInput
-	err = json.Unmarshal(raw, &policyIn)
+	err = strictUnmarshal(raw, &policyIn)
 	if err != nil {
 		return PolicyInput{}, err
 	}
@@ -0,0 +1,186 @@
+package service
+
+// This file contains the token holder administration methods of the service.
+
+import (
+	"time"
+
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/config"
+	"golang.org/x/net/context"
+)
+
+// TokenHolderOutput describes who holds one of a Vault cluster's root, unseal
+// or recovery tokens. It deliberately leaves out the token itself, and the data
+// key protecting it, so it is safe to return to administrators.
+type TokenHolderOutput struct {
+	ClusterID       string `json:"cluster_id"`
+	TokenType       string `json:"token_type"`
+	ShareIndex      int    `json:"share_index"`
+	Email           string `json:"email"`
+	DateCreated     string `json:"date_created,omitempty"`
+	DateInitialized string `json:"date_initialized,omitempty"`
+	DateDelivered   string `json:"date_delivered,omitempty"`
+	DateRevoked     string `json:"date_revoked,omitempty"`
+	PGPFingerprint  string `json:"pgp_fingerprint,omitempty"`
+	KeyID           string `json:"key_id,omitempty"`
+}
+
+// ReassignOptions identifies a token, and the email address of the person who
+// takes custody of it.
+type ReassignOptions struct {
+	ClusterID  string `json:"cluster_id" validate:"required"`
+	TokenType  string `json:"token_type" validate:"required,eq=root|eq=unseal|eq=recovery"`
+	ShareIndex int    `json:"share_index" validate:"gte=0"`
+	Email      string `json:"email" validate:"required,email"`
+}
+
+// RevokeOptions identifies the token to mark as revoked.
+type RevokeOptions struct {
+	ClusterID  string `json:"cluster_id" validate:"required"`
+	TokenType  string `json:"token_type" validate:"required,eq=root|eq=unseal|eq=recovery"`
+	ShareIndex int    `json:"share_index" validate:"gte=0"`
+}
+
+// ListTokenHolders implements Service. Token holders of every cluster are
+// listed if clusterID is empty. Their tokens are left out, so they are never
+// decrypted.
+func (s proxyService) ListTokenHolders(_ context.Context, clusterID string) ([]TokenHolderOutput, error) {
+	store, err := dbackend.TokenHolders()
+	if err != nil {
+		return nil, err
+	}
+	store = dbackend.MetadataStore(store)
+
+	var holders []*dbackend.TokenHolder
+	if clusterID != "" {
+		holders, err = store.ListByCluster(clusterID)
+	} else {
+		holders, err = store.List()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newTokenHolderOutputs(holders), nil
+}
+
+// GetTokenHolders implements Service. Every token held by the email address,
+// across clusters, is returned.
+func (s proxyService) GetTokenHolders(_ context.Context, email string) ([]TokenHolderOutput, error) {
+	if email == "" {
+		return nil, dbackend.ErrTokenHolderEmailUnset
+	}
+
+	store, err := dbackend.TokenHolders()
+	if err != nil {
+		return nil, err
+	}
+
+	holders, err := dbackend.MetadataStore(store).ListByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if len(holders) == 0 {
+		return nil, dbackend.ErrTokenHolderNotFound
+	}
+
+	return newTokenHolderOutputs(holders), nil
+}
+
+// ReassignTokenHolder implements Service. Only the custody record changes:
+// a PGP encrypted token stays encrypted for the previous holder's key, so
+// it must be handed over, or Vault rekeyed, before the new holder can use it.
+// The token is marked undelivered, so `armor deliver --undelivered` picks it
+// up.
+func (s proxyService) ReassignTokenHolder(_ context.Context, opts ReassignOptions) (TokenHolderOutput, error) {
+	err := config.Validator().Struct(opts)
+	if err != nil {
+		return TokenHolderOutput{}, config.NewValidationErrors(err)
+	}
+
+	store, err := dbackend.TokenHolders()
+	if err != nil {
+		return TokenHolderOutput{}, err
+	}
+
+	key := dbackend.TokenHolderKey{
+		ClusterID:  opts.ClusterID,
+		TokenType:  opts.TokenType,
+		ShareIndex: opts.ShareIndex,
+	}
+	tokenHolder, err := store.Get(key)
+	if err != nil {
+		return TokenHolderOutput{}, err
+	}
+	if tokenHolder.DateRevoked != "" {
+		return TokenHolderOutput{}, ErrTokenHolderRevoked
+	}
+
+	tokenHolder.Email = opts.Email
+	tokenHolder.DateDelivered = ""
+	err = store.Put(tokenHolder)
+	if err != nil {
+		return TokenHolderOutput{}, err
+	}
+
+	return newTokenHolderOutput(tokenHolder), nil
+}
+
+// RevokeTokenHolder implements Service. The token is kept, but is no longer
+// delivered or reassigned. Revoking a revoked token keeps its original
+// revocation date.
+func (s proxyService) RevokeTokenHolder(_ context.Context, opts RevokeOptions) (TokenHolderOutput, error) {
+	err := config.Validator().Struct(opts)
+	if err != nil {
+		return TokenHolderOutput{}, config.NewValidationErrors(err)
+	}
+
+	store, err := dbackend.TokenHolders()
+	if err != nil {
+		return TokenHolderOutput{}, err
+	}
+
+	key := dbackend.TokenHolderKey{
+		ClusterID:  opts.ClusterID,
+		TokenType:  opts.TokenType,
+		ShareIndex: opts.ShareIndex,
+	}
+	tokenHolder, err := store.Get(key)
+	if err != nil {
+		return TokenHolderOutput{}, err
+	}
+
+	if tokenHolder.DateRevoked == "" {
+		tokenHolder.DateRevoked = time.Now().Format(time.RFC3339)
+		err = store.Put(tokenHolder)
+		if err != nil {
+			return TokenHolderOutput{}, err
+		}
+	}
+
+	return newTokenHolderOutput(tokenHolder), nil
+}
+
+func newTokenHolderOutput(tokenHolder *dbackend.TokenHolder) TokenHolderOutput {
+	return TokenHolderOutput{
+		ClusterID:       tokenHolder.ClusterID,
+		TokenType:       tokenHolder.TokenType,
+		ShareIndex:      tokenHolder.ShareIndex,
+		Email:           tokenHolder.Email,
+		DateCreated:     tokenHolder.DateCreated,
+		DateInitialized: tokenHolder.DateInitialized,
+		DateDelivered:   tokenHolder.DateDelivered,
+		DateRevoked:     tokenHolder.DateRevoked,
+		PGPFingerprint:  tokenHolder.PGPFingerprint,
+		KeyID:           tokenHolder.KeyID,
+	}
+}
+
+func newTokenHolderOutputs(holders []*dbackend.TokenHolder) []TokenHolderOutput {
+	out := make([]TokenHolderOutput, 0, len(holders))
+	for _, tokenHolder := range holders {
+		out = append(out, newTokenHolderOutput(tokenHolder))
+	}
+	return out
+}
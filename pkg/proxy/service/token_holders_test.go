@@ -0,0 +1,80 @@
+package service
+
+import (
+	dbackend "github.com/cdwlabs/armor/pkg/backend/data"
+	"github.com/cdwlabs/armor/pkg/config"
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestTokenHolders_Admin(t *testing.T) {
+	os.Setenv(config.TokenHolderStoreEnvVar, dbackend.MemoryTokenHolderStore)
+	defer os.Unsetenv(config.TokenHolderStoreEnvVar)
+
+	store, err := dbackend.TokenHolders()
+	assert.NoError(t, err, "not expecting an error when creating memory store")
+
+	for i, email := range []string{"aaron.rodgers@packers.com", "david.bakhtiari@packers.com"} {
+		tokenHolder := dbackend.NewTokenHolder()
+		tokenHolder.ClusterID = "packers"
+		tokenHolder.ShareIndex = i
+		tokenHolder.Email = email
+		tokenHolder.Token = "5f4dcc3b5aa765d61d8327deb882cf99"
+		tokenHolder.TokenType = dbackend.UnsealTokenType
+		tokenHolder.DateDelivered = tokenHolder.DateCreated
+		err = store.Put(tokenHolder)
+		assert.NoError(t, err, "not expecting an error when putting token holder")
+	}
+	other := dbackend.NewTokenHolder()
+	other.ClusterID = "bears"
+	other.Email = "aaron.rodgers@packers.com"
+	other.Token = "6cb75f652a9b52798eb6cf2201057c73"
+	other.TokenType = dbackend.RootTokenType
+	err = store.Put(other)
+	assert.NoError(t, err, "not expecting an error when putting another cluster's token holder")
+
+	svc := NewProxyService(log.NewNopLogger())
+	ctx := context.Background()
+
+	holders, err := svc.ListTokenHolders(ctx, "")
+	assert.NoError(t, err, "not expecting an error when listing every cluster")
+	assert.Len(t, holders, 3, "expecting token holders of every cluster")
+
+	holders, err = svc.ListTokenHolders(ctx, "packers")
+	assert.NoError(t, err, "not expecting an error when listing a cluster")
+	assert.Len(t, holders, 2, "expecting only the cluster's token holders")
+
+	holders, err = svc.GetTokenHolders(ctx, "aaron.rodgers@packers.com")
+	assert.NoError(t, err, "not expecting an error when getting token holders by email")
+	assert.Len(t, holders, 2, "expecting every token of the email address, across clusters")
+
+	_, err = svc.GetTokenHolders(ctx, "bryan.bulaga@packers.com")
+	assert.Equal(t, dbackend.ErrTokenHolderNotFound, err, "expecting not found for an email address without tokens")
+
+	_, err = svc.ReassignTokenHolder(ctx, ReassignOptions{ClusterID: "packers", TokenType: "master", Email: "bryan.bulaga@packers.com"})
+	assert.IsType(t, config.ValidationErrors{}, err, "expecting validation errors for an unknown token type")
+
+	_, err = svc.ReassignTokenHolder(ctx, ReassignOptions{ClusterID: "packers", TokenType: dbackend.UnsealTokenType, ShareIndex: 5, Email: "bryan.bulaga@packers.com"})
+	assert.Equal(t, dbackend.ErrTokenHolderNotFound, err, "expecting not found for a missing share")
+
+	holder, err := svc.ReassignTokenHolder(ctx, ReassignOptions{ClusterID: "packers", TokenType: dbackend.UnsealTokenType, ShareIndex: 1, Email: "bryan.bulaga@packers.com"})
+	assert.NoError(t, err, "not expecting an error when reassigning a token")
+	assert.Equal(t, "bryan.bulaga@packers.com", holder.Email, "expecting the new holder's email address")
+	assert.Empty(t, holder.DateDelivered, "expecting a reassigned token to be undelivered")
+
+	found, err := store.Get(dbackend.TokenHolderKey{ClusterID: "packers", TokenType: dbackend.UnsealTokenType, ShareIndex: 1})
+	assert.NoError(t, err, "not expecting an error when getting the reassigned token")
+	assert.Equal(t, "bryan.bulaga@packers.com", found.Email, "expecting the reassignment to be persisted")
+	assert.Equal(t, "5f4dcc3b5aa765d61d8327deb882cf99", found.Token, "expecting the token to be kept")
+
+	holder, err = svc.RevokeTokenHolder(ctx, RevokeOptions{ClusterID: "packers", TokenType: dbackend.UnsealTokenType, ShareIndex: 1})
+	assert.NoError(t, err, "not expecting an error when revoking a token")
+	assert.NotEmpty(t, holder.DateRevoked, "expecting a revocation date")
+
+	_, err = svc.ReassignTokenHolder(ctx, ReassignOptions{ClusterID: "packers", TokenType: dbackend.UnsealTokenType, ShareIndex: 1, Email: "aaron.rodgers@packers.com"})
+	assert.Equal(t, ErrTokenHolderRevoked, err, "expecting a revoked token not to be reassigned")
+}